@@ -23,6 +23,9 @@ type Config struct {
 	GoogleCallbackURL  string
 	FrontendURL        string
 	SessionSecret      string
+
+	// Recipe Config
+	RecipeDuplicatePolicy string
 }
 
 func LoadConfig() (result0 *Config) {
@@ -49,6 +52,9 @@ func LoadConfig() (result0 *Config) {
 		GoogleCallbackURL:  getEnv("GOOGLE_CALLBACK_URL", "http://localhost:3030/auth/oauth/google/callback"),
 		FrontendURL:        getEnv("FRONTEND_URL", "http://localhost:3000"),
 		SessionSecret:      getEnv("SESSION_SECRET", "your-session-secret-here"),
+
+		// Recipe Config
+		RecipeDuplicatePolicy: getEnv("RECIPE_DUPLICATE_POLICY", "warn"),
 	}
 	result0 = cfg
 	return
@@ -74,8 +74,8 @@ type RecipeService interface {
 	// GetAvailableIngredients obtém ingredientes disponíveis na despensa
 	GetAvailableIngredients(ctx context.Context, pantryID uuid.UUID, userID uuid.UUID) ([]recipeDTO.AvailableIngredientDTO, error)
 
-	// SearchRecipesByIngredients busca receitas por ingredientes
-	SearchRecipesByIngredients(ctx context.Context, ingredients []string, filters map[string]string) ([]dto.RecipeResponseDTO, error)
+	// SearchRecipesByIngredients busca receitas salvas pelos ingredientes disponíveis
+	SearchRecipesByIngredients(ctx context.Context, userID uuid.UUID, input *recipeDTO.IngredientSearchDTO) ([]recipeDTO.IngredientSearchResultDTO, error)
 
 	// ValidateRecipeRequest valida uma requisição de receita
 	ValidateRecipeRequest(request *dto.RecipeRequestDTO) (uuid.UUID, error)
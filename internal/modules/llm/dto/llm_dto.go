@@ -116,6 +116,19 @@ type RecipeResponseDTO struct {
 	Tips                []string               `json:"tips,omitempty"`
 	SourceURL           string                 `json:"source_url,omitempty"`
 	GeneratedAt         string                 `json:"generated_at"`
+	DietaryWarnings     []DietaryWarningDTO    `json:"dietary_warnings,omitempty"`
+}
+
+// DietaryWarningDTO representa um ingrediente que fere uma restrição
+// alimentar. Source é "recipe" para as restrições da própria receita ou do
+// pedido e "member" para as de alguém da casa, identificado por UserID e Email
+type DietaryWarningDTO struct {
+	Ingredient  string   `json:"ingredient"`
+	Restriction string   `json:"restriction"`
+	Allergens   []string `json:"allergens"`
+	Source      string   `json:"source"`
+	UserID      string   `json:"user_id,omitempty"`
+	Email       string   `json:"email,omitempty"`
 }
 
 // RecipeIngredientDTO representa um ingrediente na receita
@@ -144,6 +157,7 @@ type RecipeNutritionDTO struct {
 	Fiber         *float64 `json:"fiber,omitempty"`
 	Sugar         *float64 `json:"sugar,omitempty"`
 	Sodium        *float64 `json:"sodium,omitempty"`
+	Confidence    *float64 `json:"confidence,omitempty"`
 }
 
 // ToLLMRequest converte LLMRequestDTO para model.LLMRequest
@@ -0,0 +1,132 @@
+// Package data bundles the food composition table loaded into the database
+// on startup, so nutrition can be computed without any external service.
+package data
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
+)
+
+// Source names the table the bundled values come from.
+const Source = "TACO"
+
+//go:embed foods.csv
+var foodsCSV string
+
+var foodColumns = []string{
+	"code", "name", "category", "energy_kcal", "protein_g", "carbohydrate_g",
+	"fat_g", "fiber_g", "density", "unit_weight", "aliases",
+}
+
+// Foods parses the bundled table.
+func Foods() ([]*model.Food, error) {
+	return parseFoods(strings.NewReader(foodsCSV))
+}
+
+func parseFoods(r io.Reader) ([]*model.Food, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = len(foodColumns)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read food table header: %w", err)
+	}
+	for i, column := range foodColumns {
+		if strings.TrimSpace(header[i]) != column {
+			return nil, fmt.Errorf("food table column %d is %q, expected %q", i+1, header[i], column)
+		}
+	}
+
+	var foods []*model.Food
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read food table: %w", err)
+		}
+
+		food, err := parseFood(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("food table line %d: %w", line, err)
+		}
+		if seen[food.Code] {
+			return nil, fmt.Errorf("food table repeats code %q", food.Code)
+		}
+		seen[food.Code] = true
+		foods = append(foods, food)
+	}
+	return foods, nil
+}
+
+func parseFood(record []string) (*model.Food, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+	if record[0] == "" || record[1] == "" {
+		return nil, fmt.Errorf("code and name are required")
+	}
+
+	values := make([]float64, 5)
+	for i := range values {
+		value, err := strconv.ParseFloat(record[3+i], 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid %s %q", foodColumns[3+i], record[3+i])
+		}
+		values[i] = value
+	}
+	density, err := optionalPositive(record[8])
+	if err != nil {
+		return nil, fmt.Errorf("invalid density: %w", err)
+	}
+	unitWeight, err := optionalPositive(record[9])
+	if err != nil {
+		return nil, fmt.Errorf("invalid unit_weight: %w", err)
+	}
+
+	aliases := model.FoodAliasesJSON{}
+	for _, alias := range strings.Split(record[10], "|") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return &model.Food{
+		Code:         record[0],
+		Name:         record[1],
+		Category:     record[2],
+		Aliases:      aliases,
+		EnergyKcal:   values[0],
+		Protein:      values[1],
+		Carbohydrate: values[2],
+		Fat:          values[3],
+		Fiber:        values[4],
+		Density:      density,
+		UnitWeight:   unitWeight,
+		Source:       Source,
+	}, nil
+}
+
+func optionalPositive(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	if parsed <= 0 {
+		return nil, fmt.Errorf("%v is not positive", parsed)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,42 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoodsParsesTheBundledTable(t *testing.T) {
+	foods, err := Foods()
+	require.NoError(t, err)
+	require.Greater(t, len(foods), 100)
+
+	codes := make(map[string]int)
+	for i, food := range foods {
+		require.NotEmpty(t, food.Aliases, food.Code)
+		require.Equal(t, Source, food.Source)
+		codes[food.Code] = i
+	}
+	egg := foods[codes["ovo-galinha-cru"]]
+	require.Equal(t, "Ovo, de galinha, inteiro, cru", egg.Name)
+	require.Equal(t, 143.0, egg.EnergyKcal)
+	require.NotNil(t, egg.UnitWeight)
+	require.Equal(t, 50.0, *egg.UnitWeight)
+}
+
+func TestParseFoodsRejectsInvalidRows(t *testing.T) {
+	header := "code,name,category,energy_kcal,protein_g,carbohydrate_g,fat_g,fiber_g,density,unit_weight,aliases\n"
+
+	_, err := parseFoods(strings.NewReader(header + "ovo,Ovo,Ovos,-1,13,1.6,8.9,0,,50,ovo\n"))
+	require.ErrorContains(t, err, "energy_kcal")
+
+	_, err = parseFoods(strings.NewReader(header + "ovo,Ovo,Ovos,143,13,1.6,8.9,0,,0,ovo\n"))
+	require.ErrorContains(t, err, "unit_weight")
+
+	_, err = parseFoods(strings.NewReader(header + "ovo,Ovo,Ovos,143,13,1.6,8.9,0,,,ovo\novo,Ovo,Ovos,143,13,1.6,8.9,0,,,ovo\n"))
+	require.ErrorContains(t, err, "repeats")
+
+	_, err = parseFoods(strings.NewReader("code,nome\n"))
+	require.Error(t, err)
+}
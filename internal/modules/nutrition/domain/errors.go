@@ -0,0 +1,7 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidRequest = errors.New("invalid nutrition request")
+)
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/dto"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
+)
+
+type FoodRepository interface {
+	Count(ctx context.Context) (int64, error)
+	CreateMany(ctx context.Context, foods []*model.Food) error
+	List(ctx context.Context) ([]*model.Food, error)
+}
+
+type NutritionService interface {
+	// SeedFoods loads the bundled food table when the database has none,
+	// returning how many foods were added.
+	SeedFoods(ctx context.Context) (int, error)
+	SearchFoods(ctx context.Context, query string, limit int) ([]dto.FoodDTO, error)
+	// EstimateNutrition matches the ingredients to foods and adds up their
+	// nutrients, per recipe and per serving.
+	EstimateNutrition(ctx context.Context, input *dto.NutritionEstimateRequestDTO) (*dto.NutritionEstimateDTO, error)
+}
+
+type NutritionHandler interface {
+	SearchFoods(c *gin.Context)
+	EstimateNutrition(c *gin.Context)
+}
@@ -0,0 +1,65 @@
+package dto
+
+// FoodDTO is a food of the composition table, with nutrients per 100 g
+type FoodDTO struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Category     string   `json:"category"`
+	Aliases      []string `json:"aliases"`
+	EnergyKcal   float64  `json:"energy_kcal"`
+	Protein      float64  `json:"protein"`
+	Carbohydrate float64  `json:"carbohydrate"`
+	Fat          float64  `json:"fat"`
+	Fiber        float64  `json:"fiber"`
+	Density      *float64 `json:"density,omitempty"`
+	UnitWeight   *float64 `json:"unit_weight,omitempty"`
+	Source       string   `json:"source"`
+}
+
+// EstimateIngredientDTO is an ingredient line of a recipe
+type EstimateIngredientDTO struct {
+	Name   string   `json:"name" validate:"required"`
+	Amount *float64 `json:"amount"`
+	Unit   string   `json:"unit"`
+}
+
+// NutritionEstimateRequestDTO asks for the nutrition of a list of ingredients
+type NutritionEstimateRequestDTO struct {
+	Ingredients []EstimateIngredientDTO `json:"ingredients" validate:"required,min=1"`
+	Servings    int                     `json:"servings"`
+}
+
+// NutritionValuesDTO holds energy in kcal and nutrients in grams
+type NutritionValuesDTO struct {
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+	Fiber         float64 `json:"fiber"`
+}
+
+// IngredientNutritionDTO is the food an ingredient was matched to and what it
+// adds to the recipe. Confidence goes from 0 (not counted) to 1.
+type IngredientNutritionDTO struct {
+	Name       string             `json:"name"`
+	Amount     *float64           `json:"amount"`
+	Unit       string             `json:"unit"`
+	Status     string             `json:"status"`
+	FoodCode   string             `json:"food_code,omitempty"`
+	FoodName   string             `json:"food_name,omitempty"`
+	Grams      float64            `json:"grams"`
+	Confidence float64            `json:"confidence"`
+	Nutrition  NutritionValuesDTO `json:"nutrition"`
+}
+
+// NutritionEstimateDTO is the nutrition of a recipe computed from its
+// ingredients
+type NutritionEstimateDTO struct {
+	Servings    int                      `json:"servings"`
+	Total       NutritionValuesDTO       `json:"total"`
+	PerServing  NutritionValuesDTO       `json:"per_serving"`
+	Confidence  float64                  `json:"confidence"`
+	Matched     int                      `json:"matched"`
+	Source      string                   `json:"source"`
+	Ingredients []IngredientNutritionDTO `json:"ingredients"`
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type NutritionHandler struct {
+	service domain.NutritionService
+}
+
+func NewNutritionHandler(service domain.NutritionService) *NutritionHandler {
+	return &NutritionHandler{service: service}
+}
+
+// SearchFoods godoc
+// @Summary Search the food composition table
+// @Description List the foods whose names fit the query, with nutrients per 100 g
+// @Tags nutrition
+// @Produce json
+// @Param q query string false "Food name"
+// @Param limit query int false "Maximum number of foods (default 20, max 100)"
+// @Success 200 {object} response.APIResponse{data=[]dto.FoodDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/nutrition/foods [get]
+// @Security BearerAuth
+func (h *NutritionHandler) SearchFoods(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			response.BadRequest(c, "limit inválido")
+			return
+		}
+		limit = parsed
+	}
+
+	foods, err := h.service.SearchFoods(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		h.handleError(c, err, "SearchFoods")
+		return
+	}
+
+	response.OK(c, foods)
+}
+
+// EstimateNutrition godoc
+// @Summary Estimate the nutrition of ingredients
+// @Description Match each ingredient to a food of the table, convert its amount into grams and add up calories, protein, carbohydrates, fat and fiber, in total and per serving, with a confidence for each match
+// @Tags nutrition
+// @Accept json
+// @Produce json
+// @Param estimate body dto.NutritionEstimateRequestDTO true "Ingredients and servings"
+// @Success 200 {object} response.APIResponse{data=dto.NutritionEstimateDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/nutrition/estimate [post]
+// @Security BearerAuth
+func (h *NutritionHandler) EstimateNutrition(c *gin.Context) {
+	var input dto.NutritionEstimateRequestDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	estimate, err := h.service.EstimateNutrition(c.Request.Context(), &input)
+	if err != nil {
+		h.handleError(c, err, "EstimateNutrition")
+		return
+	}
+
+	response.OK(c, estimate)
+}
+
+func (h *NutritionHandler) handleError(c *gin.Context, err error, function string) {
+	if errors.Is(err, domain.ErrInvalidRequest) {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	appLogger.FromContext(c.Request.Context()).Error("Nutrition request failed",
+		zap.String(appLogger.FieldModule, "nutrition"),
+		zap.String(appLogger.FieldFunction, function),
+		zap.Error(err),
+	)
+	response.InternalError(c, "Erro ao calcular informações nutricionais")
+}
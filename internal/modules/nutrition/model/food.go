@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Food is an entry of the food composition table. Nutrients are given per
+// 100 g of the edible part.
+type Food struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	Code         string          `gorm:"type:varchar(100);not null;uniqueIndex" json:"code"`
+	Name         string          `gorm:"type:varchar(255);not null" json:"name"`
+	Category     string          `gorm:"type:varchar(100)" json:"category"`
+	Aliases      FoodAliasesJSON `gorm:"type:jsonb" json:"aliases"`
+	EnergyKcal   float64         `gorm:"not null" json:"energy_kcal"`
+	Protein      float64         `gorm:"not null" json:"protein"`
+	Carbohydrate float64         `gorm:"not null" json:"carbohydrate"`
+	Fat          float64         `gorm:"not null" json:"fat"`
+	Fiber        float64         `gorm:"not null" json:"fiber"`
+	Density      *float64        `json:"density"`
+	UnitWeight   *float64        `json:"unit_weight"`
+	Source       string          `gorm:"type:varchar(50)" json:"source"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// FoodAliasesJSON holds the names a food is known by in recipes.
+type FoodAliasesJSON []string
+
+func (f *Food) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"f": f, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*Food.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*Food.BeforeCreate"), zap.Any("params", __logParams))
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
+
+// Scan implements the sql.Scanner interface for FoodAliasesJSON
+func (a *FoodAliasesJSON) Scan(value interface{}) error {
+	if value == nil {
+		*a = []string{}
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return nil
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// Value implements the driver.Valuer interface for FoodAliasesJSON
+func (a FoodAliasesJSON) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(a)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type foodRepository struct {
+	db *gorm.DB
+}
+
+func NewFoodRepository(db *gorm.DB) (result0 domain.FoodRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewFoodRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewFoodRepository"), zap.Any("params", __logParams))
+	result0 = &foodRepository{db: db}
+	return
+}
+
+func (r *foodRepository) Count(ctx context.Context) (result0 int64, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*foodRepository.Count"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*foodRepository.Count"), zap.Any("params", __logParams))
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Food{}).Count(&count).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*foodRepository.Count"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = 0
+		result1 = err
+		return
+	}
+	result0 = count
+	result1 = nil
+	return
+}
+
+func (r *foodRepository) CreateMany(ctx context.Context, foods []*model.Food) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "foods": foods}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*foodRepository.CreateMany"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*foodRepository.CreateMany"), zap.Any("params", __logParams))
+	if len(foods) == 0 {
+		result0 = nil
+		return
+	}
+	result0 = r.db.WithContext(ctx).CreateInBatches(foods, 100).Error
+	return
+}
+
+func (r *foodRepository) List(ctx context.Context) (result0 []*model.Food, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*foodRepository.List"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*foodRepository.List"), zap.Any("params", __logParams))
+	var foods []*model.Food
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&foods).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*foodRepository.List"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = foods
+	result1 = nil
+	return
+}
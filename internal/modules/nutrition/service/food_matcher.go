@@ -0,0 +1,188 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
+)
+
+// foodEntry is a food with its aliases split into normalized words.
+type foodEntry struct {
+	food    *model.Food
+	aliases [][]string
+}
+
+// foodMatch is the food an ingredient name was matched to. Score goes from 0
+// to 1: 1 when the name is an alias of the food, less when the name has words
+// the alias does not, or when the alias is more specific than the name.
+type foodMatch struct {
+	food  *model.Food
+	score float64
+}
+
+var foodAccentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// foodStopWords are connectives and preparation words that do not tell foods
+// apart.
+var foodStopWords = map[string]bool{
+	"a": true, "o": true, "e": true, "de": true, "da": true, "do": true,
+	"em": true, "com": true, "para": true, "ao": true, "bem": true,
+	"picado": true, "picada": true, "picadinho": true, "picadinha": true,
+	"ralado": true, "ralada": true, "fatiado": true, "fatiada": true,
+	"cortado": true, "cortada": true, "cubo": true, "rodela": true, "tira": true,
+	"fresco": true, "fresca": true, "grande": true, "medio": true, "media": true,
+	"pequeno": true, "pequena": true, "maduro": true, "madura": true,
+	"gosto": true, "opcional": true, "aproximadamente": true,
+}
+
+func newFoodEntries(foods []*model.Food) []*foodEntry {
+	entries := make([]*foodEntry, 0, len(foods))
+	for _, food := range foods {
+		entry := &foodEntry{food: food}
+		for _, alias := range food.Aliases {
+			if words := foodWords(alias); len(words) > 0 {
+				entry.aliases = append(entry.aliases, words)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// foodWords lowercases a name, drops accents, punctuation, notes in
+// parentheses and stop words, and puts the remaining words in the singular.
+func foodWords(name string) []string {
+	value := strings.ToLower(name)
+	if open := strings.Index(value, "("); open >= 0 {
+		if end := strings.Index(value[open:], ")"); end >= 0 {
+			value = value[:open] + " " + value[open+end+1:]
+		}
+	}
+	value = foodAccentReplacer.Replace(value)
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, value)
+
+	var words []string
+	for _, word := range strings.Fields(value) {
+		word = singularFoodWord(word)
+		if !foodStopWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func singularFoodWord(word string) string {
+	switch {
+	case len(word) > 4 && (strings.HasSuffix(word, "oes") || strings.HasSuffix(word, "aes")):
+		return word[:len(word)-3] + "ao"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// matchFood finds the food that best fits an ingredient name.
+func matchFood(name string, entries []*foodEntry) (foodMatch, bool) {
+	words := foodWords(name)
+	if len(words) == 0 {
+		return foodMatch{}, false
+	}
+
+	var best foodMatch
+	bestLength := 0
+	for _, entry := range entries {
+		for _, alias := range entry.aliases {
+			score := aliasScore(words, alias)
+			if score > best.score || (score == best.score && score > 0 && len(alias) > bestLength) {
+				best, bestLength = foodMatch{food: entry.food, score: score}, len(alias)
+			}
+		}
+	}
+	return best, best.score > 0
+}
+
+// aliasScore compares the words of a name with the words of an alias.
+func aliasScore(words []string, alias []string) float64 {
+	nameHasAlias := containsWords(words, alias)
+	aliasHasName := containsWords(alias, words)
+	switch {
+	case nameHasAlias && aliasHasName:
+		return 1
+	case nameHasAlias:
+		// "peito de frango desfiado" is still "peito de frango".
+		return 0.6 + 0.35*float64(len(alias))/float64(len(words))
+	case aliasHasName:
+		// "feijão" could be any of the "feijão carioca" kinds.
+		return 0.5 + 0.3*float64(len(words))/float64(len(alias))
+	}
+	return 0
+}
+
+func containsWords(words []string, wanted []string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, word := range words {
+			if word == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// searchFoods ranks the foods whose names fit a query, the best first.
+func searchFoods(query string, entries []*foodEntry) []*model.Food {
+	words := foodWords(query)
+	type scored struct {
+		food  *model.Food
+		score float64
+	}
+	var results []scored
+	for _, entry := range entries {
+		score := 0.0
+		if len(words) == 0 {
+			score = 1
+		}
+		for _, alias := range entry.aliases {
+			if s := aliasScore(words, alias); s > score {
+				score = s
+			}
+		}
+		if name := foodWords(entry.food.Name); score == 0 && containsWords(name, words) {
+			score = 0.5
+		}
+		if score > 0 {
+			results = append(results, scored{food: entry.food, score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].food.Name < results[j].food.Name
+	})
+
+	foods := make([]*model.Food, 0, len(results))
+	for _, result := range results {
+		foods = append(foods, result.food)
+	}
+	return foods
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/data"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/dto"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultFoodSearchLimit  = 20
+	maxFoodSearchLimit      = 100
+	maxEstimatedIngredients = 100
+
+	IngredientMatched     = "matched"
+	IngredientUnmatched   = "unmatched"
+	IngredientNoAmount    = "no_amount"
+	IngredientUnknownUnit = "unknown_unit"
+)
+
+// Confidence of the conversion of an amount into grams, multiplied by the
+// confidence of the name match.
+const (
+	massConfidence           = 1
+	volumeConfidence         = 0.9
+	assumedDensityConfidence = 0.6
+	countConfidence          = 0.85
+	assumedUnitConfidence    = 0.6
+	householdConfidence      = 0.7
+)
+
+// householdMeasures are the measures with no unit in pkg/units, in grams.
+var householdMeasures = map[string]float64{
+	"pitada": 0.5, "pitadas": 0.5,
+	"fio": 5, "fios": 5,
+	"punhado": 30, "punhados": 30,
+}
+
+type nutritionService struct {
+	foodRepository domain.FoodRepository
+
+	mu      sync.Mutex
+	entries []*foodEntry
+}
+
+func NewNutritionService(foodRepository domain.FoodRepository) domain.NutritionService {
+	return &nutritionService{foodRepository: foodRepository}
+}
+
+func (s *nutritionService) SeedFoods(ctx context.Context) (int, error) {
+	logger := appLogger.FromContext(ctx)
+
+	count, err := s.foodRepository.Count(ctx)
+	if err != nil {
+		logger.Error("Failed to count foods",
+			zap.String(appLogger.FieldModule, "nutrition"),
+			zap.String(appLogger.FieldFunction, "SeedFoods"),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+	if count > 0 {
+		return 0, nil
+	}
+
+	foods, err := data.Foods()
+	if err != nil {
+		return 0, err
+	}
+	if err := s.foodRepository.CreateMany(ctx, foods); err != nil {
+		logger.Error("Failed to seed foods",
+			zap.String(appLogger.FieldModule, "nutrition"),
+			zap.String(appLogger.FieldFunction, "SeedFoods"),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.entries = nil
+	s.mu.Unlock()
+
+	logger.Info("Food table seeded",
+		zap.String(appLogger.FieldModule, "nutrition"),
+		zap.String(appLogger.FieldFunction, "SeedFoods"),
+		zap.Int(appLogger.FieldCount, len(foods)),
+	)
+	return len(foods), nil
+}
+
+func (s *nutritionService) SearchFoods(ctx context.Context, query string, limit int) ([]dto.FoodDTO, error) {
+	switch {
+	case limit < 0:
+		return nil, fmt.Errorf("%w: limit cannot be negative", domain.ErrInvalidRequest)
+	case limit == 0:
+		limit = defaultFoodSearchLimit
+	case limit > maxFoodSearchLimit:
+		limit = maxFoodSearchLimit
+	}
+
+	entries, err := s.foodEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	foods := searchFoods(query, entries)
+	if len(foods) > limit {
+		foods = foods[:limit]
+	}
+	results := make([]dto.FoodDTO, 0, len(foods))
+	for _, food := range foods {
+		results = append(results, toFoodDTO(food))
+	}
+	return results, nil
+}
+
+func (s *nutritionService) EstimateNutrition(ctx context.Context, input *dto.NutritionEstimateRequestDTO) (*dto.NutritionEstimateDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil || len(input.Ingredients) == 0 {
+		return nil, fmt.Errorf("%w: ingredients are required", domain.ErrInvalidRequest)
+	}
+	if len(input.Ingredients) > maxEstimatedIngredients {
+		return nil, fmt.Errorf("%w: at most %d ingredients can be estimated", domain.ErrInvalidRequest, maxEstimatedIngredients)
+	}
+	if input.Servings < 0 {
+		return nil, fmt.Errorf("%w: servings cannot be negative", domain.ErrInvalidRequest)
+	}
+
+	entries, err := s.foodEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := estimateNutrition(input.Ingredients, input.Servings, entries)
+
+	logger.Info("Nutrition estimated",
+		zap.String(appLogger.FieldModule, "nutrition"),
+		zap.String(appLogger.FieldFunction, "EstimateNutrition"),
+		zap.Int(appLogger.FieldCount, len(input.Ingredients)),
+		zap.Int("matched", estimate.Matched),
+		zap.Float64("confidence", estimate.Confidence),
+	)
+	return estimate, nil
+}
+
+// foodEntries loads the food table once. An empty table is not kept, so the
+// foods seeded afterwards are seen.
+func (s *nutritionService) foodEntries(ctx context.Context) ([]*foodEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries != nil {
+		return s.entries, nil
+	}
+	foods, err := s.foodRepository.List(ctx)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list foods",
+			zap.String(appLogger.FieldModule, "nutrition"),
+			zap.String(appLogger.FieldFunction, "foodEntries"),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	entries := newFoodEntries(foods)
+	if len(entries) > 0 {
+		s.entries = entries
+	}
+	return entries, nil
+}
+
+// estimateNutrition adds up the nutrients of the ingredients. Ingredients
+// without amount ("a gosto") are listed but do not count towards the
+// confidence, which is the mean confidence of the other ingredients.
+func estimateNutrition(ingredients []dto.EstimateIngredientDTO, servings int, entries []*foodEntry) *dto.NutritionEstimateDTO {
+	if servings <= 0 {
+		servings = 1
+	}
+
+	estimate := &dto.NutritionEstimateDTO{
+		Servings:    servings,
+		Source:      data.Source,
+		Ingredients: make([]dto.IngredientNutritionDTO, 0, len(ingredients)),
+	}
+	var total dto.NutritionValuesDTO
+	counted, confidence := 0, 0.0
+	for _, ingredient := range ingredients {
+		line := dto.IngredientNutritionDTO{
+			Name:   strings.TrimSpace(ingredient.Name),
+			Amount: ingredient.Amount,
+			Unit:   strings.TrimSpace(ingredient.Unit),
+		}
+		if ingredient.Amount == nil || *ingredient.Amount <= 0 {
+			line.Status = IngredientNoAmount
+			if match, ok := matchFood(line.Name, entries); ok {
+				line.FoodCode, line.FoodName = match.food.Code, match.food.Name
+			}
+			estimate.Ingredients = append(estimate.Ingredients, line)
+			continue
+		}
+		counted++
+
+		match, ok := matchFood(line.Name, entries)
+		if !ok {
+			line.Status = IngredientUnmatched
+			estimate.Ingredients = append(estimate.Ingredients, line)
+			continue
+		}
+		line.FoodCode, line.FoodName = match.food.Code, match.food.Name
+
+		grams, unitConfidence, ok := ingredientGrams(*ingredient.Amount, line.Unit, match.food)
+		if !ok {
+			line.Status = IngredientUnknownUnit
+			estimate.Ingredients = append(estimate.Ingredients, line)
+			continue
+		}
+
+		line.Status = IngredientMatched
+		line.Grams = roundTo(grams, 1)
+		line.Confidence = roundTo(match.score*unitConfidence, 2)
+		values := foodNutrition(match.food, grams)
+		line.Nutrition = roundValues(values)
+		total = addValues(total, values)
+		confidence += match.score * unitConfidence
+		estimate.Matched++
+		estimate.Ingredients = append(estimate.Ingredients, line)
+	}
+
+	estimate.Total = roundValues(total)
+	estimate.PerServing = roundValues(scaleValues(total, 1/float64(servings)))
+	if counted > 0 {
+		estimate.Confidence = roundTo(confidence/float64(counted), 2)
+	}
+	return estimate
+}
+
+// ingredientGrams converts an amount into grams, with the confidence of the
+// conversion. Volumes use the density of the food and counts its unit
+// weight; a missing or unknown unit ("2 dentes") counts units of the food.
+func ingredientGrams(amount float64, unit string, food *model.Food) (float64, float64, bool) {
+	unit = strings.TrimSpace(unit)
+	if open := strings.Index(unit, "("); open >= 0 {
+		unit = strings.TrimSpace(unit[:open])
+	}
+	if grams, ok := householdMeasures[strings.ToLower(unit)]; ok {
+		return amount * grams, householdConfidence, true
+	}
+
+	measure, known := units.Lookup(unit)
+	if known {
+		switch measure.Dimension {
+		case units.DimensionMass:
+			return amount * measure.Factor, massConfidence, true
+		case units.DimensionVolume:
+			if food.Density != nil {
+				return amount * measure.Factor * *food.Density, volumeConfidence, true
+			}
+			return amount * measure.Factor, assumedDensityConfidence, true
+		case units.DimensionCount:
+			if food.UnitWeight != nil {
+				return amount * measure.Factor * *food.UnitWeight, countConfidence, true
+			}
+			return 0, 0, false
+		}
+	}
+
+	if food.UnitWeight == nil {
+		return 0, 0, false
+	}
+	if unit == "" {
+		return amount * *food.UnitWeight, countConfidence, true
+	}
+	return amount * *food.UnitWeight, assumedUnitConfidence, true
+}
+
+func foodNutrition(food *model.Food, grams float64) dto.NutritionValuesDTO {
+	return scaleValues(dto.NutritionValuesDTO{
+		Calories:      food.EnergyKcal,
+		Protein:       food.Protein,
+		Carbohydrates: food.Carbohydrate,
+		Fat:           food.Fat,
+		Fiber:         food.Fiber,
+	}, grams/100)
+}
+
+func addValues(a, b dto.NutritionValuesDTO) dto.NutritionValuesDTO {
+	return dto.NutritionValuesDTO{
+		Calories:      a.Calories + b.Calories,
+		Protein:       a.Protein + b.Protein,
+		Carbohydrates: a.Carbohydrates + b.Carbohydrates,
+		Fat:           a.Fat + b.Fat,
+		Fiber:         a.Fiber + b.Fiber,
+	}
+}
+
+func scaleValues(values dto.NutritionValuesDTO, factor float64) dto.NutritionValuesDTO {
+	return dto.NutritionValuesDTO{
+		Calories:      values.Calories * factor,
+		Protein:       values.Protein * factor,
+		Carbohydrates: values.Carbohydrates * factor,
+		Fat:           values.Fat * factor,
+		Fiber:         values.Fiber * factor,
+	}
+}
+
+func roundValues(values dto.NutritionValuesDTO) dto.NutritionValuesDTO {
+	return dto.NutritionValuesDTO{
+		Calories:      roundTo(values.Calories, 0),
+		Protein:       roundTo(values.Protein, 1),
+		Carbohydrates: roundTo(values.Carbohydrates, 1),
+		Fat:           roundTo(values.Fat, 1),
+		Fiber:         roundTo(values.Fiber, 1),
+	}
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
+
+func toFoodDTO(food *model.Food) dto.FoodDTO {
+	aliases := []string(food.Aliases)
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return dto.FoodDTO{
+		Code:         food.Code,
+		Name:         food.Name,
+		Category:     food.Category,
+		Aliases:      aliases,
+		EnergyKcal:   food.EnergyKcal,
+		Protein:      food.Protein,
+		Carbohydrate: food.Carbohydrate,
+		Fat:          food.Fat,
+		Fiber:        food.Fiber,
+		Density:      food.Density,
+		UnitWeight:   food.UnitWeight,
+		Source:       food.Source,
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/data"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/dto"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
+)
+
+type memoryFoodRepository struct {
+	foods []*model.Food
+	lists int
+}
+
+func (r *memoryFoodRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.foods)), nil
+}
+
+func (r *memoryFoodRepository) CreateMany(ctx context.Context, foods []*model.Food) error {
+	r.foods = append(r.foods, foods...)
+	return nil
+}
+
+func (r *memoryFoodRepository) List(ctx context.Context) ([]*model.Food, error) {
+	r.lists++
+	return r.foods, nil
+}
+
+func amount(value float64) *float64 {
+	return &value
+}
+
+func newSeededService(t *testing.T) (*nutritionService, *memoryFoodRepository) {
+	t.Helper()
+	repo := &memoryFoodRepository{}
+	svc := NewNutritionService(repo).(*nutritionService)
+	if _, err := svc.SeedFoods(context.Background()); err != nil {
+		t.Fatalf("unexpected seed error: %v", err)
+	}
+	return svc, repo
+}
+
+func TestNutritionService_SeedFoodsOnlyOnce(t *testing.T) {
+	repo := &memoryFoodRepository{}
+	svc := NewNutritionService(repo)
+
+	if _, err := svc.SearchFoods(context.Background(), "ovo", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seeded, err := svc.SeedFoods(context.Background())
+	if err != nil || seeded == 0 || len(repo.foods) != seeded {
+		t.Fatalf("expected the table to be seeded, got %d, %v", seeded, err)
+	}
+	if seeded, err := svc.SeedFoods(context.Background()); err != nil || seeded != 0 {
+		t.Fatalf("expected a second seed to add nothing, got %d, %v", seeded, err)
+	}
+
+	foods, err := svc.SearchFoods(context.Background(), "ovos", 3)
+	if err != nil || len(foods) == 0 || foods[0].Code != "ovo-galinha-cru" {
+		t.Fatalf("expected the foods seeded after the first search to be found, got %+v, %v", foods, err)
+	}
+}
+
+func TestNutritionService_EstimateNutrition(t *testing.T) {
+	svc, repo := newSeededService(t)
+
+	estimate, err := svc.EstimateNutrition(context.Background(), &dto.NutritionEstimateRequestDTO{
+		Servings: 4,
+		Ingredients: []dto.EstimateIngredientDTO{
+			{Name: "Farinha de trigo", Amount: amount(2), Unit: "xícaras (chá)"},
+			{Name: "Ovos", Amount: amount(3)},
+			{Name: "Açúcar", Amount: amount(200), Unit: "g"},
+			{Name: "Sal", Unit: "a gosto"},
+			{Name: "Essência de baunilha", Amount: amount(1), Unit: "colher de chá"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flour, eggs, sugar, salt, vanilla := estimate.Ingredients[0], estimate.Ingredients[1], estimate.Ingredients[2], estimate.Ingredients[3], estimate.Ingredients[4]
+	if flour.FoodCode != "farinha-trigo" || flour.Grams != 254.4 || flour.Confidence != 0.9 {
+		t.Fatalf("expected two cups of flour by density, got %+v", flour)
+	}
+	if eggs.Grams != 150 || eggs.Nutrition.Calories != 215 || eggs.Confidence != 0.85 {
+		t.Fatalf("expected three eggs of 50 g, got %+v", eggs)
+	}
+	if sugar.Grams != 200 || sugar.Confidence != 1 || sugar.Nutrition.Carbohydrates != 199 {
+		t.Fatalf("expected 200 g of sugar, got %+v", sugar)
+	}
+	if salt.Status != IngredientNoAmount || salt.FoodCode != "sal" {
+		t.Fatalf("expected salt without amount, got %+v", salt)
+	}
+	if vanilla.Status != IngredientUnmatched || vanilla.Confidence != 0 {
+		t.Fatalf("expected vanilla to be unmatched, got %+v", vanilla)
+	}
+
+	if estimate.Matched != 3 || estimate.Confidence != 0.69 {
+		t.Fatalf("expected three matches out of four counted ingredients, got %d at %v", estimate.Matched, estimate.Confidence)
+	}
+	if estimate.Total.Calories != 1904 || estimate.PerServing.Calories != 476 || estimate.PerServing.Fiber != 1.5 {
+		t.Fatalf("unexpected totals %+v per serving %+v", estimate.Total, estimate.PerServing)
+	}
+
+	if _, err := svc.EstimateNutrition(context.Background(), &dto.NutritionEstimateRequestDTO{Ingredients: []dto.EstimateIngredientDTO{{Name: "Ovo", Amount: amount(1)}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lists != 1 {
+		t.Fatalf("expected the food table to be listed once, got %d", repo.lists)
+	}
+}
+
+func TestNutritionService_EstimateNutritionValidation(t *testing.T) {
+	svc, _ := newSeededService(t)
+
+	for name, input := range map[string]*dto.NutritionEstimateRequestDTO{
+		"nil":      nil,
+		"empty":    {},
+		"servings": {Servings: -1, Ingredients: []dto.EstimateIngredientDTO{{Name: "Ovo", Amount: amount(1)}}},
+	} {
+		if _, err := svc.EstimateNutrition(context.Background(), input); !errors.Is(err, domain.ErrInvalidRequest) {
+			t.Fatalf("%s: expected invalid request, got %v", name, err)
+		}
+	}
+}
+
+func TestMatchFood(t *testing.T) {
+	foods, err := data.Foods()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := newFoodEntries(foods)
+
+	for name, expected := range map[string]struct {
+		code  string
+		score float64
+	}{
+		"Cebolas picadas":          {"cebola-crua", 1},
+		"Feijões":                  {"feijao-carioca-cru", 1},
+		"feijão preto":             {"feijao-preto-cru", 1},
+		"Peito de frango desfiado": {"frango-peito-cru", 0.83},
+		"2 dentes de alho":         {"alho-cru", 0.83},
+		"Tomate (sem pele)":        {"tomate-cru", 1},
+		"Carne moída":              {"carne-moida-acem", 1},
+	} {
+		match, ok := matchFood(name, entries)
+		if !ok || match.food.Code != expected.code || roundTo(match.score, 2) != expected.score {
+			t.Fatalf("%s: expected %s at %v, got %+v", name, expected.code, expected.score, match)
+		}
+	}
+
+	if _, ok := matchFood("Essência de baunilha", entries); ok {
+		t.Fatalf("expected vanilla not to match")
+	}
+}
+
+func TestIngredientGrams(t *testing.T) {
+	condensed := &model.Food{Density: amount(1.3)}
+	egg := &model.Food{UnitWeight: amount(50)}
+	meat := &model.Food{}
+
+	cases := []struct {
+		name       string
+		amount     float64
+		unit       string
+		food       *model.Food
+		grams      float64
+		confidence float64
+		ok         bool
+	}{
+		{"kg", 0.5, "kg", meat, 500, massConfidence, true},
+		{"density", 2, "colheres de sopa", condensed, 39, volumeConfidence, true},
+		{"no density", 1, "xícara", meat, 240, assumedDensityConfidence, true},
+		{"dozen", 1, "dz", egg, 600, countConfidence, true},
+		{"unit word", 2, "unidades grandes", egg, 100, assumedUnitConfidence, true},
+		{"pinch", 2, "pitadas", meat, 1, householdConfidence, true},
+		{"can", 1, "lata", meat, 0, 0, false},
+		{"count without weight", 1, "un", meat, 0, 0, false},
+	}
+	for _, c := range cases {
+		grams, confidence, ok := ingredientGrams(c.amount, c.unit, c.food)
+		if ok != c.ok || roundTo(grams, 1) != c.grams || confidence != c.confidence {
+			t.Fatalf("%s: expected %v g at %v (%v), got %v g at %v (%v)", c.name, c.grams, c.confidence, c.ok, grams, confidence, ok)
+		}
+	}
+}
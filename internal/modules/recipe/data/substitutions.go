@@ -0,0 +1,164 @@
+// Package data bundles the ingredient substitution table, so substitutes can
+// be suggested without any external service.
+package data
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nclsgg/despensa-digital/backend/pkg/dietary"
+)
+
+// Diet tags a substitute can meet, from the dietary taxonomy.
+const (
+	DietVegan       = dietary.Vegan
+	DietVegetarian  = dietary.Vegetarian
+	DietLactoseFree = dietary.LactoseFree
+	DietGlutenFree  = dietary.GlutenFree
+	DietEggFree     = dietary.EggFree
+	DietNutFree     = dietary.NutFree
+)
+
+// dietImplies lists the diets a diet tag also meets.
+var dietImplies = map[string][]string{
+	DietVegan:       {DietVegetarian, DietLactoseFree, DietEggFree},
+	DietVegetarian:  nil,
+	DietLactoseFree: nil,
+	DietGlutenFree:  nil,
+	DietEggFree:     nil,
+	DietNutFree:     nil,
+}
+
+//go:embed substitutions.csv
+var substitutionsCSV string
+
+var substitutionColumns = []string{"ingredient", "substitute", "ratio", "unit", "diets", "notes"}
+
+// Substitution is a swap of an ingredient by another one.
+type Substitution struct {
+	Ingredient string
+	Substitute string
+	// Ratio is the amount of the substitute for each unit of the ingredient.
+	Ratio float64
+	// Unit is the unit of the substitute amount, or empty for the unit of
+	// the ingredient.
+	Unit string
+	// Diets are the diets the substitute meets, implied ones included.
+	Diets []string
+	Notes string
+}
+
+// Meets tells whether the substitute meets every one of the diets.
+func (s Substitution) Meets(diets []string) bool {
+	for _, diet := range diets {
+		met := false
+		for _, tag := range s.Diets {
+			if tag == diet {
+				met = true
+				break
+			}
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
+// Substitutions parses the bundled table.
+func Substitutions() ([]Substitution, error) {
+	return parseSubstitutions(strings.NewReader(substitutionsCSV))
+}
+
+func parseSubstitutions(r io.Reader) ([]Substitution, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = len(substitutionColumns)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read substitution table header: %w", err)
+	}
+	for i, column := range substitutionColumns {
+		if strings.TrimSpace(header[i]) != column {
+			return nil, fmt.Errorf("substitution table column %d is %q, expected %q", i+1, header[i], column)
+		}
+	}
+
+	var substitutions []Substitution
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read substitution table: %w", err)
+		}
+
+		substitution, err := parseSubstitution(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("substitution table line %d: %w", line, err)
+		}
+		key := strings.ToLower(substitution.Ingredient + "|" + substitution.Substitute)
+		if seen[key] {
+			return nil, fmt.Errorf("substitution table repeats %q by %q", substitution.Ingredient, substitution.Substitute)
+		}
+		seen[key] = true
+		substitutions = append(substitutions, substitution)
+	}
+	return substitutions, nil
+}
+
+func parseSubstitution(record []string) (Substitution, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+	if record[0] == "" || record[1] == "" {
+		return Substitution{}, fmt.Errorf("ingredient and substitute are required")
+	}
+	if strings.EqualFold(record[0], record[1]) {
+		return Substitution{}, fmt.Errorf("%q is its own substitute", record[0])
+	}
+
+	ratio, err := strconv.ParseFloat(record[2], 64)
+	if err != nil || ratio <= 0 {
+		return Substitution{}, fmt.Errorf("invalid ratio %q", record[2])
+	}
+
+	var diets []string
+	added := make(map[string]bool)
+	add := func(diet string) {
+		if !added[diet] {
+			added[diet] = true
+			diets = append(diets, diet)
+		}
+	}
+	for _, diet := range strings.Split(record[4], "|") {
+		if diet = strings.TrimSpace(diet); diet == "" {
+			continue
+		}
+		implied, ok := dietImplies[diet]
+		if !ok {
+			return Substitution{}, fmt.Errorf("unknown diet %q", diet)
+		}
+		add(diet)
+		for _, other := range implied {
+			add(other)
+		}
+	}
+
+	return Substitution{
+		Ingredient: record[0],
+		Substitute: record[1],
+		Ratio:      ratio,
+		Unit:       record[3],
+		Diets:      diets,
+		Notes:      record[5],
+	}, nil
+}
@@ -0,0 +1,43 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstitutionsParsesTheBundledTable(t *testing.T) {
+	substitutions, err := Substitutions()
+	require.NoError(t, err)
+	require.Greater(t, len(substitutions), 50)
+
+	var flaxseed *Substitution
+	for i, substitution := range substitutions {
+		require.NotEmpty(t, substitution.Notes, substitution.Ingredient)
+		if substitution.Ingredient == "ovo" && substitution.Substitute == "linhaça" {
+			flaxseed = &substitutions[i]
+		}
+	}
+	require.NotNil(t, flaxseed)
+	require.Equal(t, 1.0, flaxseed.Ratio)
+	require.Equal(t, "colher de sopa", flaxseed.Unit)
+	require.True(t, flaxseed.Meets([]string{DietVegetarian, DietEggFree, DietGlutenFree}))
+	require.True(t, flaxseed.Meets(nil))
+}
+
+func TestParseSubstitutionsRejectsInvalidRows(t *testing.T) {
+	header := "ingredient,substitute,ratio,unit,diets,notes\n"
+
+	_, err := parseSubstitutions(strings.NewReader(header + "ovo,banana,0,unidade,vegan,Amassada\n"))
+	require.ErrorContains(t, err, "ratio")
+
+	_, err = parseSubstitutions(strings.NewReader(header + "leite,leite de coco,1,,paleo,Na mesma quantidade\n"))
+	require.ErrorContains(t, err, "paleo")
+
+	_, err = parseSubstitutions(strings.NewReader(header + "mel,melado,1,,vegan,\nmel,Melado,1,,vegan,\n"))
+	require.ErrorContains(t, err, "repeats")
+
+	_, err = parseSubstitutions(strings.NewReader("ingredient,substitute\n"))
+	require.Error(t, err)
+}
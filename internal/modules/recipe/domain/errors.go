@@ -11,4 +11,14 @@ var (
 	ErrInvalidLLMResponse = errors.New("recipe: invalid llm response")
 	ErrRecipeNotFound     = errors.New("recipe: recipe not found")
 	ErrInvalidRecipeData  = errors.New("recipe: invalid recipe data")
+	ErrStockChanged       = errors.New("recipe: pantry stock changed")
+	ErrInsufficientStock  = errors.New("recipe: pantry does not cover the recipe")
+	ErrMealPlanNotFound   = errors.New("recipe: meal plan entry not found")
+	ErrImportFailed       = errors.New("recipe: could not fetch recipe page")
+	ErrUnsupportedFormat  = errors.New("recipe: unsupported export format")
+	ErrShareNotFound      = errors.New("recipe: share not found")
+	ErrShareExpired       = errors.New("recipe: share expired")
+	ErrDuplicateRecipe    = errors.New("recipe: similar recipe already saved")
+	ErrVersionNotFound    = errors.New("recipe: version not found")
+	ErrVersionConflict    = errors.New("recipe: recipe changed by another request")
 )
@@ -0,0 +1,12 @@
+package domain
+
+// Meal plan periods, shared by the planner and the shopping lists made from
+// a plan so that every period that can be listed can also be bought.
+const (
+	// MealPlanDateLayout is the layout of the dates of a meal plan.
+	MealPlanDateLayout = "2006-01-02"
+	// DefaultMealPlanDays is the length of a period without an end.
+	DefaultMealPlanDays = 7
+	// MaxMealPlanRangeDays is the longest period that can be listed.
+	MaxMealPlanRangeDays = 62
+)
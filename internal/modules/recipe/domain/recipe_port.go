@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
@@ -12,18 +13,145 @@ import (
 type RecipeService interface {
 	GenerateRecipe(ctx context.Context, request *llmDTO.RecipeRequestDTO, userID uuid.UUID) (*llmDTO.RecipeResponseDTO, error)
 	GenerateMultipleRecipes(ctx context.Context, request *llmDTO.RecipeRequestDTO, userID uuid.UUID, count int) ([]*llmDTO.RecipeResponseDTO, error)
-	SaveRecipe(ctx context.Context, recipe *recipeDTO.SaveRecipeDTO, userID uuid.UUID) error
-	SaveMultipleRecipes(ctx context.Context, recipes []*recipeDTO.SaveRecipeDTO, userID uuid.UUID) error
+	// SaveRecipe saves a recipe, warning about or refusing near-duplicates of
+	// the saved recipes of the user, or replacing one of them.
+	SaveRecipe(ctx context.Context, recipe *recipeDTO.SaveRecipeDTO, userID uuid.UUID) (*recipeDTO.SavedRecipeDTO, error)
+	// SaveMultipleRecipes saves recipes atomically as SaveRecipe does; when one
+	// of them is refused nothing is saved.
+	SaveMultipleRecipes(ctx context.Context, recipes []*recipeDTO.SaveRecipeDTO, userID uuid.UUID) ([]recipeDTO.SavedRecipeDTO, error)
+	// FindRecipeDuplicates lists the saved recipes of a user similar to a
+	// recipe, the most similar first.
+	FindRecipeDuplicates(ctx context.Context, userID uuid.UUID, recipe *recipeDTO.SaveRecipeDTO) ([]recipeDTO.RecipeDuplicateDTO, error)
 	GetRecipeByID(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) (*recipeDTO.RecipeDetailDTO, error)
 	GetUserRecipes(ctx context.Context, userID uuid.UUID) ([]*recipeDTO.RecipeDetailDTO, error)
 	GetAvailableIngredients(ctx context.Context, pantryID uuid.UUID, userID uuid.UUID) ([]recipeDTO.AvailableIngredientDTO, error)
-	SearchRecipesByIngredients(ctx context.Context, ingredients []string, filters map[string]string) ([]llmDTO.RecipeResponseDTO, error)
+	// SearchRecipesByIngredients ranks the saved recipes of a user by how many
+	// of their ingredients are at hand, listing the missing ones.
+	SearchRecipesByIngredients(ctx context.Context, userID uuid.UUID, input *recipeDTO.IngredientSearchDTO) ([]recipeDTO.IngredientSearchResultDTO, error)
+	// SearchRecipes lists a page of the saved recipes of a user matching the
+	// filter, most recent first unless filter.SortBy says otherwise.
+	SearchRecipes(ctx context.Context, userID uuid.UUID, filter recipeDTO.RecipeFilterDTO) (*recipeDTO.RecipeListDTO, error)
+	UpdateRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.UpdateRecipeDTO) (*recipeDTO.RecipeDetailDTO, error)
+	DeleteRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) error
+	SetRecipeTags(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, tags []string) (*recipeDTO.RecipeDetailDTO, error)
+	SetRecipeFavorite(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, favorite bool) (*recipeDTO.RecipeDetailDTO, error)
+	// SetRecipeRating rates a saved recipe from 1 to 5; nil clears the rating.
+	SetRecipeRating(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, rating *int) (*recipeDTO.RecipeDetailDTO, error)
+	SetRecipeNotes(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.SetRecipeNotesDTO) (*recipeDTO.RecipeDetailDTO, error)
+	ListRecipeTags(ctx context.Context, userID uuid.UUID) ([]recipeDTO.RecipeTagDTO, error)
+	// PreviewCooking shows what cooking a recipe would take from a pantry
+	// without changing it.
+	PreviewCooking(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.CookRecipeDTO) (*recipeDTO.CookingPreviewDTO, error)
+	// CookRecipe takes the ingredients of a recipe from a pantry and records
+	// the cooking in the history of the recipe.
+	CookRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.CookRecipeDTO) (*recipeDTO.RecipeCookingDTO, error)
+	// LogRecipeCooking records that a recipe was cooked without taking
+	// anything from a pantry.
+	LogRecipeCooking(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.LogRecipeCookingDTO) (*recipeDTO.RecipeCookingDTO, error)
+	ListRecipeCookings(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) ([]recipeDTO.RecipeCookingDTO, error)
+	// ScaleRecipe returns a saved recipe scaled to the servings, with the
+	// amounts rounded and normalized and the hands-on step times adjusted.
+	ScaleRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, servings int) (*recipeDTO.ScaledRecipeDTO, error)
+	// ImportRecipe reads the schema.org recipe of a web page, given as HTML or
+	// fetched from its URL, and saves it unless a preview is asked.
+	ImportRecipe(ctx context.Context, userID uuid.UUID, input *recipeDTO.ImportRecipeDTO) (*recipeDTO.ImportedRecipeDTO, error)
+	// ExportRecipe renders a saved recipe as markdown, json-ld or pdf.
+	ExportRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, format string) (*recipeDTO.RecipeExportDTO, error)
+	// ExportRecipes renders every saved recipe of a user in the format and
+	// packs them in a zip.
+	ExportRecipes(ctx context.Context, userID uuid.UUID, format string) (*recipeDTO.RecipeExportDTO, error)
+	// CalculateRecipeNutrition computes the nutrition of a saved recipe from
+	// the food composition table and keeps it when any ingredient is found.
+	CalculateRecipeNutrition(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) (*recipeDTO.RecipeNutritionEstimateDTO, error)
+	// SuggestSubstitutions lists the substitutes in a pantry for the
+	// ingredients of a recipe it lacks, respecting the dietary restrictions
+	// of the user and of the recipe.
+	SuggestSubstitutions(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.CookRecipeDTO) (*recipeDTO.RecipeSubstitutionsDTO, error)
+	// ListRecipeVersions lists the versions of the content of a recipe, the
+	// latest first.
+	ListRecipeVersions(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) ([]recipeDTO.RecipeVersionSummaryDTO, error)
+	GetRecipeVersion(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, version int) (*recipeDTO.RecipeVersionDTO, error)
+	// DiffRecipeVersions compares two versions of a recipe: the ingredients
+	// added, removed and changed, the steps and the other fields.
+	DiffRecipeVersions(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, from int, to int) (*recipeDTO.RecipeVersionDiffDTO, error)
+	// RestoreRecipeVersion brings back the content of an old version as a
+	// new version.
+	RestoreRecipeVersion(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, version int) (*recipeDTO.RecipeDetailDTO, error)
 }
 
 type RecipeRepository interface {
 	Create(ctx context.Context, recipe *recipeModel.Recipe) error
 	CreateMany(ctx context.Context, recipes []*recipeModel.Recipe) error
+	// SaveMany creates the new recipes and updates the replaced ones in one
+	// transaction.
+	SaveMany(ctx context.Context, created []*recipeModel.Recipe, replaced []*recipeModel.Recipe) error
 	FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*recipeModel.Recipe, error)
+	// ListVersions lists the saved versions of a recipe, the latest first.
+	// Versions are written through Recipe.Versions when the recipe is saved.
+	ListVersions(ctx context.Context, recipeID uuid.UUID) ([]*recipeModel.RecipeVersion, error)
+	FindVersion(ctx context.Context, recipeID uuid.UUID, version int) (*recipeModel.RecipeVersion, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*recipeModel.Recipe, error)
 	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	Update(ctx context.Context, recipe *recipeModel.Recipe) error
+	// Search returns a page of the recipes of a user matching the filter and
+	// the total number of matches.
+	Search(ctx context.Context, userID uuid.UUID, filter recipeDTO.RecipeFilterDTO) ([]*recipeModel.Recipe, int64, error)
+	// ListTags counts the recipes of a user by tag, most used first.
+	ListTags(ctx context.Context, userID uuid.UUID) ([]recipeDTO.RecipeTagDTO, error)
+}
+
+type RecipeCookingRepository interface {
+	// Apply deducts the quantities of the cooking from the pantry items,
+	// records it and updates the cooking count of the recipe in one
+	// transaction. It fails with ErrStockChanged when an item no longer has
+	// the quantity to deduct.
+	Apply(ctx context.Context, cooking *recipeModel.RecipeCooking) error
+	ListByRecipeID(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) ([]*recipeModel.RecipeCooking, error)
+}
+
+// RecipeShareService manages the public links to recipes and copies shared
+// recipes into the collection of other users.
+type RecipeShareService interface {
+	CreateShare(ctx context.Context, userID uuid.UUID, recipeID uuid.UUID, input *recipeDTO.CreateRecipeShareDTO) (*recipeDTO.RecipeShareDTO, error)
+	ListShares(ctx context.Context, userID uuid.UUID, recipeID uuid.UUID) ([]recipeDTO.RecipeShareDTO, error)
+	RevokeShare(ctx context.Context, userID uuid.UUID, recipeID uuid.UUID, shareID uuid.UUID) error
+	// GetSharedRecipe reads the recipe behind a public token, without owner
+	// identifiers.
+	GetSharedRecipe(ctx context.Context, token string) (*recipeDTO.SharedRecipeDTO, error)
+	// CopySharedRecipe saves a copy of a shared recipe for the user, keeping
+	// the original recipe and its author as attribution. The link may be the
+	// token or the public URL.
+	CopySharedRecipe(ctx context.Context, userID uuid.UUID, link string) (*recipeDTO.RecipeDetailDTO, error)
+}
+
+type RecipeShareRepository interface {
+	Create(ctx context.Context, share *recipeModel.RecipeShare) error
+	FindByID(ctx context.Context, id uuid.UUID) (*recipeModel.RecipeShare, error)
+	FindByToken(ctx context.Context, token string) (*recipeModel.RecipeShare, error)
+	// ListByRecipeID lists the links of a recipe, most recent first.
+	ListByRecipeID(ctx context.Context, recipeID uuid.UUID) ([]*recipeModel.RecipeShare, error)
+	Update(ctx context.Context, share *recipeModel.RecipeShare) error
+}
+
+type MealPlanService interface {
+	ListMealPlan(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID, from string, to string) (*recipeDTO.MealPlanDTO, error)
+	PlanMeal(ctx context.Context, userID uuid.UUID, input *recipeDTO.PlanMealDTO) (*recipeDTO.MealPlanEntryDTO, error)
+	// UpdateMealPlanEntry moves a planned meal to another day or meal, or
+	// changes its recipe, servings or notes.
+	UpdateMealPlanEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID, input *recipeDTO.UpdateMealPlanEntryDTO) (*recipeDTO.MealPlanEntryDTO, error)
+	DeleteMealPlanEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) error
+	// GenerateMealPlan fills the free meals of a period with saved recipes,
+	// favoring the ones that use pantry items about to expire.
+	GenerateMealPlan(ctx context.Context, userID uuid.UUID, input *recipeDTO.GenerateMealPlanDTO) (*recipeDTO.GeneratedMealPlanDTO, error)
+}
+
+type MealPlanRepository interface {
+	Create(ctx context.Context, entry *recipeModel.MealPlanEntry) error
+	CreateMany(ctx context.Context, entries []*recipeModel.MealPlanEntry) error
+	FindByID(ctx context.Context, id uuid.UUID) (*recipeModel.MealPlanEntry, error)
+	Update(ctx context.Context, entry *recipeModel.MealPlanEntry) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByPantry lists the meals planned for a pantry between from and to,
+	// both included, in date order.
+	ListByPantry(ctx context.Context, pantryID uuid.UUID, from time.Time, to time.Time) ([]*recipeModel.MealPlanEntry, error)
 }
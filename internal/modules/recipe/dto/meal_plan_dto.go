@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// PlanMealDTO represents a recipe planned for a meal. Date is YYYY-MM-DD.
+type PlanMealDTO struct {
+	PantryID string `json:"pantry_id" validate:"required,uuid"`
+	RecipeID string `json:"recipe_id" validate:"required,uuid"`
+	Date     string `json:"date" validate:"required"`
+	MealType string `json:"meal_type" validate:"required,oneof=breakfast lunch dinner snack dessert"`
+	// Servings defaults to the serving size of the recipe.
+	Servings int    `json:"servings,omitempty" validate:"omitempty,min=1,max=100"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// UpdateMealPlanEntryDTO represents a partial update of a planned meal
+type UpdateMealPlanEntryDTO struct {
+	Date     *string `json:"date"`
+	MealType *string `json:"meal_type" validate:"omitempty,oneof=breakfast lunch dinner snack dessert"`
+	RecipeID *string `json:"recipe_id" validate:"omitempty,uuid"`
+	Servings *int    `json:"servings" validate:"omitempty,min=1,max=100"`
+	Notes    *string `json:"notes"`
+}
+
+// MealPlanEntryDTO represents a planned meal returned to the client
+type MealPlanEntryDTO struct {
+	ID          string    `json:"id"`
+	PantryID    string    `json:"pantry_id"`
+	RecipeID    string    `json:"recipe_id"`
+	RecipeTitle string    `json:"recipe_title"`
+	Date        string    `json:"date"`
+	MealType    string    `json:"meal_type"`
+	Servings    int       `json:"servings"`
+	Notes       string    `json:"notes,omitempty"`
+	Generated   bool      `json:"generated"`
+	PlannedBy   string    `json:"planned_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MealPlanDTO represents the meals planned for a pantry in a period
+type MealPlanDTO struct {
+	PantryID string             `json:"pantry_id"`
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Entries  []MealPlanEntryDTO `json:"entries"`
+}
+
+// GenerateMealPlanDTO represents a request to fill the free meals of a period
+type GenerateMealPlanDTO struct {
+	PantryID string `json:"pantry_id" validate:"required,uuid"`
+	// StartDate defaults to today.
+	StartDate string `json:"start_date,omitempty"`
+	// Days defaults to 7.
+	Days int `json:"days,omitempty" validate:"omitempty,min=1,max=14"`
+	// MealTypes defaults to lunch and dinner.
+	MealTypes []string `json:"meal_types,omitempty"`
+	// Servings defaults to the serving size of each recipe.
+	Servings int `json:"servings,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// GeneratedMealPlanDTO represents the meals created by the generator. Kept
+// counts the meals that were already planned and Unfilled the ones left free
+// for lack of recipes.
+type GeneratedMealPlanDTO struct {
+	PantryID      string             `json:"pantry_id"`
+	From          string             `json:"from"`
+	To            string             `json:"to"`
+	Created       []MealPlanEntryDTO `json:"created"`
+	Kept          int                `json:"kept"`
+	Unfilled      int                `json:"unfilled"`
+	ExpiringItems []string           `json:"expiring_items"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// CookRecipeDTO represents a request to cook a saved recipe from a pantry
+type CookRecipeDTO struct {
+	PantryID        string     `json:"pantry_id" validate:"required,uuid"`
+	Servings        int        `json:"servings,omitempty" validate:"omitempty,min=1,max=100"`
+	SkipIngredients []string   `json:"skip_ingredients,omitempty"`
+	CookedAt        *time.Time `json:"cooked_at,omitempty"`
+	// AllowPartial cooks even when the pantry does not cover every ingredient,
+	// taking what there is.
+	AllowPartial bool `json:"allow_partial,omitempty"`
+}
+
+// LogRecipeCookingDTO represents a cooking logged without a pantry. Servings
+// defaults to the servings of the recipe and cooked_at to now.
+type LogRecipeCookingDTO struct {
+	Servings int        `json:"servings,omitempty" validate:"omitempty,min=1,max=100"`
+	CookedAt *time.Time `json:"cooked_at,omitempty"`
+}
+
+// CookingDeductionDTO represents the quantity taken from one pantry item
+type CookingDeductionDTO struct {
+	Ingredient   string  `json:"ingredient,omitempty"`
+	PantryItemID string  `json:"pantry_item_id"`
+	Name         string  `json:"name"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Before       float64 `json:"before"`
+	After        float64 `json:"after"`
+}
+
+// CookingIngredientDTO represents how one ingredient of a recipe is taken from
+// the pantry. Status is one of covered, insufficient, missing, incompatible,
+// unmeasured or skipped.
+type CookingIngredientDTO struct {
+	Name       string                `json:"name"`
+	Needed     *float64              `json:"needed,omitempty"`
+	Unit       string                `json:"unit"`
+	Status     string                `json:"status"`
+	Deductions []CookingDeductionDTO `json:"deductions"`
+}
+
+// CookingPreviewDTO represents what cooking a recipe would take from a pantry
+type CookingPreviewDTO struct {
+	RecipeID    string                 `json:"recipe_id"`
+	PantryID    string                 `json:"pantry_id"`
+	Servings    int                    `json:"servings"`
+	CanCook     bool                   `json:"can_cook"`
+	Ingredients []CookingIngredientDTO `json:"ingredients"`
+}
+
+// RecipeCookingDTO represents an entry of the cooking history of a recipe.
+// RecipeVersion is the version of the recipe that was cooked; cookings
+// recorded before versions existed have none.
+type RecipeCookingDTO struct {
+	ID            string                 `json:"id"`
+	RecipeID      string                 `json:"recipe_id"`
+	RecipeVersion int                    `json:"recipe_version,omitempty"`
+	PantryID      string                 `json:"pantry_id,omitempty"`
+	Servings      int                    `json:"servings"`
+	CookedAt      time.Time              `json:"cooked_at"`
+	Deductions    []CookingDeductionDTO  `json:"deductions"`
+	Ingredients   []CookingIngredientDTO `json:"ingredients,omitempty"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
+)
 
 // AvailableIngredientDTO representa um ingrediente disponível em uma despensa
 // exposto para o frontend.
@@ -28,6 +32,32 @@ type SaveRecipeDTO struct {
 	NutritionInfo       SaveRecipeNutritionDTO     `json:"nutrition_info"`
 	Tips                []string                   `json:"tips"`
 	GeneratedAt         string                     `json:"generated_at"`
+	// OnDuplicate overrides the configured handling of near-duplicates:
+	// warn saves anyway, refuse rejects the recipe and replace overwrites the
+	// most similar saved recipe.
+	OnDuplicate string `json:"on_duplicate,omitempty" validate:"omitempty,oneof=warn refuse replace"`
+	// ReplaceRecipeID overwrites this saved recipe instead of creating a new
+	// one, whether or not it is similar.
+	ReplaceRecipeID string `json:"replace_recipe_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// SavedRecipeDTO tells how a recipe was saved, which saved recipes it looks
+// like and which of its ingredients break dietary restrictions
+type SavedRecipeDTO struct {
+	ID              string                     `json:"id"`
+	Title           string                     `json:"title"`
+	Replaced        bool                       `json:"replaced"`
+	Duplicates      []RecipeDuplicateDTO       `json:"duplicates,omitempty"`
+	DietaryWarnings []llmDTO.DietaryWarningDTO `json:"dietary_warnings,omitempty"`
+}
+
+// RecipeDuplicateDTO represents a saved recipe similar to another one
+type RecipeDuplicateDTO struct {
+	ID                string  `json:"id"`
+	Title             string  `json:"title"`
+	Similarity        float64 `json:"similarity"`
+	TitleSimilarity   float64 `json:"title_similarity"`
+	IngredientOverlap float64 `json:"ingredient_overlap"`
 }
 
 // SaveRecipeIngredientDTO represents an ingredient in a recipe to be saved
@@ -48,10 +78,12 @@ type SaveRecipeInstructionDTO struct {
 
 // SaveRecipeNutritionDTO represents nutrition info in a recipe to be saved
 type SaveRecipeNutritionDTO struct {
-	Calories      *int `json:"calories"`
-	Protein       *int `json:"protein"`
-	Carbohydrates *int `json:"carbohydrates"`
-	Fat           *int `json:"fat"`
+	Calories      *int     `json:"calories"`
+	Protein       *int     `json:"protein"`
+	Carbohydrates *int     `json:"carbohydrates"`
+	Fat           *int     `json:"fat"`
+	Fiber         *int     `json:"fiber"`
+	Confidence    *float64 `json:"confidence"`
 }
 
 // RecipeDetailDTO represents a saved recipe returned to the client
@@ -71,8 +103,22 @@ type RecipeDetailDTO struct {
 	DietaryRestrictions []string                     `json:"dietary_restrictions"`
 	NutritionInfo       RecipeNutritionDetailDTO     `json:"nutrition_info"`
 	Tips                []string                     `json:"tips"`
+	Tags                []string                     `json:"tags"`
+	Favorite            bool                         `json:"favorite"`
+	Rating              *int                         `json:"rating"`
+	Notes               string                       `json:"notes"`
+	Modifications       []string                     `json:"modifications"`
+	LastCookedAt        *time.Time                   `json:"last_cooked_at"`
+	TimesCooked         int                          `json:"times_cooked"`
+	SourceURL           string                       `json:"source_url,omitempty"`
+	Attribution         *RecipeAttributionDTO        `json:"attribution,omitempty"`
+	Version             int                          `json:"version"`
 	GeneratedAt         time.Time                    `json:"generated_at"`
 	CreatedAt           time.Time                    `json:"created_at"`
+	UpdatedAt           time.Time                    `json:"updated_at"`
+	// DietaryWarnings are set when the ingredients or restrictions of a recipe
+	// are imported, copied or changed.
+	DietaryWarnings []llmDTO.DietaryWarningDTO `json:"dietary_warnings,omitempty"`
 }
 
 // RecipeIngredientDetailDTO represents an ingredient in a saved recipe
@@ -93,8 +139,86 @@ type RecipeInstructionDetailDTO struct {
 
 // RecipeNutritionDetailDTO represents nutrition info in a saved recipe
 type RecipeNutritionDetailDTO struct {
-	Calories      *int `json:"calories,omitempty"`
-	Protein       *int `json:"protein,omitempty"`
-	Carbohydrates *int `json:"carbohydrates,omitempty"`
-	Fat           *int `json:"fat,omitempty"`
+	Calories      *int     `json:"calories,omitempty"`
+	Protein       *int     `json:"protein,omitempty"`
+	Carbohydrates *int     `json:"carbohydrates,omitempty"`
+	Fat           *int     `json:"fat,omitempty"`
+	Fiber         *int     `json:"fiber,omitempty"`
+	Confidence    *float64 `json:"confidence,omitempty"`
+}
+
+// UpdateRecipeDTO represents a partial update of a saved recipe. Fields left
+// out of the request are kept.
+type UpdateRecipeDTO struct {
+	Title               *string                    `json:"title" validate:"omitempty,min=1,max=255"`
+	Description         *string                    `json:"description"`
+	Ingredients         []SaveRecipeIngredientDTO  `json:"ingredients" validate:"omitempty,min=1,dive"`
+	Instructions        []SaveRecipeInstructionDTO `json:"instructions" validate:"omitempty,min=1,dive"`
+	CookingTime         *int                       `json:"cooking_time"`
+	PreparationTime     *int                       `json:"preparation_time"`
+	TotalTime           *int                       `json:"total_time"`
+	ServingSize         *int                       `json:"serving_size"`
+	Difficulty          *string                    `json:"difficulty" validate:"omitempty,oneof=easy medium hard"`
+	MealType            *string                    `json:"meal_type" validate:"omitempty,oneof=breakfast lunch dinner snack dessert"`
+	Cuisine             *string                    `json:"cuisine" validate:"omitempty,max=100"`
+	DietaryRestrictions []string                   `json:"dietary_restrictions"`
+	NutritionInfo       *SaveRecipeNutritionDTO    `json:"nutrition_info"`
+	Tips                []string                   `json:"tips"`
+	Tags                []string                   `json:"tags"`
+	Favorite            *bool                      `json:"favorite"`
+	Rating              *int                       `json:"rating" validate:"omitempty,min=1,max=5"`
+	Notes               *string                    `json:"notes"`
+	Modifications       []string                   `json:"modifications"`
+}
+
+// SetRecipeTagsDTO replaces the tags of a recipe
+type SetRecipeTagsDTO struct {
+	Tags []string `json:"tags"`
+}
+
+// SetRecipeFavoriteDTO marks or unmarks a recipe as favorite
+type SetRecipeFavoriteDTO struct {
+	Favorite bool `json:"favorite"`
+}
+
+// SetRecipeRatingDTO rates a recipe from 1 to 5. A null rating clears it.
+type SetRecipeRatingDTO struct {
+	Rating *int `json:"rating" validate:"omitempty,min=1,max=5"`
+}
+
+// SetRecipeNotesDTO replaces the personal notes of a recipe and the
+// modifications made to it when cooking
+type SetRecipeNotesDTO struct {
+	Notes         string   `json:"notes"`
+	Modifications []string `json:"modifications"`
+}
+
+// RecipeFilterDTO filters and paginates the saved recipes of a user. Empty
+// fields do not filter.
+type RecipeFilterDTO struct {
+	Query              string   `json:"query,omitempty"`
+	Cuisine            string   `json:"cuisine,omitempty"`
+	MealType           string   `json:"meal_type,omitempty"`
+	Difficulty         string   `json:"difficulty,omitempty"`
+	MaxTotalTime       *int     `json:"max_total_time,omitempty"`
+	DietaryRestriction string   `json:"dietary_restriction,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	Favorite           *bool    `json:"favorite,omitempty"`
+	SortBy             string   `json:"sort_by,omitempty"` // "recent", "title", "total_time", "rating", "not_cooked_recently"
+	Limit              int      `json:"limit,omitempty"`
+	Offset             int      `json:"offset,omitempty"`
+}
+
+// RecipeListDTO represents a page of saved recipes
+type RecipeListDTO struct {
+	Recipes []*RecipeDetailDTO `json:"recipes"`
+	Total   int64              `json:"total"`
+	Limit   int                `json:"limit"`
+	Offset  int                `json:"offset"`
+}
+
+// RecipeTagDTO represents a tag used by the recipes of a user
+type RecipeTagDTO struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
 }
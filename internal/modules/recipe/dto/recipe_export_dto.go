@@ -0,0 +1,8 @@
+package dto
+
+// RecipeExportDTO is a rendered recipe export ready to be downloaded.
+type RecipeExportDTO struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
@@ -0,0 +1,18 @@
+package dto
+
+// ImportRecipeDTO represents a web page to import a recipe from. HTML takes
+// precedence over URL, which is then only kept as the source of the recipe.
+type ImportRecipeDTO struct {
+	URL     string `json:"url"`
+	HTML    string `json:"html"`
+	Preview bool   `json:"preview"`
+}
+
+// ImportedRecipeDTO represents a recipe read from a web page. Format tells
+// where the recipe was found (json-ld or microdata) and Saved whether it was
+// stored in the recipes of the user.
+type ImportedRecipeDTO struct {
+	Recipe RecipeDetailDTO `json:"recipe"`
+	Format string          `json:"format"`
+	Saved  bool            `json:"saved"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import nutritionDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/dto"
+
+// RecipeNutritionEstimateDTO is the nutrition computed for a saved recipe.
+// Updated tells whether the recipe nutrition was replaced, which only happens
+// when at least one ingredient was found in the food table.
+type RecipeNutritionEstimateDTO struct {
+	RecipeID      string                            `json:"recipe_id"`
+	NutritionInfo RecipeNutritionDetailDTO          `json:"nutrition_info"`
+	Updated       bool                              `json:"updated"`
+	Estimate      nutritionDTO.NutritionEstimateDTO `json:"estimate"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+// ScaledIngredientDTO represents an ingredient scaled to other servings.
+// OriginalAmount and OriginalUnit are the values saved in the recipe.
+type ScaledIngredientDTO struct {
+	Name           string   `json:"name"`
+	Amount         *float64 `json:"amount"`
+	Unit           string   `json:"unit"`
+	OriginalAmount *float64 `json:"original_amount"`
+	OriginalUnit   string   `json:"original_unit"`
+	Alternative    *string  `json:"alternative,omitempty"`
+}
+
+// ScaledInstructionDTO represents a step of a scaled recipe. Scaled tells
+// whether the time of the step changed with the servings.
+type ScaledInstructionDTO struct {
+	Step         int    `json:"step"`
+	Description  string `json:"description"`
+	Time         *int   `json:"time,omitempty"`
+	OriginalTime *int   `json:"original_time,omitempty"`
+	Scaled       bool   `json:"scaled"`
+}
+
+// ScaledRecipeDTO represents a saved recipe scaled to a number of servings
+type ScaledRecipeDTO struct {
+	RecipeID         string                 `json:"recipe_id"`
+	Title            string                 `json:"title"`
+	Servings         int                    `json:"servings"`
+	OriginalServings int                    `json:"original_servings"`
+	ScaleFactor      float64                `json:"scale_factor"`
+	Ingredients      []ScaledIngredientDTO  `json:"ingredients"`
+	Instructions     []ScaledInstructionDTO `json:"instructions"`
+	PreparationTime  *int                   `json:"preparation_time"`
+	CookingTime      *int                   `json:"cooking_time"`
+	TotalTime        *int                   `json:"total_time"`
+}
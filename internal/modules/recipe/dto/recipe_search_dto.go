@@ -0,0 +1,24 @@
+package dto
+
+// IngredientSearchDTO represents a search of the saved recipes by the
+// ingredients at hand: the listed ones, the stock of a pantry or both.
+// MinCoverage is the share (0 to 1) of ingredients a recipe must have.
+type IngredientSearchDTO struct {
+	Ingredients        []string `json:"ingredients"`
+	PantryID           string   `json:"pantry_id"`
+	MealType           string   `json:"meal_type"`
+	MaxTotalTime       *int     `json:"max_total_time"`
+	DietaryRestriction string   `json:"dietary_restriction"`
+	MinCoverage        float64  `json:"min_coverage"`
+	Limit              int      `json:"limit"`
+}
+
+// IngredientSearchResultDTO represents a saved recipe found by ingredients.
+// Coverage is the share of its ingredients that are at hand; the ones used
+// "a gosto", without an amount, are not counted.
+type IngredientSearchResultDTO struct {
+	Recipe             RecipeDetailDTO             `json:"recipe"`
+	Coverage           float64                     `json:"coverage"`
+	MatchedIngredients []string                    `json:"matched_ingredients"`
+	MissingIngredients []RecipeIngredientDetailDTO `json:"missing_ingredients"`
+}
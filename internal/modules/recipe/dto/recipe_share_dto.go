@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// CreateRecipeShareDTO creates a public link to a recipe. Without
+// expires_in_hours the link works until it is revoked.
+type CreateRecipeShareDTO struct {
+	ExpiresInHours int `json:"expires_in_hours,omitempty" validate:"omitempty,min=1,max=8760"`
+}
+
+// RecipeShareDTO represents a public link to a recipe
+type RecipeShareDTO struct {
+	ID        string     `json:"id"`
+	RecipeID  string     `json:"recipe_id"`
+	Token     string     `json:"token"`
+	Path      string     `json:"path"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SharedRecipeDTO is the public view of a shared recipe. It leaves out the
+// owner identifiers and what is personal to the owner, such as tags, rating,
+// notes and pantry availability.
+type SharedRecipeDTO struct {
+	Title               string                       `json:"title"`
+	Description         string                       `json:"description"`
+	Author              string                       `json:"author,omitempty"`
+	Ingredients         []SharedRecipeIngredientDTO  `json:"ingredients"`
+	Instructions        []RecipeInstructionDetailDTO `json:"instructions"`
+	CookingTime         *int                         `json:"cooking_time"`
+	PreparationTime     *int                         `json:"preparation_time"`
+	TotalTime           *int                         `json:"total_time"`
+	ServingSize         *int                         `json:"serving_size"`
+	Difficulty          string                       `json:"difficulty"`
+	MealType            string                       `json:"meal_type"`
+	Cuisine             string                       `json:"cuisine"`
+	DietaryRestrictions []string                     `json:"dietary_restrictions"`
+	NutritionInfo       RecipeNutritionDetailDTO     `json:"nutrition_info"`
+	Tips                []string                     `json:"tips"`
+	SourceURL           string                       `json:"source_url,omitempty"`
+	ExpiresAt           *time.Time                   `json:"expires_at,omitempty"`
+	UpdatedAt           time.Time                    `json:"updated_at"`
+}
+
+// SharedRecipeIngredientDTO represents an ingredient of a shared recipe
+type SharedRecipeIngredientDTO struct {
+	Name        string   `json:"name"`
+	Amount      *float64 `json:"amount"`
+	Unit        string   `json:"unit"`
+	Alternative *string  `json:"alternative,omitempty"`
+}
+
+// CopySharedRecipeDTO copies a shared recipe. Link is the share token or the
+// public URL of the recipe.
+type CopySharedRecipeDTO struct {
+	Link string `json:"link" validate:"required"`
+}
+
+// RecipeAttributionDTO tells where a copied recipe came from
+type RecipeAttributionDTO struct {
+	RecipeID string `json:"recipe_id"`
+	Author   string `json:"author,omitempty"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+// SubstitutionPantryItemDTO represents a pantry item that can stand in for an
+// ingredient. Quantity is what is left after the rest of the recipe.
+type SubstitutionPantryItemDTO struct {
+	PantryItemID string  `json:"pantry_item_id"`
+	Name         string  `json:"name"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+}
+
+// IngredientSubstituteDTO represents a substitute of an ingredient found in
+// the pantry. Amount is the quantity of the substitute for the missing amount
+// of the ingredient; Sufficient tells whether the pantry has that much.
+type IngredientSubstituteDTO struct {
+	Name        string                      `json:"name"`
+	Ratio       float64                     `json:"ratio"`
+	Amount      *float64                    `json:"amount,omitempty"`
+	Unit        string                      `json:"unit"`
+	Notes       string                      `json:"notes,omitempty"`
+	Diets       []string                    `json:"diets"`
+	Sufficient  bool                        `json:"sufficient"`
+	PantryItems []SubstitutionPantryItemDTO `json:"pantry_items"`
+}
+
+// IngredientSubstitutionDTO represents an ingredient the pantry does not
+// cover and its substitutes. Status is the cooking status of the ingredient:
+// missing, insufficient or incompatible.
+type IngredientSubstitutionDTO struct {
+	Name        string                    `json:"name"`
+	Missing     *float64                  `json:"missing,omitempty"`
+	Unit        string                    `json:"unit"`
+	Status      string                    `json:"status"`
+	Alternative *string                   `json:"alternative,omitempty"`
+	Substitutes []IngredientSubstituteDTO `json:"substitutes"`
+}
+
+// RecipeSubstitutionsDTO represents the substitutes in a pantry for the
+// ingredients of a recipe it lacks. Diets are the dietary restrictions every
+// substitute respects.
+type RecipeSubstitutionsDTO struct {
+	RecipeID    string                      `json:"recipe_id"`
+	PantryID    string                      `json:"pantry_id"`
+	Servings    int                         `json:"servings"`
+	Diets       []string                    `json:"diets"`
+	Ingredients []IngredientSubstitutionDTO `json:"ingredients"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// RecipeVersionSummaryDTO represents a version in the history of a recipe.
+// RestoredFrom is the version it brought back, when it is a restore.
+type RecipeVersionSummaryDTO struct {
+	Version      int       `json:"version"`
+	Title        string    `json:"title"`
+	AuthorID     string    `json:"author_id"`
+	RestoredFrom *int      `json:"restored_from,omitempty"`
+	Current      bool      `json:"current"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// RecipeVersionDTO represents the content of a recipe at a version
+type RecipeVersionDTO struct {
+	RecipeVersionSummaryDTO
+	Description         string                       `json:"description"`
+	Ingredients         []RecipeIngredientDetailDTO  `json:"ingredients"`
+	Instructions        []RecipeInstructionDetailDTO `json:"instructions"`
+	CookingTime         *int                         `json:"cooking_time"`
+	PreparationTime     *int                         `json:"preparation_time"`
+	TotalTime           *int                         `json:"total_time"`
+	ServingSize         *int                         `json:"serving_size"`
+	Difficulty          string                       `json:"difficulty"`
+	MealType            string                       `json:"meal_type"`
+	Cuisine             string                       `json:"cuisine"`
+	DietaryRestrictions []string                     `json:"dietary_restrictions"`
+	Tips                []string                     `json:"tips"`
+}
+
+// RecipeFieldChangeDTO represents a field with another value in the newer
+// version
+type RecipeFieldChangeDTO struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RecipeIngredientChangeDTO represents an ingredient added, removed or
+// changed (amount, unit or alternative) between two versions
+type RecipeIngredientChangeDTO struct {
+	Change string                     `json:"change"`
+	Name   string                     `json:"name"`
+	From   *RecipeIngredientDetailDTO `json:"from,omitempty"`
+	To     *RecipeIngredientDetailDTO `json:"to,omitempty"`
+}
+
+// RecipeInstructionChangeDTO represents a step added, removed or rewritten
+// between two versions. Steps only renumbered are not listed.
+type RecipeInstructionChangeDTO struct {
+	Change string                      `json:"change"`
+	From   *RecipeInstructionDetailDTO `json:"from,omitempty"`
+	To     *RecipeInstructionDetailDTO `json:"to,omitempty"`
+}
+
+// RecipeVersionDiffDTO represents what changed from one version of a recipe
+// to another
+type RecipeVersionDiffDTO struct {
+	RecipeID     string                       `json:"recipe_id"`
+	From         int                          `json:"from"`
+	To           int                          `json:"to"`
+	Fields       []RecipeFieldChangeDTO       `json:"fields"`
+	Ingredients  []RecipeIngredientChangeDTO  `json:"ingredients"`
+	Instructions []RecipeInstructionChangeDTO `json:"instructions"`
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type MealPlanHandler struct {
+	mealPlanService recipeDomain.MealPlanService
+}
+
+func NewMealPlanHandler(mealPlanService recipeDomain.MealPlanService) *MealPlanHandler {
+	return &MealPlanHandler{mealPlanService: mealPlanService}
+}
+
+// ListMealPlan godoc
+// @Summary List the meal plan of a pantry
+// @Description List the meals planned for a pantry, ordered by day and meal. The period defaults to the seven days starting today
+// @Tags meal-plans
+// @Produce json
+// @Param pantry_id query string true "Pantry ID"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=dto.MealPlanDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/meal-plans [get]
+// @Security BearerAuth
+func (h *MealPlanHandler) ListMealPlan(c *gin.Context) {
+	userID, ok := contextUserID(c, "ListMealPlan")
+	if !ok {
+		return
+	}
+
+	pantryID, err := uuid.Parse(c.Query("pantry_id"))
+	if err != nil {
+		response.BadRequest(c, "pantry_id inválido: "+err.Error())
+		return
+	}
+
+	plan, err := h.mealPlanService.ListMealPlan(c.Request.Context(), userID, pantryID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.OK(c, plan)
+}
+
+// PlanMeal godoc
+// @Summary Plan a meal
+// @Description Plan a saved recipe for a meal of a day in a pantry
+// @Tags meal-plans
+// @Accept json
+// @Produce json
+// @Param meal body dto.PlanMealDTO true "Meal data"
+// @Success 201 {object} response.Response{data=dto.MealPlanEntryDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/meal-plans [post]
+// @Security BearerAuth
+func (h *MealPlanHandler) PlanMeal(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	userID, ok := contextUserID(c, "PlanMeal")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.PlanMealDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	entry, err := h.mealPlanService.PlanMeal(c.Request.Context(), userID, &input)
+	if err != nil {
+		logger.Error("Failed to plan meal",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "PlanMeal"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, entry)
+}
+
+// UpdateMealPlanEntry godoc
+// @Summary Move or change a planned meal
+// @Description Change the day, meal, recipe, servings or notes of a planned meal
+// @Tags meal-plans
+// @Accept json
+// @Produce json
+// @Param id path string true "Meal plan entry ID"
+// @Param meal body dto.UpdateMealPlanEntryDTO true "Fields to change"
+// @Success 200 {object} response.Response{data=dto.MealPlanEntryDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/meal-plans/{id} [patch]
+// @Security BearerAuth
+func (h *MealPlanHandler) UpdateMealPlanEntry(c *gin.Context) {
+	entryID, userID, ok := mealPlanRequestIDs(c, "UpdateMealPlanEntry")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.UpdateMealPlanEntryDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	entry, err := h.mealPlanService.UpdateMealPlanEntry(c.Request.Context(), userID, entryID, &input)
+	if err != nil {
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.OK(c, entry)
+}
+
+// DeleteMealPlanEntry godoc
+// @Summary Remove a planned meal
+// @Tags meal-plans
+// @Produce json
+// @Param id path string true "Meal plan entry ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/meal-plans/{id} [delete]
+// @Security BearerAuth
+func (h *MealPlanHandler) DeleteMealPlanEntry(c *gin.Context) {
+	entryID, userID, ok := mealPlanRequestIDs(c, "DeleteMealPlanEntry")
+	if !ok {
+		return
+	}
+
+	if err := h.mealPlanService.DeleteMealPlanEntry(c.Request.Context(), userID, entryID); err != nil {
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"message": "Refeição removida do planejamento"})
+}
+
+// GenerateMealPlan godoc
+// @Summary Generate a meal plan
+// @Description Fill the free meals of the week with saved recipes, preferring the ones that use pantry items about to expire. Meals already planned are kept
+// @Tags meal-plans
+// @Accept json
+// @Produce json
+// @Param plan body dto.GenerateMealPlanDTO true "Pantry, period and meals"
+// @Success 201 {object} response.Response{data=dto.GeneratedMealPlanDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/meal-plans/generate [post]
+// @Security BearerAuth
+func (h *MealPlanHandler) GenerateMealPlan(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	userID, ok := contextUserID(c, "GenerateMealPlan")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.GenerateMealPlanDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	plan, err := h.mealPlanService.GenerateMealPlan(c.Request.Context(), userID, &input)
+	if err != nil {
+		logger.Error("Failed to generate meal plan",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GenerateMealPlan"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, plan)
+}
+
+func mealPlanRequestIDs(c *gin.Context, function string) (uuid.UUID, uuid.UUID, bool) {
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "ID da refeição inválido: "+err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, ok := contextUserID(c, function)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return entryID, userID, true
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// PreviewCooking godoc
+// @Summary Preview cooking a recipe
+// @Description Show which pantry items cooking a saved recipe would use and how much of each, converting units. Nothing is changed
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param cooking body dto.CookRecipeDTO true "Pantry and servings"
+// @Success 200 {object} response.Response{data=dto.CookingPreviewDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/cook/preview [post]
+// @Security BearerAuth
+func (h *RecipeHandler) PreviewCooking(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "PreviewCooking")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.CookRecipeDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	preview, err := h.recipeService.PreviewCooking(c.Request.Context(), recipeID, userID, &input)
+	if err != nil {
+		logger.Error("Failed to preview recipe cooking",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "PreviewCooking"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, preview)
+}
+
+// CookRecipe godoc
+// @Summary Cook a recipe
+// @Description Take the ingredients of a saved recipe from the pantry in one transaction and record the cooking in the history of the recipe. Answers 409 when the pantry does not cover every ingredient and allow_partial is not set, or when the pantry changed and the quantities are no longer available
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param cooking body dto.CookRecipeDTO true "Pantry, servings and ingredients to skip"
+// @Success 201 {object} response.Response{data=dto.RecipeCookingDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/cook [post]
+// @Security BearerAuth
+func (h *RecipeHandler) CookRecipe(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "CookRecipe")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.CookRecipeDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	cooking, err := h.recipeService.CookRecipe(c.Request.Context(), recipeID, userID, &input)
+	if err != nil {
+		logger.Error("Failed to cook recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CookRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, cooking)
+}
+
+// LogRecipeCooking godoc
+// @Summary Log a recipe as cooked
+// @Description Record in the history of a saved recipe that it was cooked, without taking anything from a pantry. The body is optional: servings default to the servings of the recipe and cooked_at to now
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param cooking body dto.LogRecipeCookingDTO false "Servings and when the recipe was cooked"
+// @Success 201 {object} response.Response{data=dto.RecipeCookingDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/cooked [post]
+// @Security BearerAuth
+func (h *RecipeHandler) LogRecipeCooking(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "LogRecipeCooking")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.LogRecipeCookingDTO
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	cooking, err := h.recipeService.LogRecipeCooking(c.Request.Context(), recipeID, userID, &input)
+	if err != nil {
+		logger.Error("Failed to log recipe cooking",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "LogRecipeCooking"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, cooking)
+}
+
+// ListRecipeCookings godoc
+// @Summary List the cooking history of a recipe
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} response.Response{data=[]dto.RecipeCookingDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/cookings [get]
+// @Security BearerAuth
+func (h *RecipeHandler) ListRecipeCookings(c *gin.Context) {
+	recipeID, userID, ok := recipeRequestIDs(c, "ListRecipeCookings")
+	if !ok {
+		return
+	}
+
+	cookings, err := h.recipeService.ListRecipeCookings(c.Request.Context(), recipeID, userID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, cookings)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ExportRecipe godoc
+// @Summary Export a recipe
+// @Description Download a saved recipe as markdown, schema.org JSON-LD or a printable PDF with ingredients, steps, times, nutrition and tips
+// @Tags recipes
+// @Produce text/markdown
+// @Produce application/ld+json
+// @Produce application/pdf
+// @Param id path string true "Recipe ID"
+// @Param format query string false "markdown (default), json-ld or pdf"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/export [get]
+// @Security BearerAuth
+func (h *RecipeHandler) ExportRecipe(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "ExportRecipe")
+	if !ok {
+		return
+	}
+
+	export, err := h.recipeService.ExportRecipe(c.Request.Context(), recipeID, userID, c.Query("format"))
+	if err != nil {
+		logger.Error("Failed to export recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ExportRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.String("format", c.Query("format")),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeRecipeExport(c, export)
+}
+
+// ExportRecipes godoc
+// @Summary Export all recipes
+// @Description Download every saved recipe of the user in a zip, one file per recipe in the format
+// @Tags recipes
+// @Produce application/zip
+// @Param format query string false "markdown (default), json-ld or pdf"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/export [get]
+// @Security BearerAuth
+func (h *RecipeHandler) ExportRecipes(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	userID, ok := contextUserID(c, "ExportRecipes")
+	if !ok {
+		return
+	}
+
+	export, err := h.recipeService.ExportRecipes(c.Request.Context(), userID, c.Query("format"))
+	if err != nil {
+		logger.Error("Failed to export recipes",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ExportRecipes"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("format", c.Query("format")),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	writeRecipeExport(c, export)
+}
+
+func writeRecipeExport(c *gin.Context, export *recipeDTO.RecipeExportDTO) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+	c.Data(http.StatusOK, export.ContentType, export.Content)
+}
@@ -87,6 +87,12 @@ func extractDetail(err error, base error, fallback string) string {
 }
 
 func (h *RecipeHandler) handleServiceError(c *gin.Context, err error) {
+	handleRecipeError(c, err)
+}
+
+// handleRecipeError answers a request with the status matching an error of the
+// recipe services.
+func handleRecipeError(c *gin.Context, err error) {
 	switch {
 	case errors.Is(err, recipeDomain.ErrInvalidRequest):
 		detail := extractDetail(err, recipeDomain.ErrInvalidRequest, "Invalid recipe request")
@@ -103,9 +109,30 @@ func (h *RecipeHandler) handleServiceError(c *gin.Context, err error) {
 		response.InternalError(c, "Failed to process recipe request")
 	case errors.Is(err, recipeDomain.ErrRecipeNotFound):
 		response.Fail(c, http.StatusNotFound, "NOT_FOUND", "Recipe not found")
+	case errors.Is(err, recipeDomain.ErrMealPlanNotFound):
+		response.Fail(c, http.StatusNotFound, "NOT_FOUND", "Meal plan entry not found")
 	case errors.Is(err, recipeDomain.ErrInvalidRecipeData):
 		detail := extractDetail(err, recipeDomain.ErrInvalidRecipeData, "Invalid recipe data")
 		response.BadRequest(c, detail)
+	case errors.Is(err, recipeDomain.ErrImportFailed):
+		response.Fail(c, http.StatusBadGateway, "IMPORT_FAILED", "Could not fetch the recipe page")
+	case errors.Is(err, recipeDomain.ErrUnsupportedFormat):
+		response.BadRequest(c, "Unsupported export format, use markdown, json-ld or pdf")
+	case errors.Is(err, recipeDomain.ErrStockChanged):
+		response.Fail(c, http.StatusConflict, "STOCK_CHANGED", "Pantry stock changed, preview the cooking again")
+	case errors.Is(err, recipeDomain.ErrInsufficientStock):
+		response.Fail(c, http.StatusConflict, "INSUFFICIENT_STOCK", "The pantry does not cover every ingredient, skip them or allow partial cooking")
+	case errors.Is(err, recipeDomain.ErrShareNotFound):
+		response.Fail(c, http.StatusNotFound, "SHARE_NOT_FOUND", "Shared recipe not found")
+	case errors.Is(err, recipeDomain.ErrDuplicateRecipe):
+		detail := extractDetail(err, recipeDomain.ErrDuplicateRecipe, "A similar recipe is already saved")
+		response.Fail(c, http.StatusConflict, "DUPLICATE_RECIPE", detail)
+	case errors.Is(err, recipeDomain.ErrShareExpired):
+		response.Fail(c, http.StatusGone, "SHARE_EXPIRED", "This link has expired")
+	case errors.Is(err, recipeDomain.ErrVersionNotFound):
+		response.Fail(c, http.StatusNotFound, "VERSION_NOT_FOUND", "Recipe version not found")
+	case errors.Is(err, recipeDomain.ErrVersionConflict):
+		response.Fail(c, http.StatusConflict, "VERSION_CONFLICT", "The recipe was changed by another request, load it and try again")
 	default:
 		response.InternalError(c, "Unexpected error while processing recipe request")
 	}
@@ -549,7 +576,7 @@ func (h *RecipeHandler) EstimateTokens(c *gin.Context) {
 
 // SaveRecipe godoc
 // @Summary Save a generated recipe
-// @Description Save one or more generated recipes to the database
+// @Description Save one or more generated recipes to the database. Recipes similar to a saved one by title and ingredients are reported in duplicates, or refused with 409 when the duplicate policy is refuse. Set on_duplicate to replace, or replace_recipe_id, to overwrite a saved recipe instead
 // @Tags recipes
 // @Accept json
 // @Produce json
@@ -557,6 +584,8 @@ func (h *RecipeHandler) EstimateTokens(c *gin.Context) {
 // @Success 200 {object} response.Response{data=map[string]interface{}}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/recipes/save [post]
 // @Security BearerAuth
@@ -598,7 +627,8 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 			recipePtrs[i] = &recipesArray[i]
 		}
 
-		if err := h.recipeService.SaveMultipleRecipes(c.Request.Context(), recipePtrs, userID); err != nil {
+		saved, err := h.recipeService.SaveMultipleRecipes(c.Request.Context(), recipePtrs, userID)
+		if err != nil {
 			logger.Error("Failed to save multiple recipes",
 				zap.String(appLogger.FieldModule, "recipe"),
 				zap.String(appLogger.FieldFunction, "SaveRecipe"),
@@ -624,6 +654,7 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 		response.OK(c, map[string]interface{}{
 			"message": message,
 			"count":   len(recipesArray),
+			"recipes": saved,
 		})
 		return
 	}
@@ -641,7 +672,8 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 		return
 	}
 
-	if err := h.recipeService.SaveRecipe(c.Request.Context(), &singleRecipe, userID); err != nil {
+	saved, err := h.recipeService.SaveRecipe(c.Request.Context(), &singleRecipe, userID)
+	if err != nil {
 		logger.Error("Failed to save recipe",
 			zap.String(appLogger.FieldModule, "recipe"),
 			zap.String(appLogger.FieldFunction, "SaveRecipe"),
@@ -658,17 +690,68 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 		zap.String(appLogger.FieldUserID, userID.String()),
 	)
 
+	message := "Receita salva com sucesso."
+	if saved.Replaced {
+		message = "Receita substituída com sucesso."
+	}
 	response.OK(c, map[string]interface{}{
-		"message": "Receita salva com sucesso.",
+		"message": message,
+		"recipe":  saved,
 	})
 }
 
+// FindRecipeDuplicates godoc
+// @Summary Find saved recipes similar to a recipe
+// @Description Compare a recipe with the saved ones by normalized title and ingredient set, without saving it, and list the near-duplicates, most similar first
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param recipe body dto.SaveRecipeDTO true "Recipe to compare"
+// @Success 200 {object} response.Response{data=[]dto.RecipeDuplicateDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/duplicates [post]
+// @Security BearerAuth
+func (h *RecipeHandler) FindRecipeDuplicates(c *gin.Context) {
+	userID, ok := contextUserID(c, "FindRecipeDuplicates")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.SaveRecipeDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	duplicates, err := h.recipeService.FindRecipeDuplicates(c.Request.Context(), userID, &input)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, duplicates)
+}
+
 // GetRecipes godoc
-// @Summary Get all saved recipes
-// @Description Get all recipes saved by the logged-in user
+// @Summary List saved recipes
+// @Description List a page of the recipes saved by the logged-in user, optionally filtered by title, cuisine, meal type, difficulty, maximum total time, dietary restriction, tags and favorites
 // @Tags recipes
 // @Produce json
-// @Success 200 {object} response.Response{data=[]dto.RecipeDetailDTO}
+// @Param q query string false "Text in the title"
+// @Param cuisine query string false "Cuisine"
+// @Param meal_type query string false "Meal type (breakfast, lunch, dinner, snack, dessert)"
+// @Param difficulty query string false "Difficulty (easy, medium, hard)"
+// @Param max_total_time query int false "Maximum total time in minutes"
+// @Param dietary query string false "Dietary restriction"
+// @Param tags query string false "Tags, comma separated; every tag must match"
+// @Param favorite query bool false "Only favorites (true) or non favorites (false)"
+// @Param sort_by query string false "recent (default), title, total_time, rating or not_cooked_recently"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} response.Response{data=dto.RecipeListDTO}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/recipes [get]
@@ -676,33 +759,18 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 func (h *RecipeHandler) GetRecipes(c *gin.Context) {
 	logger := appLogger.FromContext(c.Request.Context())
 
-	userIDInterface, exists := c.Get("userID")
-	if !exists {
-		response.Unauthorized(c, "userID não encontrado no contexto")
+	userID, ok := contextUserID(c, "GetRecipes")
+	if !ok {
 		return
 	}
 
-	userID, ok := userIDInterface.(uuid.UUID)
-	if !ok {
-		if userString, ok := userIDInterface.(string); ok {
-			parsed, err := uuid.Parse(userString)
-			if err != nil {
-				logger.Error("Invalid user ID format",
-					zap.String(appLogger.FieldModule, "recipe"),
-					zap.String(appLogger.FieldFunction, "GetRecipes"),
-					zap.Error(err),
-				)
-				response.Unauthorized(c, "user_id inválido no contexto")
-				return
-			}
-			userID = parsed
-		} else {
-			response.Unauthorized(c, "user_id inválido no contexto")
-			return
-		}
+	filter, problem := parseRecipeFilter(c)
+	if problem != "" {
+		response.BadRequest(c, problem)
+		return
 	}
 
-	recipes, err := h.recipeService.GetUserRecipes(c.Request.Context(), userID)
+	recipes, err := h.recipeService.SearchRecipes(c.Request.Context(), userID, filter)
 	if err != nil {
 		logger.Error("Failed to get user recipes",
 			zap.String(appLogger.FieldModule, "recipe"),
@@ -714,24 +782,11 @@ func (h *RecipeHandler) GetRecipes(c *gin.Context) {
 		return
 	}
 
-	if len(recipes) == 0 {
-		logger.Info("No recipes found for user",
-			zap.String(appLogger.FieldModule, "recipe"),
-			zap.String(appLogger.FieldFunction, "GetRecipes"),
-			zap.String(appLogger.FieldUserID, userID.String()),
-		)
-		response.OK(c, map[string]interface{}{
-			"message": "Nenhuma receita encontrada.",
-			"recipes": []interface{}{},
-		})
-		return
-	}
-
 	logger.Info("User recipes retrieved successfully",
 		zap.String(appLogger.FieldModule, "recipe"),
 		zap.String(appLogger.FieldFunction, "GetRecipes"),
 		zap.String(appLogger.FieldUserID, userID.String()),
-		zap.Int(appLogger.FieldCount, len(recipes)),
+		zap.Int(appLogger.FieldCount, len(recipes.Recipes)),
 	)
 
 	response.OK(c, recipes)
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// ImportRecipe godoc
+// @Summary Import a recipe from a web page
+// @Description Read the schema.org recipe (JSON-LD or microdata) of a web page, given as HTML or as a URL to fetch, splitting each ingredient line into amount, unit and name. The recipe is saved unless preview is true
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param page body dto.ImportRecipeDTO true "Page URL or HTML"
+// @Success 200 {object} response.Response{data=dto.ImportedRecipeDTO}
+// @Success 201 {object} response.Response{data=dto.ImportedRecipeDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/import [post]
+// @Security BearerAuth
+func (h *RecipeHandler) ImportRecipe(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	userID, ok := contextUserID(c, "ImportRecipe")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.ImportRecipeDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	imported, err := h.recipeService.ImportRecipe(c.Request.Context(), userID, &input)
+	if err != nil {
+		logger.Warn("Failed to import recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ImportRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	if imported.Saved {
+		response.Success(c, http.StatusCreated, imported)
+		return
+	}
+	response.OK(c, imported)
+}
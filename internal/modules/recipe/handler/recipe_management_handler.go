@@ -0,0 +1,415 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// UpdateRecipe godoc
+// @Summary Update a saved recipe
+// @Description Update the informed fields of a recipe saved by the logged-in user. Fields left out are kept
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param recipe body dto.UpdateRecipeDTO true "Fields to update"
+// @Success 200 {object} response.Response{data=dto.RecipeDetailDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id} [patch]
+// @Security BearerAuth
+func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "UpdateRecipe")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.UpdateRecipeDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid recipe update request",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "UpdateRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	recipe, err := h.recipeService.UpdateRecipe(c.Request.Context(), recipeID, userID, &input)
+	if err != nil {
+		logger.Error("Failed to update recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "UpdateRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, recipe)
+}
+
+// DeleteRecipe godoc
+// @Summary Delete a saved recipe
+// @Description Delete a recipe saved by the logged-in user
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id} [delete]
+// @Security BearerAuth
+func (h *RecipeHandler) DeleteRecipe(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "DeleteRecipe")
+	if !ok {
+		return
+	}
+
+	if err := h.recipeService.DeleteRecipe(c.Request.Context(), recipeID, userID); err != nil {
+		logger.Error("Failed to delete recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "DeleteRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, map[string]interface{}{
+		"message": "Receita removida com sucesso.",
+	})
+}
+
+// SetRecipeTags godoc
+// @Summary Replace the tags of a recipe
+// @Description Replace the tags of a saved recipe. Tags are lowercased and deduplicated; a recipe has at most 20 tags of up to 40 characters
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param tags body dto.SetRecipeTagsDTO true "Tags"
+// @Success 200 {object} response.Response{data=dto.RecipeDetailDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/tags [put]
+// @Security BearerAuth
+func (h *RecipeHandler) SetRecipeTags(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "SetRecipeTags")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.SetRecipeTagsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	recipe, err := h.recipeService.SetRecipeTags(c.Request.Context(), recipeID, userID, input.Tags)
+	if err != nil {
+		logger.Error("Failed to set recipe tags",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SetRecipeTags"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, recipe)
+}
+
+// SetRecipeFavorite godoc
+// @Summary Mark or unmark a recipe as favorite
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param favorite body dto.SetRecipeFavoriteDTO true "Favorite flag"
+// @Success 200 {object} response.Response{data=dto.RecipeDetailDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/favorite [put]
+// @Security BearerAuth
+func (h *RecipeHandler) SetRecipeFavorite(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "SetRecipeFavorite")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.SetRecipeFavoriteDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	recipe, err := h.recipeService.SetRecipeFavorite(c.Request.Context(), recipeID, userID, input.Favorite)
+	if err != nil {
+		logger.Error("Failed to set recipe favorite",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SetRecipeFavorite"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, recipe)
+}
+
+// SetRecipeRating godoc
+// @Summary Rate a recipe
+// @Description Rate a saved recipe from 1 to 5 stars. A null rating clears it
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param rating body dto.SetRecipeRatingDTO true "Rating"
+// @Success 200 {object} response.Response{data=dto.RecipeDetailDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/rating [put]
+// @Security BearerAuth
+func (h *RecipeHandler) SetRecipeRating(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "SetRecipeRating")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.SetRecipeRatingDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	recipe, err := h.recipeService.SetRecipeRating(c.Request.Context(), recipeID, userID, input.Rating)
+	if err != nil {
+		logger.Error("Failed to set recipe rating",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SetRecipeRating"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, recipe)
+}
+
+// SetRecipeNotes godoc
+// @Summary Replace the notes of a recipe
+// @Description Replace the personal notes of a saved recipe and the list of modifications made to it, such as "less sugar" or "baked 10 minutes more"
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param notes body dto.SetRecipeNotesDTO true "Notes and modifications"
+// @Success 200 {object} response.Response{data=dto.RecipeDetailDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/notes [put]
+// @Security BearerAuth
+func (h *RecipeHandler) SetRecipeNotes(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "SetRecipeNotes")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.SetRecipeNotesDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	recipe, err := h.recipeService.SetRecipeNotes(c.Request.Context(), recipeID, userID, &input)
+	if err != nil {
+		logger.Error("Failed to set recipe notes",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SetRecipeNotes"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, recipe)
+}
+
+// ListRecipeTags godoc
+// @Summary List recipe tags
+// @Description List the tags used by the recipes of the logged-in user with how many recipes use each one
+// @Tags recipes
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.RecipeTagDTO}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/tags [get]
+// @Security BearerAuth
+func (h *RecipeHandler) ListRecipeTags(c *gin.Context) {
+	userID, ok := contextUserID(c, "ListRecipeTags")
+	if !ok {
+		return
+	}
+
+	tags, err := h.recipeService.ListRecipeTags(c.Request.Context(), userID)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, tags)
+}
+
+// parseRecipeFilter reads the recipe filter from the query string. Tags may
+// be repeated or separated by commas.
+func parseRecipeFilter(c *gin.Context) (recipeDTO.RecipeFilterDTO, string) {
+	filter := recipeDTO.RecipeFilterDTO{
+		Query:              c.Query("q"),
+		Cuisine:            c.Query("cuisine"),
+		MealType:           c.Query("meal_type"),
+		Difficulty:         c.Query("difficulty"),
+		DietaryRestriction: c.Query("dietary"),
+		SortBy:             c.Query("sort_by"),
+	}
+
+	for _, raw := range c.QueryArray("tags") {
+		for _, tag := range strings.Split(raw, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	var err error
+	if filter.MaxTotalTime, err = queryInt(c, "max_total_time"); err != nil {
+		return filter, "max_total_time deve ser um número inteiro"
+	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return filter, "limit deve ser um número inteiro"
+	}
+	if limit != nil {
+		filter.Limit = *limit
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return filter, "offset deve ser um número inteiro"
+	}
+	if offset != nil {
+		filter.Offset = *offset
+	}
+
+	if raw := strings.TrimSpace(c.Query("favorite")); raw != "" {
+		favorite, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, "favorite deve ser true ou false"
+		}
+		filter.Favorite = &favorite
+	}
+
+	return filter, ""
+}
+
+func queryInt(c *gin.Context, name string) (*int, error) {
+	raw := strings.TrimSpace(c.Query(name))
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// recipeRequestIDs reads the recipe ID from the path and the user ID from the
+// context, answering the request when one of them is invalid.
+func recipeRequestIDs(c *gin.Context, function string) (uuid.UUID, uuid.UUID, bool) {
+	recipeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		appLogger.FromContext(c.Request.Context()).Warn("Invalid recipe ID format",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String("recipe_id", c.Param("id")),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "ID da receita inválido: "+err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, ok := contextUserID(c, function)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return recipeID, userID, true
+}
+
+func contextUserID(c *gin.Context, function string) (uuid.UUID, bool) {
+	userIDInterface, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "userID não encontrado no contexto")
+		return uuid.Nil, false
+	}
+
+	switch value := userIDInterface.(type) {
+	case uuid.UUID:
+		return value, true
+	case string:
+		parsed, err := uuid.Parse(value)
+		if err == nil {
+			return parsed, true
+		}
+		appLogger.FromContext(c.Request.Context()).Error("Invalid user ID format",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.Error(err),
+		)
+	}
+	response.Unauthorized(c, "user_id inválido no contexto")
+	return uuid.Nil, false
+}
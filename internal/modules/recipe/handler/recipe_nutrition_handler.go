@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// CalculateRecipeNutrition godoc
+// @Summary Calculate the nutrition of a saved recipe
+// @Description Match the ingredients of the recipe to the food composition table and compute calories, protein, carbohydrates, fat and fiber per serving, with a confidence for each match. The recipe nutrition is replaced when at least one ingredient is found
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} response.Response{data=dto.RecipeNutritionEstimateDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/nutrition [post]
+// @Security BearerAuth
+func (h *RecipeHandler) CalculateRecipeNutrition(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "CalculateRecipeNutrition")
+	if !ok {
+		return
+	}
+
+	result, err := h.recipeService.CalculateRecipeNutrition(c.Request.Context(), recipeID, userID)
+	if err != nil {
+		logger.Warn("Failed to calculate recipe nutrition",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CalculateRecipeNutrition"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
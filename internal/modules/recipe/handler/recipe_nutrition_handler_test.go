@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+)
+
+// stubRecipeService answers only the calls a test sets up; the other methods
+// of the interface panic.
+type stubRecipeService struct {
+	recipeDomain.RecipeService
+	calculateNutritionFn func(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) (*recipeDTO.RecipeNutritionEstimateDTO, error)
+}
+
+func (s *stubRecipeService) CalculateRecipeNutrition(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) (*recipeDTO.RecipeNutritionEstimateDTO, error) {
+	return s.calculateNutritionFn(ctx, recipeID, userID)
+}
+
+func TestRecipeHandler_CalculateRecipeNutrition_PassesRecipeAndUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recipeID, userID := uuid.New(), uuid.New()
+	var gotRecipeID, gotUserID uuid.UUID
+	h := &RecipeHandler{recipeService: &stubRecipeService{
+		calculateNutritionFn: func(ctx context.Context, recipe uuid.UUID, user uuid.UUID) (*recipeDTO.RecipeNutritionEstimateDTO, error) {
+			gotRecipeID, gotUserID = recipe, user
+			return &recipeDTO.RecipeNutritionEstimateDTO{RecipeID: recipe.String()}, nil
+		},
+	}}
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/recipes/"+recipeID.String()+"/nutrition", nil)
+	c.Params = gin.Params{{Key: "id", Value: recipeID.String()}}
+	c.Set("userID", userID)
+
+	h.CalculateRecipeNutrition(c)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if gotRecipeID != recipeID || gotUserID != userID {
+		t.Fatalf("expected recipe %s of user %s, got recipe %s of user %s", recipeID, userID, gotRecipeID, gotUserID)
+	}
+}
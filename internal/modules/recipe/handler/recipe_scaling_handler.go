@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// ScaleRecipe godoc
+// @Summary Scale a recipe
+// @Description Return a saved recipe scaled to the servings. Amounts are rounded to what can be measured (eggs to whole units) and normalized (1000 g becomes 1 kg), and the time of hands-on steps is adjusted. Nothing is saved
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param servings query int true "Servings (1 to 100)"
+// @Success 200 {object} response.Response{data=dto.ScaledRecipeDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/scale [get]
+// @Security BearerAuth
+func (h *RecipeHandler) ScaleRecipe(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "ScaleRecipe")
+	if !ok {
+		return
+	}
+
+	servings, err := queryInt(c, "servings")
+	if err != nil || servings == nil {
+		logger.Warn("Invalid recipe scaling request",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ScaleRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("servings", c.Query("servings")),
+		)
+		response.BadRequest(c, "Parâmetro servings inválido")
+		return
+	}
+
+	scaled, err := h.recipeService.ScaleRecipe(c.Request.Context(), recipeID, userID, *servings)
+	if err != nil {
+		logger.Error("Failed to scale recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ScaleRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Int("servings", *servings),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, scaled)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// SearchRecipesByIngredients godoc
+// @Summary Search saved recipes by ingredients
+// @Description Rank the saved recipes by the share of their ingredients found in a list or in the current stock of a pantry, listing the missing ingredients of each. Ingredients without amount ("a gosto") are not counted
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param search body dto.IngredientSearchDTO true "Ingredients, pantry and filters"
+// @Success 200 {object} response.Response{data=[]dto.IngredientSearchResultDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/search/ingredients [post]
+// @Security BearerAuth
+func (h *RecipeHandler) SearchRecipesByIngredients(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	userID, ok := contextUserID(c, "SearchRecipesByIngredients")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.IngredientSearchDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	results, err := h.recipeService.SearchRecipesByIngredients(c.Request.Context(), userID, &input)
+	if err != nil {
+		logger.Warn("Failed to search recipes by ingredients",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SearchRecipesByIngredients"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, results)
+}
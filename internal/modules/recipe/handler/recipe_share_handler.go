@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type RecipeShareHandler struct {
+	shareService recipeDomain.RecipeShareService
+}
+
+func NewRecipeShareHandler(shareService recipeDomain.RecipeShareService) *RecipeShareHandler {
+	return &RecipeShareHandler{shareService: shareService}
+}
+
+// CreateShare godoc
+// @Summary Share a recipe
+// @Description Create a public read-only link to a saved recipe. Without expires_in_hours the link works until it is revoked
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param share body dto.CreateRecipeShareDTO false "Share options"
+// @Success 201 {object} response.Response{data=dto.RecipeShareDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/shares [post]
+// @Security BearerAuth
+func (h *RecipeShareHandler) CreateShare(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "CreateShare")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.CreateRecipeShareDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+			return
+		}
+	}
+
+	share, err := h.shareService.CreateShare(c.Request.Context(), userID, recipeID, &input)
+	if err != nil {
+		logger.Warn("Failed to share recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CreateShare"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, share)
+}
+
+// ListShares godoc
+// @Summary List the links of a recipe
+// @Description List the public links created for a saved recipe, most recent first, including revoked and expired ones
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} response.Response{data=[]dto.RecipeShareDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/shares [get]
+// @Security BearerAuth
+func (h *RecipeShareHandler) ListShares(c *gin.Context) {
+	recipeID, userID, ok := recipeRequestIDs(c, "ListShares")
+	if !ok {
+		return
+	}
+
+	shares, err := h.shareService.ListShares(c.Request.Context(), userID, recipeID)
+	if err != nil {
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.OK(c, shares)
+}
+
+// RevokeShare godoc
+// @Summary Revoke a recipe link
+// @Description Stop a public link to a recipe from working. Copies already made are kept
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param shareId path string true "Share ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/shares/{shareId} [delete]
+// @Security BearerAuth
+func (h *RecipeShareHandler) RevokeShare(c *gin.Context) {
+	recipeID, userID, ok := recipeRequestIDs(c, "RevokeShare")
+	if !ok {
+		return
+	}
+
+	shareID, err := uuid.Parse(c.Param("shareId"))
+	if err != nil {
+		response.BadRequest(c, "ID do compartilhamento inválido: "+err.Error())
+		return
+	}
+
+	if err := h.shareService.RevokeShare(c.Request.Context(), userID, recipeID, shareID); err != nil {
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"message": "Link da receita revogado"})
+}
+
+// GetSharedRecipe godoc
+// @Summary View a shared recipe
+// @Description Read a recipe through its public link, without authentication
+// @Tags recipes
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} response.Response{data=dto.SharedRecipeDTO}
+// @Failure 404 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/public/recipes/{token} [get]
+func (h *RecipeShareHandler) GetSharedRecipe(c *gin.Context) {
+	recipe, err := h.shareService.GetSharedRecipe(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.OK(c, recipe)
+}
+
+// CopySharedRecipe godoc
+// @Summary Copy a shared recipe
+// @Description Save a copy of a shared recipe in the collection of the user, keeping the original recipe and author as attribution
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param copy body dto.CopySharedRecipeDTO true "Share link or token"
+// @Success 201 {object} response.Response{data=dto.RecipeDetailDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/shared/copy [post]
+// @Security BearerAuth
+func (h *RecipeShareHandler) CopySharedRecipe(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	userID, ok := contextUserID(c, "CopySharedRecipe")
+	if !ok {
+		return
+	}
+
+	var input recipeDTO.CopySharedRecipeDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, "Dados de entrada inválidos: "+err.Error())
+		return
+	}
+
+	recipe, err := h.shareService.CopySharedRecipe(c.Request.Context(), userID, input.Link)
+	if err != nil {
+		logger.Warn("Failed to copy shared recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CopySharedRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		handleRecipeError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, recipe)
+}
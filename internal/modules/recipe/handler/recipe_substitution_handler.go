@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// SuggestSubstitutions godoc
+// @Summary Suggest ingredient substitutions
+// @Description List the ingredients of a saved recipe the pantry lacks, with the substitutes found in the pantry for each one. Substitutes come from a table of common Brazilian swaps with their ratios and respect the dietary restrictions of the user profile and of the recipe. Nothing is changed
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param pantry_id query string true "Pantry ID"
+// @Param servings query int false "Servings (1 to 100); defaults to the servings of the recipe"
+// @Success 200 {object} response.Response{data=dto.RecipeSubstitutionsDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/substitutions [get]
+// @Security BearerAuth
+func (h *RecipeHandler) SuggestSubstitutions(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "SuggestSubstitutions")
+	if !ok {
+		return
+	}
+
+	input := &dto.CookRecipeDTO{PantryID: c.Query("pantry_id")}
+	servings, err := queryInt(c, "servings")
+	if err != nil {
+		logger.Warn("Invalid substitution request",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SuggestSubstitutions"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("servings", c.Query("servings")),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Parâmetro servings inválido")
+		return
+	}
+	if servings != nil {
+		input.Servings = *servings
+	}
+
+	substitutions, err := h.recipeService.SuggestSubstitutions(c.Request.Context(), recipeID, userID, input)
+	if err != nil {
+		logger.Error("Failed to suggest substitutions",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SuggestSubstitutions"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.String("pantry_id", input.PantryID),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, substitutions)
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// ListRecipeVersions godoc
+// @Summary List the versions of a recipe
+// @Description List the versions of the content of a saved recipe, the latest first. Each edit that changes the content creates a version with its author and time
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} response.Response{data=[]dto.RecipeVersionSummaryDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/versions [get]
+// @Security BearerAuth
+func (h *RecipeHandler) ListRecipeVersions(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "ListRecipeVersions")
+	if !ok {
+		return
+	}
+
+	versions, err := h.recipeService.ListRecipeVersions(c.Request.Context(), recipeID, userID)
+	if err != nil {
+		logger.Error("Failed to list recipe versions",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ListRecipeVersions"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, versions)
+}
+
+// GetRecipeVersion godoc
+// @Summary Get a version of a recipe
+// @Description Return the content of a saved recipe at a version
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param version path int true "Version"
+// @Success 200 {object} response.Response{data=dto.RecipeVersionDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/versions/{version} [get]
+// @Security BearerAuth
+func (h *RecipeHandler) GetRecipeVersion(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "GetRecipeVersion")
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		logger.Warn("Invalid recipe version",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GetRecipeVersion"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("version", c.Param("version")),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Versão inválida")
+		return
+	}
+
+	result, err := h.recipeService.GetRecipeVersion(c.Request.Context(), recipeID, userID, version)
+	if err != nil {
+		logger.Error("Failed to get recipe version",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GetRecipeVersion"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Int("version", version),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// DiffRecipeVersions godoc
+// @Summary Compare two versions of a recipe
+// @Description List what changed from one version of a saved recipe to another: ingredients added, removed or with another amount, steps added, removed or rewritten, and the other fields
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param from query int true "Older version"
+// @Param to query int false "Newer version; defaults to the current one"
+// @Success 200 {object} response.Response{data=dto.RecipeVersionDiffDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/versions/diff [get]
+// @Security BearerAuth
+func (h *RecipeHandler) DiffRecipeVersions(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "DiffRecipeVersions")
+	if !ok {
+		return
+	}
+	from, err := queryInt(c, "from")
+	if err != nil || from == nil {
+		logger.Warn("Invalid recipe version diff request",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "DiffRecipeVersions"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("from", c.Query("from")),
+		)
+		response.BadRequest(c, "Parâmetro from inválido")
+		return
+	}
+	to, err := queryInt(c, "to")
+	if err != nil || (to != nil && *to < 1) {
+		logger.Warn("Invalid recipe version diff request",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "DiffRecipeVersions"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("to", c.Query("to")),
+		)
+		response.BadRequest(c, "Parâmetro to inválido")
+		return
+	}
+	newer := 0
+	if to != nil {
+		newer = *to
+	}
+
+	diff, err := h.recipeService.DiffRecipeVersions(c.Request.Context(), recipeID, userID, *from, newer)
+	if err != nil {
+		logger.Error("Failed to compare recipe versions",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "DiffRecipeVersions"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Int("from", *from),
+			zap.Int("to", newer),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, diff)
+}
+
+// RestoreRecipeVersion godoc
+// @Summary Restore a version of a recipe
+// @Description Bring back the content of an old version of a saved recipe. The restored content becomes a new version, so the history is kept. Tags, favorite, rating, notes and cooking history are not changed
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param version path int true "Version to restore"
+// @Success 200 {object} response.Response{data=dto.RecipeDetailDTO}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/recipes/{id}/versions/{version}/restore [post]
+// @Security BearerAuth
+func (h *RecipeHandler) RestoreRecipeVersion(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	recipeID, userID, ok := recipeRequestIDs(c, "RestoreRecipeVersion")
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		logger.Warn("Invalid recipe version",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "RestoreRecipeVersion"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("version", c.Param("version")),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Versão inválida")
+		return
+	}
+
+	recipe, err := h.recipeService.RestoreRecipeVersion(c.Request.Context(), recipeID, userID, version)
+	if err != nil {
+		logger.Error("Failed to restore recipe version",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "RestoreRecipeVersion"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Int("version", version),
+			zap.Error(err),
+		)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response.OK(c, recipe)
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MealPlanEntry is a recipe planned for a meal of a day in a pantry. The
+// recipe belongs to the user who planned it.
+type MealPlanEntry struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	PantryID  uuid.UUID `gorm:"type:uuid;not null;index:idx_meal_plan_pantry_date,priority:1" json:"pantry_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	RecipeID  uuid.UUID `gorm:"type:uuid;not null;index" json:"recipe_id"`
+	Date      time.Time `gorm:"type:date;not null;index:idx_meal_plan_pantry_date,priority:2" json:"date"`
+	MealType  string    `gorm:"type:varchar(50);not null" json:"meal_type"`
+	Servings  int       `gorm:"not null" json:"servings"`
+	Notes     string    `gorm:"type:text" json:"notes"`
+	Generated bool      `gorm:"not null;default:false" json:"generated"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (e *MealPlanEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"e": e, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*MealPlanEntry.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*MealPlanEntry.BeforeCreate"), zap.Any("params", __logParams))
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
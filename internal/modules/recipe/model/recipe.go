@@ -9,28 +9,51 @@ import (
 	"gorm.io/gorm"
 )
 
-// Recipe represents a saved recipe in the database
+// Recipe represents a saved recipe in the database. Version is the number of
+// the current version of its content, 0 for recipes saved before versions
+// existed and not edited since. Versions holds the new versions to be written
+// together with the recipe; they are not loaded with it.
 type Recipe struct {
-	ID                  uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID              uuid.UUID              `gorm:"type:uuid;not null;index" json:"user_id"`
-	Title               string                 `gorm:"type:varchar(255);not null" json:"title"`
-	Description         string                 `gorm:"type:text" json:"description"`
-	Ingredients         RecipeIngredientsJSON  `gorm:"type:jsonb;not null" json:"ingredients"`
-	Instructions        RecipeInstructionsJSON `gorm:"type:jsonb;not null" json:"instructions"`
-	CookingTime         *int                   `gorm:"type:int" json:"cooking_time"`
-	PreparationTime     *int                   `gorm:"type:int" json:"preparation_time"`
-	TotalTime           *int                   `gorm:"type:int" json:"total_time"`
-	ServingSize         *int                   `gorm:"type:int" json:"serving_size"`
-	Difficulty          string                 `gorm:"type:varchar(50)" json:"difficulty"`
-	MealType            string                 `gorm:"type:varchar(50)" json:"meal_type"`
-	Cuisine             string                 `gorm:"type:varchar(100)" json:"cuisine"`
-	DietaryRestrictions RecipeDietaryJSON      `gorm:"type:jsonb" json:"dietary_restrictions"`
-	NutritionInfo       RecipeNutritionJSON    `gorm:"type:jsonb" json:"nutrition_info"`
-	Tips                RecipeTipsJSON         `gorm:"type:jsonb" json:"tips"`
-	GeneratedAt         time.Time              `gorm:"type:timestamp with time zone;not null" json:"generated_at"`
-	CreatedAt           time.Time              `gorm:"type:timestamp with time zone;not null;default:now()" json:"created_at"`
-	UpdatedAt           time.Time              `gorm:"type:timestamp with time zone;not null;default:now()" json:"updated_at"`
-	DeletedAt           gorm.DeletedAt         `gorm:"index" json:"-"`
+	ID                  uuid.UUID               `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID              uuid.UUID               `gorm:"type:uuid;not null;index" json:"user_id"`
+	Title               string                  `gorm:"type:varchar(255);not null" json:"title"`
+	Description         string                  `gorm:"type:text" json:"description"`
+	Ingredients         RecipeIngredientsJSON   `gorm:"type:jsonb;not null" json:"ingredients"`
+	Instructions        RecipeInstructionsJSON  `gorm:"type:jsonb;not null" json:"instructions"`
+	CookingTime         *int                    `gorm:"type:int" json:"cooking_time"`
+	PreparationTime     *int                    `gorm:"type:int" json:"preparation_time"`
+	TotalTime           *int                    `gorm:"type:int" json:"total_time"`
+	ServingSize         *int                    `gorm:"type:int" json:"serving_size"`
+	Difficulty          string                  `gorm:"type:varchar(50)" json:"difficulty"`
+	MealType            string                  `gorm:"type:varchar(50)" json:"meal_type"`
+	Cuisine             string                  `gorm:"type:varchar(100)" json:"cuisine"`
+	DietaryRestrictions RecipeDietaryJSON       `gorm:"type:jsonb" json:"dietary_restrictions"`
+	NutritionInfo       RecipeNutritionJSON     `gorm:"type:jsonb" json:"nutrition_info"`
+	Tips                RecipeTipsJSON          `gorm:"type:jsonb" json:"tips"`
+	Tags                RecipeTagsJSON          `gorm:"type:jsonb" json:"tags"`
+	Favorite            bool                    `gorm:"not null;default:false;index" json:"favorite"`
+	Rating              *int                    `gorm:"type:smallint;index" json:"rating"`
+	Notes               string                  `gorm:"type:text" json:"notes"`
+	Modifications       RecipeModificationsJSON `gorm:"type:jsonb" json:"modifications"`
+	LastCookedAt        *time.Time              `gorm:"type:timestamp with time zone;index" json:"last_cooked_at"`
+	TimesCooked         int                     `gorm:"not null;default:0" json:"times_cooked"`
+	SourceURL           string                  `gorm:"type:text" json:"source_url"`
+	OriginRecipeID      *uuid.UUID              `gorm:"type:uuid;index" json:"origin_recipe_id"`
+	OriginAuthor        string                  `gorm:"type:varchar(255)" json:"origin_author"`
+	Version             int                     `gorm:"not null;default:0" json:"version"`
+	Versions            []RecipeVersion         `gorm:"foreignKey:RecipeID" json:"-"`
+	GeneratedAt         time.Time               `gorm:"type:timestamp with time zone;not null" json:"generated_at"`
+	CreatedAt           time.Time               `gorm:"type:timestamp with time zone;not null;default:now()" json:"created_at"`
+	UpdatedAt           time.Time               `gorm:"type:timestamp with time zone;not null;default:now()" json:"updated_at"`
+	DeletedAt           gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// ServingCount is the number of servings the recipe makes, 1 when unknown.
+func (r *Recipe) ServingCount() int {
+	if r.ServingSize != nil && *r.ServingSize > 0 {
+		return *r.ServingSize
+	}
+	return 1
 }
 
 // RecipeIngredient represents an ingredient in a recipe
@@ -49,12 +72,15 @@ type RecipeInstruction struct {
 	Time        *int   `json:"time,omitempty"`
 }
 
-// RecipeNutrition represents nutritional information
+// RecipeNutrition represents nutritional information per serving. Confidence
+// is set when the values were computed from the food composition table.
 type RecipeNutrition struct {
-	Calories      *int `json:"calories,omitempty"`
-	Protein       *int `json:"protein,omitempty"`
-	Carbohydrates *int `json:"carbohydrates,omitempty"`
-	Fat           *int `json:"fat,omitempty"`
+	Calories      *int     `json:"calories,omitempty"`
+	Protein       *int     `json:"protein,omitempty"`
+	Carbohydrates *int     `json:"carbohydrates,omitempty"`
+	Fat           *int     `json:"fat,omitempty"`
+	Fiber         *int     `json:"fiber,omitempty"`
+	Confidence    *float64 `json:"confidence,omitempty"`
 }
 
 // Custom JSON types for GORM
@@ -62,6 +88,8 @@ type RecipeIngredientsJSON []RecipeIngredient
 type RecipeInstructionsJSON []RecipeInstruction
 type RecipeDietaryJSON []string
 type RecipeTipsJSON []string
+type RecipeTagsJSON []string
+type RecipeModificationsJSON []string
 type RecipeNutritionJSON RecipeNutrition
 
 // Scan implements the sql.Scanner interface for RecipeIngredientsJSON
@@ -148,6 +176,48 @@ func (r RecipeTipsJSON) Value() (driver.Value, error) {
 	return json.Marshal(r)
 }
 
+// Scan implements the sql.Scanner interface for RecipeTagsJSON
+func (r *RecipeTagsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*r = []string{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// Value implements the driver.Valuer interface for RecipeTagsJSON
+func (r RecipeTagsJSON) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for RecipeModificationsJSON
+func (r *RecipeModificationsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*r = []string{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// Value implements the driver.Valuer interface for RecipeModificationsJSON
+func (r RecipeModificationsJSON) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(r)
+}
+
 // Scan implements the sql.Scanner interface for RecipeNutritionJSON
 func (r *RecipeNutritionJSON) Scan(value interface{}) error {
 	if value == nil {
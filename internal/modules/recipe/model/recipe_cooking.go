@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RecipeCooking records a recipe cooked together with the quantities it took
+// from the pantry items. PantryID is nil when the cooking was only logged,
+// without taking anything from a pantry. RecipeVersion is the version of the
+// recipe that was cooked.
+type RecipeCooking struct {
+	ID            uuid.UUID                `gorm:"type:uuid;primary_key" json:"id"`
+	RecipeID      uuid.UUID                `gorm:"type:uuid;not null;index" json:"recipe_id"`
+	RecipeVersion int                      `gorm:"not null;default:0" json:"recipe_version"`
+	UserID        uuid.UUID                `gorm:"type:uuid;not null;index" json:"user_id"`
+	PantryID      *uuid.UUID               `gorm:"type:uuid;index" json:"pantry_id"`
+	Servings      int                      `gorm:"not null" json:"servings"`
+	CookedAt      time.Time                `gorm:"not null;index" json:"cooked_at"`
+	Deductions    []RecipeCookingDeduction `gorm:"foreignKey:CookingID" json:"deductions"`
+	CreatedAt     time.Time                `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// RecipeCookingDeduction is the quantity one ingredient took from one pantry
+// item, in the unit of the item. PreviousQuantity is the stock before it.
+type RecipeCookingDeduction struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	CookingID        uuid.UUID `gorm:"type:uuid;not null;index" json:"cooking_id"`
+	PantryItemID     uuid.UUID `gorm:"type:uuid;not null;index" json:"pantry_item_id"`
+	Ingredient       string    `gorm:"not null" json:"ingredient"`
+	Name             string    `gorm:"not null" json:"name"`
+	Quantity         float64   `gorm:"not null" json:"quantity"`
+	Unit             string    `json:"unit"`
+	PreviousQuantity float64   `json:"previous_quantity"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (c *RecipeCooking) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"c": c, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*RecipeCooking.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*RecipeCooking.BeforeCreate"), zap.Any("params", __logParams))
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+func (d *RecipeCookingDeduction) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"d": d, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*RecipeCookingDeduction.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*RecipeCookingDeduction.BeforeCreate"), zap.Any("params", __logParams))
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RecipeShare is a public read-only link to a recipe. The link stops working
+// once it is revoked or, when it has an expiration, once it expires.
+type RecipeShare struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	RecipeID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"recipe_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Token     string     `gorm:"not null;uniqueIndex" json:"token"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (s *RecipeShare) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"s": s, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*RecipeShare.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*RecipeShare.BeforeCreate"), zap.Any("params", __logParams))
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// Active reports whether the link can still be used.
+func (s *RecipeShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || now.Before(*s.ExpiresAt))
+}
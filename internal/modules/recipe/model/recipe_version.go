@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RecipeVersion is an immutable snapshot of the content of a recipe, taken
+// each time an edit changes it. AuthorID is the user who made the edit and
+// RestoredFrom the version it brought back, when it is a restore.
+type RecipeVersion struct {
+	ID                  uuid.UUID              `gorm:"type:uuid;primary_key" json:"id"`
+	RecipeID            uuid.UUID              `gorm:"type:uuid;not null;uniqueIndex:idx_recipe_version" json:"recipe_id"`
+	Version             int                    `gorm:"not null;uniqueIndex:idx_recipe_version" json:"version"`
+	AuthorID            uuid.UUID              `gorm:"type:uuid;not null;index" json:"author_id"`
+	RestoredFrom        *int                   `json:"restored_from"`
+	Title               string                 `gorm:"type:varchar(255);not null" json:"title"`
+	Description         string                 `gorm:"type:text" json:"description"`
+	Ingredients         RecipeIngredientsJSON  `gorm:"type:jsonb;not null" json:"ingredients"`
+	Instructions        RecipeInstructionsJSON `gorm:"type:jsonb;not null" json:"instructions"`
+	CookingTime         *int                   `gorm:"type:int" json:"cooking_time"`
+	PreparationTime     *int                   `gorm:"type:int" json:"preparation_time"`
+	TotalTime           *int                   `gorm:"type:int" json:"total_time"`
+	ServingSize         *int                   `gorm:"type:int" json:"serving_size"`
+	Difficulty          string                 `gorm:"type:varchar(50)" json:"difficulty"`
+	MealType            string                 `gorm:"type:varchar(50)" json:"meal_type"`
+	Cuisine             string                 `gorm:"type:varchar(100)" json:"cuisine"`
+	DietaryRestrictions RecipeDietaryJSON      `gorm:"type:jsonb" json:"dietary_restrictions"`
+	Tips                RecipeTipsJSON         `gorm:"type:jsonb" json:"tips"`
+	CreatedAt           time.Time              `gorm:"type:timestamp with time zone;not null" json:"created_at"`
+}
+
+func (v *RecipeVersion) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"v": v, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*RecipeVersion.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*RecipeVersion.BeforeCreate"), zap.Any("params", __logParams))
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return
+}
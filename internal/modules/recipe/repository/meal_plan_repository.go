@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mealPlanRepository struct {
+	db *gorm.DB
+}
+
+func NewMealPlanRepository(db *gorm.DB) (result0 domain.MealPlanRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewMealPlanRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewMealPlanRepository"), zap.Any("params", __logParams))
+	result0 = &mealPlanRepository{db: db}
+	return
+}
+
+func (r *mealPlanRepository) Create(ctx context.Context, entry *model.MealPlanEntry) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "entry": entry}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*mealPlanRepository.Create"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*mealPlanRepository.Create"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Create(entry).Error
+	return
+}
+
+func (r *mealPlanRepository) CreateMany(ctx context.Context, entries []*model.MealPlanEntry) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "entries": entries}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*mealPlanRepository.CreateMany"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*mealPlanRepository.CreateMany"), zap.Any("params", __logParams))
+	if len(entries) == 0 {
+		result0 = nil
+		return
+	}
+	result0 = r.db.WithContext(ctx).Create(entries).Error
+	return
+}
+
+func (r *mealPlanRepository) FindByID(ctx context.Context, id uuid.UUID) (result0 *model.MealPlanEntry, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "id": id}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*mealPlanRepository.FindByID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*mealPlanRepository.FindByID"), zap.Any("params", __logParams))
+	var entry model.MealPlanEntry
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&entry).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*mealPlanRepository.FindByID"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &entry
+	result1 = nil
+	return
+}
+
+func (r *mealPlanRepository) Update(ctx context.Context, entry *model.MealPlanEntry) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "entry": entry}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*mealPlanRepository.Update"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*mealPlanRepository.Update"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Save(entry).Error
+	return
+}
+
+func (r *mealPlanRepository) Delete(ctx context.Context, id uuid.UUID) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "id": id}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*mealPlanRepository.Delete"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*mealPlanRepository.Delete"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.MealPlanEntry{}).Error
+	return
+}
+
+func (r *mealPlanRepository) ListByPantry(ctx context.Context, pantryID uuid.UUID, from time.Time, to time.Time) (result0 []*model.MealPlanEntry, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "pantryID": pantryID, "from": from, "to": to}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*mealPlanRepository.ListByPantry"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*mealPlanRepository.ListByPantry"), zap.Any("params", __logParams))
+	var entries []*model.MealPlanEntry
+	err := r.db.WithContext(ctx).
+		Where("pantry_id = ? AND date >= ? AND date <= ?", pantryID, from, to).
+		Order("date ASC, created_at ASC").
+		Find(&entries).Error
+	result0 = entries
+	result1 = err
+	return
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMealPlanRepositoryListsPantryPeriod(t *testing.T) {
+	db := setupRecipeCookingTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.MealPlanEntry{}))
+	repo := NewMealPlanRepository(db)
+	ctx := context.Background()
+
+	userID, pantryID := uuid.New(), uuid.New()
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, repo.CreateMany(ctx, []*model.MealPlanEntry{
+		{PantryID: pantryID, UserID: userID, RecipeID: uuid.New(), Date: day(2), MealType: "lunch", Servings: 2},
+		{PantryID: pantryID, UserID: userID, RecipeID: uuid.New(), Date: day(8), MealType: "dinner", Servings: 2},
+		{PantryID: pantryID, UserID: userID, RecipeID: uuid.New(), Date: day(9), MealType: "lunch", Servings: 2},
+		{PantryID: uuid.New(), UserID: userID, RecipeID: uuid.New(), Date: day(3), MealType: "lunch", Servings: 2},
+	}))
+
+	entries, err := repo.ListByPantry(ctx, pantryID, day(2), day(8))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, 2, entries[0].Date.Day())
+	require.Equal(t, 8, entries[1].Date.Day())
+
+	moved := entries[1]
+	moved.Date = day(10)
+	require.NoError(t, repo.Update(ctx, moved))
+	require.NoError(t, repo.Delete(ctx, entries[0].ID))
+
+	entries, err = repo.ListByPantry(ctx, pantryID, day(1), day(31))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, 9, entries[0].Date.Day())
+	require.Equal(t, moved.ID, entries[1].ID)
+
+	_, err = repo.FindByID(ctx, uuid.New())
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// stockTolerance absorbs the rounding of quantities stored as numeric.
+const stockTolerance = 1e-9
+
+type recipeCookingRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeCookingRepository(db *gorm.DB) (result0 domain.RecipeCookingRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewRecipeCookingRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewRecipeCookingRepository"), zap.Any("params", __logParams))
+	result0 = &recipeCookingRepository{db: db}
+	return
+}
+
+func (r *recipeCookingRepository) Apply(ctx context.Context, cooking *model.RecipeCooking) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "cooking": cooking}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeCookingRepository.Apply"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeCookingRepository.Apply"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, deduction := range cooking.Deductions {
+			result := tx.Model(&itemModel.Item{}).
+				Where("id = ? AND pantry_id = ? AND quantity >= ?", deduction.PantryItemID, cooking.PantryID, deduction.Quantity-stockTolerance).
+				Update("quantity", gorm.Expr("CASE WHEN quantity > ? THEN quantity - ? ELSE 0 END", deduction.Quantity, deduction.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return domain.ErrStockChanged
+			}
+		}
+		if err := tx.Create(cooking).Error; err != nil {
+			return err
+		}
+		// The recipe keeps its last cooking for sorting the recipe list; a
+		// cooking logged with an earlier date does not move it back.
+		return tx.Model(&model.Recipe{}).
+			Where("id = ? AND user_id = ?", cooking.RecipeID, cooking.UserID).
+			UpdateColumns(map[string]interface{}{
+				"times_cooked":   gorm.Expr("times_cooked + 1"),
+				"last_cooked_at": gorm.Expr("CASE WHEN last_cooked_at IS NULL OR last_cooked_at < ? THEN ? ELSE last_cooked_at END", cooking.CookedAt, cooking.CookedAt),
+			}).Error
+	})
+	return
+}
+
+func (r *recipeCookingRepository) ListByRecipeID(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) (result0 []*model.RecipeCooking, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "recipeID": recipeID, "userID": userID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeCookingRepository.ListByRecipeID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeCookingRepository.ListByRecipeID"), zap.Any("params", __logParams))
+	var cookings []*model.RecipeCooking
+	err := r.db.WithContext(ctx).
+		Preload("Deductions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("recipe_id = ? AND user_id = ?", recipeID, userID).
+		Order("cooked_at DESC").
+		Find(&cookings).Error
+	result0 = cookings
+	result1 = err
+	return
+}
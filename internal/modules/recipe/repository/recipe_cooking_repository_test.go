@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRecipeCookingTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// Every connection to :memory: opens a new database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(
+		&itemModel.Item{},
+		&model.RecipeCooking{},
+		&model.RecipeCookingDeduction{},
+	))
+	// The recipe defaults only exist in postgres; the cooking only updates
+	// the cooking columns of the recipe.
+	require.NoError(t, db.Exec(`CREATE TABLE recipes (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		last_cooked_at DATETIME,
+		times_cooked INTEGER NOT NULL DEFAULT 0,
+		deleted_at DATETIME
+	)`).Error)
+
+	return db
+}
+
+func TestRecipeCookingRepositoryApplyDeductsAndRecords(t *testing.T) {
+	db := setupRecipeCookingTestDB(t)
+	repo := NewRecipeCookingRepository(db)
+	ctx := context.Background()
+
+	userID, pantryID, recipeID := uuid.New(), uuid.New(), uuid.New()
+	rice := &itemModel.Item{ID: uuid.New(), PantryID: pantryID, AddedBy: userID, Name: "Arroz", Quantity: 1, Unit: "kg"}
+	eggs := &itemModel.Item{ID: uuid.New(), PantryID: pantryID, AddedBy: userID, Name: "Ovo", Quantity: 2, Unit: "un"}
+	require.NoError(t, db.Create(rice).Error)
+	require.NoError(t, db.Create(eggs).Error)
+	require.NoError(t, db.Exec("INSERT INTO recipes (id, user_id) VALUES (?, ?)", recipeID, userID).Error)
+
+	cooking := &model.RecipeCooking{
+		RecipeID: recipeID,
+		UserID:   userID,
+		PantryID: &pantryID,
+		Servings: 2,
+		CookedAt: time.Now().UTC(),
+		Deductions: []model.RecipeCookingDeduction{
+			{PantryItemID: rice.ID, Ingredient: "Arroz", Name: "Arroz", Quantity: 0.2, Unit: "kg", PreviousQuantity: 1},
+			{PantryItemID: eggs.ID, Ingredient: "Ovos", Name: "Ovo", Quantity: 2, Unit: "un", PreviousQuantity: 2},
+		},
+	}
+	require.NoError(t, repo.Apply(ctx, cooking))
+
+	var storedRice, storedEggs itemModel.Item
+	require.NoError(t, db.First(&storedRice, "id = ?", rice.ID).Error)
+	require.InDelta(t, 0.8, storedRice.Quantity, 1e-9)
+	require.NoError(t, db.First(&storedEggs, "id = ?", eggs.ID).Error)
+	require.InDelta(t, 0.0, storedEggs.Quantity, 1e-9)
+
+	again := &model.RecipeCooking{
+		RecipeID: recipeID,
+		UserID:   userID,
+		PantryID: &pantryID,
+		Servings: 2,
+		CookedAt: time.Now().UTC(),
+		Deductions: []model.RecipeCookingDeduction{
+			{PantryItemID: rice.ID, Ingredient: "Arroz", Name: "Arroz", Quantity: 0.2, Unit: "kg", PreviousQuantity: 0.8},
+			{PantryItemID: eggs.ID, Ingredient: "Ovos", Name: "Ovo", Quantity: 2, Unit: "un", PreviousQuantity: 2},
+		},
+	}
+	require.True(t, errors.Is(repo.Apply(ctx, again), domain.ErrStockChanged))
+	var unchanged itemModel.Item
+	require.NoError(t, db.First(&unchanged, "id = ?", rice.ID).Error)
+	require.InDelta(t, 0.8, unchanged.Quantity, 1e-9, "a failed cooking must not deduct anything")
+
+	otherPantryID := uuid.New()
+	otherPantry := &model.RecipeCooking{
+		RecipeID:   recipeID,
+		UserID:     userID,
+		PantryID:   &otherPantryID,
+		Servings:   1,
+		CookedAt:   time.Now().UTC(),
+		Deductions: []model.RecipeCookingDeduction{{PantryItemID: rice.ID, Ingredient: "Arroz", Name: "Arroz", Quantity: 0.1, Unit: "kg"}},
+	}
+	require.True(t, errors.Is(repo.Apply(ctx, otherPantry), domain.ErrStockChanged))
+
+	var stats struct {
+		TimesCooked  int
+		LastCookedAt time.Time
+	}
+	require.NoError(t, db.Raw("SELECT times_cooked, last_cooked_at FROM recipes WHERE id = ?", recipeID).Scan(&stats).Error)
+	require.Equal(t, 1, stats.TimesCooked, "failed cookings must not count")
+	require.WithinDuration(t, cooking.CookedAt, stats.LastCookedAt, time.Second)
+
+	cookings, err := repo.ListByRecipeID(ctx, recipeID, userID)
+	require.NoError(t, err)
+	require.Len(t, cookings, 1)
+	require.Len(t, cookings[0].Deductions, 2)
+	require.Equal(t, "Arroz", cookings[0].Deductions[0].Ingredient)
+
+	cookings, err = repo.ListByRecipeID(ctx, recipeID, uuid.New())
+	require.NoError(t, err)
+	require.Empty(t, cookings)
+}
+
+func TestRecipeCookingRepositoryApplyLogsCookingWithoutPantry(t *testing.T) {
+	db := setupRecipeCookingTestDB(t)
+	repo := NewRecipeCookingRepository(db)
+	ctx := context.Background()
+
+	userID, recipeID := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO recipes (id, user_id) VALUES (?, ?)", recipeID, userID).Error)
+
+	recent := time.Now().UTC().Add(-time.Hour)
+	require.NoError(t, repo.Apply(ctx, &model.RecipeCooking{RecipeID: recipeID, UserID: userID, Servings: 2, CookedAt: recent}))
+	require.NoError(t, repo.Apply(ctx, &model.RecipeCooking{RecipeID: recipeID, UserID: userID, Servings: 2, CookedAt: recent.Add(-48 * time.Hour)}))
+
+	var stats struct {
+		TimesCooked  int
+		LastCookedAt time.Time
+	}
+	require.NoError(t, db.Raw("SELECT times_cooked, last_cooked_at FROM recipes WHERE id = ?", recipeID).Scan(&stats).Error)
+	require.Equal(t, 2, stats.TimesCooked)
+	require.WithinDuration(t, recent, stats.LastCookedAt, time.Second, "an older cooking must not move the last cooking back")
+
+	cookings, err := repo.ListByRecipeID(ctx, recipeID, userID)
+	require.NoError(t, err)
+	require.Len(t, cookings, 2)
+	require.Nil(t, cookings[0].PantryID)
+	require.Empty(t, cookings[0].Deductions)
+}
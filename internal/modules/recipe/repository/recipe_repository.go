@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
 	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -14,6 +18,33 @@ type recipeRepository struct {
 	db *gorm.DB
 }
 
+// recipeSaveOmitted are the columns saving an edited recipe leaves alone: the
+// cooking log, only written by the cooking repository, so a recipe loaded
+// before a cooking does not put its old values back, and the version, only
+// moved forward by saveRecipe.
+var recipeSaveOmitted = []string{"times_cooked", "last_cooked_at", "version"}
+
+// saveRecipe writes an edited recipe only if it is still at the version the
+// edit started from, moving it to the last version the edit adds. Two
+// concurrent edits cannot record the same version number, and an edit made on
+// a stale copy does not overwrite newer content.
+func saveRecipe(tx *gorm.DB, recipe *model.Recipe) error {
+	loaded := recipe.Version
+	if len(recipe.Versions) > 0 {
+		loaded = recipe.Versions[0].Version - 1
+	}
+	result := tx.Model(&model.Recipe{}).
+		Where("id = ? AND version = ?", recipe.ID, loaded).
+		Update("version", recipe.Version)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrVersionConflict
+	}
+	return tx.Omit(recipeSaveOmitted...).Save(recipe).Error
+}
+
 func NewRecipeRepository(db *gorm.DB) (result0 *recipeRepository) {
 	__logParams := map[string]any{"db": db}
 	__logStart := time.Now()
@@ -58,6 +89,31 @@ func (r *recipeRepository) CreateMany(ctx context.Context, recipes []*model.Reci
 	return
 }
 
+// SaveMany creates the new recipes and updates the replaced ones in one
+// transaction
+func (r *recipeRepository) SaveMany(ctx context.Context, created []*model.Recipe, replaced []*model.Recipe) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "created": created, "replaced": replaced}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeRepository.SaveMany"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeRepository.SaveMany"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, recipe := range created {
+			if err := tx.Create(recipe).Error; err != nil {
+				return err
+			}
+		}
+		for _, recipe := range replaced {
+			if err := saveRecipe(tx, recipe); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
+
 func (r *recipeRepository) FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (result0 *model.Recipe, result1 error) {
 	__logParams := map[string]any{"r": r, "ctx": ctx, "id": id, "userID": userID}
 	__logStart := time.Now()
@@ -120,3 +176,205 @@ func (r *recipeRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid
 		Delete(&model.Recipe{}).Error
 	return
 }
+
+func (r *recipeRepository) Update(ctx context.Context, recipe *model.Recipe) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "recipe": recipe}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeRepository.Update"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeRepository.Update"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveRecipe(tx, recipe)
+	})
+	return
+}
+
+func (r *recipeRepository) Search(ctx context.Context, userID uuid.UUID, filter recipeDTO.RecipeFilterDTO) (result0 []*model.Recipe, result1 int64, result2 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "userID": userID, "filter": filter}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeRepository.Search"), zap.Any("result", map[string]any{"result0": result0, "result1": result1, "result2": result2}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeRepository.Search"), zap.Any("params", __logParams))
+	scope := recipeFilterScope(userID, filter)
+
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.Recipe{}).Scopes(scope).Count(&total).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeRepository.Search"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = 0
+		result2 = err
+		return
+	}
+
+	var recipes []*model.Recipe
+	err := r.db.WithContext(ctx).
+		Scopes(scope).
+		Order(recipeSortOrder(filter.SortBy)).
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&recipes).Error
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeRepository.Search"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = 0
+		result2 = err
+		return
+	}
+
+	result0 = recipes
+	result1 = total
+	result2 = nil
+	return
+}
+
+func (r *recipeRepository) ListTags(ctx context.Context, userID uuid.UUID) (result0 []recipeDTO.RecipeTagDTO, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "userID": userID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeRepository.ListTags"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeRepository.ListTags"), zap.Any("params", __logParams))
+	var tags []recipeDTO.RecipeTagDTO
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT tag, COUNT(*) AS count
+			FROM recipes, jsonb_array_elements_text(recipes.tags) AS tag
+			WHERE recipes.user_id = ? AND recipes.deleted_at IS NULL
+			GROUP BY tag
+			ORDER BY count DESC, tag ASC`, userID).
+		Scan(&tags).Error
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeRepository.ListTags"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+
+	result0 = tags
+	result1 = nil
+	return
+}
+
+// recipeTotalTime is the total time of a recipe, falling back to the sum of
+// the preparation and cooking times. It is NULL when the times are unknown.
+const recipeTotalTime = "COALESCE(total_time, preparation_time + cooking_time)"
+
+func recipeFilterScope(userID uuid.UUID, filter recipeDTO.RecipeFilterDTO) (result0 func(*gorm.DB) *gorm.DB) {
+	__logParams := map[string]any{"userID": userID, "filter": filter}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "recipeFilterScope"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "recipeFilterScope"), zap.Any("params", __logParams))
+	result0 = func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		if query := strings.TrimSpace(filter.Query); query != "" {
+			db = db.Where("title ILIKE ?", "%"+escapeLike(query)+"%")
+		}
+		if filter.Cuisine != "" {
+			db = db.Where("LOWER(cuisine) = LOWER(?)", filter.Cuisine)
+		}
+		if filter.MealType != "" {
+			db = db.Where("meal_type = ?", filter.MealType)
+		}
+		if filter.Difficulty != "" {
+			db = db.Where("difficulty = ?", filter.Difficulty)
+		}
+		if filter.MaxTotalTime != nil {
+			db = db.Where(recipeTotalTime+" <= ?", *filter.MaxTotalTime)
+		}
+		if filter.DietaryRestriction != "" {
+			db = db.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(dietary_restrictions) AS restriction WHERE LOWER(restriction) = LOWER(?))", filter.DietaryRestriction)
+		}
+		if len(filter.Tags) > 0 {
+			tags, _ := json.Marshal(filter.Tags)
+			db = db.Where("tags @> ?::jsonb", string(tags))
+		}
+		if filter.Favorite != nil {
+			db = db.Where("favorite = ?", *filter.Favorite)
+		}
+		return db
+	}
+	return
+}
+
+func recipeSortOrder(sortBy string) (result0 string) {
+	__logParams := map[string]any{"sortBy": sortBy}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "recipeSortOrder"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "recipeSortOrder"), zap.Any("params", __logParams))
+	switch sortBy {
+	case "title":
+		result0 = "LOWER(title) ASC, created_at DESC"
+	case "total_time":
+		result0 = recipeTotalTime + " ASC NULLS LAST, created_at DESC"
+	case "rating":
+		result0 = "rating DESC NULLS LAST, created_at DESC"
+	case "not_cooked_recently":
+		// Recipes never cooked come first, then the ones cooked longest ago.
+		result0 = "last_cooked_at ASC NULLS FIRST, created_at ASC"
+	default:
+		result0 = "created_at DESC"
+	}
+	return
+}
+
+func escapeLike(value string) (result0 string) {
+	__logParams := map[string]any{"value": value}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "escapeLike"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "escapeLike"), zap.Any("params", __logParams))
+	result0 = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+	return
+}
+
+// ListVersions lists the saved versions of a recipe, the latest first
+func (r *recipeRepository) ListVersions(ctx context.Context, recipeID uuid.UUID) (result0 []*model.RecipeVersion, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "recipeID": recipeID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeRepository.ListVersions"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeRepository.ListVersions"), zap.Any("params", __logParams))
+	var versions []*model.RecipeVersion
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ?", recipeID).
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeRepository.ListVersions"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = versions
+	result1 = nil
+	return
+}
+
+func (r *recipeRepository) FindVersion(ctx context.Context, recipeID uuid.UUID, version int) (result0 *model.RecipeVersion, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "recipeID": recipeID, "version": version}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeRepository.FindVersion"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeRepository.FindVersion"), zap.Any("params", __logParams))
+	var found model.RecipeVersion
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ? AND version = ?", recipeID, version).
+		First(&found).Error
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeRepository.FindVersion"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &found
+	result1 = nil
+	return
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupRecipeVersionTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := setupRecipeCookingTestDB(t)
+	require.NoError(t, db.Exec(`DROP TABLE recipes`).Error)
+	// The recipe defaults and timestamp types only exist in postgres.
+	require.NoError(t, db.Exec(`CREATE TABLE recipes (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		title TEXT NOT NULL,
+		description TEXT,
+		ingredients TEXT NOT NULL,
+		instructions TEXT NOT NULL,
+		cooking_time INTEGER,
+		preparation_time INTEGER,
+		total_time INTEGER,
+		serving_size INTEGER,
+		difficulty TEXT,
+		meal_type TEXT,
+		cuisine TEXT,
+		dietary_restrictions TEXT,
+		nutrition_info TEXT,
+		tips TEXT,
+		tags TEXT,
+		favorite BOOLEAN NOT NULL DEFAULT false,
+		rating INTEGER,
+		notes TEXT,
+		modifications TEXT,
+		last_cooked_at DATETIME,
+		times_cooked INTEGER NOT NULL DEFAULT 0,
+		source_url TEXT,
+		origin_recipe_id TEXT,
+		origin_author TEXT,
+		version INTEGER NOT NULL DEFAULT 0,
+		generated_at DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE recipe_versions (
+		id TEXT PRIMARY KEY,
+		recipe_id TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		author_id TEXT NOT NULL,
+		restored_from INTEGER,
+		title TEXT NOT NULL,
+		description TEXT,
+		ingredients TEXT NOT NULL,
+		instructions TEXT NOT NULL,
+		cooking_time INTEGER,
+		preparation_time INTEGER,
+		total_time INTEGER,
+		serving_size INTEGER,
+		difficulty TEXT,
+		meal_type TEXT,
+		cuisine TEXT,
+		dietary_restrictions TEXT,
+		tips TEXT,
+		created_at DATETIME NOT NULL,
+		UNIQUE (recipe_id, version)
+	)`).Error)
+	return db
+}
+
+func TestRecipeRepositoryWritesVersionsWithTheRecipe(t *testing.T) {
+	db := setupRecipeVersionTestDB(t)
+	repo := NewRecipeRepository(db)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	now := time.Now()
+	recipe := &model.Recipe{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Title:        "Arroz",
+		Ingredients:  model.RecipeIngredientsJSON{{Name: "Arroz"}},
+		Instructions: model.RecipeInstructionsJSON{{Step: 1, Description: "Cozinhe"}},
+		Version:      1,
+		Versions:     []model.RecipeVersion{{Version: 1, AuthorID: userID, Title: "Arroz", CreatedAt: now}},
+		GeneratedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	require.NoError(t, repo.Create(ctx, recipe))
+
+	found, err := repo.FindByID(ctx, recipe.ID, userID)
+	require.NoError(t, err)
+	require.Empty(t, found.Versions)
+	found.Title = "Arroz soltinho"
+	found.Version = 2
+	found.Versions = []model.RecipeVersion{{Version: 2, AuthorID: userID, Title: "Arroz soltinho", CreatedAt: now}}
+	require.NoError(t, repo.Update(ctx, found))
+
+	versions, err := repo.ListVersions(ctx, recipe.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 2, versions[0].Version)
+	require.Equal(t, recipe.ID, versions[0].RecipeID)
+	require.Equal(t, "Arroz", versions[1].Title)
+
+	version, err := repo.FindVersion(ctx, recipe.ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, "Arroz", version.Title)
+
+	found.Versions = append(found.Versions, model.RecipeVersion{Version: 2, AuthorID: userID, Title: "Outro", CreatedAt: now})
+	require.Error(t, repo.Update(ctx, found))
+
+	_, err = repo.FindVersion(ctx, recipe.ID, 3)
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestRecipeRepositoryUpdateKeepsCookingLog(t *testing.T) {
+	db := setupRecipeVersionTestDB(t)
+	repo := NewRecipeRepository(db)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	now := time.Now()
+	recipe := &model.Recipe{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Title:        "Arroz",
+		Ingredients:  model.RecipeIngredientsJSON{{Name: "Arroz"}},
+		Instructions: model.RecipeInstructionsJSON{{Step: 1, Description: "Cozinhe"}},
+		GeneratedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	require.NoError(t, repo.Create(ctx, recipe))
+
+	stale, err := repo.FindByID(ctx, recipe.ID, userID)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`UPDATE recipes SET times_cooked = 2, last_cooked_at = ? WHERE id = ?`, now, recipe.ID).Error)
+
+	rating := 5
+	stale.Rating = &rating
+	require.NoError(t, repo.Update(ctx, stale))
+	require.NoError(t, repo.SaveMany(ctx, nil, []*model.Recipe{stale}))
+
+	found, err := repo.FindByID(ctx, recipe.ID, userID)
+	require.NoError(t, err)
+	require.Equal(t, 2, found.TimesCooked)
+	require.NotNil(t, found.LastCookedAt)
+	require.Equal(t, 5, *found.Rating)
+}
+
+func TestRecipeRepositoryUpdateRefusesConcurrentVersions(t *testing.T) {
+	db := setupRecipeVersionTestDB(t)
+	repo := NewRecipeRepository(db)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	now := time.Now()
+	recipe := &model.Recipe{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Title:        "Arroz",
+		Ingredients:  model.RecipeIngredientsJSON{{Name: "Arroz"}},
+		Instructions: model.RecipeInstructionsJSON{{Step: 1, Description: "Cozinhe"}},
+		Version:      1,
+		Versions:     []model.RecipeVersion{{Version: 1, AuthorID: userID, Title: "Arroz", CreatedAt: now}},
+		GeneratedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	require.NoError(t, repo.Create(ctx, recipe))
+
+	first, err := repo.FindByID(ctx, recipe.ID, userID)
+	require.NoError(t, err)
+	second, err := repo.FindByID(ctx, recipe.ID, userID)
+	require.NoError(t, err)
+	rated, err := repo.FindByID(ctx, recipe.ID, userID)
+	require.NoError(t, err)
+
+	first.Title = "Arroz soltinho"
+	first.Version = 2
+	first.Versions = []model.RecipeVersion{{Version: 2, AuthorID: userID, Title: first.Title, CreatedAt: now}}
+	require.NoError(t, repo.Update(ctx, first))
+
+	second.Title = "Arroz branco"
+	second.Version = 2
+	second.Versions = []model.RecipeVersion{{Version: 2, AuthorID: userID, Title: second.Title, CreatedAt: now}}
+	require.ErrorIs(t, repo.Update(ctx, second), domain.ErrVersionConflict)
+
+	rating := 4
+	rated.Rating = &rating
+	require.ErrorIs(t, repo.Update(ctx, rated), domain.ErrVersionConflict)
+
+	found, err := repo.FindByID(ctx, recipe.ID, userID)
+	require.NoError(t, err)
+	require.Equal(t, 2, found.Version)
+	versions, err := repo.ListVersions(ctx, recipe.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, "Arroz soltinho", versions[0].Title)
+}
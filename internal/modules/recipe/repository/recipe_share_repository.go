@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type recipeShareRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeShareRepository(db *gorm.DB) (result0 domain.RecipeShareRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewRecipeShareRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewRecipeShareRepository"), zap.Any("params", __logParams))
+	result0 = &recipeShareRepository{db: db}
+	return
+}
+
+func (r *recipeShareRepository) Create(ctx context.Context, share *model.RecipeShare) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "share": share}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeShareRepository.Create"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeShareRepository.Create"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Create(share).Error
+	return
+}
+
+func (r *recipeShareRepository) FindByID(ctx context.Context, id uuid.UUID) (result0 *model.RecipeShare, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "id": id}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeShareRepository.FindByID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeShareRepository.FindByID"), zap.Any("params", __logParams))
+	var share model.RecipeShare
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&share).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeShareRepository.FindByID"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &share
+	result1 = nil
+	return
+}
+
+func (r *recipeShareRepository) FindByToken(ctx context.Context, token string) (result0 *model.RecipeShare, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "token": token}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeShareRepository.FindByToken"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeShareRepository.FindByToken"), zap.Any("params", __logParams))
+	var share model.RecipeShare
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&share).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeShareRepository.FindByToken"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &share
+	result1 = nil
+	return
+}
+
+func (r *recipeShareRepository) ListByRecipeID(ctx context.Context, recipeID uuid.UUID) (result0 []*model.RecipeShare, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "recipeID": recipeID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeShareRepository.ListByRecipeID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeShareRepository.ListByRecipeID"), zap.Any("params", __logParams))
+	var shares []*model.RecipeShare
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ?", recipeID).
+		Order("created_at DESC").
+		Find(&shares).Error
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*recipeShareRepository.ListByRecipeID"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = shares
+	result1 = nil
+	return
+}
+
+func (r *recipeShareRepository) Update(ctx context.Context, share *model.RecipeShare) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "share": share}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*recipeShareRepository.Update"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*recipeShareRepository.Update"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Save(share).Error
+	return
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestRecipeShareRepositoryFindsAndRevokesShares(t *testing.T) {
+	db := setupRecipeCookingTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.RecipeShare{}))
+	repo := NewRecipeShareRepository(db)
+	ctx := context.Background()
+
+	recipeID, userID := uuid.New(), uuid.New()
+	first := &model.RecipeShare{RecipeID: recipeID, UserID: userID, Token: "first", CreatedAt: time.Now().Add(-time.Hour)}
+	second := &model.RecipeShare{RecipeID: recipeID, UserID: userID, Token: "second"}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+	require.NoError(t, repo.Create(ctx, &model.RecipeShare{RecipeID: uuid.New(), UserID: userID, Token: "other"}))
+	require.Error(t, repo.Create(ctx, &model.RecipeShare{RecipeID: recipeID, UserID: userID, Token: "first"}))
+
+	shares, err := repo.ListByRecipeID(ctx, recipeID)
+	require.NoError(t, err)
+	require.Len(t, shares, 2)
+	require.Equal(t, second.ID, shares[0].ID)
+
+	found, err := repo.FindByToken(ctx, "first")
+	require.NoError(t, err)
+	require.Equal(t, first.ID, found.ID)
+
+	revokedAt := time.Now().UTC()
+	found.RevokedAt = &revokedAt
+	require.NoError(t, repo.Update(ctx, found))
+
+	found, err = repo.FindByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.RevokedAt)
+	require.False(t, found.Active(time.Now()))
+
+	_, err = repo.FindByToken(ctx, "missing")
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
@@ -0,0 +1,617 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	itemDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/item/domain"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	pantryDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/domain"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	maxMealPlanDays       = 14
+	maxMealPlanServings   = 100
+	expiringMealPlanScore = 10
+	urgentMealPlanScore   = 5
+	urgentMealPlanDays    = 2
+	coverageMealPlanScore = 5
+	repeatMealPlanPenalty = 100
+)
+
+// mealTypeOrder is the order of the meals in a day.
+var mealTypeOrder = map[string]int{"breakfast": 0, "lunch": 1, "snack": 2, "dinner": 3, "dessert": 4}
+
+var defaultGeneratedMealTypes = []string{"lunch", "dinner"}
+
+type mealPlanService struct {
+	mealPlanRepository recipeDomain.MealPlanRepository
+	recipeRepository   recipeDomain.RecipeRepository
+	itemRepository     itemDomain.ItemRepository
+	pantryService      pantryDomain.PantryService
+}
+
+func NewMealPlanService(
+	mealPlanRepository recipeDomain.MealPlanRepository,
+	recipeRepository recipeDomain.RecipeRepository,
+	itemRepository itemDomain.ItemRepository,
+	pantryService pantryDomain.PantryService,
+) recipeDomain.MealPlanService {
+	return &mealPlanService{
+		mealPlanRepository: mealPlanRepository,
+		recipeRepository:   recipeRepository,
+		itemRepository:     itemRepository,
+		pantryService:      pantryService,
+	}
+}
+
+// mealSlot is a meal of a day in the plan.
+type mealSlot struct {
+	date     time.Time
+	mealType string
+}
+
+// mealCandidate is a saved recipe with the pantry items its ingredients use.
+type mealCandidate struct {
+	recipe  *recipeModel.Recipe
+	stock   []*cookingStock
+	covered float64
+	uses    int
+}
+
+// ListMealPlan lists the meals planned for a pantry between from and to. The
+// period defaults to the seven days starting today.
+func (ms *mealPlanService) ListMealPlan(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID, from string, to string) (*recipeDTO.MealPlanDTO, error) {
+	start := mealPlanToday()
+	if strings.TrimSpace(from) != "" {
+		var err error
+		if start, err = parseMealPlanDate(from, "from"); err != nil {
+			return nil, err
+		}
+	}
+	end := start.AddDate(0, 0, recipeDomain.DefaultMealPlanDays-1)
+	if strings.TrimSpace(to) != "" {
+		var err error
+		if end, err = parseMealPlanDate(to, "to"); err != nil {
+			return nil, err
+		}
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: to cannot be before from", recipeDomain.ErrInvalidRequest)
+	}
+	if end.Sub(start) > recipeDomain.MaxMealPlanRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: the period can have at most %d days", recipeDomain.ErrInvalidRequest, recipeDomain.MaxMealPlanRangeDays)
+	}
+
+	if err := checkPantryAccess(ctx, ms.pantryService, pantryID, userID, "ListMealPlan"); err != nil {
+		return nil, err
+	}
+
+	entries, err := ms.mealPlanRepository.ListByPantry(ctx, pantryID, start, end)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list meal plan",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ListMealPlan"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	sortMealPlanEntries(entries)
+
+	return &recipeDTO.MealPlanDTO{
+		PantryID: pantryID.String(),
+		From:     start.Format(recipeDomain.MealPlanDateLayout),
+		To:       end.Format(recipeDomain.MealPlanDateLayout),
+		Entries:  ms.convertEntries(ctx, entries),
+	}, nil
+}
+
+// PlanMeal plans a saved recipe of the user for a meal of a pantry
+func (ms *mealPlanService) PlanMeal(ctx context.Context, userID uuid.UUID, input *recipeDTO.PlanMealDTO) (*recipeDTO.MealPlanEntryDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil {
+		return nil, fmt.Errorf("%w: meal data is required", recipeDomain.ErrInvalidRequest)
+	}
+	pantryID, err := uuid.Parse(strings.TrimSpace(input.PantryID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: pantry_id must be a valid UUID", recipeDomain.ErrInvalidRequest)
+	}
+	recipeID, err := uuid.Parse(strings.TrimSpace(input.RecipeID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: recipe_id must be a valid UUID", recipeDomain.ErrInvalidRequest)
+	}
+	date, err := parseMealPlanDate(input.Date, "date")
+	if err != nil {
+		return nil, err
+	}
+	mealType, err := normalizeMealType(input.MealType)
+	if err != nil {
+		return nil, err
+	}
+	if input.Servings < 0 || input.Servings > maxMealPlanServings {
+		return nil, fmt.Errorf("%w: servings must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxMealPlanServings)
+	}
+
+	if err := checkPantryAccess(ctx, ms.pantryService, pantryID, userID, "PlanMeal"); err != nil {
+		return nil, err
+	}
+	recipe, err := findUserRecipe(ctx, ms.recipeRepository, recipeID, userID, "PlanMeal")
+	if err != nil {
+		return nil, err
+	}
+
+	servings := input.Servings
+	if servings == 0 {
+		servings = recipe.ServingCount()
+	}
+	entry := &recipeModel.MealPlanEntry{
+		PantryID: pantryID,
+		UserID:   userID,
+		RecipeID: recipe.ID,
+		Date:     date,
+		MealType: mealType,
+		Servings: servings,
+		Notes:    strings.TrimSpace(input.Notes),
+	}
+	if err := ms.mealPlanRepository.Create(ctx, entry); err != nil {
+		logger.Error("Failed to plan meal",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "PlanMeal"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.Info("Meal planned successfully",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "PlanMeal"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("pantry_id", pantryID.String()),
+		zap.String("meal_plan_id", entry.ID.String()),
+	)
+
+	result := convertMealPlanEntry(entry, recipe.Title)
+	return &result, nil
+}
+
+// UpdateMealPlanEntry moves a planned meal or changes its recipe, servings or
+// notes. Only the recipes of the user can be planned.
+func (ms *mealPlanService) UpdateMealPlanEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID, input *recipeDTO.UpdateMealPlanEntryDTO) (*recipeDTO.MealPlanEntryDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil {
+		return nil, fmt.Errorf("%w: meal data is required", recipeDomain.ErrInvalidRequest)
+	}
+	entry, err := ms.loadEntry(ctx, userID, entryID, "UpdateMealPlanEntry")
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Date != nil {
+		if entry.Date, err = parseMealPlanDate(*input.Date, "date"); err != nil {
+			return nil, err
+		}
+	}
+	if input.MealType != nil {
+		if entry.MealType, err = normalizeMealType(*input.MealType); err != nil {
+			return nil, err
+		}
+	}
+	if input.Servings != nil {
+		if *input.Servings < 1 || *input.Servings > maxMealPlanServings {
+			return nil, fmt.Errorf("%w: servings must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxMealPlanServings)
+		}
+		entry.Servings = *input.Servings
+	}
+	if input.Notes != nil {
+		entry.Notes = strings.TrimSpace(*input.Notes)
+	}
+	if input.RecipeID != nil {
+		recipeID, err := uuid.Parse(strings.TrimSpace(*input.RecipeID))
+		if err != nil {
+			return nil, fmt.Errorf("%w: recipe_id must be a valid UUID", recipeDomain.ErrInvalidRequest)
+		}
+		if _, err := findUserRecipe(ctx, ms.recipeRepository, recipeID, userID, "UpdateMealPlanEntry"); err != nil {
+			return nil, err
+		}
+		entry.RecipeID = recipeID
+		entry.UserID = userID
+	}
+
+	if err := ms.mealPlanRepository.Update(ctx, entry); err != nil {
+		logger.Error("Failed to update meal plan entry",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "UpdateMealPlanEntry"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("meal_plan_id", entryID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result := ms.convertEntries(ctx, []*recipeModel.MealPlanEntry{entry})[0]
+	return &result, nil
+}
+
+// DeleteMealPlanEntry removes a planned meal
+func (ms *mealPlanService) DeleteMealPlanEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) error {
+	if _, err := ms.loadEntry(ctx, userID, entryID, "DeleteMealPlanEntry"); err != nil {
+		return err
+	}
+
+	if err := ms.mealPlanRepository.Delete(ctx, entryID); err != nil {
+		appLogger.FromContext(ctx).Error("Failed to delete meal plan entry",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "DeleteMealPlanEntry"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("meal_plan_id", entryID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// GenerateMealPlan fills the free meals of the period with the saved recipes
+// of the user. Meals are filled in order and each one takes the recipe that
+// uses the most pantry items expiring until that day, then the one with the
+// most ingredients in stock. A recipe is repeated only when every candidate
+// was already used.
+func (ms *mealPlanService) GenerateMealPlan(ctx context.Context, userID uuid.UUID, input *recipeDTO.GenerateMealPlanDTO) (*recipeDTO.GeneratedMealPlanDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil {
+		return nil, fmt.Errorf("%w: meal plan data is required", recipeDomain.ErrInvalidRequest)
+	}
+	pantryID, err := uuid.Parse(strings.TrimSpace(input.PantryID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: pantry_id must be a valid UUID", recipeDomain.ErrInvalidRequest)
+	}
+	start := mealPlanToday()
+	if strings.TrimSpace(input.StartDate) != "" {
+		if start, err = parseMealPlanDate(input.StartDate, "start_date"); err != nil {
+			return nil, err
+		}
+	}
+	days := input.Days
+	if days == 0 {
+		days = recipeDomain.DefaultMealPlanDays
+	}
+	if days < 1 || days > maxMealPlanDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxMealPlanDays)
+	}
+	if input.Servings < 0 || input.Servings > maxMealPlanServings {
+		return nil, fmt.Errorf("%w: servings must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxMealPlanServings)
+	}
+	mealTypes := defaultGeneratedMealTypes
+	if len(input.MealTypes) > 0 {
+		mealTypes = make([]string, 0, len(input.MealTypes))
+		for _, value := range input.MealTypes {
+			mealType, err := normalizeMealType(value)
+			if err != nil {
+				return nil, err
+			}
+			if !containsString(mealTypes, mealType) {
+				mealTypes = append(mealTypes, mealType)
+			}
+		}
+		sort.SliceStable(mealTypes, func(i, j int) bool {
+			return mealTypeOrder[mealTypes[i]] < mealTypeOrder[mealTypes[j]]
+		})
+	}
+	end := start.AddDate(0, 0, days-1)
+
+	if err := checkPantryAccess(ctx, ms.pantryService, pantryID, userID, "GenerateMealPlan"); err != nil {
+		return nil, err
+	}
+
+	existing, err := ms.mealPlanRepository.ListByPantry(ctx, pantryID, start, end)
+	if err != nil {
+		logger.Error("Failed to list meal plan",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GenerateMealPlan"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	recipes, err := ms.recipeRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get user recipes from database",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GenerateMealPlan"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	items, err := ms.itemRepository.ListByPantryID(ctx, pantryID)
+	if err != nil {
+		logger.Error("Failed to list items from pantry",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GenerateMealPlan"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	planned := make(map[mealSlot]bool, len(existing))
+	for _, entry := range existing {
+		planned[mealSlot{date: mealPlanDay(entry.Date), mealType: entry.MealType}] = true
+	}
+	var slots []mealSlot
+	kept := 0
+	for day := 0; day < days; day++ {
+		for _, mealType := range mealTypes {
+			slot := mealSlot{date: start.AddDate(0, 0, day), mealType: mealType}
+			if planned[slot] {
+				kept++
+				continue
+			}
+			slots = append(slots, slot)
+		}
+	}
+
+	picks, expiring := planMeals(slots, recipes, items)
+
+	entries := make([]*recipeModel.MealPlanEntry, 0, len(picks))
+	titles := make(map[uuid.UUID]string, len(picks))
+	for idx, recipe := range picks {
+		if recipe == nil {
+			continue
+		}
+		servings := input.Servings
+		if servings == 0 {
+			servings = recipe.ServingCount()
+		}
+		entries = append(entries, &recipeModel.MealPlanEntry{
+			PantryID:  pantryID,
+			UserID:    userID,
+			RecipeID:  recipe.ID,
+			Date:      slots[idx].date,
+			MealType:  slots[idx].mealType,
+			Servings:  servings,
+			Generated: true,
+		})
+		titles[recipe.ID] = recipe.Title
+	}
+	if err := ms.mealPlanRepository.CreateMany(ctx, entries); err != nil {
+		logger.Error("Failed to save generated meal plan",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GenerateMealPlan"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result := &recipeDTO.GeneratedMealPlanDTO{
+		PantryID:      pantryID.String(),
+		From:          start.Format(recipeDomain.MealPlanDateLayout),
+		To:            end.Format(recipeDomain.MealPlanDateLayout),
+		Created:       make([]recipeDTO.MealPlanEntryDTO, 0, len(entries)),
+		Kept:          kept,
+		Unfilled:      len(slots) - len(entries),
+		ExpiringItems: expiring,
+	}
+	for _, entry := range entries {
+		result.Created = append(result.Created, convertMealPlanEntry(entry, titles[entry.RecipeID]))
+	}
+
+	logger.Info("Meal plan generated successfully",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "GenerateMealPlan"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("pantry_id", pantryID.String()),
+		zap.Int(appLogger.FieldCount, len(entries)),
+		zap.Int("unfilled", result.Unfilled),
+	)
+
+	return result, nil
+}
+
+// planMeals picks a recipe for each slot, nil when no recipe fits it, and
+// returns the names of the expiring items the picks use.
+func planMeals(slots []mealSlot, recipes []*recipeModel.Recipe, items []*itemModel.Item) ([]*recipeModel.Recipe, []string) {
+	stock := indexCookingStock(items)
+	candidates := make([]*mealCandidate, 0, len(recipes))
+	for _, recipe := range recipes {
+		candidate := &mealCandidate{recipe: recipe}
+		seen := make(map[*cookingStock]bool)
+		total := 0
+		for _, ingredient := range recipe.Ingredients {
+			key := normalizeIngredientName(ingredient.Name)
+			if key == "" {
+				continue
+			}
+			total++
+			matches := matchCookingStock(key, stock)
+			if len(matches) > 0 {
+				candidate.covered++
+			}
+			for _, entry := range matches {
+				if !seen[entry] {
+					seen[entry] = true
+					candidate.stock = append(candidate.stock, entry)
+				}
+			}
+		}
+		if total > 0 {
+			candidate.covered /= float64(total)
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].recipe, candidates[j].recipe
+		if !strings.EqualFold(a.Title, b.Title) {
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+
+	claimed := make(map[*cookingStock]bool)
+	var expiring []string
+	picks := make([]*recipeModel.Recipe, len(slots))
+	for idx, slot := range slots {
+		var options []*mealCandidate
+		for _, candidate := range candidates {
+			if candidate.recipe.MealType == "" || candidate.recipe.MealType == slot.mealType {
+				options = append(options, candidate)
+			}
+		}
+		if len(options) == 0 {
+			options = candidates
+		}
+
+		var best *mealCandidate
+		bestScore := 0.0
+		for _, candidate := range options {
+			score := candidate.covered*coverageMealPlanScore - float64(candidate.uses*repeatMealPlanPenalty)
+			for _, entry := range candidate.stock {
+				if claimed[entry] || entry.item.ExpiresAt == nil || entry.item.ExpiresAt.Before(slot.date) {
+					continue
+				}
+				daysLeft := entry.item.ExpiresAt.Sub(slot.date).Hours() / 24
+				if daysLeft > float64(len(slots)+maxMealPlanDays) {
+					continue
+				}
+				score += expiringMealPlanScore
+				if daysLeft <= urgentMealPlanDays {
+					score += urgentMealPlanScore
+				}
+			}
+			if best == nil || score > bestScore {
+				best, bestScore = candidate, score
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		best.uses++
+		picks[idx] = best.recipe
+		for _, entry := range best.stock {
+			if claimed[entry] || entry.item.ExpiresAt == nil || entry.item.ExpiresAt.Before(slot.date) {
+				continue
+			}
+			claimed[entry] = true
+			expiring = append(expiring, entry.item.Name)
+		}
+	}
+	if expiring == nil {
+		expiring = []string{}
+	}
+	return picks, expiring
+}
+
+func (ms *mealPlanService) loadEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID, function string) (*recipeModel.MealPlanEntry, error) {
+	entry, err := ms.mealPlanRepository.FindByID(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, recipeDomain.ErrMealPlanNotFound
+		}
+		appLogger.FromContext(ctx).Error("Failed to get meal plan entry",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("meal_plan_id", entryID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if err := checkPantryAccess(ctx, ms.pantryService, entry.PantryID, userID, function); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// convertEntries converts the entries with the titles of their recipes. A
+// recipe removed after being planned has an empty title.
+func (ms *mealPlanService) convertEntries(ctx context.Context, entries []*recipeModel.MealPlanEntry) []recipeDTO.MealPlanEntryDTO {
+	titles := make(map[uuid.UUID]string)
+	result := make([]recipeDTO.MealPlanEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		title, ok := titles[entry.RecipeID]
+		if !ok {
+			if recipe, err := ms.recipeRepository.FindByID(ctx, entry.RecipeID, entry.UserID); err == nil {
+				title = recipe.Title
+			}
+			titles[entry.RecipeID] = title
+		}
+		result = append(result, convertMealPlanEntry(entry, title))
+	}
+	return result
+}
+
+func convertMealPlanEntry(entry *recipeModel.MealPlanEntry, title string) recipeDTO.MealPlanEntryDTO {
+	return recipeDTO.MealPlanEntryDTO{
+		ID:          entry.ID.String(),
+		PantryID:    entry.PantryID.String(),
+		RecipeID:    entry.RecipeID.String(),
+		RecipeTitle: title,
+		Date:        entry.Date.Format(recipeDomain.MealPlanDateLayout),
+		MealType:    entry.MealType,
+		Servings:    entry.Servings,
+		Notes:       entry.Notes,
+		Generated:   entry.Generated,
+		PlannedBy:   entry.UserID.String(),
+		CreatedAt:   entry.CreatedAt,
+	}
+}
+
+// sortMealPlanEntries orders the entries by day and then by meal of the day.
+func sortMealPlanEntries(entries []*recipeModel.MealPlanEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := mealPlanDay(entries[i].Date), mealPlanDay(entries[j].Date)
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return mealTypeOrder[entries[i].MealType] < mealTypeOrder[entries[j].MealType]
+	})
+}
+
+func normalizeMealType(value string) (string, error) {
+	mealType := strings.ToLower(strings.TrimSpace(value))
+	if !containsString(recipeMealTypes, mealType) {
+		return "", fmt.Errorf("%w: meal_type must be one of %s", recipeDomain.ErrInvalidRequest, strings.Join(recipeMealTypes, ", "))
+	}
+	return mealType, nil
+}
+
+func parseMealPlanDate(value string, field string) (time.Time, error) {
+	date, err := time.Parse(recipeDomain.MealPlanDateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a date in the YYYY-MM-DD format", recipeDomain.ErrInvalidRequest, field)
+	}
+	return date, nil
+}
+
+// mealPlanDay drops the time of a date read from the database.
+func mealPlanDay(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func mealPlanToday() time.Time {
+	return mealPlanDay(time.Now())
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"gorm.io/gorm"
+)
+
+type memoryMealPlanRepository struct {
+	entries map[uuid.UUID]*recipeModel.MealPlanEntry
+}
+
+func newMemoryMealPlanRepository(entries ...*recipeModel.MealPlanEntry) *memoryMealPlanRepository {
+	repo := &memoryMealPlanRepository{entries: make(map[uuid.UUID]*recipeModel.MealPlanEntry)}
+	for _, entry := range entries {
+		repo.Create(context.Background(), entry)
+	}
+	return repo
+}
+
+func (r *memoryMealPlanRepository) Create(ctx context.Context, entry *recipeModel.MealPlanEntry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	r.entries[entry.ID] = entry
+	return nil
+}
+
+func (r *memoryMealPlanRepository) CreateMany(ctx context.Context, entries []*recipeModel.MealPlanEntry) error {
+	for _, entry := range entries {
+		r.Create(ctx, entry)
+	}
+	return nil
+}
+
+func (r *memoryMealPlanRepository) FindByID(ctx context.Context, id uuid.UUID) (*recipeModel.MealPlanEntry, error) {
+	entry, ok := r.entries[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return entry, nil
+}
+
+func (r *memoryMealPlanRepository) Update(ctx context.Context, entry *recipeModel.MealPlanEntry) error {
+	r.entries[entry.ID] = entry
+	return nil
+}
+
+func (r *memoryMealPlanRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.entries, id)
+	return nil
+}
+
+func (r *memoryMealPlanRepository) ListByPantry(ctx context.Context, pantryID uuid.UUID, from time.Time, to time.Time) ([]*recipeModel.MealPlanEntry, error) {
+	var entries []*recipeModel.MealPlanEntry
+	for _, entry := range r.entries {
+		if entry.PantryID == pantryID && !entry.Date.Before(from) && !entry.Date.After(to) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func mealPlanDate(day int) time.Time {
+	return time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPlanMeals_PrefersExpiringItemsAndAvoidsRepeats(t *testing.T) {
+	spinachExpiry := mealPlanDate(3)
+	chickenExpiry := mealPlanDate(5)
+	items := []*model.Item{
+		{ID: uuid.New(), Name: "Espinafre", Quantity: 1, Unit: "maço", ExpiresAt: &spinachExpiry},
+		{ID: uuid.New(), Name: "Frango", Quantity: 1, Unit: "kg", ExpiresAt: &chickenExpiry},
+		{ID: uuid.New(), Name: "Arroz", Quantity: 2, Unit: "kg"},
+	}
+	rice := &recipeModel.Recipe{ID: uuid.New(), Title: "Arroz branco", Ingredients: recipeModel.RecipeIngredientsJSON{{Name: "Arroz"}}}
+	chicken := &recipeModel.Recipe{ID: uuid.New(), Title: "Frango assado", MealType: "dinner", Ingredients: recipeModel.RecipeIngredientsJSON{{Name: "Frango"}, {Name: "Alho"}}}
+	spinach := &recipeModel.Recipe{ID: uuid.New(), Title: "Torta de espinafre", MealType: "lunch", Ingredients: recipeModel.RecipeIngredientsJSON{{Name: "Espinafre"}, {Name: "Ovos"}}}
+	slots := []mealSlot{
+		{date: mealPlanDate(2), mealType: "lunch"},
+		{date: mealPlanDate(2), mealType: "dinner"},
+		{date: mealPlanDate(3), mealType: "lunch"},
+		{date: mealPlanDate(3), mealType: "dinner"},
+	}
+
+	picks, expiring := planMeals(slots, []*recipeModel.Recipe{rice, chicken, spinach}, items)
+
+	if picks[0] != spinach {
+		t.Fatalf("expected the spinach about to expire on the first lunch, got %s", picks[0].Title)
+	}
+	if picks[1] != chicken {
+		t.Fatalf("expected the expiring chicken on the first dinner, got %s", picks[1].Title)
+	}
+	if picks[2] != rice || picks[3] != rice {
+		t.Fatalf("expected the remaining meals to use the recipe not used yet, got %s and %s", picks[2].Title, picks[3].Title)
+	}
+	if len(expiring) != 2 || expiring[0] != "Espinafre" || expiring[1] != "Frango" {
+		t.Fatalf("expected the expiring items used to be reported, got %v", expiring)
+	}
+}
+
+func TestMealPlanService_GenerateMealPlan_KeepsPlannedMeals(t *testing.T) {
+	userID, pantryID := uuid.New(), uuid.New()
+	soup := &recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Sopa", Ingredients: recipeModel.RecipeIngredientsJSON{{Name: "Batata"}}}
+	planned := &recipeModel.MealPlanEntry{PantryID: pantryID, UserID: userID, RecipeID: soup.ID, Date: mealPlanDate(2), MealType: "lunch", Servings: 2}
+	meals := newMemoryMealPlanRepository(planned)
+	svc := NewMealPlanService(meals, newMemoryRecipeRepository(soup), &stubItemRepository{}, &stubPantryService{})
+
+	result, err := svc.GenerateMealPlan(context.Background(), userID, &recipeDTO.GenerateMealPlanDTO{
+		PantryID:  pantryID.String(),
+		StartDate: "2026-03-02",
+		Days:      2,
+		Servings:  3,
+	})
+	if err != nil {
+		t.Fatalf("expected generation to succeed, got %v", err)
+	}
+	if result.Kept != 1 || len(result.Created) != 3 || result.Unfilled != 0 {
+		t.Fatalf("expected 1 meal kept and 3 created, got %+v", result)
+	}
+	if result.From != "2026-03-02" || result.To != "2026-03-03" {
+		t.Fatalf("unexpected period %s to %s", result.From, result.To)
+	}
+	for _, entry := range result.Created {
+		if !entry.Generated || entry.Servings != 3 || entry.RecipeTitle != "Sopa" {
+			t.Fatalf("unexpected generated entry %+v", entry)
+		}
+	}
+	if len(meals.entries) != 4 {
+		t.Fatalf("expected 4 planned meals, got %d", len(meals.entries))
+	}
+
+	_, err = svc.GenerateMealPlan(context.Background(), userID, &recipeDTO.GenerateMealPlanDTO{PantryID: pantryID.String(), Days: 30})
+	if !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected a too long period to be refused, got %v", err)
+	}
+}
+
+func TestMealPlanService_PlanMoveAndRemove(t *testing.T) {
+	userID, pantryID := uuid.New(), uuid.New()
+	servings := 4
+	recipe := &recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Feijoada", ServingSize: &servings}
+	meals := newMemoryMealPlanRepository()
+	svc := NewMealPlanService(meals, newMemoryRecipeRepository(recipe), &stubItemRepository{}, &stubPantryService{})
+	ctx := context.Background()
+
+	entry, err := svc.PlanMeal(ctx, userID, &recipeDTO.PlanMealDTO{
+		PantryID: pantryID.String(),
+		RecipeID: recipe.ID.String(),
+		Date:     "2026-03-07",
+		MealType: "Lunch",
+	})
+	if err != nil {
+		t.Fatalf("expected planning to succeed, got %v", err)
+	}
+	if entry.Servings != 4 || entry.MealType != "lunch" || entry.RecipeTitle != "Feijoada" {
+		t.Fatalf("unexpected planned meal %+v", entry)
+	}
+
+	_, err = svc.PlanMeal(ctx, userID, &recipeDTO.PlanMealDTO{PantryID: pantryID.String(), RecipeID: recipe.ID.String(), Date: "07/03/2026", MealType: "lunch"})
+	if !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid date to be refused, got %v", err)
+	}
+	_, err = svc.PlanMeal(ctx, uuid.New(), &recipeDTO.PlanMealDTO{PantryID: pantryID.String(), RecipeID: recipe.ID.String(), Date: "2026-03-07", MealType: "lunch"})
+	if !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected the recipe of another user to be refused, got %v", err)
+	}
+
+	entryID := uuid.MustParse(entry.ID)
+	date, mealType := "2026-03-08", "dinner"
+	moved, err := svc.UpdateMealPlanEntry(ctx, userID, entryID, &recipeDTO.UpdateMealPlanEntryDTO{Date: &date, MealType: &mealType})
+	if err != nil {
+		t.Fatalf("expected moving to succeed, got %v", err)
+	}
+	if moved.Date != date || moved.MealType != mealType || moved.Servings != 4 {
+		t.Fatalf("unexpected moved meal %+v", moved)
+	}
+
+	plan, err := svc.ListMealPlan(ctx, userID, pantryID, "2026-03-02", "2026-03-08")
+	if err != nil || len(plan.Entries) != 1 {
+		t.Fatalf("expected the moved meal to be listed, got %+v, %v", plan, err)
+	}
+
+	if err := svc.DeleteMealPlanEntry(ctx, userID, entryID); err != nil {
+		t.Fatalf("expected removing to succeed, got %v", err)
+	}
+	if err := svc.DeleteMealPlanEntry(ctx, userID, entryID); !errors.Is(err, recipeDomain.ErrMealPlanNotFound) {
+		t.Fatalf("expected meal plan not found, got %v", err)
+	}
+}
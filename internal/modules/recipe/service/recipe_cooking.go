@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+)
+
+const (
+	cookingCovered      = "covered"
+	cookingInsufficient = "insufficient"
+	cookingMissing      = "missing"
+	cookingIncompatible = "incompatible"
+	cookingUnmeasured   = "unmeasured"
+	cookingSkipped      = "skipped"
+
+	maxCookingServings = 100
+)
+
+// cookingPlan is what cooking a recipe takes from the pantry.
+type cookingPlan struct {
+	ingredients []recipeDTO.CookingIngredientDTO
+	deductions  []recipeModel.RecipeCookingDeduction
+	canCook     bool
+	// stock is the pantry left after the plan.
+	stock []*cookingStock
+}
+
+// cookingStock is a pantry item with the quantity still free to be taken.
+type cookingStock struct {
+	item      *itemModel.Item
+	key       string
+	available float64
+}
+
+// PreviewCooking shows what cooking a recipe would take from a pantry
+func (rs *recipeService) PreviewCooking(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.CookRecipeDTO) (*recipeDTO.CookingPreviewDTO, error) {
+	recipe, pantryID, servings, plan, err := rs.prepareCooking(ctx, recipeID, userID, input, "PreviewCooking")
+	if err != nil {
+		return nil, err
+	}
+
+	return &recipeDTO.CookingPreviewDTO{
+		RecipeID:    recipe.ID.String(),
+		PantryID:    pantryID.String(),
+		Servings:    servings,
+		CanCook:     plan.canCook,
+		Ingredients: plan.ingredients,
+	}, nil
+}
+
+// CookRecipe takes the ingredients of a recipe from a pantry and records the
+// cooking. The plan is computed again from the current stock, so it matches
+// the preview unless the pantry changed in between. A plan the pantry does
+// not cover is only cooked when the input allows partial cooking.
+func (rs *recipeService) CookRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.CookRecipeDTO) (*recipeDTO.RecipeCookingDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	recipe, pantryID, servings, plan, err := rs.prepareCooking(ctx, recipeID, userID, input, "CookRecipe")
+	if err != nil {
+		return nil, err
+	}
+	if !plan.canCook && !input.AllowPartial {
+		logger.Warn("Pantry does not cover the recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CookRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.String("pantry_id", pantryID.String()),
+		)
+		return nil, recipeDomain.ErrInsufficientStock
+	}
+
+	cookedAt, err := cookingTime(input.CookedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	cooking := &recipeModel.RecipeCooking{
+		RecipeID:      recipe.ID,
+		RecipeVersion: currentRecipeVersion(recipe),
+		UserID:        userID,
+		PantryID:      &pantryID,
+		Servings:      servings,
+		CookedAt:      cookedAt,
+		Deductions:    plan.deductions,
+	}
+	if err := rs.cookingRepository.Apply(ctx, cooking); err != nil {
+		if errors.Is(err, recipeDomain.ErrStockChanged) {
+			logger.Warn("Pantry stock changed while cooking recipe",
+				zap.String(appLogger.FieldModule, "recipe"),
+				zap.String(appLogger.FieldFunction, "CookRecipe"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("recipe_id", recipeID.String()),
+				zap.String("pantry_id", pantryID.String()),
+			)
+			return nil, err
+		}
+		logger.Error("Failed to record recipe cooking",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CookRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.Info("Recipe cooked successfully",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "CookRecipe"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("recipe_id", recipeID.String()),
+		zap.String("pantry_id", pantryID.String()),
+		zap.Int(appLogger.FieldCount, len(cooking.Deductions)),
+	)
+
+	result := convertCookingToDTO(cooking)
+	result.Ingredients = plan.ingredients
+	return &result, nil
+}
+
+// LogRecipeCooking records that a recipe was cooked without taking anything
+// from a pantry, for recipes cooked away from home or with ingredients that
+// were not tracked.
+func (rs *recipeService) LogRecipeCooking(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.LogRecipeCookingDTO) (*recipeDTO.RecipeCookingDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil {
+		input = &recipeDTO.LogRecipeCookingDTO{}
+	}
+	if input.Servings < 0 || input.Servings > maxCookingServings {
+		return nil, fmt.Errorf("%w: servings must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxCookingServings)
+	}
+	cookedAt, err := cookingTime(input.CookedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "LogRecipeCooking")
+	if err != nil {
+		return nil, err
+	}
+
+	servings := input.Servings
+	if servings == 0 {
+		servings = recipe.ServingCount()
+	}
+	cooking := &recipeModel.RecipeCooking{
+		RecipeID:      recipe.ID,
+		RecipeVersion: currentRecipeVersion(recipe),
+		UserID:        userID,
+		Servings:      servings,
+		CookedAt:      cookedAt,
+	}
+	if err := rs.cookingRepository.Apply(ctx, cooking); err != nil {
+		logger.Error("Failed to log recipe cooking",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "LogRecipeCooking"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.Info("Recipe cooking logged",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "LogRecipeCooking"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("recipe_id", recipeID.String()),
+	)
+
+	result := convertCookingToDTO(cooking)
+	return &result, nil
+}
+
+// ListRecipeCookings lists the cooking history of a recipe, most recent first
+func (rs *recipeService) ListRecipeCookings(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) ([]recipeDTO.RecipeCookingDTO, error) {
+	if _, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "ListRecipeCookings"); err != nil {
+		return nil, err
+	}
+
+	cookings, err := rs.cookingRepository.ListByRecipeID(ctx, recipeID, userID)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list recipe cookings",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ListRecipeCookings"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result := make([]recipeDTO.RecipeCookingDTO, 0, len(cookings))
+	for _, cooking := range cookings {
+		result = append(result, convertCookingToDTO(cooking))
+	}
+	return result, nil
+}
+
+func (rs *recipeService) prepareCooking(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.CookRecipeDTO, function string) (*recipeModel.Recipe, uuid.UUID, int, cookingPlan, error) {
+	if input == nil {
+		return nil, uuid.Nil, 0, cookingPlan{}, fmt.Errorf("%w: cooking data is required", recipeDomain.ErrInvalidRequest)
+	}
+	pantryID, err := uuid.Parse(strings.TrimSpace(input.PantryID))
+	if err != nil {
+		return nil, uuid.Nil, 0, cookingPlan{}, fmt.Errorf("%w: pantry_id must be a valid UUID", recipeDomain.ErrInvalidRequest)
+	}
+	if input.Servings < 0 || input.Servings > maxCookingServings {
+		return nil, uuid.Nil, 0, cookingPlan{}, fmt.Errorf("%w: servings must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxCookingServings)
+	}
+
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, function)
+	if err != nil {
+		return nil, uuid.Nil, 0, cookingPlan{}, err
+	}
+	if err := checkPantryAccess(ctx, rs.pantryService, pantryID, userID, function); err != nil {
+		return nil, uuid.Nil, 0, cookingPlan{}, err
+	}
+
+	items, err := rs.itemRepository.ListByPantryID(ctx, pantryID)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list items from pantry",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return nil, uuid.Nil, 0, cookingPlan{}, err
+	}
+
+	servings := input.Servings
+	if servings == 0 {
+		servings = recipe.ServingCount()
+	}
+	return recipe, pantryID, servings, planCooking(recipe, servings, items, input.SkipIngredients), nil
+}
+
+// planCooking matches the ingredients of a recipe scaled to the servings with
+// the pantry items and decides what to take from each item. Items of the same
+// product are used from the one expiring first. Ingredients without an amount
+// ("a gosto") are not taken from the pantry.
+func planCooking(recipe *recipeModel.Recipe, servings int, items []*itemModel.Item, skip []string) cookingPlan {
+	scale := float64(servings) / float64(recipe.ServingCount())
+
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[normalizeIngredientName(name)] = true
+	}
+	stock := indexCookingStock(items)
+
+	plan := cookingPlan{canCook: true, stock: stock}
+	for _, ingredient := range recipe.Ingredients {
+		key := normalizeIngredientName(ingredient.Name)
+		if key == "" {
+			continue
+		}
+		line := recipeDTO.CookingIngredientDTO{
+			Name:       strings.TrimSpace(ingredient.Name),
+			Unit:       strings.TrimSpace(ingredient.Unit),
+			Deductions: []recipeDTO.CookingDeductionDTO{},
+		}
+		switch {
+		case skipped[key]:
+			line.Status = cookingSkipped
+		case ingredient.Amount == nil || *ingredient.Amount <= 0:
+			line.Status = cookingUnmeasured
+		default:
+			needed := *ingredient.Amount * scale
+			rounded := math.Round(needed*100) / 100
+			line.Needed = &rounded
+			unit := line.Unit
+			if unit == "" {
+				unit = "un"
+			}
+			line.Status, line.Deductions = takeFromStock(line.Name, needed, unit, matchCookingStock(key, stock), &plan.deductions)
+		}
+		if line.Status != cookingCovered && line.Status != cookingUnmeasured && line.Status != cookingSkipped {
+			plan.canCook = false
+		}
+		plan.ingredients = append(plan.ingredients, line)
+	}
+	return plan
+}
+
+// takeFromStock takes an amount from the candidate items in order, recording
+// each deduction in the unit of the item.
+func takeFromStock(ingredient string, needed float64, unit string, candidates []*cookingStock, deductions *[]recipeModel.RecipeCookingDeduction) (string, []recipeDTO.CookingDeductionDTO) {
+	if len(candidates) == 0 {
+		return cookingMissing, []recipeDTO.CookingDeductionDTO{}
+	}
+
+	taken := []recipeDTO.CookingDeductionDTO{}
+	remaining := needed
+	convertible := false
+	for _, entry := range candidates {
+		wanted, ok := units.Convert(remaining, unit, entry.item.Unit)
+		if !ok {
+			continue
+		}
+		convertible = true
+		if entry.available <= 0 {
+			continue
+		}
+
+		quantity := roundStockQuantity(math.Min(wanted, entry.available))
+		back, _ := units.Convert(quantity, entry.item.Unit, unit)
+		remaining -= back
+
+		before := entry.available
+		entry.available = roundStockQuantity(entry.available - quantity)
+		*deductions = append(*deductions, recipeModel.RecipeCookingDeduction{
+			PantryItemID:     entry.item.ID,
+			Ingredient:       ingredient,
+			Name:             entry.item.Name,
+			Quantity:         quantity,
+			Unit:             entry.item.Unit,
+			PreviousQuantity: before,
+		})
+		taken = append(taken, recipeDTO.CookingDeductionDTO{
+			PantryItemID: entry.item.ID.String(),
+			Name:         entry.item.Name,
+			Quantity:     quantity,
+			Unit:         entry.item.Unit,
+			Before:       before,
+			After:        entry.available,
+		})
+		if remaining <= needed*1e-6 {
+			break
+		}
+	}
+
+	switch {
+	case !convertible:
+		return cookingIncompatible, taken
+	case remaining > needed*1e-6:
+		return cookingInsufficient, taken
+	default:
+		return cookingCovered, taken
+	}
+}
+
+// indexCookingStock lists the pantry items in stock, the ones expiring first
+// ahead and items without an expiry date last.
+func indexCookingStock(items []*itemModel.Item) []*cookingStock {
+	stock := make([]*cookingStock, 0, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			continue
+		}
+		stock = append(stock, &cookingStock{item: item, key: normalizeIngredientName(item.Name), available: item.Quantity})
+	}
+	sort.SliceStable(stock, func(i, j int) bool {
+		a, b := stock[i].item, stock[j].item
+		switch {
+		case a.ExpiresAt != nil && b.ExpiresAt != nil && !a.ExpiresAt.Equal(*b.ExpiresAt):
+			return a.ExpiresAt.Before(*b.ExpiresAt)
+		case (a.ExpiresAt == nil) != (b.ExpiresAt == nil):
+			return a.ExpiresAt != nil
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return stock
+}
+
+// matchCookingStock finds the items of an ingredient. Items with the same name
+// win; otherwise an item whose name appears in the ingredient name is used, so
+// "cebola roxa picada" takes from "Cebola". The longest such name wins.
+func matchCookingStock(key string, stock []*cookingStock) []*cookingStock {
+	var exact []*cookingStock
+	for _, entry := range stock {
+		if entry.key == key {
+			exact = append(exact, entry)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+
+	padded := " " + key + " "
+	var best []*cookingStock
+	bestLength := 0
+	for _, entry := range stock {
+		if entry.key == "" || !strings.Contains(padded, " "+entry.key+" ") {
+			continue
+		}
+		switch {
+		case len(entry.key) > bestLength:
+			best, bestLength = []*cookingStock{entry}, len(entry.key)
+		case len(entry.key) == bestLength:
+			best = append(best, entry)
+		}
+	}
+	return best
+}
+
+// normalizeIngredientName is the key used to match ingredients with pantry
+// items: lower case, without accents and extra spaces, each word in the
+// singular.
+func normalizeIngredientName(name string) string {
+	words := strings.Fields(units.Fold(name))
+	for i, word := range words {
+		if len(word) > 3 && strings.HasSuffix(word, "s") {
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func roundStockQuantity(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
+
+// cookingTime is when a recipe was cooked: now, or the informed time when it
+// is not in the future.
+func cookingTime(informed *time.Time) (time.Time, error) {
+	now := time.Now().UTC()
+	if informed == nil {
+		return now, nil
+	}
+	if informed.After(now.Add(time.Minute)) {
+		return time.Time{}, fmt.Errorf("%w: cooked_at cannot be in the future", recipeDomain.ErrInvalidRequest)
+	}
+	return informed.UTC(), nil
+}
+
+func convertCookingToDTO(cooking *recipeModel.RecipeCooking) recipeDTO.RecipeCookingDTO {
+	result := recipeDTO.RecipeCookingDTO{
+		ID:            cooking.ID.String(),
+		RecipeID:      cooking.RecipeID.String(),
+		RecipeVersion: cooking.RecipeVersion,
+		Servings:      cooking.Servings,
+		CookedAt:      cooking.CookedAt,
+		Deductions:    make([]recipeDTO.CookingDeductionDTO, 0, len(cooking.Deductions)),
+	}
+	if cooking.PantryID != nil {
+		result.PantryID = cooking.PantryID.String()
+	}
+	for _, deduction := range cooking.Deductions {
+		result.Deductions = append(result.Deductions, recipeDTO.CookingDeductionDTO{
+			Ingredient:   deduction.Ingredient,
+			PantryItemID: deduction.PantryItemID.String(),
+			Name:         deduction.Name,
+			Quantity:     deduction.Quantity,
+			Unit:         deduction.Unit,
+			Before:       deduction.PreviousQuantity,
+			After:        roundStockQuantity(deduction.PreviousQuantity - deduction.Quantity),
+		})
+	}
+	return result
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+)
+
+type stubCookingRepository struct {
+	applied []*recipeModel.RecipeCooking
+	err     error
+}
+
+func (r *stubCookingRepository) Apply(ctx context.Context, cooking *recipeModel.RecipeCooking) error {
+	if r.err != nil {
+		return r.err
+	}
+	cooking.ID = uuid.New()
+	r.applied = append(r.applied, cooking)
+	return nil
+}
+
+func (r *stubCookingRepository) ListByRecipeID(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) ([]*recipeModel.RecipeCooking, error) {
+	return r.applied, nil
+}
+
+func cookingAmount(value float64) *float64 {
+	return &value
+}
+
+func TestPlanCooking_ConvertsUnitsAndUsesExpiringFirst(t *testing.T) {
+	servings := 2
+	recipe := &recipeModel.Recipe{
+		ServingSize: &servings,
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Leite", Amount: cookingAmount(1), Unit: "xícara"},
+			{Name: "Ovos", Amount: cookingAmount(3)},
+			{Name: "Cebola roxa picada", Amount: cookingAmount(100), Unit: "g"},
+			{Name: "Açúcar", Amount: cookingAmount(2), Unit: "colher de sopa"},
+			{Name: "Sal", Unit: "a gosto"},
+			{Name: "Manteiga", Amount: cookingAmount(50), Unit: "g"},
+			{Name: "Fermento", Amount: cookingAmount(1), Unit: "colher de chá"},
+		},
+	}
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(72 * time.Hour)
+	items := []*model.Item{
+		{ID: uuid.New(), Name: "leite", Quantity: 1, Unit: "l", ExpiresAt: &later},
+		{ID: uuid.New(), Name: "Leite", Quantity: 0.3, Unit: "l", ExpiresAt: &soon},
+		{ID: uuid.New(), Name: "Ovo", Quantity: 4, Unit: "un"},
+		{ID: uuid.New(), Name: "Cebola", Quantity: 1, Unit: "kg"},
+		{ID: uuid.New(), Name: "Acucar", Quantity: 1, Unit: "kg"},
+		{ID: uuid.New(), Name: "Manteiga", Quantity: 0, Unit: "g"},
+		{ID: uuid.New(), Name: "Fermento", Quantity: 1, Unit: "pacote"},
+	}
+
+	plan := planCooking(recipe, 4, items, []string{"sal"})
+
+	if plan.canCook {
+		t.Fatalf("expected recipe not to be cookable without enough eggs and butter")
+	}
+	if len(plan.ingredients) != 7 {
+		t.Fatalf("expected 7 ingredients, got %d", len(plan.ingredients))
+	}
+
+	milk := plan.ingredients[0]
+	if milk.Status != cookingCovered || len(milk.Deductions) != 2 {
+		t.Fatalf("expected milk covered by two items, got %+v", milk)
+	}
+	if milk.Deductions[0].PantryItemID != items[1].ID.String() || milk.Deductions[0].Quantity != 0.3 || milk.Deductions[0].After != 0 {
+		t.Fatalf("expected the milk expiring first to be used up, got %+v", milk.Deductions[0])
+	}
+	if milk.Deductions[1].Quantity != 0.18 || milk.Deductions[1].After != 0.82 {
+		t.Fatalf("expected 0.18 l from the second milk, got %+v", milk.Deductions[1])
+	}
+
+	eggs := plan.ingredients[1]
+	if eggs.Status != cookingInsufficient || *eggs.Needed != 6 || eggs.Deductions[0].Quantity != 4 {
+		t.Fatalf("expected the 4 eggs in stock to be taken for the 6 needed, got %+v", eggs)
+	}
+
+	onion := plan.ingredients[2]
+	if onion.Status != cookingCovered || onion.Deductions[0].Quantity != 0.2 || onion.Deductions[0].Unit != "kg" {
+		t.Fatalf("expected 0.2 kg of onion, got %+v", onion)
+	}
+
+	sugar := plan.ingredients[3]
+	if sugar.Status != cookingIncompatible || len(sugar.Deductions) != 0 {
+		t.Fatalf("expected sugar in spoons to be incompatible with kg, got %+v", sugar)
+	}
+	if plan.ingredients[4].Status != cookingSkipped {
+		t.Fatalf("expected salt to be skipped, got %s", plan.ingredients[4].Status)
+	}
+	if plan.ingredients[5].Status != cookingMissing {
+		t.Fatalf("expected butter out of stock to be missing, got %s", plan.ingredients[5].Status)
+	}
+	if plan.ingredients[6].Status != cookingIncompatible {
+		t.Fatalf("expected yeast in packs to be incompatible, got %s", plan.ingredients[6].Status)
+	}
+	if len(plan.deductions) != 4 {
+		t.Fatalf("expected 4 deductions, got %d", len(plan.deductions))
+	}
+}
+
+func TestPlanCooking_SharesStockBetweenIngredients(t *testing.T) {
+	recipe := &recipeModel.Recipe{
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Ovo", Amount: cookingAmount(2), Unit: "un"},
+			{Name: "Ovos", Amount: cookingAmount(2), Unit: "unidades"},
+		},
+	}
+	eggID := uuid.New()
+
+	plan := planCooking(recipe, 1, []*model.Item{{ID: eggID, Name: "Ovos", Quantity: 3, Unit: "un"}}, nil)
+
+	if plan.ingredients[0].Status != cookingCovered {
+		t.Fatalf("expected first eggs covered, got %s", plan.ingredients[0].Status)
+	}
+	second := plan.ingredients[1]
+	if second.Status != cookingInsufficient || second.Deductions[0].Quantity != 1 || second.Deductions[0].Before != 1 {
+		t.Fatalf("expected only one egg left for the second ingredient, got %+v", second)
+	}
+}
+
+func TestRecipeService_CookRecipe(t *testing.T) {
+	userID, pantryID := uuid.New(), uuid.New()
+	recipe := &recipeModel.Recipe{
+		ID:     uuid.New(),
+		UserID: userID,
+		Title:  "Arroz",
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Arroz", Amount: cookingAmount(200), Unit: "g"},
+		},
+	}
+	riceID := uuid.New()
+	cookings := &stubCookingRepository{}
+	svc := &recipeService{
+		itemRepository:    &stubItemRepository{items: []*model.Item{{ID: riceID, PantryID: pantryID, Name: "Arroz", Quantity: 1, Unit: "kg"}}},
+		pantryService:     &stubPantryService{},
+		recipeRepository:  newMemoryRecipeRepository(recipe),
+		cookingRepository: cookings,
+	}
+
+	cookedAt := time.Now().Add(-2 * time.Hour)
+	result, err := svc.CookRecipe(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{
+		PantryID: pantryID.String(),
+		Servings: 2,
+		CookedAt: &cookedAt,
+	})
+	if err != nil {
+		t.Fatalf("expected cooking to succeed, got %v", err)
+	}
+	if len(cookings.applied) != 1 {
+		t.Fatalf("expected one cooking to be recorded, got %d", len(cookings.applied))
+	}
+	applied := cookings.applied[0]
+	if applied.Servings != 2 || applied.PantryID == nil || *applied.PantryID != pantryID || !applied.CookedAt.Equal(cookedAt) {
+		t.Fatalf("unexpected cooking %+v", applied)
+	}
+	if len(result.Deductions) != 1 || result.Deductions[0].PantryItemID != riceID.String() || result.Deductions[0].Quantity != 0.4 {
+		t.Fatalf("expected 0.4 kg of rice for two servings, got %+v", result.Deductions)
+	}
+
+	future := time.Now().Add(time.Hour)
+	_, err = svc.CookRecipe(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: pantryID.String(), CookedAt: &future})
+	if !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected cooking in the future to be refused, got %v", err)
+	}
+
+	_, err = svc.PreviewCooking(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: "despensa"})
+	if !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected invalid pantry id to be refused, got %v", err)
+	}
+
+	cookings.err = recipeDomain.ErrStockChanged
+	_, err = svc.CookRecipe(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: pantryID.String()})
+	if !errors.Is(err, recipeDomain.ErrStockChanged) {
+		t.Fatalf("expected stock changed error, got %v", err)
+	}
+
+	cookings.err = nil
+	_, err = svc.CookRecipe(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: pantryID.String(), Servings: 10})
+	if !errors.Is(err, recipeDomain.ErrInsufficientStock) || len(cookings.applied) != 1 {
+		t.Fatalf("expected a recipe the pantry does not cover to be refused, got %v", err)
+	}
+	result, err = svc.CookRecipe(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: pantryID.String(), Servings: 10, AllowPartial: true})
+	if err != nil || len(result.Deductions) != 1 || result.Deductions[0].Quantity != 1 {
+		t.Fatalf("expected partial cooking to take the whole kilo of rice, got %+v (%v)", result, err)
+	}
+}
+
+func TestRecipeService_LogRecipeCooking(t *testing.T) {
+	userID := uuid.New()
+	servings := 4
+	recipe := &recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Lasanha", ServingSize: &servings}
+	cookings := &stubCookingRepository{}
+	svc := &recipeService{
+		recipeRepository:  newMemoryRecipeRepository(recipe),
+		cookingRepository: cookings,
+	}
+
+	result, err := svc.LogRecipeCooking(context.Background(), recipe.ID, userID, nil)
+	if err != nil {
+		t.Fatalf("expected the cooking to be logged, got %v", err)
+	}
+	if result.Servings != 4 || result.PantryID != "" || len(result.Deductions) != 0 {
+		t.Fatalf("expected a cooking of the recipe servings without pantry, got %+v", result)
+	}
+	if len(cookings.applied) != 1 || cookings.applied[0].PantryID != nil {
+		t.Fatalf("expected one cooking without pantry, got %+v", cookings.applied)
+	}
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	result, err = svc.LogRecipeCooking(context.Background(), recipe.ID, userID, &recipeDTO.LogRecipeCookingDTO{Servings: 2, CookedAt: &yesterday})
+	if err != nil || result.Servings != 2 || !result.CookedAt.Equal(yesterday) {
+		t.Fatalf("expected the informed servings and date, got %+v (%v)", result, err)
+	}
+
+	future := time.Now().Add(time.Hour)
+	if _, err := svc.LogRecipeCooking(context.Background(), recipe.ID, userID, &recipeDTO.LogRecipeCookingDTO{CookedAt: &future}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected cooking in the future to be refused, got %v", err)
+	}
+	if _, err := svc.LogRecipeCooking(context.Background(), recipe.ID, uuid.New(), nil); !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected recipe not found for another user, got %v", err)
+	}
+}
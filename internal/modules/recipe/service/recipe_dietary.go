@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
+	pantryDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/domain"
+	profileDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/domain"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/nclsgg/despensa-digital/backend/pkg/dietary"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const dietarySourceRecipe = "recipe"
+
+// householdDietaryMembers lists the members of a pantry, or of every pantry of
+// the user when pantryID is nil, with the dietary restrictions of their
+// profiles. The user is always a member. A failed lookup only leaves the
+// household out of the checks.
+func householdDietaryMembers(ctx context.Context, pantryService pantryDomain.PantryService, profileRepository profileDomain.ProfileRepository, userID uuid.UUID, pantryID *uuid.UUID, function string) []dietary.Member {
+	members, err := loadHouseholdDietaryMembers(ctx, pantryService, profileRepository, userID, pantryID, function)
+	if err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to get the dietary restrictions of the household",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return members
+}
+
+func loadHouseholdDietaryMembers(ctx context.Context, pantryService pantryDomain.PantryService, profileRepository profileDomain.ProfileRepository, userID uuid.UUID, pantryID *uuid.UUID, function string) ([]dietary.Member, error) {
+	var pantryIDs []uuid.UUID
+	switch {
+	case pantryService == nil:
+	case pantryID != nil:
+		pantryIDs = []uuid.UUID{*pantryID}
+	default:
+		pantries, err := pantryService.ListPantriesByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, pantry := range pantries {
+			pantryIDs = append(pantryIDs, pantry.ID)
+		}
+	}
+
+	var users []dietary.Member
+	for _, id := range pantryIDs {
+		pantryUsers, err := pantryService.ListUsersInPantry(ctx, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range pantryUsers {
+			users = append(users, dietary.Member{UserID: user.UserID, Email: user.Email})
+		}
+	}
+
+	return dietary.Household(userID, users, dietary.ProfileRestrictions(ctx, profileRepository, "recipe", function))
+}
+
+// checkRecipeDietary flags the ingredients that break the restrictions of a
+// member of the household or of the recipe itself.
+func checkRecipeDietary(ingredients []string, restrictions []string, members []dietary.Member) []llmDTO.DietaryWarningDTO {
+	var warnings []llmDTO.DietaryWarningDTO
+	for _, warning := range dietary.CheckHousehold(ingredients, restrictions, dietarySourceRecipe, members) {
+		warnings = append(warnings, convertDietaryWarning(warning))
+	}
+	return warnings
+}
+
+func recipeIngredientNames(recipe *recipeModel.Recipe) []string {
+	names := make([]string, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		names = append(names, ingredient.Name)
+	}
+	return names
+}
+
+func convertDietaryWarning(warning dietary.Warning) llmDTO.DietaryWarningDTO {
+	result := llmDTO.DietaryWarningDTO{
+		Ingredient:  warning.Ingredient,
+		Restriction: warning.Restriction,
+		Allergens:   warning.Allergens,
+		Source:      warning.Source,
+	}
+	if warning.Member != nil {
+		result.UserID = warning.Member.UserID.String()
+		result.Email = warning.Member.Email
+	}
+	return result
+}
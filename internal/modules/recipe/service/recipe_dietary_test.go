@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
+	pantryModel "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/model"
+	profileModel "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/model"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+)
+
+func TestRecipeService_SaveRecipe_FlagsDietaryConflicts(t *testing.T) {
+	userID, memberID, pantryID := uuid.New(), uuid.New(), uuid.New()
+	profiles := map[uuid.UUID]*profileModel.Profile{
+		userID:   {DietaryRestrictions: profileModel.StringArray{"Low carb"}},
+		memberID: {DietaryRestrictions: profileModel.StringArray{"Alergia a ovo"}},
+	}
+	svc := &recipeService{
+		pantryService: &stubPantryService{
+			listPantriesFn: func(ctx context.Context, id uuid.UUID) ([]*pantryModel.Pantry, error) {
+				return []*pantryModel.Pantry{{ID: pantryID}}, nil
+			},
+			listUsersFn: func(ctx context.Context, id, requester uuid.UUID) ([]*pantryModel.PantryUserInfo, error) {
+				return []*pantryModel.PantryUserInfo{
+					{UserID: userID, Email: "dono@example.com"},
+					{UserID: memberID, Email: "membro@example.com"},
+				}, nil
+			},
+		},
+		recipeRepository:  newMemoryRecipeRepository(),
+		profileRepository: &stubProfileRepository{profiles: profiles},
+	}
+
+	input := saveRecipeInput("Omelete de queijo", "Ovos", "Queijo minas", "Sal")
+	input.DietaryRestrictions = []string{"vegetariano", "sem lactose"}
+	saved, err := svc.SaveRecipe(context.Background(), input, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []llmDTO.DietaryWarningDTO{
+		{Ingredient: "Ovos", Restriction: "egg_free", Allergens: []string{"egg"}, Source: "member", UserID: memberID.String(), Email: "membro@example.com"},
+		{Ingredient: "Queijo minas", Restriction: "lactose_free", Allergens: []string{"lactose"}, Source: "recipe"},
+	}
+	if !reflect.DeepEqual(saved.DietaryWarnings, expected) {
+		t.Fatalf("expected warnings %+v, got %+v", expected, saved.DietaryWarnings)
+	}
+}
+
+func TestRecipeService_SaveRecipe_IgnoresHouseholdLookupFailures(t *testing.T) {
+	svc := &recipeService{pantryService: &stubPantryService{}, recipeRepository: newMemoryRecipeRepository()}
+
+	input := saveRecipeInput("Salada", "Alface", "Tomate")
+	input.DietaryRestrictions = []string{"vegano"}
+	saved, err := svc.SaveRecipe(context.Background(), input, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saved.DietaryWarnings) != 0 {
+		t.Fatalf("expected no warnings, got %+v", saved.DietaryWarnings)
+	}
+}
+
+func TestRecipeService_UpdateRecipe_FlagsDietaryConflictsOfChangedIngredients(t *testing.T) {
+	userID := uuid.New()
+	svc := &recipeService{
+		pantryService:     &stubPantryService{},
+		recipeRepository:  newMemoryRecipeRepository(),
+		profileRepository: &stubProfileRepository{profiles: map[uuid.UUID]*profileModel.Profile{}},
+	}
+	input := saveRecipeInput("Salada", "Alface", "Tomate")
+	input.DietaryRestrictions = []string{"vegano"}
+	saved, err := svc.SaveRecipe(context.Background(), input, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recipeID := uuid.MustParse(saved.ID)
+
+	title := "Salada com queijo"
+	updated, err := svc.UpdateRecipe(context.Background(), recipeID, userID, &recipeDTO.UpdateRecipeDTO{Title: &title})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated.DietaryWarnings) != 0 {
+		t.Fatalf("expected no warnings when the ingredients do not change, got %+v", updated.DietaryWarnings)
+	}
+
+	updated, err = svc.UpdateRecipe(context.Background(), recipeID, userID, &recipeDTO.UpdateRecipeDTO{
+		Ingredients: []recipeDTO.SaveRecipeIngredientDTO{{Name: "Alface", Unit: "a gosto"}, {Name: "Queijo minas", Unit: "a gosto"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated.DietaryWarnings) != 1 || updated.DietaryWarnings[0].Ingredient != "Queijo minas" || updated.DietaryWarnings[0].Source != "recipe" {
+		t.Fatalf("expected the cheese to conflict with the vegan recipe, got %+v", updated.DietaryWarnings)
+	}
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	duplicatePolicyWarn    = "warn"
+	duplicatePolicyRefuse  = "refuse"
+	duplicatePolicyReplace = "replace"
+
+	// recipeDuplicateThreshold is the similarity from which a recipe is taken
+	// as a near-duplicate of a saved one. The similarity is the mean of the
+	// title and ingredient overlaps, so the same ingredients under another
+	// title are not enough, and neither is the same title with half of the
+	// ingredients changed.
+	recipeDuplicateThreshold = 0.75
+	maxRecipeDuplicates      = 5
+)
+
+// recipeTitleStopWords are left out when comparing titles: "Bolo de cenoura"
+// and "Bolo cenoura" are the same title.
+var recipeTitleStopWords = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "e": true, "de": true, "da": true, "do": true,
+	"das": true, "dos": true, "com": true, "sem": true, "em": true, "na": true, "no": true,
+	"ao": true, "para": true, "pra": true, "um": true, "uma": true,
+}
+
+// recipeSavePlan is how a recipe is stored next to the saved ones.
+type recipeSavePlan struct {
+	recipe     *recipeModel.Recipe
+	replaced   bool
+	duplicates []recipeDTO.RecipeDuplicateDTO
+}
+
+// FindRecipeDuplicates lists the saved recipes similar to a recipe
+func (rs *recipeService) FindRecipeDuplicates(ctx context.Context, userID uuid.UUID, input *recipeDTO.SaveRecipeDTO) ([]recipeDTO.RecipeDuplicateDTO, error) {
+	if input == nil || strings.TrimSpace(input.Title) == "" || len(input.Ingredients) == 0 {
+		return nil, fmt.Errorf("%w: title and ingredients are required", recipeDomain.ErrInvalidRequest)
+	}
+
+	recipe := &recipeModel.Recipe{Title: input.Title}
+	for _, ingredient := range input.Ingredients {
+		recipe.Ingredients = append(recipe.Ingredients, recipeModel.RecipeIngredient{Name: ingredient.Name})
+	}
+
+	saved, err := rs.savedRecipes(ctx, userID, "FindRecipeDuplicates")
+	if err != nil {
+		return nil, err
+	}
+	return findRecipeDuplicates(recipe, saved), nil
+}
+
+// resolveDuplicatePolicy is the handling of near-duplicates asked for a
+// recipe, or the configured one. Anything but refuse in the configuration
+// warns.
+func (rs *recipeService) resolveDuplicatePolicy(requested string) string {
+	if requested != "" {
+		return requested
+	}
+	if strings.EqualFold(strings.TrimSpace(rs.duplicatePolicy), duplicatePolicyRefuse) {
+		return duplicatePolicyRefuse
+	}
+	return duplicatePolicyWarn
+}
+
+func (rs *recipeService) savedRecipes(ctx context.Context, userID uuid.UUID, function string) ([]*recipeModel.Recipe, error) {
+	saved, err := rs.recipeRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list saved recipes",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// planRecipeSave decides how a recipe is stored: as a new recipe, over the
+// saved recipe to replace, or not at all when near-duplicates are refused. The
+// content saved is recorded as a version of the recipe.
+func planRecipeSave(recipe *recipeModel.Recipe, input *recipeDTO.SaveRecipeDTO, policy string, saved []*recipeModel.Recipe) (*recipeSavePlan, error) {
+	switch policy {
+	case duplicatePolicyWarn, duplicatePolicyRefuse, duplicatePolicyReplace:
+	default:
+		return nil, fmt.Errorf("%w: on_duplicate must be warn, refuse or replace", recipeDomain.ErrInvalidRequest)
+	}
+
+	plan := &recipeSavePlan{recipe: recipe, duplicates: findRecipeDuplicates(recipe, saved)}
+	now := time.Now().UTC()
+
+	var target *recipeModel.Recipe
+	if raw := strings.TrimSpace(input.ReplaceRecipeID); raw != "" {
+		replaceID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid replace_recipe_id", recipeDomain.ErrInvalidRequest)
+		}
+		for _, candidate := range saved {
+			if candidate.ID == replaceID {
+				target = candidate
+				break
+			}
+		}
+		if target == nil {
+			return nil, recipeDomain.ErrRecipeNotFound
+		}
+	} else if len(plan.duplicates) > 0 {
+		switch policy {
+		case duplicatePolicyRefuse:
+			closest := plan.duplicates[0]
+			return nil, fmt.Errorf("%w: %q is similar to the saved recipe %q (%s)", recipeDomain.ErrDuplicateRecipe, recipe.Title, closest.Title, closest.ID)
+		case duplicatePolicyReplace:
+			for _, candidate := range saved {
+				if candidate.ID.String() == plan.duplicates[0].ID {
+					target = candidate
+					break
+				}
+			}
+		}
+	}
+
+	if target != nil {
+		previous := snapshotRecipeVersion(target)
+		replaceRecipeContent(target, recipe)
+		recordRecipeVersion(target, &previous, recipe.UserID, now)
+		plan.recipe = target
+		plan.replaced = true
+	} else {
+		recordRecipeVersion(recipe, nil, recipe.UserID, now)
+	}
+	return plan, nil
+}
+
+// replaceRecipeContent overwrites the content of a saved recipe with the one of
+// a new version. What the user added to it (tags, favorite, rating, notes and
+// cooking history) is kept.
+func replaceRecipeContent(target *recipeModel.Recipe, recipe *recipeModel.Recipe) {
+	target.Title = recipe.Title
+	target.Description = recipe.Description
+	target.Ingredients = recipe.Ingredients
+	target.Instructions = recipe.Instructions
+	target.CookingTime = recipe.CookingTime
+	target.PreparationTime = recipe.PreparationTime
+	target.TotalTime = recipe.TotalTime
+	target.ServingSize = recipe.ServingSize
+	target.Difficulty = recipe.Difficulty
+	target.MealType = recipe.MealType
+	target.Cuisine = recipe.Cuisine
+	target.DietaryRestrictions = recipe.DietaryRestrictions
+	target.NutritionInfo = recipe.NutritionInfo
+	target.Tips = recipe.Tips
+	target.SourceURL = recipe.SourceURL
+	target.GeneratedAt = recipe.GeneratedAt
+	target.OriginRecipeID = nil
+	target.OriginAuthor = ""
+}
+
+// findRecipeDuplicates compares a recipe with the saved ones by normalized
+// title and ingredient set and keeps the near-duplicates, most similar first.
+func findRecipeDuplicates(recipe *recipeModel.Recipe, saved []*recipeModel.Recipe) []recipeDTO.RecipeDuplicateDTO {
+	title := recipeTitleWords(recipe.Title)
+	ingredients := recipeIngredientKeys(recipe)
+
+	duplicates := []recipeDTO.RecipeDuplicateDTO{}
+	for _, candidate := range saved {
+		titleSimilarity := wordOverlap(title, recipeTitleWords(candidate.Title))
+		ingredientOverlap := ingredientSetOverlap(ingredients, recipeIngredientKeys(candidate))
+		similarity := (titleSimilarity + ingredientOverlap) / 2
+		if similarity < recipeDuplicateThreshold {
+			continue
+		}
+		duplicates = append(duplicates, recipeDTO.RecipeDuplicateDTO{
+			ID:                candidate.ID.String(),
+			Title:             candidate.Title,
+			Similarity:        roundSimilarity(similarity),
+			TitleSimilarity:   roundSimilarity(titleSimilarity),
+			IngredientOverlap: roundSimilarity(ingredientOverlap),
+		})
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		if duplicates[i].Similarity != duplicates[j].Similarity {
+			return duplicates[i].Similarity > duplicates[j].Similarity
+		}
+		return duplicates[i].Title < duplicates[j].Title
+	})
+	if len(duplicates) > maxRecipeDuplicates {
+		duplicates = duplicates[:maxRecipeDuplicates]
+	}
+	return duplicates
+}
+
+// recipeTitleWords are the words of a normalized title, without punctuation
+// and stop words.
+func recipeTitleWords(title string) []string {
+	normalized := normalizeIngredientName(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, title))
+
+	var words []string
+	for _, word := range strings.Fields(normalized) {
+		if !recipeTitleStopWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func recipeIngredientKeys(recipe *recipeModel.Recipe) []string {
+	seen := make(map[string]bool, len(recipe.Ingredients))
+	keys := make([]string, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		key := normalizeIngredientName(ingredient.Name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// wordOverlap is the Jaccard index of two word sets.
+func wordOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, word := range a {
+		set[word] = true
+	}
+	union := len(set)
+	common := 0
+	counted := make(map[string]bool, len(b))
+	for _, word := range b {
+		if counted[word] {
+			continue
+		}
+		counted[word] = true
+		if set[word] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+// ingredientSetOverlap is the Jaccard index of two ingredient sets. As when
+// cooking, an ingredient whose name contains the other counts as the same:
+// "cebola roxa picada" and "cebola".
+func ingredientSetOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	used := make([]bool, len(b))
+	common := 0
+	for _, key := range a {
+		for j, other := range b {
+			if used[j] || !sameIngredient(key, other) {
+				continue
+			}
+			used[j] = true
+			common++
+			break
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func sameIngredient(a, b string) bool {
+	if a == b {
+		return true
+	}
+	paddedA, paddedB := " "+a+" ", " "+b+" "
+	return strings.Contains(paddedA, paddedB) || strings.Contains(paddedB, paddedA)
+}
+
+func roundSimilarity(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func convertSavePlanToDTO(plan *recipeSavePlan) *recipeDTO.SavedRecipeDTO {
+	return &recipeDTO.SavedRecipeDTO{
+		ID:         plan.recipe.ID.String(),
+		Title:      plan.recipe.Title,
+		Replaced:   plan.replaced,
+		Duplicates: plan.duplicates,
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+)
+
+func savedCarrotCake(userID uuid.UUID) *recipeModel.Recipe {
+	rating := 4
+	return &recipeModel.Recipe{
+		ID:     uuid.New(),
+		UserID: userID,
+		Title:  "Bolo de cenoura",
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Cenouras"}, {Name: "Ovos"}, {Name: "Farinha de trigo"}, {Name: "Açúcar"}, {Name: "Óleo"},
+		},
+		Tags:   recipeModel.RecipeTagsJSON{"bolo"},
+		Rating: &rating,
+	}
+}
+
+func saveRecipeInput(title string, ingredients ...string) *recipeDTO.SaveRecipeDTO {
+	input := &recipeDTO.SaveRecipeDTO{
+		ID:           uuid.New().String(),
+		Title:        title,
+		Instructions: []recipeDTO.SaveRecipeInstructionDTO{{Step: 1, Description: "Misture e asse"}},
+	}
+	for _, name := range ingredients {
+		input.Ingredients = append(input.Ingredients, recipeDTO.SaveRecipeIngredientDTO{Name: name, Unit: "a gosto"})
+	}
+	return input
+}
+
+func TestFindRecipeDuplicates(t *testing.T) {
+	saved := savedCarrotCake(uuid.New())
+
+	for name, c := range map[string]struct {
+		input     *recipeDTO.SaveRecipeDTO
+		duplicate bool
+	}{
+		"same recipe with another spelling": {saveRecipeInput("Bolo Cenoura!", "cenoura ralada", "ovo", "farinha de trigo", "acucar", "oleo", "fermento"), true},
+		"another flavor":                    {saveRecipeInput("Bolo de laranja", "Laranja", "Ovos", "Farinha de trigo", "Açúcar", "Óleo"), false},
+		"same ingredients, another dish":    {saveRecipeInput("Panqueca", "Cenouras", "Ovos", "Farinha de trigo", "Açúcar", "Óleo"), false},
+	} {
+		recipe, err := (&recipeService{}).convertSaveRecipeDTOToModel(c.input, saved.UserID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		duplicates := findRecipeDuplicates(recipe, []*recipeModel.Recipe{saved})
+		if (len(duplicates) > 0) != c.duplicate {
+			t.Fatalf("%s: expected duplicate %v, got %+v", name, c.duplicate, duplicates)
+		}
+	}
+
+	recipe, _ := (&recipeService{}).convertSaveRecipeDTOToModel(saveRecipeInput("Bolo Cenoura!", "cenoura ralada", "ovo", "farinha de trigo", "acucar", "oleo", "fermento"), saved.UserID)
+	duplicate := findRecipeDuplicates(recipe, []*recipeModel.Recipe{saved})[0]
+	if duplicate.ID != saved.ID.String() || duplicate.TitleSimilarity != 1 || duplicate.IngredientOverlap != 0.83 || duplicate.Similarity != 0.92 {
+		t.Fatalf("unexpected duplicate %+v", duplicate)
+	}
+}
+
+func TestRecipeService_SaveRecipe_DuplicatePolicies(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+	input := func() *recipeDTO.SaveRecipeDTO {
+		return saveRecipeInput("Bolo de Cenoura", "Cenoura", "Ovos", "Farinha de trigo", "Açúcar", "Óleo", "Fermento")
+	}
+
+	saved := savedCarrotCake(userID)
+	repo := newMemoryRecipeRepository(saved)
+	svc := &recipeService{recipeRepository: repo}
+	result, err := svc.SaveRecipe(ctx, input(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Replaced || len(result.Duplicates) != 1 || result.Duplicates[0].ID != saved.ID.String() || len(repo.recipes) != 2 {
+		t.Fatalf("expected the recipe to be saved with a warning, got %+v", result)
+	}
+
+	saved = savedCarrotCake(userID)
+	repo = newMemoryRecipeRepository(saved)
+	svc = &recipeService{recipeRepository: repo, duplicatePolicy: "refuse"}
+	if _, err := svc.SaveRecipe(ctx, input(), userID); !errors.Is(err, recipeDomain.ErrDuplicateRecipe) || len(repo.recipes) != 1 {
+		t.Fatalf("expected the duplicate to be refused, got %v", err)
+	}
+	if _, err := svc.SaveRecipe(ctx, saveRecipeInput("Pudim", "Leite condensado", "Leite", "Ovos"), userID); err != nil {
+		t.Fatalf("expected a different recipe to be saved, got %v", err)
+	}
+
+	replace := input()
+	replace.OnDuplicate = "replace"
+	result, err = svc.SaveRecipe(ctx, replace, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kept := repo.recipes[saved.ID]
+	if !result.Replaced || result.ID != saved.ID.String() || len(repo.recipes) != 2 {
+		t.Fatalf("expected the saved recipe to be replaced, got %+v", result)
+	}
+	if kept.Title != "Bolo de Cenoura" || len(kept.Ingredients) != 6 || len(kept.Tags) != 1 || kept.Rating == nil {
+		t.Fatalf("expected the content to be replaced and the user data kept, got %+v", kept)
+	}
+
+	unknown := input()
+	unknown.ReplaceRecipeID = uuid.New().String()
+	if _, err := svc.SaveRecipe(ctx, unknown, userID); !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected recipe not found, got %v", err)
+	}
+}
+
+func TestRecipeService_SaveMultipleRecipes_Duplicates(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+	saved := savedCarrotCake(userID)
+	repo := newMemoryRecipeRepository(saved)
+	svc := &recipeService{recipeRepository: repo, duplicatePolicy: "refuse"}
+
+	pudding := saveRecipeInput("Pudim", "Leite condensado", "Leite", "Ovos")
+	cake := saveRecipeInput("Bolo de cenoura", "Cenoura", "Ovos", "Farinha de trigo", "Açúcar", "Óleo")
+	if _, err := svc.SaveMultipleRecipes(ctx, []*recipeDTO.SaveRecipeDTO{pudding, cake}, userID); !errors.Is(err, recipeDomain.ErrDuplicateRecipe) || len(repo.recipes) != 1 {
+		t.Fatalf("expected the batch to be refused, got %v", err)
+	}
+
+	cake.ReplaceRecipeID = saved.ID.String()
+	again := saveRecipeInput("Bolo de cenoura", "Cenoura", "Ovos")
+	again.ReplaceRecipeID = saved.ID.String()
+	if _, err := svc.SaveMultipleRecipes(ctx, []*recipeDTO.SaveRecipeDTO{cake, again}, userID); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected two replacements of one recipe to be refused, got %v", err)
+	}
+
+	results, err := svc.SaveMultipleRecipes(ctx, []*recipeDTO.SaveRecipeDTO{pudding, cake}, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Replaced || !results[1].Replaced || results[1].ID != saved.ID.String() || len(repo.recipes) != 2 {
+		t.Fatalf("expected the pudding to be created and the cake replaced, got %+v", results)
+	}
+}
+
+func TestRecipeService_SaveMultipleRecipes_DuplicatesWithinBatch(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+	first := func() *recipeDTO.SaveRecipeDTO {
+		return saveRecipeInput("Bolo de cenoura", "Cenoura", "Ovos", "Farinha de trigo", "Açúcar", "Óleo")
+	}
+	second := func() *recipeDTO.SaveRecipeDTO {
+		return saveRecipeInput("Bolo de Cenoura", "Cenouras", "Ovos", "Farinha de trigo", "Açúcar", "Óleo", "Chocolate")
+	}
+
+	repo := newMemoryRecipeRepository()
+	svc := &recipeService{recipeRepository: repo, duplicatePolicy: "refuse"}
+	if _, err := svc.SaveMultipleRecipes(ctx, []*recipeDTO.SaveRecipeDTO{first(), second()}, userID); !errors.Is(err, recipeDomain.ErrDuplicateRecipe) || len(repo.recipes) != 0 {
+		t.Fatalf("expected the batch with two similar cakes to be refused, got %v", err)
+	}
+
+	svc.duplicatePolicy = ""
+	results, err := svc.SaveMultipleRecipes(ctx, []*recipeDTO.SaveRecipeDTO{first(), second()}, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.recipes) != 2 || len(results[1].Duplicates) != 1 || results[1].Duplicates[0].ID != results[0].ID {
+		t.Fatalf("expected the second cake to be saved with a warning about the first, got %+v", results)
+	}
+
+	repo = newMemoryRecipeRepository()
+	svc.recipeRepository = repo
+	replacing := second()
+	replacing.OnDuplicate = "replace"
+	results, err = svc.SaveMultipleRecipes(ctx, []*recipeDTO.SaveRecipeDTO{first(), replacing}, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.recipes) != 1 || results[1].ID != results[0].ID || len(repo.recipes[uuid.MustParse(results[0].ID)].Ingredients) != 6 {
+		t.Fatalf("expected one cake saved with the content of the second, got %+v", results)
+	}
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/pdf"
+	"github.com/nclsgg/despensa-digital/backend/pkg/slug"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+)
+
+const (
+	exportFormatMarkdown = "markdown"
+	exportFormatJSONLD   = "json-ld"
+	exportFormatPDF      = "pdf"
+)
+
+var (
+	mealTypeLabels = map[string]string{
+		"breakfast": "Café da manhã",
+		"lunch":     "Almoço",
+		"dinner":    "Jantar",
+		"snack":     "Lanche",
+		"dessert":   "Sobremesa",
+	}
+	difficultyLabels = map[string]string{
+		"easy":   "Fácil",
+		"medium": "Média",
+		"hard":   "Difícil",
+	}
+)
+
+// ExportRecipe renders a saved recipe in one of the export formats
+func (rs *recipeService) ExportRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, format string) (*recipeDTO.RecipeExportDTO, error) {
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "ExportRecipe")
+	if err != nil {
+		return nil, err
+	}
+	return renderRecipeExport(recipe, format)
+}
+
+// ExportRecipes renders every saved recipe of a user and packs them in a zip
+func (rs *recipeService) ExportRecipes(ctx context.Context, userID uuid.UUID, format string) (*recipeDTO.RecipeExportDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := recipeExportFormat(format); err != nil {
+		return nil, err
+	}
+
+	recipes, err := rs.recipeRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list recipes for export",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ExportRecipes"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	content, err := renderRecipesZip(recipes, format)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Recipes exported",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "ExportRecipes"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.Int(appLogger.FieldCount, len(recipes)),
+	)
+
+	return &recipeDTO.RecipeExportDTO{
+		FileName:    "receitas.zip",
+		ContentType: "application/zip",
+		Content:     content,
+	}, nil
+}
+
+// recipeExportFormat resolves the export format, markdown by default.
+func recipeExportFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", exportFormatMarkdown, "md":
+		return exportFormatMarkdown, nil
+	case exportFormatJSONLD, "jsonld":
+		return exportFormatJSONLD, nil
+	case exportFormatPDF:
+		return exportFormatPDF, nil
+	default:
+		return "", recipeDomain.ErrUnsupportedFormat
+	}
+}
+
+// renderRecipeExport renders a recipe in one of the export formats.
+func renderRecipeExport(recipe *recipeModel.Recipe, format string) (*recipeDTO.RecipeExportDTO, error) {
+	format, err := recipeExportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case exportFormatMarkdown:
+		return &recipeDTO.RecipeExportDTO{
+			FileName:    slug.FileName(recipe.Title, "receita", "md"),
+			ContentType: "text/markdown; charset=utf-8",
+			Content:     []byte(renderRecipeMarkdown(recipe)),
+		}, nil
+	case exportFormatJSONLD:
+		content, err := renderRecipeJSONLD(recipe)
+		if err != nil {
+			return nil, err
+		}
+		return &recipeDTO.RecipeExportDTO{
+			FileName:    slug.FileName(recipe.Title, "receita", "jsonld"),
+			ContentType: "application/ld+json; charset=utf-8",
+			Content:     content,
+		}, nil
+	default:
+		return &recipeDTO.RecipeExportDTO{
+			FileName:    slug.FileName(recipe.Title, "receita", "pdf"),
+			ContentType: "application/pdf",
+			Content:     renderRecipePDF(recipe),
+		}, nil
+	}
+}
+
+// renderRecipesZip packs one file per recipe, numbering the names that repeat.
+func renderRecipesZip(recipes []*recipeModel.Recipe, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	used := make(map[string]bool)
+	for _, recipe := range recipes {
+		export, err := renderRecipeExport(recipe, format)
+		if err != nil {
+			return nil, err
+		}
+
+		name := export.FileName
+		extension := name[strings.LastIndex(name, "."):]
+		for count := 2; used[name]; count++ {
+			name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(export.FileName, extension), count, extension)
+		}
+		used[name] = true
+
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: recipe.UpdatedAt}
+		if header.Modified.IsZero() {
+			header.Modified = time.Now()
+		}
+		file, err := archive.CreateHeader(header)
+		if err != nil {
+			return nil, fmt.Errorf("create zip entry: %w", err)
+		}
+		if _, err := file.Write(export.Content); err != nil {
+			return nil, fmt.Errorf("write zip entry: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("close zip: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func renderRecipeMarkdown(recipe *recipeModel.Recipe) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", recipe.Title)
+	if description := strings.TrimSpace(recipe.Description); description != "" {
+		fmt.Fprintf(&b, "\n%s\n", description)
+	}
+	if details := recipeDetailLines(recipe); len(details) > 0 {
+		b.WriteString("\n")
+		for _, line := range details {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+	}
+
+	b.WriteString("\n## Ingredientes\n\n")
+	for _, ingredient := range recipe.Ingredients {
+		fmt.Fprintf(&b, "- %s\n", formatIngredientLine(ingredient))
+	}
+
+	b.WriteString("\n## Modo de preparo\n\n")
+	for idx, instruction := range recipe.Instructions {
+		fmt.Fprintf(&b, "%d. %s\n", idx+1, instructionLine(instruction))
+	}
+
+	if nutrition := nutritionLines(recipe.NutritionInfo); len(nutrition) > 0 {
+		b.WriteString("\n## Informação nutricional\n\n")
+		for _, line := range nutrition {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+	}
+
+	if len(recipe.Tips) > 0 {
+		b.WriteString("\n## Dicas\n\n")
+		for _, tip := range recipe.Tips {
+			fmt.Fprintf(&b, "- %s\n", tip)
+		}
+	}
+
+	if recipe.SourceURL != "" {
+		fmt.Fprintf(&b, "\nFonte: %s\n", recipe.SourceURL)
+	}
+	return b.String()
+}
+
+func renderRecipePDF(recipe *recipeModel.Recipe) []byte {
+	doc := pdf.New()
+	doc.Text(recipe.Title, pdf.Title)
+	if description := strings.TrimSpace(recipe.Description); description != "" {
+		doc.Space(4)
+		doc.Text(description, pdf.Body)
+	}
+	if details := recipeDetailLines(recipe); len(details) > 0 {
+		doc.Space(6)
+		doc.Text(strings.Join(details, "  •  "), pdf.Small)
+	}
+
+	doc.Space(10)
+	doc.Text("Ingredientes", pdf.Heading)
+	for _, ingredient := range recipe.Ingredients {
+		doc.Text("• "+formatIngredientLine(ingredient), pdf.Style{Size: pdf.Body.Size, Indent: 10})
+	}
+
+	doc.Space(10)
+	doc.Text("Modo de preparo", pdf.Heading)
+	for idx, instruction := range recipe.Instructions {
+		doc.Text(fmt.Sprintf("%d. %s", idx+1, instructionLine(instruction)), pdf.Style{Size: pdf.Body.Size, Indent: 10})
+		doc.Space(2)
+	}
+
+	if nutrition := nutritionLines(recipe.NutritionInfo); len(nutrition) > 0 {
+		doc.Space(10)
+		doc.Text("Informação nutricional", pdf.Heading)
+		doc.Text(strings.Join(nutrition, "  •  "), pdf.Style{Size: pdf.Body.Size, Indent: 10})
+	}
+
+	if len(recipe.Tips) > 0 {
+		doc.Space(10)
+		doc.Text("Dicas", pdf.Heading)
+		for _, tip := range recipe.Tips {
+			doc.Text("• "+tip, pdf.Style{Size: pdf.Body.Size, Indent: 10})
+		}
+	}
+
+	if recipe.SourceURL != "" {
+		doc.Space(12)
+		doc.Text("Fonte: "+recipe.SourceURL, pdf.Small)
+	}
+	return doc.Bytes()
+}
+
+// renderRecipeJSONLD writes the recipe as a schema.org Recipe, which the
+// recipe import reads back.
+func renderRecipeJSONLD(recipe *recipeModel.Recipe) ([]byte, error) {
+	document := map[string]any{
+		"@context": "https://schema.org",
+		"@type":    "Recipe",
+		"name":     recipe.Title,
+	}
+	if recipe.Description != "" {
+		document["description"] = recipe.Description
+	}
+	if recipe.ServingSize != nil && *recipe.ServingSize > 0 {
+		document["recipeYield"] = fmt.Sprintf("%d porções", *recipe.ServingSize)
+	}
+	for key, minutes := range map[string]*int{"prepTime": recipe.PreparationTime, "cookTime": recipe.CookingTime, "totalTime": recipe.TotalTime} {
+		if minutes != nil && *minutes > 0 {
+			document[key] = isoDuration(*minutes)
+		}
+	}
+	if label, ok := mealTypeLabels[recipe.MealType]; ok {
+		document["recipeCategory"] = label
+	}
+	if recipe.Cuisine != "" {
+		document["recipeCuisine"] = recipe.Cuisine
+	}
+	if len(recipe.Tags) > 0 {
+		document["keywords"] = strings.Join(recipe.Tags, ", ")
+	}
+
+	ingredients := make([]string, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, formatIngredientLine(ingredient))
+	}
+	document["recipeIngredient"] = ingredients
+
+	steps := make([]map[string]any, 0, len(recipe.Instructions))
+	for idx, instruction := range recipe.Instructions {
+		step := map[string]any{"@type": "HowToStep", "position": idx + 1, "text": instruction.Description}
+		if instruction.Time != nil && *instruction.Time > 0 {
+			step["totalTime"] = isoDuration(*instruction.Time)
+		}
+		steps = append(steps, step)
+	}
+	document["recipeInstructions"] = steps
+
+	nutrition := map[string]any{}
+	for key, value := range map[string]struct {
+		amount *int
+		unit   string
+	}{
+		"calories":            {recipe.NutritionInfo.Calories, "kcal"},
+		"proteinContent":      {recipe.NutritionInfo.Protein, "g"},
+		"carbohydrateContent": {recipe.NutritionInfo.Carbohydrates, "g"},
+		"fatContent":          {recipe.NutritionInfo.Fat, "g"},
+		"fiberContent":        {recipe.NutritionInfo.Fiber, "g"},
+	} {
+		if value.amount != nil {
+			nutrition[key] = fmt.Sprintf("%d %s", *value.amount, value.unit)
+		}
+	}
+	if len(nutrition) > 0 {
+		nutrition["@type"] = "NutritionInformation"
+		document["nutrition"] = nutrition
+	}
+
+	if recipe.SourceURL != "" {
+		document["isBasedOn"] = recipe.SourceURL
+	}
+	if !recipe.CreatedAt.IsZero() {
+		document["dateCreated"] = recipe.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode json-ld: %w", err)
+	}
+	return content, nil
+}
+
+// recipeDetailLines lists the servings, times, meal, cuisine and difficulty
+// that are filled in.
+func recipeDetailLines(recipe *recipeModel.Recipe) []string {
+	var lines []string
+	if recipe.ServingSize != nil && *recipe.ServingSize > 0 {
+		lines = append(lines, fmt.Sprintf("Rendimento: %d porções", *recipe.ServingSize))
+	}
+	if recipe.PreparationTime != nil && *recipe.PreparationTime > 0 {
+		lines = append(lines, "Preparo: "+formatMinutes(*recipe.PreparationTime))
+	}
+	if recipe.CookingTime != nil && *recipe.CookingTime > 0 {
+		lines = append(lines, "Cozimento: "+formatMinutes(*recipe.CookingTime))
+	}
+	if recipe.TotalTime != nil && *recipe.TotalTime > 0 {
+		lines = append(lines, "Tempo total: "+formatMinutes(*recipe.TotalTime))
+	}
+	if label, ok := mealTypeLabels[recipe.MealType]; ok {
+		lines = append(lines, "Refeição: "+label)
+	}
+	if recipe.Cuisine != "" {
+		lines = append(lines, "Cozinha: "+recipe.Cuisine)
+	}
+	if label, ok := difficultyLabels[recipe.Difficulty]; ok {
+		lines = append(lines, "Dificuldade: "+label)
+	}
+	return lines
+}
+
+func nutritionLines(nutrition recipeModel.RecipeNutritionJSON) []string {
+	var lines []string
+	if nutrition.Calories != nil {
+		lines = append(lines, fmt.Sprintf("Calorias: %d kcal", *nutrition.Calories))
+	}
+	if nutrition.Protein != nil {
+		lines = append(lines, fmt.Sprintf("Proteínas: %d g", *nutrition.Protein))
+	}
+	if nutrition.Carbohydrates != nil {
+		lines = append(lines, fmt.Sprintf("Carboidratos: %d g", *nutrition.Carbohydrates))
+	}
+	if nutrition.Fat != nil {
+		lines = append(lines, fmt.Sprintf("Gorduras: %d g", *nutrition.Fat))
+	}
+	if nutrition.Fiber != nil {
+		lines = append(lines, fmt.Sprintf("Fibras: %d g", *nutrition.Fiber))
+	}
+	return lines
+}
+
+// formatIngredientLine writes an ingredient the way recipes are read, e.g.
+// "2 xícaras de farinha" or "3 ovos".
+func formatIngredientLine(ingredient recipeModel.RecipeIngredient) string {
+	name := strings.TrimSpace(ingredient.Name)
+	if ingredient.Amount == nil || *ingredient.Amount <= 0 {
+		return name
+	}
+	amount := formatExportAmount(*ingredient.Amount)
+	unit := strings.TrimSpace(ingredient.Unit)
+	if unit == "" || units.Normalize(unit) == "un" {
+		return amount + " " + name
+	}
+	if label, ok := kitchenUnitNames[unit]; ok {
+		unit = label
+	}
+	return fmt.Sprintf("%s %s de %s", amount, unit, name)
+}
+
+func instructionLine(instruction recipeModel.RecipeInstruction) string {
+	line := strings.TrimSpace(instruction.Description)
+	if instruction.Time != nil && *instruction.Time > 0 {
+		line += " (" + formatMinutes(*instruction.Time) + ")"
+	}
+	return line
+}
+
+// formatExportAmount prints an amount with a decimal comma and no trailing
+// zeros.
+func formatExportAmount(amount float64) string {
+	value := strconv.FormatFloat(math.Round(amount*100)/100, 'f', -1, 64)
+	return strings.ReplaceAll(value, ".", ",")
+}
+
+func formatMinutes(minutes int) string {
+	hours, rest := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d min", minutes)
+	case rest == 0:
+		return fmt.Sprintf("%d h", hours)
+	default:
+		return fmt.Sprintf("%d h %d min", hours, rest)
+	}
+}
+
+func isoDuration(minutes int) string {
+	hours, rest := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("PT%dM", rest)
+	case rest == 0:
+		return fmt.Sprintf("PT%dH", hours)
+	default:
+		return fmt.Sprintf("PT%dH%dM", hours, rest)
+	}
+}
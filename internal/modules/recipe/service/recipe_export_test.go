@@ -0,0 +1,171 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/nclsgg/despensa-digital/backend/pkg/recipeschema"
+)
+
+func newExportTestRecipe(userID uuid.UUID) *recipeModel.Recipe {
+	flour, eggs, butter := 2.5, 3.0, 1.0
+	servings, prep, cook, total := 8, 20, 40, 60
+	calories, protein := 320, 5
+	stepTime := 40
+	return &recipeModel.Recipe{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       "Bolo de Cenoura (fofinho)",
+		Description: "O clássico da tarde.",
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "farinha de trigo", Amount: &flour, Unit: "xícaras"},
+			{Name: "ovos", Amount: &eggs, Unit: "un"},
+			{Name: "manteiga", Amount: &butter, Unit: "colher_sopa"},
+			{Name: "sal a gosto", Unit: ""},
+		},
+		Instructions: recipeModel.RecipeInstructionsJSON{
+			{Step: 1, Description: "Bata tudo no liquidificador."},
+			{Step: 2, Description: "Asse em forno médio.", Time: &stepTime},
+		},
+		ServingSize:     &servings,
+		PreparationTime: &prep,
+		CookingTime:     &cook,
+		TotalTime:       &total,
+		Difficulty:      "easy",
+		MealType:        "dessert",
+		Cuisine:         "Brasileira",
+		NutritionInfo:   recipeModel.RecipeNutritionJSON{Calories: &calories, Protein: &protein},
+		Tips:            recipeModel.RecipeTipsJSON{"Use cenouras bem laranjas."},
+		Tags:            recipeModel.RecipeTagsJSON{"bolo", "lanche"},
+	}
+}
+
+func TestRenderRecipeMarkdown(t *testing.T) {
+	markdown := renderRecipeMarkdown(newExportTestRecipe(uuid.New()))
+
+	for _, expected := range []string{
+		"# Bolo de Cenoura (fofinho)\n",
+		"- Rendimento: 8 porções\n",
+		"- Tempo total: 1 h\n",
+		"- Refeição: Sobremesa\n",
+		"- Dificuldade: Fácil\n",
+		"## Ingredientes\n\n- 2,5 xícaras de farinha de trigo\n- 3 ovos\n- 1 colher de sopa de manteiga\n- sal a gosto\n",
+		"2. Asse em forno médio. (40 min)\n",
+		"- Calorias: 320 kcal\n",
+		"## Dicas\n\n- Use cenouras bem laranjas.\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", expected, markdown)
+		}
+	}
+}
+
+func TestRenderRecipeJSONLD_IsReadByTheImport(t *testing.T) {
+	content, err := renderRecipeJSONLD(newExportTestRecipe(uuid.New()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if document["@type"] != "Recipe" || document["totalTime"] != "PT1H" || document["recipeYield"] != "8 porções" {
+		t.Fatalf("unexpected document %v", document)
+	}
+
+	page := `<script type="application/ld+json">` + string(content) + `</script>`
+	parsed, err := recipeschema.Parse(page)
+	if err != nil {
+		t.Fatalf("expected the export to be importable: %v", err)
+	}
+	imported, err := importedRecipeModel(parsed, uuid.New(), "", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected import error: %v", err)
+	}
+	if imported.Title != "Bolo de Cenoura (fofinho)" || imported.MealType != "dessert" || *imported.ServingSize != 8 || *imported.PreparationTime != 20 {
+		t.Fatalf("unexpected imported recipe %+v", imported)
+	}
+	flour := imported.Ingredients[0]
+	if flour.Name != "farinha de trigo" || flour.Unit != "xícaras" || *flour.Amount != 2.5 {
+		t.Fatalf("unexpected flour %+v", flour)
+	}
+	if len(imported.Instructions) != 2 || *imported.NutritionInfo.Calories != 320 {
+		t.Fatalf("unexpected instructions or nutrition %+v %+v", imported.Instructions, imported.NutritionInfo)
+	}
+}
+
+func TestRenderRecipeExport_Formats(t *testing.T) {
+	recipe := newExportTestRecipe(uuid.New())
+
+	export, err := renderRecipeExport(recipe, "pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if export.FileName != "bolo-de-cenoura-fofinho.pdf" || export.ContentType != "application/pdf" || !bytes.HasPrefix(export.Content, []byte("%PDF-1.4")) {
+		t.Fatalf("unexpected pdf export %s %s", export.FileName, export.ContentType)
+	}
+
+	export, err = renderRecipeExport(recipe, "")
+	if err != nil || export.FileName != "bolo-de-cenoura-fofinho.md" {
+		t.Fatalf("expected markdown by default, got %+v, %v", export, err)
+	}
+
+	if _, err := renderRecipeExport(recipe, "docx"); !errors.Is(err, recipeDomain.ErrUnsupportedFormat) {
+		t.Fatalf("expected unsupported format, got %v", err)
+	}
+}
+
+func TestRecipeService_ExportRecipes_Zip(t *testing.T) {
+	userID := uuid.New()
+	first := newExportTestRecipe(userID)
+	second := newExportTestRecipe(userID)
+	third := newExportTestRecipe(userID)
+	third.Title = "Bolo de Cenoura (fofinho) 2"
+	other := newExportTestRecipe(uuid.New())
+	svc := &recipeService{recipeRepository: newMemoryRecipeRepository(first, second, third, other)}
+
+	export, err := svc.ExportRecipes(context.Background(), userID, "json-ld")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if export.FileName != "receitas.zip" || export.ContentType != "application/zip" {
+		t.Fatalf("unexpected export %s %s", export.FileName, export.ContentType)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(export.Content), int64(len(export.Content)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	names := make(map[string]bool, len(archive.File))
+	for _, file := range archive.File {
+		names[file.Name] = true
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+		if !json.Valid(content) {
+			t.Fatalf("expected %s to be json", file.Name)
+		}
+	}
+	// The third title slugs to the name the second copy would take.
+	if len(archive.File) != 3 || len(names) != 3 || !names["bolo-de-cenoura-fofinho.jsonld"] || !names["bolo-de-cenoura-fofinho-2.jsonld"] {
+		t.Fatalf("expected three distinct files, got %v", names)
+	}
+
+	if _, err := svc.ExportRecipes(context.Background(), userID, "docx"); !errors.Is(err, recipeDomain.ErrUnsupportedFormat) {
+		t.Fatalf("expected unsupported format, got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/recipeschema"
+	"go.uber.org/zap"
+)
+
+const (
+	maxImportPageSize      = 2 << 20
+	maxRecipeTitleLength   = 255
+	maxRecipeCuisineLength = 100
+)
+
+// importMealTypes maps the recipe categories used by recipe sites, in
+// Portuguese and English, to the meal types of the saved recipes.
+var importMealTypes = map[string]string{
+	"cafe da manha": "breakfast", "breakfast": "breakfast", "brunch": "breakfast",
+	"almoco": "lunch", "lunch": "lunch", "prato principal": "lunch", "main course": "lunch", "main dish": "lunch",
+	"jantar": "dinner", "dinner": "dinner",
+	"lanche": "snack", "snack": "snack", "petisco": "snack", "aperitivo": "snack", "appetizer": "snack", "entrada": "snack",
+	"sobremesa": "dessert", "dessert": "dessert", "doce": "dessert",
+}
+
+var nutritionNumberPattern = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+
+// ImportRecipe reads the schema.org recipe of a web page and saves it
+func (rs *recipeService) ImportRecipe(ctx context.Context, userID uuid.UUID, input *recipeDTO.ImportRecipeDTO) (*recipeDTO.ImportedRecipeDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil {
+		return nil, fmt.Errorf("%w: import data is required", recipeDomain.ErrInvalidRequest)
+	}
+	sourceURL := strings.TrimSpace(input.URL)
+	page := input.HTML
+	if strings.TrimSpace(page) == "" {
+		if sourceURL == "" {
+			return nil, fmt.Errorf("%w: url or html is required", recipeDomain.ErrInvalidRequest)
+		}
+		fetched, err := rs.fetchRecipePage(ctx, sourceURL)
+		if err != nil {
+			logger.Warn("Failed to fetch recipe page",
+				zap.String(appLogger.FieldModule, "recipe"),
+				zap.String(appLogger.FieldFunction, "ImportRecipe"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("url", sourceURL),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		page = fetched
+	}
+	if len(page) > maxImportPageSize {
+		return nil, fmt.Errorf("%w: the page can have at most %d bytes", recipeDomain.ErrInvalidRequest, maxImportPageSize)
+	}
+
+	parsed, err := recipeschema.Parse(page)
+	if err != nil {
+		logger.Warn("No recipe found in page",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ImportRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("url", sourceURL),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("%w: the page has no schema.org recipe", recipeDomain.ErrInvalidRequest)
+	}
+
+	recipe, err := importedRecipeModel(parsed, userID, sourceURL, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if recipe.NutritionInfo.Calories == nil {
+		rs.applyEstimatedNutrition(ctx, recipe, "ImportRecipe")
+	}
+
+	result := &recipeDTO.ImportedRecipeDTO{Format: string(parsed.Format)}
+	if !input.Preview {
+		recordRecipeVersion(recipe, nil, userID, recipe.GeneratedAt)
+		if err := rs.recipeRepository.Create(ctx, recipe); err != nil {
+			logger.Error("Failed to create imported recipe",
+				zap.String(appLogger.FieldModule, "recipe"),
+				zap.String(appLogger.FieldFunction, "ImportRecipe"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("recipe_id", recipe.ID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		result.Saved = true
+	}
+	result.Recipe = *convertModelToRecipeDetailDTO(recipe)
+	result.Recipe.DietaryWarnings = checkRecipeDietary(recipeIngredientNames(recipe), recipe.DietaryRestrictions,
+		householdDietaryMembers(ctx, rs.pantryService, rs.profileRepository, userID, nil, "ImportRecipe"))
+
+	logger.Info("Recipe imported",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "ImportRecipe"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("recipe_id", recipe.ID.String()),
+		zap.String("format", result.Format),
+		zap.Bool("saved", result.Saved),
+	)
+
+	return result, nil
+}
+
+// fetchRecipePage downloads a page, telling the errors of the user (a bad or
+// private address) from the ones of the site.
+func (rs *recipeService) fetchRecipePage(ctx context.Context, pageURL string) (string, error) {
+	if rs.pageFetcher == nil {
+		return "", fmt.Errorf("%w: importing from a url is not available, send the html of the page", recipeDomain.ErrInvalidRequest)
+	}
+	page, err := rs.pageFetcher.Fetch(ctx, pageURL)
+	if err != nil {
+		if errors.Is(err, recipeschema.ErrInvalidURL) || errors.Is(err, recipeschema.ErrBlockedAddress) {
+			return "", fmt.Errorf("%w: url must be a public http or https address", recipeDomain.ErrInvalidRequest)
+		}
+		return "", fmt.Errorf("%w: %v", recipeDomain.ErrImportFailed, err)
+	}
+	return page, nil
+}
+
+// importedRecipeModel maps a schema.org recipe into a saved recipe
+func importedRecipeModel(parsed *recipeschema.Recipe, userID uuid.UUID, sourceURL string, now time.Time) (*recipeModel.Recipe, error) {
+	title := truncateRunes(parsed.Name, maxRecipeTitleLength)
+	if title == "" {
+		return nil, fmt.Errorf("%w: the recipe on the page has no name", recipeDomain.ErrInvalidRequest)
+	}
+
+	ingredients := make([]recipeModel.RecipeIngredient, 0, len(parsed.Ingredients))
+	for _, line := range parsed.Ingredients {
+		parsedIngredient := recipeschema.ParseIngredient(line)
+		unit := parsedIngredient.Unit
+		if unit == "" && parsedIngredient.Amount != nil {
+			// "3 ovos" counts units.
+			unit = "un"
+		}
+		ingredients = append(ingredients, recipeModel.RecipeIngredient{
+			Name:   parsedIngredient.Name,
+			Amount: parsedIngredient.Amount,
+			Unit:   unit,
+		})
+	}
+	if len(ingredients) == 0 {
+		return nil, fmt.Errorf("%w: the recipe on the page has no ingredients", recipeDomain.ErrInvalidRequest)
+	}
+
+	instructions := make([]recipeModel.RecipeInstruction, 0, len(parsed.Instructions))
+	for idx, step := range parsed.Instructions {
+		instructions = append(instructions, recipeModel.RecipeInstruction{
+			Step:        idx + 1,
+			Description: step,
+		})
+	}
+	if len(instructions) == 0 {
+		return nil, fmt.Errorf("%w: the recipe on the page has no instructions", recipeDomain.ErrInvalidRequest)
+	}
+
+	totalTime := parsed.TotalTime
+	if totalTime == 0 {
+		totalTime = parsed.PrepTime + parsed.CookTime
+	}
+
+	recipe := &recipeModel.Recipe{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Title:           title,
+		Description:     parsed.Description,
+		Ingredients:     recipeModel.RecipeIngredientsJSON(ingredients),
+		Instructions:    recipeModel.RecipeInstructionsJSON(instructions),
+		PreparationTime: positiveInt(parsed.PrepTime),
+		CookingTime:     positiveInt(parsed.CookTime),
+		TotalTime:       positiveInt(totalTime),
+		ServingSize:     positiveInt(parsed.Servings()),
+		MealType:        importMealTypes[normalizeIngredientName(parsed.Category)],
+		Cuisine:         truncateRunes(parsed.Cuisine, maxRecipeCuisineLength),
+		NutritionInfo: recipeModel.RecipeNutritionJSON(recipeModel.RecipeNutrition{
+			Calories:      nutritionValue(parsed.Calories),
+			Protein:       nutritionValue(parsed.Protein),
+			Carbohydrates: nutritionValue(parsed.Carbs),
+			Fat:           nutritionValue(parsed.Fat),
+		}),
+		DietaryRestrictions: recipeModel.RecipeDietaryJSON{},
+		Tips:                recipeModel.RecipeTipsJSON{},
+		Tags:                recipeModel.RecipeTagsJSON(importedRecipeTags(parsed.Keywords)),
+		SourceURL:           sourceURL,
+		GeneratedAt:         now,
+	}
+	return recipe, nil
+}
+
+// importedRecipeTags keeps the keywords of the page that fit as tags
+func importedRecipeTags(keywords []string) []string {
+	candidates := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if len(candidates) == maxRecipeTags {
+			break
+		}
+		if len([]rune(keyword)) <= maxRecipeTagLength {
+			candidates = append(candidates, keyword)
+		}
+	}
+	tags, err := normalizeRecipeTags(candidates)
+	if err != nil {
+		return []string{}
+	}
+	return tags
+}
+
+// nutritionValue reads the number of a schema.org nutrition value such as
+// "320 kcal"
+func nutritionValue(value string) *int {
+	match := nutritionNumberPattern.FindString(value)
+	if match == "" {
+		return nil
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(match, ",", "."), 64)
+	if err != nil {
+		return nil
+	}
+	rounded := int(amount + 0.5)
+	return &rounded
+}
+
+func positiveInt(value int) *int {
+	if value <= 0 {
+		return nil
+	}
+	return &value
+}
+
+func truncateRunes(value string, limit int) string {
+	value = strings.TrimSpace(value)
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return strings.TrimSpace(string(runes[:limit]))
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	"github.com/nclsgg/despensa-digital/backend/pkg/recipeschema"
+)
+
+const importedRecipePage = `<html><head>
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Recipe",
+ "name": "Brigadeiro", "recipeYield": "20 unidades", "recipeCategory": "Sobremesas",
+ "prepTime": "PT5M", "cookTime": "PT15M", "keywords": "doce, festa",
+ "recipeIngredient": ["1 lata de leite condensado", "1 colher (sopa) de manteiga", "2 colheres (sopa) de chocolate em pó", "chocolate granulado a gosto"],
+ "recipeInstructions": "Leve ao fogo o leite condensado, a manteiga e o chocolate.\nMexa até desgrudar do fundo.\nEnrole e passe no granulado.",
+ "nutrition": {"calories": "95 kcal"}}
+</script></head><body></body></html>`
+
+type stubPageFetcher struct {
+	pages map[string]string
+	err   error
+}
+
+func (f *stubPageFetcher) Fetch(ctx context.Context, pageURL string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	page, ok := f.pages[pageURL]
+	if !ok {
+		return "", fmt.Errorf("%w: status 404", recipeschema.ErrUnexpectedReply)
+	}
+	return page, nil
+}
+
+func TestRecipeService_ImportRecipe_FromURL(t *testing.T) {
+	userID := uuid.New()
+	repo := newMemoryRecipeRepository()
+	pageURL := "https://receitas.example/brigadeiro"
+	svc := &recipeService{
+		recipeRepository: repo,
+		pageFetcher:      &stubPageFetcher{pages: map[string]string{pageURL: importedRecipePage}},
+	}
+
+	imported, err := svc.ImportRecipe(context.Background(), userID, &recipeDTO.ImportRecipeDTO{URL: pageURL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !imported.Saved || imported.Format != "json-ld" || len(repo.recipes) != 1 {
+		t.Fatalf("expected the recipe to be saved, got %+v with %d recipes", imported, len(repo.recipes))
+	}
+
+	recipe := imported.Recipe
+	if recipe.Title != "Brigadeiro" || recipe.SourceURL != pageURL || recipe.MealType != "dessert" {
+		t.Fatalf("unexpected recipe %+v", recipe)
+	}
+	if *recipe.ServingSize != 20 || *recipe.TotalTime != 20 || *recipe.NutritionInfo.Calories != 95 {
+		t.Fatalf("unexpected servings, time or calories %d %d %d", *recipe.ServingSize, *recipe.TotalTime, *recipe.NutritionInfo.Calories)
+	}
+	if len(recipe.Ingredients) != 4 || len(recipe.Instructions) != 3 || recipe.Instructions[2].Step != 3 {
+		t.Fatalf("unexpected ingredients or instructions %+v %+v", recipe.Ingredients, recipe.Instructions)
+	}
+	butter := recipe.Ingredients[1]
+	if butter.Name != "manteiga" || butter.Unit != "colher de sopa" || *butter.Amount != 1 {
+		t.Fatalf("unexpected butter %+v", butter)
+	}
+	sprinkles := recipe.Ingredients[3]
+	if sprinkles.Name != "chocolate granulado" || sprinkles.Amount != nil {
+		t.Fatalf("unexpected sprinkles %+v", sprinkles)
+	}
+	if len(recipe.Tags) != 2 || recipe.Tags[0] != "doce" {
+		t.Fatalf("unexpected tags %v", recipe.Tags)
+	}
+}
+
+func TestRecipeService_ImportRecipe_PreviewDoesNotSave(t *testing.T) {
+	repo := newMemoryRecipeRepository()
+	svc := &recipeService{recipeRepository: repo}
+
+	imported, err := svc.ImportRecipe(context.Background(), uuid.New(), &recipeDTO.ImportRecipeDTO{HTML: importedRecipePage, Preview: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if imported.Saved || len(repo.recipes) != 0 || imported.Recipe.Title != "Brigadeiro" {
+		t.Fatalf("expected an unsaved preview, got %+v", imported)
+	}
+}
+
+func TestRecipeService_ImportRecipe_Errors(t *testing.T) {
+	userID := uuid.New()
+	svc := &recipeService{recipeRepository: newMemoryRecipeRepository()}
+
+	if _, err := svc.ImportRecipe(context.Background(), userID, &recipeDTO.ImportRecipeDTO{}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected missing page to be refused, got %v", err)
+	}
+	if _, err := svc.ImportRecipe(context.Background(), userID, &recipeDTO.ImportRecipeDTO{HTML: "<html><body>Sem receita</body></html>"}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected a page without recipe to be refused, got %v", err)
+	}
+	if _, err := svc.ImportRecipe(context.Background(), userID, &recipeDTO.ImportRecipeDTO{URL: "https://receitas.example"}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected url import without fetcher to be refused, got %v", err)
+	}
+
+	svc.pageFetcher = &stubPageFetcher{err: fmt.Errorf("%w: 127.0.0.1", recipeschema.ErrBlockedAddress)}
+	if _, err := svc.ImportRecipe(context.Background(), userID, &recipeDTO.ImportRecipeDTO{URL: "http://localhost"}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected a private address to be refused, got %v", err)
+	}
+
+	svc.pageFetcher = &stubPageFetcher{}
+	if _, err := svc.ImportRecipe(context.Background(), userID, &recipeDTO.ImportRecipeDTO{URL: "https://receitas.example/missing"}); !errors.Is(err, recipeDomain.ErrImportFailed) {
+		t.Fatalf("expected a failed fetch, got %v", err)
+	}
+}
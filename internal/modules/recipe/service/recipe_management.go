@@ -0,0 +1,513 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRecipePageSize  = 20
+	maxRecipePageSize      = 100
+	maxRecipeTags          = 20
+	maxRecipeTagLength     = 40
+	minRecipeRating        = 1
+	maxRecipeRating        = 5
+	maxRecipeNotesLength   = 5000
+	maxRecipeModifications = 30
+)
+
+var (
+	recipeDifficulties = []string{"easy", "medium", "hard"}
+	recipeMealTypes    = []string{"breakfast", "lunch", "dinner", "snack", "dessert"}
+	recipeSortOptions  = []string{"recent", "title", "total_time", "rating", "not_cooked_recently"}
+)
+
+// SearchRecipes lists a page of the saved recipes of a user matching the filter
+func (rs *recipeService) SearchRecipes(ctx context.Context, userID uuid.UUID, filter recipeDTO.RecipeFilterDTO) (*recipeDTO.RecipeListDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	filter, err := normalizeRecipeFilter(filter)
+	if err != nil {
+		logger.Warn("Invalid recipe filter",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SearchRecipes"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	recipes, total, err := rs.recipeRepository.Search(ctx, userID, filter)
+	if err != nil {
+		logger.Error("Failed to search recipes in database",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SearchRecipes"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result := &recipeDTO.RecipeListDTO{
+		Recipes: make([]*recipeDTO.RecipeDetailDTO, 0, len(recipes)),
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}
+	for _, recipe := range recipes {
+		result.Recipes = append(result.Recipes, convertModelToRecipeDetailDTO(recipe))
+	}
+
+	logger.Info("Recipes searched successfully",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "SearchRecipes"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.Int(appLogger.FieldCount, len(result.Recipes)),
+		zap.Int64("total", total),
+	)
+
+	return result, nil
+}
+
+// UpdateRecipe applies a partial update to a saved recipe
+func (rs *recipeService) UpdateRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.UpdateRecipeDTO) (*recipeDTO.RecipeDetailDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil {
+		return nil, fmt.Errorf("%w: recipe data is required", recipeDomain.ErrInvalidRequest)
+	}
+
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "UpdateRecipe")
+	if err != nil {
+		return nil, err
+	}
+
+	previous := snapshotRecipeVersion(recipe)
+	if err := applyRecipeUpdate(recipe, input); err != nil {
+		logger.Warn("Invalid recipe update",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "UpdateRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	// The nutrition of other ingredients or servings is estimated again unless
+	// the update brings its own.
+	if (input.Ingredients != nil || input.ServingSize != nil) && input.NutritionInfo == nil {
+		rs.applyEstimatedNutrition(ctx, recipe, "UpdateRecipe")
+	}
+	recordRecipeVersion(recipe, &previous, userID, time.Now().UTC())
+
+	result, err := rs.saveRecipeChanges(ctx, recipe, "UpdateRecipe")
+	if err != nil {
+		return nil, err
+	}
+	if input.Ingredients != nil || input.DietaryRestrictions != nil {
+		result.DietaryWarnings = checkRecipeDietary(recipeIngredientNames(recipe), recipe.DietaryRestrictions,
+			householdDietaryMembers(ctx, rs.pantryService, rs.profileRepository, userID, nil, "UpdateRecipe"))
+	}
+	return result, nil
+}
+
+// DeleteRecipe removes a saved recipe of the user
+func (rs *recipeService) DeleteRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) error {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "DeleteRecipe"); err != nil {
+		return err
+	}
+
+	if err := rs.recipeRepository.Delete(ctx, recipeID, userID); err != nil {
+		logger.Error("Failed to delete recipe from database",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "DeleteRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	logger.Info("Recipe deleted successfully",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "DeleteRecipe"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("recipe_id", recipeID.String()),
+	)
+
+	return nil
+}
+
+// SetRecipeTags replaces the tags of a saved recipe
+func (rs *recipeService) SetRecipeTags(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, tags []string) (*recipeDTO.RecipeDetailDTO, error) {
+	normalized, err := normalizeRecipeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "SetRecipeTags")
+	if err != nil {
+		return nil, err
+	}
+	recipe.Tags = recipeModel.RecipeTagsJSON(normalized)
+
+	return rs.saveRecipeChanges(ctx, recipe, "SetRecipeTags")
+}
+
+// SetRecipeFavorite marks or unmarks a saved recipe as favorite
+func (rs *recipeService) SetRecipeFavorite(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, favorite bool) (*recipeDTO.RecipeDetailDTO, error) {
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "SetRecipeFavorite")
+	if err != nil {
+		return nil, err
+	}
+	recipe.Favorite = favorite
+
+	return rs.saveRecipeChanges(ctx, recipe, "SetRecipeFavorite")
+}
+
+// SetRecipeRating rates a saved recipe from 1 to 5. A nil rating clears it.
+func (rs *recipeService) SetRecipeRating(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, rating *int) (*recipeDTO.RecipeDetailDTO, error) {
+	if err := validateRecipeRating(rating); err != nil {
+		return nil, err
+	}
+
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "SetRecipeRating")
+	if err != nil {
+		return nil, err
+	}
+	recipe.Rating = rating
+
+	return rs.saveRecipeChanges(ctx, recipe, "SetRecipeRating")
+}
+
+// SetRecipeNotes replaces the personal notes and modifications of a saved
+// recipe
+func (rs *recipeService) SetRecipeNotes(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.SetRecipeNotesDTO) (*recipeDTO.RecipeDetailDTO, error) {
+	if input == nil {
+		return nil, fmt.Errorf("%w: notes are required", recipeDomain.ErrInvalidRequest)
+	}
+	notes, modifications, err := normalizeRecipeNotes(input.Notes, input.Modifications)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "SetRecipeNotes")
+	if err != nil {
+		return nil, err
+	}
+	recipe.Notes = notes
+	recipe.Modifications = recipeModel.RecipeModificationsJSON(modifications)
+
+	return rs.saveRecipeChanges(ctx, recipe, "SetRecipeNotes")
+}
+
+// ListRecipeTags lists the tags used by the recipes of a user
+func (rs *recipeService) ListRecipeTags(ctx context.Context, userID uuid.UUID) ([]recipeDTO.RecipeTagDTO, error) {
+	tags, err := rs.recipeRepository.ListTags(ctx, userID)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list recipe tags",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ListRecipeTags"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if tags == nil {
+		tags = []recipeDTO.RecipeTagDTO{}
+	}
+	return tags, nil
+}
+
+func findUserRecipe(ctx context.Context, recipes recipeDomain.RecipeRepository, recipeID uuid.UUID, userID uuid.UUID, function string) (*recipeModel.Recipe, error) {
+	recipe, err := recipes.FindByID(ctx, recipeID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, recipeDomain.ErrRecipeNotFound
+		}
+		appLogger.FromContext(ctx).Error("Failed to get recipe from database",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return recipe, nil
+}
+
+func (rs *recipeService) saveRecipeChanges(ctx context.Context, recipe *recipeModel.Recipe, function string) (*recipeDTO.RecipeDetailDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if err := rs.recipeRepository.Update(ctx, recipe); err != nil {
+		if errors.Is(err, recipeDomain.ErrVersionConflict) {
+			logger.Warn("Recipe changed by another request",
+				zap.String(appLogger.FieldModule, "recipe"),
+				zap.String(appLogger.FieldFunction, function),
+				zap.String(appLogger.FieldUserID, recipe.UserID.String()),
+				zap.String("recipe_id", recipe.ID.String()),
+			)
+			return nil, err
+		}
+		logger.Error("Failed to update recipe in database",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, recipe.UserID.String()),
+			zap.String("recipe_id", recipe.ID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.Info("Recipe updated successfully",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, function),
+		zap.String(appLogger.FieldUserID, recipe.UserID.String()),
+		zap.String("recipe_id", recipe.ID.String()),
+	)
+
+	return convertModelToRecipeDetailDTO(recipe), nil
+}
+
+// applyRecipeUpdate copies the informed fields of input into recipe,
+// validating them the same way a saved recipe is validated.
+func applyRecipeUpdate(recipe *recipeModel.Recipe, input *recipeDTO.UpdateRecipeDTO) error {
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" || len(title) > 255 {
+			return fmt.Errorf("%w: title must have between 1 and 255 characters", recipeDomain.ErrInvalidRequest)
+		}
+		recipe.Title = title
+	}
+	if input.Description != nil {
+		recipe.Description = *input.Description
+	}
+	if input.Ingredients != nil {
+		if len(input.Ingredients) == 0 {
+			return fmt.Errorf("%w: at least one ingredient is required", recipeDomain.ErrInvalidRequest)
+		}
+		ingredients := make(recipeModel.RecipeIngredientsJSON, 0, len(input.Ingredients))
+		for _, ing := range input.Ingredients {
+			if strings.TrimSpace(ing.Name) == "" {
+				return fmt.Errorf("%w: ingredient name is required", recipeDomain.ErrInvalidRequest)
+			}
+			ingredients = append(ingredients, recipeModel.RecipeIngredient{
+				Name:        strings.TrimSpace(ing.Name),
+				Amount:      ing.Amount,
+				Unit:        strings.TrimSpace(ing.Unit),
+				Available:   ing.Available,
+				Alternative: ing.Alternative,
+			})
+		}
+		recipe.Ingredients = ingredients
+	}
+	if input.Instructions != nil {
+		if len(input.Instructions) == 0 {
+			return fmt.Errorf("%w: at least one instruction is required", recipeDomain.ErrInvalidRequest)
+		}
+		instructions := make(recipeModel.RecipeInstructionsJSON, 0, len(input.Instructions))
+		for _, inst := range input.Instructions {
+			if inst.Step < 1 || strings.TrimSpace(inst.Description) == "" {
+				return fmt.Errorf("%w: instructions need a step and a description", recipeDomain.ErrInvalidRequest)
+			}
+			instructions = append(instructions, recipeModel.RecipeInstruction{
+				Step:        inst.Step,
+				Description: inst.Description,
+				Time:        inst.Time,
+			})
+		}
+		recipe.Instructions = instructions
+	}
+	for _, value := range []*int{input.CookingTime, input.PreparationTime, input.TotalTime, input.ServingSize} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("%w: times and serving size cannot be negative", recipeDomain.ErrInvalidRequest)
+		}
+	}
+	if input.CookingTime != nil {
+		recipe.CookingTime = input.CookingTime
+	}
+	if input.PreparationTime != nil {
+		recipe.PreparationTime = input.PreparationTime
+	}
+	if input.TotalTime != nil {
+		recipe.TotalTime = input.TotalTime
+	}
+	if input.ServingSize != nil {
+		recipe.ServingSize = input.ServingSize
+	}
+	if input.Difficulty != nil {
+		if *input.Difficulty != "" && !containsString(recipeDifficulties, *input.Difficulty) {
+			return fmt.Errorf("%w: difficulty must be one of %s", recipeDomain.ErrInvalidRequest, strings.Join(recipeDifficulties, ", "))
+		}
+		recipe.Difficulty = *input.Difficulty
+	}
+	if input.MealType != nil {
+		if *input.MealType != "" && !containsString(recipeMealTypes, *input.MealType) {
+			return fmt.Errorf("%w: meal_type must be one of %s", recipeDomain.ErrInvalidRequest, strings.Join(recipeMealTypes, ", "))
+		}
+		recipe.MealType = *input.MealType
+	}
+	if input.Cuisine != nil {
+		cuisine := strings.TrimSpace(*input.Cuisine)
+		if len(cuisine) > 100 {
+			return fmt.Errorf("%w: cuisine must have at most 100 characters", recipeDomain.ErrInvalidRequest)
+		}
+		recipe.Cuisine = cuisine
+	}
+	if input.DietaryRestrictions != nil {
+		recipe.DietaryRestrictions = recipeModel.RecipeDietaryJSON(cleanStringSlice(input.DietaryRestrictions))
+	}
+	if input.NutritionInfo != nil {
+		recipe.NutritionInfo = recipeModel.RecipeNutritionJSON{
+			Calories:      input.NutritionInfo.Calories,
+			Protein:       input.NutritionInfo.Protein,
+			Carbohydrates: input.NutritionInfo.Carbohydrates,
+			Fat:           input.NutritionInfo.Fat,
+			Fiber:         input.NutritionInfo.Fiber,
+			Confidence:    input.NutritionInfo.Confidence,
+		}
+	}
+	if input.Tips != nil {
+		recipe.Tips = recipeModel.RecipeTipsJSON(cleanStringSlice(input.Tips))
+	}
+	if input.Tags != nil {
+		tags, err := normalizeRecipeTags(input.Tags)
+		if err != nil {
+			return err
+		}
+		recipe.Tags = recipeModel.RecipeTagsJSON(tags)
+	}
+	if input.Favorite != nil {
+		recipe.Favorite = *input.Favorite
+	}
+	if input.Rating != nil {
+		if err := validateRecipeRating(input.Rating); err != nil {
+			return err
+		}
+		recipe.Rating = input.Rating
+	}
+	if input.Notes != nil || input.Modifications != nil {
+		notes, modifications := recipe.Notes, []string(recipe.Modifications)
+		if input.Notes != nil {
+			notes = *input.Notes
+		}
+		if input.Modifications != nil {
+			modifications = input.Modifications
+		}
+		notes, modifications, err := normalizeRecipeNotes(notes, modifications)
+		if err != nil {
+			return err
+		}
+		recipe.Notes = notes
+		recipe.Modifications = recipeModel.RecipeModificationsJSON(modifications)
+	}
+	return nil
+}
+
+func validateRecipeRating(rating *int) error {
+	if rating != nil && (*rating < minRecipeRating || *rating > maxRecipeRating) {
+		return fmt.Errorf("%w: rating must be between %d and %d", recipeDomain.ErrInvalidRequest, minRecipeRating, maxRecipeRating)
+	}
+	return nil
+}
+
+// normalizeRecipeNotes trims the notes and drops empty modifications.
+func normalizeRecipeNotes(notes string, modifications []string) (string, []string, error) {
+	notes = strings.TrimSpace(notes)
+	if len([]rune(notes)) > maxRecipeNotesLength {
+		return "", nil, fmt.Errorf("%w: notes must have at most %d characters", recipeDomain.ErrInvalidRequest, maxRecipeNotesLength)
+	}
+	modifications = cleanStringSlice(modifications)
+	if len(modifications) > maxRecipeModifications {
+		return "", nil, fmt.Errorf("%w: a recipe can have at most %d modifications", recipeDomain.ErrInvalidRequest, maxRecipeModifications)
+	}
+	for _, modification := range modifications {
+		if len([]rune(modification)) > maxRecipeNotesLength {
+			return "", nil, fmt.Errorf("%w: modifications must have at most %d characters", recipeDomain.ErrInvalidRequest, maxRecipeNotesLength)
+		}
+	}
+	return notes, modifications, nil
+}
+
+// normalizeRecipeTags lowercases and deduplicates tags, keeping their order.
+func normalizeRecipeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		normalized := strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		if len([]rune(normalized)) > maxRecipeTagLength {
+			return nil, fmt.Errorf("%w: tags must have at most %d characters", recipeDomain.ErrInvalidRequest, maxRecipeTagLength)
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+	if len(result) > maxRecipeTags {
+		return nil, fmt.Errorf("%w: a recipe can have at most %d tags", recipeDomain.ErrInvalidRequest, maxRecipeTags)
+	}
+	return result, nil
+}
+
+func normalizeRecipeFilter(filter recipeDTO.RecipeFilterDTO) (recipeDTO.RecipeFilterDTO, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Cuisine = strings.TrimSpace(filter.Cuisine)
+	filter.DietaryRestriction = strings.TrimSpace(filter.DietaryRestriction)
+	filter.MealType = strings.ToLower(strings.TrimSpace(filter.MealType))
+	filter.Difficulty = strings.ToLower(strings.TrimSpace(filter.Difficulty))
+	filter.SortBy = strings.ToLower(strings.TrimSpace(filter.SortBy))
+
+	if filter.MealType != "" && !containsString(recipeMealTypes, filter.MealType) {
+		return filter, fmt.Errorf("%w: meal_type must be one of %s", recipeDomain.ErrInvalidRequest, strings.Join(recipeMealTypes, ", "))
+	}
+	if filter.Difficulty != "" && !containsString(recipeDifficulties, filter.Difficulty) {
+		return filter, fmt.Errorf("%w: difficulty must be one of %s", recipeDomain.ErrInvalidRequest, strings.Join(recipeDifficulties, ", "))
+	}
+	if filter.SortBy != "" && !containsString(recipeSortOptions, filter.SortBy) {
+		return filter, fmt.Errorf("%w: sort_by must be one of %s", recipeDomain.ErrInvalidRequest, strings.Join(recipeSortOptions, ", "))
+	}
+	if filter.MaxTotalTime != nil && *filter.MaxTotalTime <= 0 {
+		return filter, fmt.Errorf("%w: max_total_time must be positive", recipeDomain.ErrInvalidRequest)
+	}
+
+	tags, err := normalizeRecipeTags(filter.Tags)
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = tags
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultRecipePageSize
+	}
+	if filter.Limit > maxRecipePageSize {
+		filter.Limit = maxRecipePageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return filter, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"gorm.io/gorm"
+)
+
+type memoryRecipeRepository struct {
+	recipes map[uuid.UUID]*recipeModel.Recipe
+	deleted []uuid.UUID
+	filter  recipeDTO.RecipeFilterDTO
+}
+
+func newMemoryRecipeRepository(recipes ...*recipeModel.Recipe) *memoryRecipeRepository {
+	repo := &memoryRecipeRepository{recipes: make(map[uuid.UUID]*recipeModel.Recipe)}
+	for _, recipe := range recipes {
+		repo.recipes[recipe.ID] = recipe
+	}
+	return repo
+}
+
+func (r *memoryRecipeRepository) Create(ctx context.Context, recipe *recipeModel.Recipe) error {
+	if recipe.ID == uuid.Nil {
+		recipe.ID = uuid.New()
+	}
+	r.recipes[recipe.ID] = recipe
+	return nil
+}
+
+func (r *memoryRecipeRepository) CreateMany(ctx context.Context, recipes []*recipeModel.Recipe) error {
+	for _, recipe := range recipes {
+		if err := r.Create(ctx, recipe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRecipeRepository) SaveMany(ctx context.Context, created []*recipeModel.Recipe, replaced []*recipeModel.Recipe) error {
+	if err := r.CreateMany(ctx, created); err != nil {
+		return err
+	}
+	for _, recipe := range replaced {
+		r.recipes[recipe.ID] = recipe
+	}
+	return nil
+}
+
+func (r *memoryRecipeRepository) FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*recipeModel.Recipe, error) {
+	recipe, ok := r.recipes[id]
+	if !ok || recipe.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return recipe, nil
+}
+
+func (r *memoryRecipeRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*recipeModel.Recipe, error) {
+	var recipes []*recipeModel.Recipe
+	for _, recipe := range r.recipes {
+		if recipe.UserID == userID {
+			recipes = append(recipes, recipe)
+		}
+	}
+	return recipes, nil
+}
+
+// ListVersions returns the versions recorded on the recipe, which the memory
+// repository keeps as they were saved.
+func (r *memoryRecipeRepository) ListVersions(ctx context.Context, recipeID uuid.UUID) ([]*recipeModel.RecipeVersion, error) {
+	recipe, ok := r.recipes[recipeID]
+	if !ok {
+		return nil, nil
+	}
+	versions := make([]*recipeModel.RecipeVersion, 0, len(recipe.Versions))
+	for i := len(recipe.Versions) - 1; i >= 0; i-- {
+		versions = append(versions, &recipe.Versions[i])
+	}
+	return versions, nil
+}
+
+func (r *memoryRecipeRepository) FindVersion(ctx context.Context, recipeID uuid.UUID, version int) (*recipeModel.RecipeVersion, error) {
+	versions, _ := r.ListVersions(ctx, recipeID)
+	for _, found := range versions {
+		if found.Version == version {
+			return found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryRecipeRepository) Update(ctx context.Context, recipe *recipeModel.Recipe) error {
+	r.recipes[recipe.ID] = recipe
+	return nil
+}
+
+func (r *memoryRecipeRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	delete(r.recipes, id)
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func (r *memoryRecipeRepository) Search(ctx context.Context, userID uuid.UUID, filter recipeDTO.RecipeFilterDTO) ([]*recipeModel.Recipe, int64, error) {
+	r.filter = filter
+	recipes, _ := r.FindByUserID(ctx, userID)
+	return recipes, int64(len(recipes)), nil
+}
+
+func (r *memoryRecipeRepository) ListTags(ctx context.Context, userID uuid.UUID) ([]recipeDTO.RecipeTagDTO, error) {
+	return nil, nil
+}
+
+func TestRecipeService_UpdateRecipe_AppliesInformedFields(t *testing.T) {
+	userID := uuid.New()
+	servings := 2
+	recipe := &recipeModel.Recipe{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       "Feijoada",
+		Description: "Clássica",
+		ServingSize: &servings,
+		Difficulty:  "hard",
+	}
+	repo := newMemoryRecipeRepository(recipe)
+	svc := &recipeService{recipeRepository: repo}
+
+	title := "  Feijoada leve  "
+	favorite := true
+	result, err := svc.UpdateRecipe(context.Background(), recipe.ID, userID, &recipeDTO.UpdateRecipeDTO{
+		Title:    &title,
+		Tags:     []string{"Almoço", " almoço ", "Fim de  Semana"},
+		Favorite: &favorite,
+	})
+	if err != nil {
+		t.Fatalf("expected update to succeed, got %v", err)
+	}
+	if result.Title != "Feijoada leve" {
+		t.Fatalf("expected trimmed title, got %q", result.Title)
+	}
+	if result.Description != "Clássica" || result.Difficulty != "hard" {
+		t.Fatalf("expected fields left out to be kept, got %q and %q", result.Description, result.Difficulty)
+	}
+	if len(result.Tags) != 2 || result.Tags[0] != "almoço" || result.Tags[1] != "fim de semana" {
+		t.Fatalf("unexpected tags %v", result.Tags)
+	}
+	if !result.Favorite || !repo.recipes[recipe.ID].Favorite {
+		t.Fatalf("expected recipe to be saved as favorite")
+	}
+
+	difficulty := "impossible"
+	_, err = svc.UpdateRecipe(context.Background(), recipe.ID, userID, &recipeDTO.UpdateRecipeDTO{Difficulty: &difficulty})
+	if !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected invalid request for difficulty, got %v", err)
+	}
+
+	_, err = svc.UpdateRecipe(context.Background(), recipe.ID, uuid.New(), &recipeDTO.UpdateRecipeDTO{Title: &title})
+	if !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected recipe of another user to be not found, got %v", err)
+	}
+}
+
+func TestRecipeService_DeleteRecipe(t *testing.T) {
+	userID := uuid.New()
+	recipe := &recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Pão de queijo"}
+	repo := newMemoryRecipeRepository(recipe)
+	svc := &recipeService{recipeRepository: repo}
+
+	if err := svc.DeleteRecipe(context.Background(), recipe.ID, uuid.New()); !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected not found for another user, got %v", err)
+	}
+	if len(repo.deleted) != 0 {
+		t.Fatalf("expected nothing to be deleted, got %v", repo.deleted)
+	}
+
+	if err := svc.DeleteRecipe(context.Background(), recipe.ID, userID); err != nil {
+		t.Fatalf("expected delete to succeed, got %v", err)
+	}
+	if _, ok := repo.recipes[recipe.ID]; ok {
+		t.Fatalf("expected recipe to be removed")
+	}
+}
+
+func TestRecipeService_SearchRecipes_NormalizesFilter(t *testing.T) {
+	userID := uuid.New()
+	repo := newMemoryRecipeRepository(&recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Moqueca"})
+	svc := &recipeService{recipeRepository: repo}
+
+	result, err := svc.SearchRecipes(context.Background(), userID, recipeDTO.RecipeFilterDTO{
+		Query:    "  moqueca ",
+		MealType: "Dinner",
+		Tags:     []string{"Peixe", "peixe"},
+		Limit:    500,
+		Offset:   -3,
+	})
+	if err != nil {
+		t.Fatalf("expected search to succeed, got %v", err)
+	}
+	if result.Total != 1 || len(result.Recipes) != 1 {
+		t.Fatalf("expected one recipe, got %d (total %d)", len(result.Recipes), result.Total)
+	}
+	if repo.filter.Query != "moqueca" || repo.filter.MealType != "dinner" {
+		t.Fatalf("expected trimmed and lowercased filter, got %+v", repo.filter)
+	}
+	if len(repo.filter.Tags) != 1 || repo.filter.Tags[0] != "peixe" {
+		t.Fatalf("expected deduplicated tags, got %v", repo.filter.Tags)
+	}
+	if result.Limit != maxRecipePageSize || result.Offset != 0 {
+		t.Fatalf("expected limit %d and offset 0, got %d and %d", maxRecipePageSize, result.Limit, result.Offset)
+	}
+
+	result, err = svc.SearchRecipes(context.Background(), userID, recipeDTO.RecipeFilterDTO{})
+	if err != nil || result.Limit != defaultRecipePageSize {
+		t.Fatalf("expected default page size, got %+v (%v)", result, err)
+	}
+
+	for _, filter := range []recipeDTO.RecipeFilterDTO{
+		{SortBy: "popularity"},
+		{Difficulty: "extreme"},
+		{MealType: "brunch"},
+	} {
+		if _, err := svc.SearchRecipes(context.Background(), userID, filter); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+			t.Fatalf("expected invalid request for %+v, got %v", filter, err)
+		}
+	}
+}
+
+func TestNormalizeRecipeTags_Limits(t *testing.T) {
+	tags := make([]string, 0, maxRecipeTags+1)
+	for i := 0; i <= maxRecipeTags; i++ {
+		tags = append(tags, uuid.NewString()[:8])
+	}
+	if _, err := normalizeRecipeTags(tags); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected too many tags to be refused, got %v", err)
+	}
+
+	long := make([]rune, maxRecipeTagLength+1)
+	for i := range long {
+		long[i] = 'ç'
+	}
+	if _, err := normalizeRecipeTags([]string{string(long)}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected long tag to be refused, got %v", err)
+	}
+
+	normalized, err := normalizeRecipeTags([]string{"", "   ", "Vegano"})
+	if err != nil || len(normalized) != 1 || normalized[0] != "vegano" {
+		t.Fatalf("expected blank tags to be dropped, got %v (%v)", normalized, err)
+	}
+}
+
+func TestRecipeService_SetRecipeRatingAndNotes(t *testing.T) {
+	userID := uuid.New()
+	recipe := &recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Pudim"}
+	repo := newMemoryRecipeRepository(recipe)
+	svc := &recipeService{recipeRepository: repo}
+
+	rating := 4
+	result, err := svc.SetRecipeRating(context.Background(), recipe.ID, userID, &rating)
+	if err != nil || result.Rating == nil || *result.Rating != 4 {
+		t.Fatalf("expected rating 4, got %+v (%v)", result, err)
+	}
+	for _, invalid := range []int{0, 6} {
+		if _, err := svc.SetRecipeRating(context.Background(), recipe.ID, userID, &invalid); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+			t.Fatalf("expected rating %d to be refused, got %v", invalid, err)
+		}
+	}
+	if result, err := svc.SetRecipeRating(context.Background(), recipe.ID, userID, nil); err != nil || result.Rating != nil {
+		t.Fatalf("expected the rating to be cleared, got %+v (%v)", result, err)
+	}
+
+	result, err = svc.SetRecipeNotes(context.Background(), recipe.ID, userID, &recipeDTO.SetRecipeNotesDTO{
+		Notes:         "  Fica melhor no dia seguinte  ",
+		Modifications: []string{"Metade do açúcar", " ", "Forno a 160 °C"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Notes != "Fica melhor no dia seguinte" || len(result.Modifications) != 2 || result.Modifications[1] != "Forno a 160 °C" {
+		t.Fatalf("expected trimmed notes and modifications, got %q %v", result.Notes, result.Modifications)
+	}
+
+	notes := "Usei leite sem lactose"
+	result, err = svc.UpdateRecipe(context.Background(), recipe.ID, userID, &recipeDTO.UpdateRecipeDTO{Notes: &notes})
+	if err != nil || result.Notes != notes || len(result.Modifications) != 2 {
+		t.Fatalf("expected only the notes to change, got %+v (%v)", result, err)
+	}
+
+	if _, err := svc.SetRecipeNotes(context.Background(), recipe.ID, userID, &recipeDTO.SetRecipeNotesDTO{Notes: strings.Repeat("a", maxRecipeNotesLength+1)}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected long notes to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
+	nutritionDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/dto"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// EnrichRecipeWithNutrition replaces the nutrition guessed by the LLM with the
+// values per serving computed from the food composition table, when any
+// ingredient of the recipe is found in it.
+func (rs *recipeService) EnrichRecipeWithNutrition(ctx context.Context, recipe *llmDTO.RecipeResponseDTO) error {
+	if recipe == nil {
+		return fmt.Errorf("%w: recipe is required", recipeDomain.ErrInvalidRequest)
+	}
+
+	// The generated recipe is estimated like a saved one.
+	draft := &recipeModel.Recipe{}
+	if recipe.ServingSize != nil {
+		servings := int(math.Round(*recipe.ServingSize))
+		draft.ServingSize = &servings
+	}
+	for _, ingredient := range recipe.Ingredients {
+		draft.Ingredients = append(draft.Ingredients, recipeModel.RecipeIngredient{
+			Name:   ingredient.Name,
+			Amount: ingredient.Amount,
+			Unit:   ingredient.Unit,
+		})
+	}
+
+	estimate, err := rs.estimateNutrition(ctx, recipeEstimateIngredients(draft), draft.ServingCount())
+	if err != nil || estimate == nil || estimate.Matched == 0 {
+		return err
+	}
+
+	perServing := estimate.PerServing
+	confidence := estimate.Confidence
+	recipe.NutritionInfo = llmDTO.RecipeNutritionDTO{
+		Calories:      &perServing.Calories,
+		Protein:       &perServing.Protein,
+		Carbohydrates: &perServing.Carbohydrates,
+		Fat:           &perServing.Fat,
+		Fiber:         &perServing.Fiber,
+		Confidence:    &confidence,
+	}
+	return nil
+}
+
+// CalculateRecipeNutrition computes the nutrition of a saved recipe and keeps
+// it when at least one ingredient is found in the food table
+func (rs *recipeService) CalculateRecipeNutrition(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) (*recipeDTO.RecipeNutritionEstimateDTO, error) {
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "CalculateRecipeNutrition")
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := rs.estimateNutrition(ctx, recipeEstimateIngredients(recipe), recipe.ServingCount())
+	if err != nil {
+		return nil, err
+	}
+	if estimate == nil {
+		return nil, fmt.Errorf("%w: nutrition cannot be computed for this recipe", recipeDomain.ErrInvalidRequest)
+	}
+
+	result := &recipeDTO.RecipeNutritionEstimateDTO{RecipeID: recipe.ID.String(), Estimate: *estimate}
+	if estimate.Matched > 0 {
+		recipe.NutritionInfo = recipeNutritionFromEstimate(estimate)
+		detail, err := rs.saveRecipeChanges(ctx, recipe, "CalculateRecipeNutrition")
+		if err != nil {
+			return nil, err
+		}
+		result.NutritionInfo = detail.NutritionInfo
+		result.Updated = true
+	} else {
+		result.NutritionInfo = convertModelToRecipeDetailDTO(recipe).NutritionInfo
+	}
+
+	appLogger.FromContext(ctx).Info("Recipe nutrition calculated",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "CalculateRecipeNutrition"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("recipe_id", recipe.ID.String()),
+		zap.Int("matched", estimate.Matched),
+		zap.Float64("confidence", estimate.Confidence),
+	)
+
+	return result, nil
+}
+
+// applyEstimatedNutrition fills the nutrition of a recipe from the food table.
+// Failures are only logged: the recipe is kept without nutrition.
+func (rs *recipeService) applyEstimatedNutrition(ctx context.Context, recipe *recipeModel.Recipe, function string) {
+	estimate, err := rs.estimateNutrition(ctx, recipeEstimateIngredients(recipe), recipe.ServingCount())
+	if err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to compute recipe nutrition",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, recipe.UserID.String()),
+			zap.String("recipe_id", recipe.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	if estimate != nil && estimate.Matched > 0 {
+		recipe.NutritionInfo = recipeNutritionFromEstimate(estimate)
+	}
+}
+
+// estimateNutrition returns nil when there is nothing to estimate or no
+// nutrition service.
+func (rs *recipeService) estimateNutrition(ctx context.Context, ingredients []nutritionDTO.EstimateIngredientDTO, servings int) (*nutritionDTO.NutritionEstimateDTO, error) {
+	if rs.nutritionService == nil || len(ingredients) == 0 {
+		return nil, nil
+	}
+	return rs.nutritionService.EstimateNutrition(ctx, &nutritionDTO.NutritionEstimateRequestDTO{
+		Ingredients: ingredients,
+		Servings:    servings,
+	})
+}
+
+func recipeEstimateIngredients(recipe *recipeModel.Recipe) []nutritionDTO.EstimateIngredientDTO {
+	ingredients := make([]nutritionDTO.EstimateIngredientDTO, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, nutritionDTO.EstimateIngredientDTO{
+			Name:   ingredient.Name,
+			Amount: ingredient.Amount,
+			Unit:   ingredient.Unit,
+		})
+	}
+	return ingredients
+}
+
+func recipeNutritionFromEstimate(estimate *nutritionDTO.NutritionEstimateDTO) recipeModel.RecipeNutritionJSON {
+	perServing := estimate.PerServing
+	confidence := estimate.Confidence
+	return recipeModel.RecipeNutritionJSON{
+		Calories:      roundedNutrient(perServing.Calories),
+		Protein:       roundedNutrient(perServing.Protein),
+		Carbohydrates: roundedNutrient(perServing.Carbohydrates),
+		Fat:           roundedNutrient(perServing.Fat),
+		Fiber:         roundedNutrient(perServing.Fiber),
+		Confidence:    &confidence,
+	}
+}
+
+func roundedNutrient(value float64) *int {
+	rounded := int(math.Round(value))
+	return &rounded
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/data"
+	nutritionModel "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
+	nutritionService "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/service"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+)
+
+type stubFoodRepository struct {
+	foods []*nutritionModel.Food
+}
+
+func (r *stubFoodRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.foods)), nil
+}
+
+func (r *stubFoodRepository) CreateMany(ctx context.Context, foods []*nutritionModel.Food) error {
+	r.foods = append(r.foods, foods...)
+	return nil
+}
+
+func (r *stubFoodRepository) List(ctx context.Context) ([]*nutritionModel.Food, error) {
+	return r.foods, nil
+}
+
+func newNutritionTestService(t *testing.T, recipes ...*recipeModel.Recipe) (*recipeService, *memoryRecipeRepository) {
+	t.Helper()
+	foods, err := data.Foods()
+	if err != nil {
+		t.Fatalf("unexpected error loading foods: %v", err)
+	}
+	repo := newMemoryRecipeRepository(recipes...)
+	return &recipeService{
+		recipeRepository: repo,
+		nutritionService: nutritionService.NewNutritionService(&stubFoodRepository{foods: foods}),
+	}, repo
+}
+
+func TestRecipeService_CalculateRecipeNutrition_SavesPerServing(t *testing.T) {
+	userID := uuid.New()
+	servings := 2
+	recipe := &recipeModel.Recipe{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       "Omelete",
+		ServingSize: &servings,
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Ovos", Amount: cookingAmount(4)},
+			{Name: "Sal", Unit: "a gosto"},
+		},
+	}
+	svc, repo := newNutritionTestService(t, recipe)
+
+	result, err := svc.CalculateRecipeNutrition(context.Background(), recipe.ID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Updated || result.Estimate.Matched != 1 || result.Estimate.Confidence != 0.85 {
+		t.Fatalf("expected the eggs to be matched, got %+v", result)
+	}
+	if result.NutritionInfo.Calories == nil || *result.NutritionInfo.Calories != 143 {
+		t.Fatalf("expected two eggs of calories per serving, got %+v", result.NutritionInfo)
+	}
+
+	saved := repo.recipes[recipe.ID].NutritionInfo
+	if saved.Calories == nil || *saved.Calories != 143 || saved.Confidence == nil || *saved.Confidence != 0.85 {
+		t.Fatalf("expected the nutrition to be saved, got %+v", saved)
+	}
+
+	if _, err := svc.CalculateRecipeNutrition(context.Background(), recipe.ID, uuid.New()); !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected recipe not found for another user, got %v", err)
+	}
+}
+
+func TestRecipeService_CalculateRecipeNutrition_KeepsUnmatchedRecipe(t *testing.T) {
+	userID := uuid.New()
+	calories := 300
+	recipe := &recipeModel.Recipe{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Title:         "Drinque",
+		Ingredients:   recipeModel.RecipeIngredientsJSON{{Name: "Essência de baunilha", Amount: cookingAmount(1), Unit: "colher de chá"}},
+		NutritionInfo: recipeModel.RecipeNutritionJSON{Calories: &calories},
+	}
+	svc, repo := newNutritionTestService(t, recipe)
+
+	result, err := svc.CalculateRecipeNutrition(context.Background(), recipe.ID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Updated || result.Estimate.Matched != 0 {
+		t.Fatalf("expected nothing to be matched, got %+v", result)
+	}
+	if saved := repo.recipes[recipe.ID].NutritionInfo; saved.Calories == nil || *saved.Calories != 300 {
+		t.Fatalf("expected the recipe nutrition to be kept, got %+v", saved)
+	}
+}
+
+func TestRecipeService_SaveRecipe_FillsMissingNutrition(t *testing.T) {
+	userID := uuid.New()
+	servings := 1
+	svc, repo := newNutritionTestService(t)
+
+	input := &recipeDTO.SaveRecipeDTO{
+		ID:           uuid.New().String(),
+		Title:        "Calda",
+		ServingSize:  &servings,
+		Ingredients:  []recipeDTO.SaveRecipeIngredientDTO{{Name: "Açúcar", Amount: cookingAmount(100), Unit: "g"}},
+		Instructions: []recipeDTO.SaveRecipeInstructionDTO{{Step: 1, Description: "Misture"}},
+	}
+	if _, err := svc.SaveRecipe(context.Background(), input, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saved := repo.recipes[uuid.MustParse(input.ID)].NutritionInfo
+	if saved.Calories == nil || *saved.Calories != 387 || saved.Confidence == nil || *saved.Confidence != 1 {
+		t.Fatalf("expected the nutrition of 100 g of sugar, got %+v", saved)
+	}
+}
+
+func TestRecipeService_EnrichRecipeWithNutrition_ReplacesGuess(t *testing.T) {
+	svc, _ := newNutritionTestService(t)
+	servings := 2.0
+	guess := 900.0
+	recipe := &llmDTO.RecipeResponseDTO{
+		ServingSize: &servings,
+		Ingredients: []llmDTO.RecipeIngredientDTO{{Name: "Ovos", Amount: cookingAmount(2), Unit: "unidades"}},
+		NutritionInfo: llmDTO.RecipeNutritionDTO{
+			Calories: &guess,
+		},
+	}
+
+	if err := svc.EnrichRecipeWithNutrition(context.Background(), recipe); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recipe.NutritionInfo.Calories == nil || *recipe.NutritionInfo.Calories != 72 || recipe.NutritionInfo.Confidence == nil {
+		t.Fatalf("expected the guess to be replaced, got %+v", recipe.NutritionInfo)
+	}
+}
+
+func TestRecipeService_SaveMultipleRecipes_FillsMissingNutrition(t *testing.T) {
+	userID := uuid.New()
+	servings := 1
+	svc, repo := newNutritionTestService(t)
+
+	input := saveRecipeInput("Calda")
+	input.ServingSize = &servings
+	input.Ingredients = []recipeDTO.SaveRecipeIngredientDTO{{Name: "Açúcar", Amount: cookingAmount(100), Unit: "g"}}
+	if _, err := svc.SaveMultipleRecipes(context.Background(), []*recipeDTO.SaveRecipeDTO{input}, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saved := repo.recipes[uuid.MustParse(input.ID)].NutritionInfo
+	if saved.Calories == nil || *saved.Calories != 387 {
+		t.Fatalf("expected the nutrition of 100 g of sugar, got %+v", saved)
+	}
+}
+
+func TestRecipeService_UpdateRecipe_EstimatesNutritionOfNewIngredients(t *testing.T) {
+	userID := uuid.New()
+	servings := 1
+	calories := 387
+	recipe := &recipeModel.Recipe{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Title:         "Calda",
+		ServingSize:   &servings,
+		Ingredients:   recipeModel.RecipeIngredientsJSON{{Name: "Açúcar", Amount: cookingAmount(100), Unit: "g"}},
+		NutritionInfo: recipeModel.RecipeNutritionJSON{Calories: &calories},
+	}
+	svc, repo := newNutritionTestService(t, recipe)
+
+	title := "Calda rala"
+	if _, err := svc.UpdateRecipe(context.Background(), recipe.ID, userID, &recipeDTO.UpdateRecipeDTO{Title: &title}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved := repo.recipes[recipe.ID].NutritionInfo; saved.Calories == nil || *saved.Calories != 387 {
+		t.Fatalf("expected the nutrition to be kept when the ingredients do not change, got %+v", saved)
+	}
+
+	_, err := svc.UpdateRecipe(context.Background(), recipe.ID, userID, &recipeDTO.UpdateRecipeDTO{
+		Ingredients: []recipeDTO.SaveRecipeIngredientDTO{{Name: "Açúcar", Amount: cookingAmount(50), Unit: "g"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved := repo.recipes[recipe.ID].NutritionInfo; saved.Calories == nil || *saved.Calories != 194 {
+		t.Fatalf("expected the nutrition of 50 g of sugar, got %+v", saved)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+)
+
+const maxScaledServings = 100
+
+// kitchenUnitNames are the names shown for the kitchen measures an amount is
+// normalized to.
+var kitchenUnitNames = map[string]string{
+	"xicara":      "xícara",
+	"colher_sopa": "colher de sopa",
+	"colher_cha":  "colher de chá",
+}
+
+// Steps mentioning a passive stage keep their time: the oven, the fire or the
+// fridge take as long for two servings as for ten. Hands-on steps take longer
+// with more food, but not proportionally.
+var (
+	passiveStepWords = []string{"forno", "asse", "assar", "assando", "cozinhe", "cozinhar", "ferva", "ferver", "fervura", "fogo", "descans", "geladeira", "congel", "refriger", "ferment", "marin", "deixe", "aguarde", "repouso"}
+	activeStepWords  = []string{"pique", "picar", "corte", "cortar", "descasque", "descascar", "rale", "ralar", "fatie", "fatiar", "lave", "lavar", "higienize", "misture", "misturar", "bata", "bater", "sove", "sovar", "amasse", "amassar", "tempere", "temperar", "enrole", "enrolar", "modele", "modelar", "recheie", "rechear", "peneire", "separe", "empane", "empanar"}
+)
+
+// ScaleRecipe returns a saved recipe scaled to the servings. Nothing is saved.
+func (rs *recipeService) ScaleRecipe(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, servings int) (*recipeDTO.ScaledRecipeDTO, error) {
+	if servings < 1 || servings > maxScaledServings {
+		return nil, fmt.Errorf("%w: servings must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxScaledServings)
+	}
+
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "ScaleRecipe")
+	if err != nil {
+		return nil, err
+	}
+
+	return scaleRecipe(recipe, servings), nil
+}
+
+func scaleRecipe(recipe *recipeModel.Recipe, servings int) *recipeDTO.ScaledRecipeDTO {
+	original := recipe.ServingCount()
+	factor := float64(servings) / float64(original)
+
+	result := &recipeDTO.ScaledRecipeDTO{
+		RecipeID:         recipe.ID.String(),
+		Title:            recipe.Title,
+		Servings:         servings,
+		OriginalServings: original,
+		ScaleFactor:      math.Round(factor*1000) / 1000,
+		Ingredients:      make([]recipeDTO.ScaledIngredientDTO, 0, len(recipe.Ingredients)),
+		Instructions:     make([]recipeDTO.ScaledInstructionDTO, 0, len(recipe.Instructions)),
+		CookingTime:      recipe.CookingTime,
+	}
+
+	for _, ingredient := range recipe.Ingredients {
+		scaled := recipeDTO.ScaledIngredientDTO{
+			Name:           ingredient.Name,
+			Unit:           ingredient.Unit,
+			OriginalAmount: ingredient.Amount,
+			OriginalUnit:   ingredient.Unit,
+			Alternative:    ingredient.Alternative,
+		}
+		if ingredient.Amount != nil && *ingredient.Amount > 0 {
+			amount, unit := scaleIngredientAmount(*ingredient.Amount*factor, ingredient.Unit)
+			scaled.Amount = &amount
+			scaled.Unit = unit
+		}
+		result.Ingredients = append(result.Ingredients, scaled)
+	}
+
+	// The hands-on minutes added or saved are carried to the recipe times.
+	delta := 0
+	for _, instruction := range recipe.Instructions {
+		step := recipeDTO.ScaledInstructionDTO{
+			Step:         instruction.Step,
+			Description:  instruction.Description,
+			Time:         instruction.Time,
+			OriginalTime: instruction.Time,
+		}
+		if instruction.Time != nil && *instruction.Time > 0 && factor != 1 && isActiveStep(instruction.Description) {
+			minutes := int(math.Max(1, math.Round(float64(*instruction.Time)*math.Sqrt(factor))))
+			step.Time = &minutes
+			step.Scaled = minutes != *instruction.Time
+			delta += minutes - *instruction.Time
+		}
+		result.Instructions = append(result.Instructions, step)
+	}
+	result.PreparationTime = shiftMinutes(recipe.PreparationTime, delta)
+	result.TotalTime = shiftMinutes(recipe.TotalTime, delta)
+
+	return result
+}
+
+// scaleIngredientAmount rounds a scaled amount to what can be measured in a
+// kitchen and expresses it in the most readable unit: counted ingredients
+// become whole units, 1000 g becomes 1 kg and 8 spoons become half a cup.
+func scaleIngredientAmount(amount float64, unit string) (float64, string) {
+	if strings.TrimSpace(unit) == "" {
+		return math.Max(1, math.Round(amount)), unit
+	}
+
+	u, known := units.Lookup(unit)
+	if !known {
+		// Cans, cloves or pinches: halves are as far as it goes.
+		return math.Max(0.5, math.Round(amount*2)/2), unit
+	}
+
+	switch {
+	case u.Dimension == units.DimensionCount:
+		count := math.Max(1, math.Round(amount*u.Factor))
+		if u.Symbol == "dz" && math.Mod(count, 12) == 0 {
+			return count / 12, unit
+		}
+		if u.Symbol == "dz" {
+			return count, "un"
+		}
+		return count, unit
+	case u.Symbol == "copo":
+		return math.Max(0.25, math.Round(amount*4)/4), unit
+	case u.Dimension == units.DimensionVolume && u.Symbol != "ml" && u.Symbol != "l":
+		value, symbol := normalizeKitchenMeasure(amount * u.Factor)
+		if symbol == u.Symbol {
+			return value, unit
+		}
+		return value, kitchenUnitNames[symbol]
+	}
+
+	value, symbol := units.Humanize(amount, unit)
+	value = roundMetricAmount(value, symbol)
+	if symbol == u.Symbol {
+		return value, unit
+	}
+	return value, symbol
+}
+
+// normalizeKitchenMeasure expresses millilitres in cups from half a cup on,
+// in tablespoons from one spoon on and in teaspoons below that.
+func normalizeKitchenMeasure(ml float64) (float64, string) {
+	switch {
+	case ml >= 120:
+		return math.Round(ml/240*4) / 4, "xicara"
+	case ml >= 15:
+		return math.Round(ml/15*2) / 2, "colher_sopa"
+	default:
+		return math.Max(0.25, math.Round(ml/5*4)/4), "colher_cha"
+	}
+}
+
+func roundMetricAmount(value float64, symbol string) float64 {
+	switch {
+	case symbol == "kg" || symbol == "l":
+		return math.Round(value*100) / 100
+	case symbol == "mg":
+		return math.Max(1, math.Round(value))
+	case value < 10:
+		return math.Max(0.1, math.Round(value*10)/10)
+	case value < 100:
+		return math.Round(value)
+	default:
+		return math.Round(value/5) * 5
+	}
+}
+
+func isActiveStep(description string) bool {
+	text := units.Fold(description)
+	for _, word := range passiveStepWords {
+		if strings.Contains(text, word) {
+			return false
+		}
+	}
+	for _, word := range activeStepWords {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func shiftMinutes(value *int, delta int) *int {
+	if value == nil {
+		return nil
+	}
+	minutes := *value + delta
+	if minutes < 1 {
+		minutes = 1
+	}
+	return &minutes
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+)
+
+func TestScaleIngredientAmount_RoundsAndNormalizes(t *testing.T) {
+	cases := []struct {
+		amount   float64
+		unit     string
+		expected float64
+		expUnit  string
+	}{
+		{amount: 1.5, unit: "", expected: 2, expUnit: ""},
+		{amount: 0.3, unit: "unidades", expected: 1, expUnit: "unidades"},
+		{amount: 1000, unit: "g", expected: 1, expUnit: "kg"},
+		{amount: 1250, unit: "gramas", expected: 1.25, expUnit: "kg"},
+		{amount: 333.33, unit: "g", expected: 335, expUnit: "g"},
+		{amount: 0.4, unit: "l", expected: 400, expUnit: "ml"},
+		{amount: 8, unit: "colher de sopa", expected: 0.5, expUnit: "xícara"},
+		{amount: 6, unit: "colheres de sopa", expected: 6, expUnit: "colheres de sopa"},
+		{amount: 0.25, unit: "xícara", expected: 4, expUnit: "colher de sopa"},
+		{amount: 0.5, unit: "colher de sopa", expected: 1.5, expUnit: "colher de chá"},
+		{amount: 1.5, unit: "dúzia", expected: 18, expUnit: "un"},
+		{amount: 1.3, unit: "lata", expected: 1.5, expUnit: "lata"},
+	}
+
+	for _, tc := range cases {
+		amount, unit := scaleIngredientAmount(tc.amount, tc.unit)
+		if amount != tc.expected || unit != tc.expUnit {
+			t.Fatalf("scaling %v %q: expected %v %q, got %v %q", tc.amount, tc.unit, tc.expected, tc.expUnit, amount, unit)
+		}
+	}
+}
+
+func TestScaleRecipe_AdjustsHandsOnStepTimes(t *testing.T) {
+	servings := 2
+	prep, total, cooking := 20, 60, 40
+	chop, bake := 10, 40
+	recipe := &recipeModel.Recipe{
+		ID:              uuid.New(),
+		Title:           "Bolo de cenoura",
+		ServingSize:     &servings,
+		PreparationTime: &prep,
+		CookingTime:     &cooking,
+		TotalTime:       &total,
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Ovos", Amount: cookingAmount(3)},
+			{Name: "Farinha", Amount: cookingAmount(250), Unit: "g"},
+			{Name: "Sal", Unit: "a gosto"},
+		},
+		Instructions: recipeModel.RecipeInstructionsJSON{
+			{Step: 1, Description: "Descasque e pique as cenouras", Time: &chop},
+			{Step: 2, Description: "Leve ao forno por 40 minutos", Time: &bake},
+			{Step: 3, Description: "Sirva"},
+		},
+	}
+
+	scaled := scaleRecipe(recipe, 8)
+
+	if scaled.ScaleFactor != 4 || scaled.OriginalServings != 2 {
+		t.Fatalf("unexpected scale %+v", scaled)
+	}
+	if *scaled.Ingredients[0].Amount != 12 || *scaled.Ingredients[1].Amount != 1 || scaled.Ingredients[1].Unit != "kg" {
+		t.Fatalf("unexpected ingredients %+v", scaled.Ingredients)
+	}
+	if scaled.Ingredients[2].Amount != nil || scaled.Ingredients[2].Unit != "a gosto" {
+		t.Fatalf("expected ingredients to taste unchanged, got %+v", scaled.Ingredients[2])
+	}
+	if *scaled.Instructions[0].Time != 20 || !scaled.Instructions[0].Scaled {
+		t.Fatalf("expected chopping to take 20 minutes, got %+v", scaled.Instructions[0])
+	}
+	if *scaled.Instructions[1].Time != 40 || scaled.Instructions[1].Scaled {
+		t.Fatalf("expected baking time unchanged, got %+v", scaled.Instructions[1])
+	}
+	if *scaled.PreparationTime != 30 || *scaled.TotalTime != 70 || *scaled.CookingTime != 40 {
+		t.Fatalf("unexpected recipe times %d %d %d", *scaled.PreparationTime, *scaled.TotalTime, *scaled.CookingTime)
+	}
+}
+
+func TestRecipeService_ScaleRecipe_ValidatesServings(t *testing.T) {
+	userID := uuid.New()
+	recipe := &recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Arroz"}
+	svc := &recipeService{recipeRepository: newMemoryRecipeRepository(recipe)}
+
+	if _, err := svc.ScaleRecipe(context.Background(), recipe.ID, userID, 0); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected zero servings to be refused, got %v", err)
+	}
+	if _, err := svc.ScaleRecipe(context.Background(), recipe.ID, uuid.New(), 2); !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected the recipe of another user not to be found, got %v", err)
+	}
+	scaled, err := svc.ScaleRecipe(context.Background(), recipe.ID, userID, 3)
+	if err != nil || scaled.Servings != 3 || scaled.OriginalServings != 1 {
+		t.Fatalf("unexpected scaled recipe %+v, %v", scaled, err)
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultIngredientSearchLimit = 20
+	maxIngredientSearchLimit     = 100
+	maxSearchIngredients         = 50
+)
+
+// ingredientSearchMatch is a recipe with the ingredients found at hand.
+type ingredientSearchMatch struct {
+	recipe   *recipeModel.Recipe
+	coverage float64
+	matched  []string
+	missing  []recipeModel.RecipeIngredient
+}
+
+// SearchRecipesByIngredients ranks the saved recipes of a user by the share of
+// their ingredients at hand.
+func (rs *recipeService) SearchRecipesByIngredients(ctx context.Context, userID uuid.UUID, input *recipeDTO.IngredientSearchDTO) ([]recipeDTO.IngredientSearchResultDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	search, err := normalizeIngredientSearch(input)
+	if err != nil {
+		logger.Warn("Invalid ingredient search",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SearchRecipesByIngredients"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	var items []*itemModel.Item
+	if search.PantryID != "" {
+		pantryID, err := uuid.Parse(search.PantryID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: pantry_id must be a valid UUID", recipeDomain.ErrInvalidRequest)
+		}
+		if err := checkPantryAccess(ctx, rs.pantryService, pantryID, userID, "SearchRecipesByIngredients"); err != nil {
+			return nil, err
+		}
+		items, err = rs.itemRepository.ListByPantryID(ctx, pantryID)
+		if err != nil {
+			logger.Error("Failed to list pantry items",
+				zap.String(appLogger.FieldModule, "recipe"),
+				zap.String(appLogger.FieldFunction, "SearchRecipesByIngredients"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("pantry_id", pantryID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+	}
+
+	recipes, err := rs.recipeRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list recipes",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SearchRecipesByIngredients"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	matches := rankRecipesByIngredients(recipes, searchStock(search.Ingredients, items), search)
+	results := make([]recipeDTO.IngredientSearchResultDTO, 0, len(matches))
+	for _, match := range matches {
+		missing := make([]recipeDTO.RecipeIngredientDetailDTO, 0, len(match.missing))
+		for _, ingredient := range match.missing {
+			missing = append(missing, recipeDTO.RecipeIngredientDetailDTO{
+				Name:        ingredient.Name,
+				Amount:      ingredient.Amount,
+				Unit:        ingredient.Unit,
+				Alternative: ingredient.Alternative,
+			})
+		}
+		results = append(results, recipeDTO.IngredientSearchResultDTO{
+			Recipe:             *convertModelToRecipeDetailDTO(match.recipe),
+			Coverage:           math.Round(match.coverage*100) / 100,
+			MatchedIngredients: match.matched,
+			MissingIngredients: missing,
+		})
+	}
+
+	logger.Info("Recipes searched by ingredients",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "SearchRecipesByIngredients"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.Int(appLogger.FieldCount, len(results)),
+	)
+
+	return results, nil
+}
+
+func normalizeIngredientSearch(input *recipeDTO.IngredientSearchDTO) (recipeDTO.IngredientSearchDTO, error) {
+	if input == nil {
+		return recipeDTO.IngredientSearchDTO{}, fmt.Errorf("%w: search data is required", recipeDomain.ErrInvalidRequest)
+	}
+	search := *input
+	search.PantryID = strings.TrimSpace(search.PantryID)
+	search.MealType = strings.ToLower(strings.TrimSpace(search.MealType))
+	search.DietaryRestriction = strings.TrimSpace(search.DietaryRestriction)
+
+	search.Ingredients = nil
+	for _, ingredient := range input.Ingredients {
+		if ingredient = strings.TrimSpace(ingredient); ingredient != "" {
+			search.Ingredients = append(search.Ingredients, ingredient)
+		}
+	}
+	if len(search.Ingredients) == 0 && search.PantryID == "" {
+		return search, fmt.Errorf("%w: ingredients or pantry_id is required", recipeDomain.ErrInvalidRequest)
+	}
+	if len(search.Ingredients) > maxSearchIngredients {
+		return search, fmt.Errorf("%w: at most %d ingredients can be searched", recipeDomain.ErrInvalidRequest, maxSearchIngredients)
+	}
+	if search.MealType != "" && !containsString(recipeMealTypes, search.MealType) {
+		return search, fmt.Errorf("%w: meal_type must be one of %s", recipeDomain.ErrInvalidRequest, strings.Join(recipeMealTypes, ", "))
+	}
+	if search.MaxTotalTime != nil && *search.MaxTotalTime <= 0 {
+		return search, fmt.Errorf("%w: max_total_time must be positive", recipeDomain.ErrInvalidRequest)
+	}
+	if search.MinCoverage < 0 || search.MinCoverage > 1 {
+		return search, fmt.Errorf("%w: min_coverage must be between 0 and 1", recipeDomain.ErrInvalidRequest)
+	}
+	switch {
+	case search.Limit < 0:
+		return search, fmt.Errorf("%w: limit cannot be negative", recipeDomain.ErrInvalidRequest)
+	case search.Limit == 0:
+		search.Limit = defaultIngredientSearchLimit
+	case search.Limit > maxIngredientSearchLimit:
+		search.Limit = maxIngredientSearchLimit
+	}
+	return search, nil
+}
+
+// searchStock joins the listed ingredients and the pantry items in stock in
+// the entries matched against the recipe ingredients.
+func searchStock(ingredients []string, items []*itemModel.Item) []*cookingStock {
+	stock := indexCookingStock(items)
+	for _, ingredient := range ingredients {
+		stock = append(stock, &cookingStock{key: normalizeIngredientName(ingredient)})
+	}
+	return stock
+}
+
+// rankRecipesByIngredients keeps the recipes that pass the filters and have at
+// least one ingredient at hand, the best covered first. Ties go to the recipe
+// missing fewer ingredients, then to favorites.
+func rankRecipesByIngredients(recipes []*recipeModel.Recipe, stock []*cookingStock, search recipeDTO.IngredientSearchDTO) []ingredientSearchMatch {
+	var matches []ingredientSearchMatch
+	for _, recipe := range recipes {
+		if !matchesIngredientSearchFilters(recipe, search) {
+			continue
+		}
+
+		match := ingredientSearchMatch{recipe: recipe, matched: []string{}}
+		counted := 0
+		for _, ingredient := range recipe.Ingredients {
+			key := normalizeIngredientName(ingredient.Name)
+			if key == "" || ingredient.Amount == nil {
+				// Ingredients "a gosto" do not decide whether a recipe can
+				// be made.
+				continue
+			}
+			counted++
+			if len(matchCookingStock(key, stock)) > 0 {
+				match.matched = append(match.matched, ingredient.Name)
+			} else {
+				match.missing = append(match.missing, ingredient)
+			}
+		}
+		if counted == 0 || len(match.matched) == 0 {
+			continue
+		}
+		match.coverage = float64(len(match.matched)) / float64(counted)
+		if match.coverage < search.MinCoverage {
+			continue
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		switch {
+		case a.coverage != b.coverage:
+			return a.coverage > b.coverage
+		case len(a.missing) != len(b.missing):
+			return len(a.missing) < len(b.missing)
+		case a.recipe.Favorite != b.recipe.Favorite:
+			return a.recipe.Favorite
+		}
+		return strings.ToLower(a.recipe.Title) < strings.ToLower(b.recipe.Title)
+	})
+	if search.Limit > 0 && len(matches) > search.Limit {
+		matches = matches[:search.Limit]
+	}
+	return matches
+}
+
+func matchesIngredientSearchFilters(recipe *recipeModel.Recipe, search recipeDTO.IngredientSearchDTO) bool {
+	if search.MealType != "" && recipe.MealType != search.MealType {
+		return false
+	}
+	if search.MaxTotalTime != nil {
+		total := recipeTotalTime(recipe)
+		if total == nil || *total > *search.MaxTotalTime {
+			return false
+		}
+	}
+	if search.DietaryRestriction != "" {
+		found := false
+		for _, restriction := range recipe.DietaryRestrictions {
+			if strings.EqualFold(strings.TrimSpace(restriction), search.DietaryRestriction) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// recipeTotalTime is the total time of a recipe, falling back to the sum of
+// the preparation and cooking times like the recipe filters of the
+// repository. It is nil when the times are unknown.
+func recipeTotalTime(recipe *recipeModel.Recipe) *int {
+	if recipe.TotalTime != nil {
+		return recipe.TotalTime
+	}
+	if recipe.PreparationTime == nil || recipe.CookingTime == nil {
+		return nil
+	}
+	total := *recipe.PreparationTime + *recipe.CookingTime
+	return &total
+}
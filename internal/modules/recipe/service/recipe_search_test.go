@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+)
+
+func newSearchTestRecipe(userID uuid.UUID, title string, mealType string, totalTime int, ingredients ...string) *recipeModel.Recipe {
+	recipe := &recipeModel.Recipe{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Title:     title,
+		MealType:  mealType,
+		TotalTime: &totalTime,
+	}
+	for _, name := range ingredients {
+		recipe.Ingredients = append(recipe.Ingredients, recipeModel.RecipeIngredient{Name: name, Amount: cookingAmount(1), Unit: "un"})
+	}
+	return recipe
+}
+
+func cookingMinutes(value int) *int {
+	return &value
+}
+
+func TestRecipeService_SearchRecipesByIngredients_RanksByCoverage(t *testing.T) {
+	userID := uuid.New()
+	omelette := newSearchTestRecipe(userID, "Omelete", "breakfast", 10, "Ovos", "Queijo")
+	omelette.Ingredients = append(omelette.Ingredients, recipeModel.RecipeIngredient{Name: "sal"})
+	cake := newSearchTestRecipe(userID, "Bolo", "dessert", 50, "Ovos", "Farinha de trigo", "Açúcar", "Leite")
+	soup := newSearchTestRecipe(userID, "Sopa", "dinner", 40, "Abóbora", "Cebola")
+	other := newSearchTestRecipe(uuid.New(), "Omelete de outro", "breakfast", 10, "Ovos")
+	svc := &recipeService{recipeRepository: newMemoryRecipeRepository(omelette, cake, soup, other)}
+
+	results, err := svc.SearchRecipesByIngredients(context.Background(), userID, &recipeDTO.IngredientSearchDTO{
+		Ingredients: []string{"ovos", "queijo", "acucar"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected the omelette and the cake, got %+v", results)
+	}
+	if results[0].Recipe.Title != "Omelete" || results[0].Coverage != 1 || len(results[0].MissingIngredients) != 0 {
+		t.Fatalf("expected the omelette to be fully covered, got %+v", results[0])
+	}
+	second := results[1]
+	if second.Recipe.Title != "Bolo" || second.Coverage != 0.5 || len(second.MatchedIngredients) != 2 {
+		t.Fatalf("expected the cake at half coverage, got %+v", second)
+	}
+	if len(second.MissingIngredients) != 2 || second.MissingIngredients[0].Name != "Farinha de trigo" || second.MissingIngredients[1].Name != "Leite" {
+		t.Fatalf("unexpected missing ingredients %+v", second.MissingIngredients)
+	}
+}
+
+func TestRecipeService_SearchRecipesByIngredients_Filters(t *testing.T) {
+	userID := uuid.New()
+	quick := newSearchTestRecipe(userID, "Salada", "lunch", 15, "Alface", "Tomate")
+	quick.DietaryRestrictions = recipeModel.RecipeDietaryJSON{"Vegana"}
+	slow := newSearchTestRecipe(userID, "Molho de tomate", "lunch", 90, "Tomate", "Cebola")
+	dinner := newSearchTestRecipe(userID, "Tomate recheado", "dinner", 30, "Tomate")
+	untimed := newSearchTestRecipe(userID, "Bruschetta", "lunch", 0, "Pão", "Tomate")
+	untimed.TotalTime, untimed.PreparationTime, untimed.CookingTime = nil, cookingMinutes(15), cookingMinutes(10)
+	unknown := newSearchTestRecipe(userID, "Tomate seco", "lunch", 0, "Tomate", "Azeite")
+	unknown.TotalTime = nil
+	svc := &recipeService{recipeRepository: newMemoryRecipeRepository(quick, slow, dinner, untimed, unknown)}
+
+	maxTime := 30
+	results, err := svc.SearchRecipesByIngredients(context.Background(), userID, &recipeDTO.IngredientSearchDTO{
+		Ingredients:  []string{"tomate"},
+		MealType:     "Lunch",
+		MaxTotalTime: &maxTime,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Recipe.Title != "Bruschetta" || results[1].Recipe.Title != "Salada" {
+		t.Fatalf("expected only the quick lunches, timed by preparation and cooking when the total is unknown, got %+v", results)
+	}
+
+	results, err = svc.SearchRecipesByIngredients(context.Background(), userID, &recipeDTO.IngredientSearchDTO{
+		Ingredients:        []string{"tomate"},
+		DietaryRestriction: "vegana",
+	})
+	if err != nil || len(results) != 1 || results[0].Recipe.Title != "Salada" {
+		t.Fatalf("expected only the vegan recipe, got %+v, %v", results, err)
+	}
+
+	results, err = svc.SearchRecipesByIngredients(context.Background(), userID, &recipeDTO.IngredientSearchDTO{
+		Ingredients: []string{"tomate"},
+		MinCoverage: 1,
+	})
+	if err != nil || len(results) != 1 || results[0].Recipe.Title != "Tomate recheado" {
+		t.Fatalf("expected only the fully covered recipe, got %+v, %v", results, err)
+	}
+}
+
+func TestRecipeService_SearchRecipesByIngredients_FromPantry(t *testing.T) {
+	userID, pantryID := uuid.New(), uuid.New()
+	rice := newSearchTestRecipe(userID, "Arroz", "lunch", 25, "Arroz branco", "Alho")
+	svc := &recipeService{
+		itemRepository: &stubItemRepository{items: []*model.Item{
+			{ID: uuid.New(), PantryID: pantryID, Name: "Arroz", Quantity: 2, Unit: "kg"},
+			{ID: uuid.New(), PantryID: pantryID, Name: "Alho", Quantity: 0, Unit: "un"},
+		}},
+		pantryService:    &stubPantryService{},
+		recipeRepository: newMemoryRecipeRepository(rice),
+	}
+
+	results, err := svc.SearchRecipesByIngredients(context.Background(), userID, &recipeDTO.IngredientSearchDTO{PantryID: pantryID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Coverage != 0.5 {
+		t.Fatalf("expected rice at half coverage, got %+v", results)
+	}
+	if len(results[0].MissingIngredients) != 1 || results[0].MissingIngredients[0].Name != "Alho" {
+		t.Fatalf("expected garlic out of stock to be missing, got %+v", results[0].MissingIngredients)
+	}
+}
+
+func TestRecipeService_SearchRecipesByIngredients_Validation(t *testing.T) {
+	svc := &recipeService{recipeRepository: newMemoryRecipeRepository()}
+	zero := 0
+
+	for name, input := range map[string]*recipeDTO.IngredientSearchDTO{
+		"empty":        {},
+		"pantry":       {PantryID: "despensa"},
+		"meal type":    {Ingredients: []string{"ovos"}, MealType: "brunch"},
+		"total time":   {Ingredients: []string{"ovos"}, MaxTotalTime: &zero},
+		"min coverage": {Ingredients: []string{"ovos"}, MinCoverage: 1.5},
+		"limit":        {Ingredients: []string{"ovos"}, Limit: -1},
+	} {
+		if _, err := svc.SearchRecipesByIngredients(context.Background(), uuid.New(), input); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+			t.Fatalf("%s: expected invalid request, got %v", name, err)
+		}
+	}
+}
@@ -12,22 +12,32 @@ import (
 	itemDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/item/domain"
 	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
 	llmSvc "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/service"
+	nutritionDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/domain"
 	pantryDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/domain"
 	pantrySvc "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/service"
+	profileDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/domain"
 	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
 	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
 	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
 	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/recipeschema"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type recipeService struct {
-	llmService       *llmSvc.LLMServiceImpl
-	itemRepository   itemDomain.ItemRepository
-	pantryService    pantryDomain.PantryService
-	recipeRepository recipeDomain.RecipeRepository
-	promptBuilder    *llmSvc.PromptBuilderImpl
+	llmService        *llmSvc.LLMServiceImpl
+	itemRepository    itemDomain.ItemRepository
+	pantryService     pantryDomain.PantryService
+	recipeRepository  recipeDomain.RecipeRepository
+	cookingRepository recipeDomain.RecipeCookingRepository
+	pageFetcher       recipeschema.Fetcher
+	nutritionService  nutritionDomain.NutritionService
+	profileRepository profileDomain.ProfileRepository
+	promptBuilder     *llmSvc.PromptBuilderImpl
+	// duplicatePolicy is the configured handling of near-duplicate recipes
+	// on save: warn or refuse.
+	duplicatePolicy string
 }
 
 func NewRecipeService(
@@ -35,13 +45,23 @@ func NewRecipeService(
 	itemRepository itemDomain.ItemRepository,
 	pantryService pantryDomain.PantryService,
 	recipeRepository recipeDomain.RecipeRepository,
+	cookingRepository recipeDomain.RecipeCookingRepository,
+	pageFetcher recipeschema.Fetcher,
+	nutritionService nutritionDomain.NutritionService,
+	profileRepository profileDomain.ProfileRepository,
+	duplicatePolicy string,
 ) recipeDomain.RecipeService {
 	return &recipeService{
-		llmService:       llmService,
-		itemRepository:   itemRepository,
-		pantryService:    pantryService,
-		recipeRepository: recipeRepository,
-		promptBuilder:    llmSvc.NewPromptBuilder(),
+		llmService:        llmService,
+		itemRepository:    itemRepository,
+		pantryService:     pantryService,
+		recipeRepository:  recipeRepository,
+		cookingRepository: cookingRepository,
+		pageFetcher:       pageFetcher,
+		nutritionService:  nutritionService,
+		profileRepository: profileRepository,
+		promptBuilder:     llmSvc.NewPromptBuilder(),
+		duplicatePolicy:   duplicatePolicy,
 	}
 }
 
@@ -159,6 +179,23 @@ func (rs *recipeService) GenerateRecipe(ctx context.Context, request *llmDTO.Rec
 
 	rs.markIngredientAvailability(recipe, availableIngredients)
 
+	ingredients := make([]string, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, ingredient.Name)
+	}
+	recipe.DietaryWarnings = checkRecipeDietary(ingredients,
+		append(append([]string{}, request.DietaryRestrictions...), recipe.DietaryRestrictions...),
+		householdDietaryMembers(ctx, rs.pantryService, rs.profileRepository, userID, &pantryID, "GenerateRecipe"))
+
+	if err := rs.EnrichRecipeWithNutrition(ctx, recipe); err != nil {
+		logger.Warn("Failed to compute recipe nutrition",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "GenerateRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+	}
+
 	logger.Info("Recipe generated successfully",
 		zap.String(appLogger.FieldModule, "recipe"),
 		zap.String(appLogger.FieldFunction, "GenerateRecipe"),
@@ -174,34 +211,8 @@ func (rs *recipeService) GenerateRecipe(ctx context.Context, request *llmDTO.Rec
 func (rs *recipeService) GetAvailableIngredients(ctx context.Context, pantryID uuid.UUID, userID uuid.UUID) ([]recipeDTO.AvailableIngredientDTO, error) {
 	logger := appLogger.FromContext(ctx)
 
-	if _, err := rs.pantryService.GetPantry(ctx, pantryID, userID); err != nil {
-		switch {
-		case errors.Is(err, pantrySvc.ErrUnauthorized):
-			logger.Warn("Unauthorized access to pantry",
-				zap.String(appLogger.FieldModule, "recipe"),
-				zap.String(appLogger.FieldFunction, "GetAvailableIngredients"),
-				zap.String(appLogger.FieldUserID, userID.String()),
-				zap.String("pantry_id", pantryID.String()),
-			)
-			return nil, recipeDomain.ErrUnauthorized
-		case errors.Is(err, pantrySvc.ErrPantryNotFound):
-			logger.Warn("Pantry not found",
-				zap.String(appLogger.FieldModule, "recipe"),
-				zap.String(appLogger.FieldFunction, "GetAvailableIngredients"),
-				zap.String(appLogger.FieldUserID, userID.String()),
-				zap.String("pantry_id", pantryID.String()),
-			)
-			return nil, recipeDomain.ErrPantryNotFound
-		default:
-			logger.Error("Failed to get pantry",
-				zap.String(appLogger.FieldModule, "recipe"),
-				zap.String(appLogger.FieldFunction, "GetAvailableIngredients"),
-				zap.String(appLogger.FieldUserID, userID.String()),
-				zap.String("pantry_id", pantryID.String()),
-				zap.Error(err),
-			)
-			return nil, err
-		}
+	if err := checkPantryAccess(ctx, rs.pantryService, pantryID, userID, "GetAvailableIngredients"); err != nil {
+		return nil, err
 	}
 
 	items, err := rs.itemRepository.ListByPantryID(ctx, pantryID)
@@ -238,17 +249,6 @@ func (rs *recipeService) GetAvailableIngredients(ctx context.Context, pantryID u
 	return ingredients, nil
 }
 
-func (rs *recipeService) SearchRecipesByIngredients(ctx context.Context, ingredients []string, filters map[string]string) ([]llmDTO.RecipeResponseDTO, error) {
-	logger := appLogger.FromContext(ctx)
-
-	logger.Warn("Search by ingredients not implemented",
-		zap.String(appLogger.FieldModule, "recipe"),
-		zap.String(appLogger.FieldFunction, "SearchRecipesByIngredients"),
-	)
-
-	return nil, fmt.Errorf("%w: search by ingredients not implemented", recipeDomain.ErrInvalidRequest)
-}
-
 func (rs *recipeService) validateRecipeRequest(request *llmDTO.RecipeRequestDTO) (uuid.UUID, error) {
 	if request.PantryID == "" {
 		return uuid.Nil, fmt.Errorf("%w: pantry_id is required", recipeDomain.ErrInvalidRequest)
@@ -294,12 +294,6 @@ func (rs *recipeService) validateRecipeRequest(request *llmDTO.RecipeRequestDTO)
 	return pantryID, nil
 }
 
-// EnrichRecipeWithNutrition adiciona informações nutricionais (placeholder)
-func (rs *recipeService) EnrichRecipeWithNutrition(ctx context.Context, recipe *llmDTO.RecipeResponseDTO) error {
-	// TODO: Implementar cálculo nutricional real
-	return nil
-}
-
 // buildPromptVariables constrói as variáveis para o prompt
 func (rs *recipeService) buildPromptVariables(request *llmDTO.RecipeRequestDTO, ingredients []recipeDTO.AvailableIngredientDTO) map[string]string {
 	var formattedIngredients []string
@@ -487,21 +481,22 @@ func (rs *recipeService) GenerateMultipleRecipes(ctx context.Context, request *l
 	return recipes, nil
 }
 
-// SaveRecipe saves a single recipe to the database
-func (rs *recipeService) SaveRecipe(ctx context.Context, recipeDTO *recipeDTO.SaveRecipeDTO, userID uuid.UUID) error {
+// SaveRecipe saves a single recipe to the database. Near-duplicates of the
+// saved recipes are reported, refused or replaced as configured or asked.
+func (rs *recipeService) SaveRecipe(ctx context.Context, input *recipeDTO.SaveRecipeDTO, userID uuid.UUID) (*recipeDTO.SavedRecipeDTO, error) {
 	logger := appLogger.FromContext(ctx)
 
-	if err := rs.validateSaveRecipeDTO(recipeDTO); err != nil {
+	if err := rs.validateSaveRecipeDTO(input); err != nil {
 		logger.Warn("Invalid recipe data for save",
 			zap.String(appLogger.FieldModule, "recipe"),
 			zap.String(appLogger.FieldFunction, "SaveRecipe"),
 			zap.String(appLogger.FieldUserID, userID.String()),
 			zap.Error(err),
 		)
-		return err
+		return nil, err
 	}
 
-	recipe, err := rs.convertSaveRecipeDTOToModel(recipeDTO, userID)
+	recipe, err := rs.convertSaveRecipeDTOToModel(input, userID)
 	if err != nil {
 		logger.Error("Failed to convert recipe DTO to model",
 			zap.String(appLogger.FieldModule, "recipe"),
@@ -509,18 +504,45 @@ func (rs *recipeService) SaveRecipe(ctx context.Context, recipeDTO *recipeDTO.Sa
 			zap.String(appLogger.FieldUserID, userID.String()),
 			zap.Error(err),
 		)
-		return fmt.Errorf("%w: %v", recipeDomain.ErrInvalidRecipeData, err)
+		return nil, fmt.Errorf("%w: %v", recipeDomain.ErrInvalidRecipeData, err)
 	}
 
-	if err := rs.recipeRepository.Create(ctx, recipe); err != nil {
+	saved, err := rs.savedRecipes(ctx, userID, "SaveRecipe")
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planRecipeSave(recipe, input, rs.resolveDuplicatePolicy(input.OnDuplicate), saved)
+	if err != nil {
+		logger.Warn("Recipe not saved",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SaveRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipe.ID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	recipe = plan.recipe
+
+	if recipe.NutritionInfo.Calories == nil {
+		rs.applyEstimatedNutrition(ctx, recipe, "SaveRecipe")
+	}
+
+	if plan.replaced {
+		err = rs.recipeRepository.Update(ctx, recipe)
+	} else {
+		err = rs.recipeRepository.Create(ctx, recipe)
+	}
+	if err != nil {
 		logger.Error("Failed to create recipe in database",
 			zap.String(appLogger.FieldModule, "recipe"),
 			zap.String(appLogger.FieldFunction, "SaveRecipe"),
 			zap.String(appLogger.FieldUserID, userID.String()),
 			zap.String("recipe_id", recipe.ID.String()),
+			zap.Bool("replaced", plan.replaced),
 			zap.Error(err),
 		)
-		return err
+		return nil, err
 	}
 
 	logger.Info("Recipe saved successfully",
@@ -529,13 +551,19 @@ func (rs *recipeService) SaveRecipe(ctx context.Context, recipeDTO *recipeDTO.Sa
 		zap.String(appLogger.FieldUserID, userID.String()),
 		zap.String("recipe_id", recipe.ID.String()),
 		zap.String("title", recipe.Title),
+		zap.Bool("replaced", plan.replaced),
+		zap.Int("duplicates", len(plan.duplicates)),
 	)
 
-	return nil
+	result := convertSavePlanToDTO(plan)
+	result.DietaryWarnings = checkRecipeDietary(recipeIngredientNames(recipe), recipe.DietaryRestrictions,
+		householdDietaryMembers(ctx, rs.pantryService, rs.profileRepository, userID, nil, "SaveRecipe"))
+	return result, nil
 }
 
-// SaveMultipleRecipes saves multiple recipes to the database atomically
-func (rs *recipeService) SaveMultipleRecipes(ctx context.Context, recipeDTOs []*recipeDTO.SaveRecipeDTO, userID uuid.UUID) error {
+// SaveMultipleRecipes saves multiple recipes to the database atomically. When
+// one of them is refused as a near-duplicate, none is saved.
+func (rs *recipeService) SaveMultipleRecipes(ctx context.Context, recipeDTOs []*recipeDTO.SaveRecipeDTO, userID uuid.UUID) ([]recipeDTO.SavedRecipeDTO, error) {
 	logger := appLogger.FromContext(ctx)
 
 	if len(recipeDTOs) == 0 {
@@ -544,10 +572,22 @@ func (rs *recipeService) SaveMultipleRecipes(ctx context.Context, recipeDTOs []*
 			zap.String(appLogger.FieldFunction, "SaveMultipleRecipes"),
 			zap.String(appLogger.FieldUserID, userID.String()),
 		)
-		return fmt.Errorf("%w: at least one recipe is required", recipeDomain.ErrInvalidRequest)
+		return nil, fmt.Errorf("%w: at least one recipe is required", recipeDomain.ErrInvalidRequest)
 	}
 
-	recipes := make([]*recipeModel.Recipe, 0, len(recipeDTOs))
+	saved, err := rs.savedRecipes(ctx, userID, "SaveMultipleRecipes")
+	if err != nil {
+		return nil, err
+	}
+
+	// The entries of the batch are compared with the saved recipes and with
+	// the entries before them, so near-identical generations saved together
+	// are handled like the ones saved one by one.
+	known := append([]*recipeModel.Recipe(nil), saved...)
+	var created, replaced, planned []*recipeModel.Recipe
+	createdIDs := make(map[uuid.UUID]bool)
+	replacedIDs := make(map[uuid.UUID]bool)
+	results := make([]recipeDTO.SavedRecipeDTO, 0, len(recipeDTOs))
 	for _, dto := range recipeDTOs {
 		if err := rs.validateSaveRecipeDTO(dto); err != nil {
 			logger.Warn("Invalid recipe data in batch",
@@ -556,7 +596,7 @@ func (rs *recipeService) SaveMultipleRecipes(ctx context.Context, recipeDTOs []*
 				zap.String(appLogger.FieldUserID, userID.String()),
 				zap.Error(err),
 			)
-			return err
+			return nil, err
 		}
 
 		recipe, err := rs.convertSaveRecipeDTOToModel(dto, userID)
@@ -567,30 +607,65 @@ func (rs *recipeService) SaveMultipleRecipes(ctx context.Context, recipeDTOs []*
 				zap.String(appLogger.FieldUserID, userID.String()),
 				zap.Error(err),
 			)
-			return fmt.Errorf("%w: %v", recipeDomain.ErrInvalidRecipeData, err)
+			return nil, fmt.Errorf("%w: %v", recipeDomain.ErrInvalidRecipeData, err)
 		}
-		recipes = append(recipes, recipe)
+
+		plan, err := planRecipeSave(recipe, dto, rs.resolveDuplicatePolicy(dto.OnDuplicate), known)
+		if err != nil {
+			logger.Warn("Recipe in batch not saved",
+				zap.String(appLogger.FieldModule, "recipe"),
+				zap.String(appLogger.FieldFunction, "SaveMultipleRecipes"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("recipe_id", recipe.ID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		if plan.recipe.NutritionInfo.Calories == nil {
+			rs.applyEstimatedNutrition(ctx, plan.recipe, "SaveMultipleRecipes")
+		}
+		switch {
+		case createdIDs[plan.recipe.ID]:
+			// An earlier entry of the batch was replaced before being created.
+		case plan.replaced:
+			if replacedIDs[plan.recipe.ID] {
+				return nil, fmt.Errorf("%w: more than one recipe replaces %q", recipeDomain.ErrInvalidRequest, plan.recipe.Title)
+			}
+			replacedIDs[plan.recipe.ID] = true
+			replaced = append(replaced, plan.recipe)
+		default:
+			createdIDs[plan.recipe.ID] = true
+			created = append(created, plan.recipe)
+			known = append(known, plan.recipe)
+		}
+		planned = append(planned, plan.recipe)
+		results = append(results, *convertSavePlanToDTO(plan))
 	}
 
-	if err := rs.recipeRepository.CreateMany(ctx, recipes); err != nil {
+	if err := rs.recipeRepository.SaveMany(ctx, created, replaced); err != nil {
 		logger.Error("Failed to create multiple recipes in database",
 			zap.String(appLogger.FieldModule, "recipe"),
 			zap.String(appLogger.FieldFunction, "SaveMultipleRecipes"),
 			zap.String(appLogger.FieldUserID, userID.String()),
-			zap.Int(appLogger.FieldCount, len(recipes)),
+			zap.Int(appLogger.FieldCount, len(results)),
 			zap.Error(err),
 		)
-		return err
+		return nil, err
 	}
 
 	logger.Info("Multiple recipes saved successfully",
 		zap.String(appLogger.FieldModule, "recipe"),
 		zap.String(appLogger.FieldFunction, "SaveMultipleRecipes"),
 		zap.String(appLogger.FieldUserID, userID.String()),
-		zap.Int(appLogger.FieldCount, len(recipes)),
+		zap.Int(appLogger.FieldCount, len(results)),
+		zap.Int("replaced", len(replaced)),
 	)
 
-	return nil
+	members := householdDietaryMembers(ctx, rs.pantryService, rs.profileRepository, userID, nil, "SaveMultipleRecipes")
+	for i, recipe := range planned {
+		results[i].DietaryWarnings = checkRecipeDietary(recipeIngredientNames(recipe), recipe.DietaryRestrictions, members)
+	}
+	return results, nil
 }
 
 // GetRecipeByID retrieves a single recipe by ID
@@ -626,7 +701,7 @@ func (rs *recipeService) GetRecipeByID(ctx context.Context, recipeID uuid.UUID,
 		zap.String("recipe_id", recipeID.String()),
 	)
 
-	return rs.convertModelToRecipeDetailDTO(recipe), nil
+	return convertModelToRecipeDetailDTO(recipe), nil
 }
 
 // GetUserRecipes retrieves all recipes for a user
@@ -646,7 +721,7 @@ func (rs *recipeService) GetUserRecipes(ctx context.Context, userID uuid.UUID) (
 
 	recipeDTOs := make([]*recipeDTO.RecipeDetailDTO, 0, len(recipes))
 	for _, recipe := range recipes {
-		recipeDTOs = append(recipeDTOs, rs.convertModelToRecipeDetailDTO(recipe))
+		recipeDTOs = append(recipeDTOs, convertModelToRecipeDetailDTO(recipe))
 	}
 
 	logger.Info("User recipes retrieved successfully",
@@ -735,6 +810,8 @@ func (rs *recipeService) convertSaveRecipeDTOToModel(dto *recipeDTO.SaveRecipeDT
 		Protein:       dto.NutritionInfo.Protein,
 		Carbohydrates: dto.NutritionInfo.Carbohydrates,
 		Fat:           dto.NutritionInfo.Fat,
+		Fiber:         dto.NutritionInfo.Fiber,
+		Confidence:    dto.NutritionInfo.Confidence,
 	}
 
 	// Parse generated_at timestamp
@@ -775,7 +852,7 @@ func (rs *recipeService) convertSaveRecipeDTOToModel(dto *recipeDTO.SaveRecipeDT
 	return recipe, nil
 }
 
-func (rs *recipeService) convertModelToRecipeDetailDTO(recipe *recipeModel.Recipe) *recipeDTO.RecipeDetailDTO {
+func convertModelToRecipeDetailDTO(recipe *recipeModel.Recipe) *recipeDTO.RecipeDetailDTO {
 	// Convert ingredients
 	ingredients := make([]recipeDTO.RecipeIngredientDetailDTO, 0, len(recipe.Ingredients))
 	for _, ing := range recipe.Ingredients {
@@ -810,12 +887,35 @@ func (rs *recipeService) convertModelToRecipeDetailDTO(recipe *recipeModel.Recip
 		tips = []string{}
 	}
 
+	// Convert tags
+	tags := []string(recipe.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	// Convert modifications
+	modifications := []string(recipe.Modifications)
+	if modifications == nil {
+		modifications = []string{}
+	}
+
 	// Convert nutrition info
 	nutritionInfo := recipeDTO.RecipeNutritionDetailDTO{
 		Calories:      recipe.NutritionInfo.Calories,
 		Protein:       recipe.NutritionInfo.Protein,
 		Carbohydrates: recipe.NutritionInfo.Carbohydrates,
 		Fat:           recipe.NutritionInfo.Fat,
+		Fiber:         recipe.NutritionInfo.Fiber,
+		Confidence:    recipe.NutritionInfo.Confidence,
+	}
+
+	// Convert attribution of copied recipes
+	var attribution *recipeDTO.RecipeAttributionDTO
+	if recipe.OriginRecipeID != nil {
+		attribution = &recipeDTO.RecipeAttributionDTO{
+			RecipeID: recipe.OriginRecipeID.String(),
+			Author:   recipe.OriginAuthor,
+		}
 	}
 
 	return &recipeDTO.RecipeDetailDTO{
@@ -834,7 +934,55 @@ func (rs *recipeService) convertModelToRecipeDetailDTO(recipe *recipeModel.Recip
 		DietaryRestrictions: dietaryRestrictions,
 		NutritionInfo:       nutritionInfo,
 		Tips:                tips,
+		Tags:                tags,
+		Favorite:            recipe.Favorite,
+		Rating:              recipe.Rating,
+		Notes:               recipe.Notes,
+		Modifications:       modifications,
+		LastCookedAt:        recipe.LastCookedAt,
+		TimesCooked:         recipe.TimesCooked,
+		SourceURL:           recipe.SourceURL,
+		Attribution:         attribution,
+		Version:             currentRecipeVersion(recipe),
 		GeneratedAt:         recipe.GeneratedAt,
 		CreatedAt:           recipe.CreatedAt,
+		UpdatedAt:           recipe.UpdatedAt,
+	}
+}
+
+// checkPantryAccess maps the pantry access errors into the recipe errors.
+func checkPantryAccess(ctx context.Context, pantries pantryDomain.PantryService, pantryID uuid.UUID, userID uuid.UUID, function string) error {
+	_, err := pantries.GetPantry(ctx, pantryID, userID)
+	if err == nil {
+		return nil
+	}
+
+	logger := appLogger.FromContext(ctx)
+	switch {
+	case errors.Is(err, pantrySvc.ErrUnauthorized):
+		logger.Warn("Unauthorized access to pantry",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+		)
+		return recipeDomain.ErrUnauthorized
+	case errors.Is(err, pantrySvc.ErrPantryNotFound):
+		logger.Warn("Pantry not found",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+		)
+		return recipeDomain.ErrPantryNotFound
+	default:
+		logger.Error("Failed to get pantry",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return err
 	}
 }
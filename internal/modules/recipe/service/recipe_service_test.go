@@ -103,7 +103,9 @@ func (s *stubItemRepository) CountByPantryID(ctx context.Context, pantryID uuid.
 }
 
 type stubPantryService struct {
-	getPantryFn func(ctx context.Context, pantryID, userID uuid.UUID) (*pantryModel.Pantry, error)
+	getPantryFn    func(ctx context.Context, pantryID, userID uuid.UUID) (*pantryModel.Pantry, error)
+	listPantriesFn func(ctx context.Context, userID uuid.UUID) ([]*pantryModel.Pantry, error)
+	listUsersFn    func(ctx context.Context, pantryID, userID uuid.UUID) ([]*pantryModel.PantryUserInfo, error)
 }
 
 func (s *stubPantryService) CreatePantry(ctx context.Context, name string, ownerID uuid.UUID) (result0 *pantryModel.Pantry, result1 error) {
@@ -146,6 +148,18 @@ func (s *stubPantryService) GetPantryWithItemCount(ctx context.Context, pantryID
 	return
 }
 
+func (s *stubPantryService) GetMyPantry(ctx context.Context, userID uuid.UUID) (result0 *pantryModel.PantryWithItemCount, result1 error) {
+	__logParams := map[string]any{"s": s, "ctx": ctx, "userID": userID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*stubPantryService.GetMyPantry"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*stubPantryService.GetMyPantry"), zap.Any("params", __logParams))
+	result0 = nil
+	result1 = errors.New("not implemented")
+	return
+}
+
 func (s *stubPantryService) ListPantriesByUser(ctx context.Context, userID uuid.UUID) (result0 []*pantryModel.Pantry, result1 error) {
 	__logParams := map[string]any{"s": s, "ctx": ctx, "userID": userID}
 	__logStart := time.Now()
@@ -153,6 +167,10 @@ func (s *stubPantryService) ListPantriesByUser(ctx context.Context, userID uuid.
 		zap.L().Info("function.exit", zap.String("func", "*stubPantryService.ListPantriesByUser"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
 	}()
 	zap.L().Info("function.entry", zap.String("func", "*stubPantryService.ListPantriesByUser"), zap.Any("params", __logParams))
+	if s.listPantriesFn != nil {
+		result0, result1 = s.listPantriesFn(ctx, userID)
+		return
+	}
 	result0 = nil
 	result1 = errors.New("not implemented")
 	return
@@ -243,6 +261,10 @@ func (s *stubPantryService) ListUsersInPantry(ctx context.Context, pantryID, use
 		zap.L().Info("function.exit", zap.String("func", "*stubPantryService.ListUsersInPantry"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
 	}()
 	zap.L().Info("function.entry", zap.String("func", "*stubPantryService.ListUsersInPantry"), zap.Any("params", __logParams))
+	if s.listUsersFn != nil {
+		result0, result1 = s.listUsersFn(ctx, pantryID, userID)
+		return
+	}
 	result0 = nil
 	result1 = errors.New("not implemented")
 	return
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pantryDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/domain"
+	profileDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/domain"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	userDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/user/domain"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/token"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	recipeShareTokenBytes  = 24
+	recipeSharePathPrefix  = "/api/v1/public/recipes/"
+	maxRecipeShareLifetime = 8760
+)
+
+type recipeShareService struct {
+	recipeRepository  recipeDomain.RecipeRepository
+	shareRepository   recipeDomain.RecipeShareRepository
+	userRepository    userDomain.UserRepository
+	pantryService     pantryDomain.PantryService
+	profileRepository profileDomain.ProfileRepository
+	now               func() time.Time
+}
+
+func NewRecipeShareService(
+	recipeRepository recipeDomain.RecipeRepository,
+	shareRepository recipeDomain.RecipeShareRepository,
+	userRepository userDomain.UserRepository,
+	pantryService pantryDomain.PantryService,
+	profileRepository profileDomain.ProfileRepository,
+) recipeDomain.RecipeShareService {
+	return &recipeShareService{
+		recipeRepository:  recipeRepository,
+		shareRepository:   shareRepository,
+		userRepository:    userRepository,
+		pantryService:     pantryService,
+		profileRepository: profileRepository,
+		now:               time.Now,
+	}
+}
+
+func (s *recipeShareService) CreateShare(ctx context.Context, userID uuid.UUID, recipeID uuid.UUID, input *recipeDTO.CreateRecipeShareDTO) (*recipeDTO.RecipeShareDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input == nil {
+		input = &recipeDTO.CreateRecipeShareDTO{}
+	}
+	if input.ExpiresInHours < 0 || input.ExpiresInHours > maxRecipeShareLifetime {
+		return nil, fmt.Errorf("%w: expires_in_hours must be between 1 and %d", recipeDomain.ErrInvalidRequest, maxRecipeShareLifetime)
+	}
+
+	if _, err := findUserRecipe(ctx, s.recipeRepository, recipeID, userID, "CreateShare"); err != nil {
+		return nil, err
+	}
+
+	shareToken, err := token.New(recipeShareTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generate share token: %w", err)
+	}
+
+	share := &recipeModel.RecipeShare{
+		RecipeID: recipeID,
+		UserID:   userID,
+		Token:    shareToken,
+	}
+	if input.ExpiresInHours > 0 {
+		expiresAt := s.now().UTC().Add(time.Duration(input.ExpiresInHours) * time.Hour)
+		share.ExpiresAt = &expiresAt
+	}
+	if err := s.shareRepository.Create(ctx, share); err != nil {
+		logger.Error("Failed to create recipe share",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CreateShare"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.Info("Recipe share created",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "CreateShare"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("recipe_id", recipeID.String()),
+		zap.String("share_id", share.ID.String()),
+	)
+
+	result := s.convertShareToDTO(share)
+	return &result, nil
+}
+
+func (s *recipeShareService) ListShares(ctx context.Context, userID uuid.UUID, recipeID uuid.UUID) ([]recipeDTO.RecipeShareDTO, error) {
+	if _, err := findUserRecipe(ctx, s.recipeRepository, recipeID, userID, "ListShares"); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepository.ListByRecipeID(ctx, recipeID)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list recipe shares",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ListShares"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result := make([]recipeDTO.RecipeShareDTO, 0, len(shares))
+	for _, share := range shares {
+		result = append(result, s.convertShareToDTO(share))
+	}
+	return result, nil
+}
+
+func (s *recipeShareService) RevokeShare(ctx context.Context, userID uuid.UUID, recipeID uuid.UUID, shareID uuid.UUID) error {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := findUserRecipe(ctx, s.recipeRepository, recipeID, userID, "RevokeShare"); err != nil {
+		return err
+	}
+
+	share, err := s.shareRepository.FindByID(ctx, shareID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return recipeDomain.ErrShareNotFound
+		}
+		return err
+	}
+	if share.RecipeID != recipeID {
+		return recipeDomain.ErrShareNotFound
+	}
+	if share.RevokedAt != nil {
+		return nil
+	}
+
+	revokedAt := s.now().UTC()
+	share.RevokedAt = &revokedAt
+	if err := s.shareRepository.Update(ctx, share); err != nil {
+		logger.Error("Failed to revoke recipe share",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "RevokeShare"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("share_id", shareID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	logger.Info("Recipe share revoked",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "RevokeShare"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("share_id", shareID.String()),
+	)
+	return nil
+}
+
+func (s *recipeShareService) GetSharedRecipe(ctx context.Context, token string) (*recipeDTO.SharedRecipeDTO, error) {
+	share, recipe, err := s.resolveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := convertModelToRecipeDetailDTO(recipe)
+	result := &recipeDTO.SharedRecipeDTO{
+		Title:               detail.Title,
+		Description:         detail.Description,
+		Author:              s.authorName(ctx, recipe.UserID),
+		Ingredients:         make([]recipeDTO.SharedRecipeIngredientDTO, 0, len(detail.Ingredients)),
+		Instructions:        detail.Instructions,
+		CookingTime:         detail.CookingTime,
+		PreparationTime:     detail.PreparationTime,
+		TotalTime:           detail.TotalTime,
+		ServingSize:         detail.ServingSize,
+		Difficulty:          detail.Difficulty,
+		MealType:            detail.MealType,
+		Cuisine:             detail.Cuisine,
+		DietaryRestrictions: detail.DietaryRestrictions,
+		NutritionInfo:       detail.NutritionInfo,
+		Tips:                detail.Tips,
+		SourceURL:           detail.SourceURL,
+		ExpiresAt:           share.ExpiresAt,
+		UpdatedAt:           detail.UpdatedAt,
+	}
+	for _, ingredient := range detail.Ingredients {
+		result.Ingredients = append(result.Ingredients, recipeDTO.SharedRecipeIngredientDTO{
+			Name:        ingredient.Name,
+			Amount:      ingredient.Amount,
+			Unit:        ingredient.Unit,
+			Alternative: ingredient.Alternative,
+		})
+	}
+	return result, nil
+}
+
+func (s *recipeShareService) CopySharedRecipe(ctx context.Context, userID uuid.UUID, link string) (*recipeDTO.RecipeDetailDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	token, err := parseRecipeShareLink(link)
+	if err != nil {
+		return nil, err
+	}
+	_, original, err := s.resolveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if original.UserID == userID {
+		return nil, fmt.Errorf("%w: the shared recipe is already yours", recipeDomain.ErrInvalidRequest)
+	}
+
+	recipe := copySharedRecipe(original, userID, s.now().UTC())
+	recipe.OriginAuthor = s.authorName(ctx, original.UserID)
+	recordRecipeVersion(recipe, nil, userID, recipe.GeneratedAt)
+	if err := s.recipeRepository.Create(ctx, recipe); err != nil {
+		logger.Error("Failed to save copy of shared recipe",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "CopySharedRecipe"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("origin_recipe_id", original.ID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.Info("Shared recipe copied",
+		zap.String(appLogger.FieldModule, "recipe"),
+		zap.String(appLogger.FieldFunction, "CopySharedRecipe"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("recipe_id", recipe.ID.String()),
+		zap.String("origin_recipe_id", original.ID.String()),
+	)
+
+	result := convertModelToRecipeDetailDTO(recipe)
+	result.DietaryWarnings = checkRecipeDietary(recipeIngredientNames(recipe), recipe.DietaryRestrictions,
+		householdDietaryMembers(ctx, s.pantryService, s.profileRepository, userID, nil, "CopySharedRecipe"))
+	return result, nil
+}
+
+// resolveShare loads the recipe behind a public token. Unknown and revoked
+// tokens are reported the same way so links cannot be probed.
+func (s *recipeShareService) resolveShare(ctx context.Context, token string) (*recipeModel.RecipeShare, *recipeModel.Recipe, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, nil, recipeDomain.ErrShareNotFound
+	}
+
+	share, err := s.shareRepository.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, recipeDomain.ErrShareNotFound
+		}
+		return nil, nil, err
+	}
+	if share.RevokedAt != nil {
+		return nil, nil, recipeDomain.ErrShareNotFound
+	}
+	if !share.Active(s.now()) {
+		return nil, nil, recipeDomain.ErrShareExpired
+	}
+
+	recipe, err := s.recipeRepository.FindByID(ctx, share.RecipeID, share.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, recipeDomain.ErrShareNotFound
+		}
+		return nil, nil, err
+	}
+	return share, recipe, nil
+}
+
+// authorName is the name shown as the author of a shared recipe. It is empty
+// when the user cannot be read, which does not stop the recipe from being
+// shown.
+func (s *recipeShareService) authorName(ctx context.Context, userID uuid.UUID) string {
+	user, err := s.userRepository.GetUserById(ctx, userID)
+	if err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to get recipe author",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "authorName"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimSpace(user.FirstName) + " " + strings.TrimSpace(user.LastName))
+}
+
+func (s *recipeShareService) convertShareToDTO(share *recipeModel.RecipeShare) recipeDTO.RecipeShareDTO {
+	return recipeDTO.RecipeShareDTO{
+		ID:        share.ID.String(),
+		RecipeID:  share.RecipeID.String(),
+		Token:     share.Token,
+		Path:      recipeSharePathPrefix + share.Token,
+		ExpiresAt: share.ExpiresAt,
+		RevokedAt: share.RevokedAt,
+		Active:    share.Active(s.now()),
+		CreatedAt: share.CreatedAt,
+	}
+}
+
+// copySharedRecipe copies the content of a recipe for another user. What is
+// personal to the owner (tags, favorite, rating, notes, cooking history and
+// pantry availability) is left out.
+func copySharedRecipe(original *recipeModel.Recipe, userID uuid.UUID, now time.Time) *recipeModel.Recipe {
+	ingredients := make(recipeModel.RecipeIngredientsJSON, 0, len(original.Ingredients))
+	for _, ingredient := range original.Ingredients {
+		ingredient.Available = false
+		ingredients = append(ingredients, ingredient)
+	}
+	originID := original.ID
+
+	return &recipeModel.Recipe{
+		ID:                  uuid.New(),
+		UserID:              userID,
+		Title:               original.Title,
+		Description:         original.Description,
+		Ingredients:         ingredients,
+		Instructions:        append(recipeModel.RecipeInstructionsJSON{}, original.Instructions...),
+		CookingTime:         original.CookingTime,
+		PreparationTime:     original.PreparationTime,
+		TotalTime:           original.TotalTime,
+		ServingSize:         original.ServingSize,
+		Difficulty:          original.Difficulty,
+		MealType:            original.MealType,
+		Cuisine:             original.Cuisine,
+		DietaryRestrictions: append(recipeModel.RecipeDietaryJSON{}, original.DietaryRestrictions...),
+		NutritionInfo:       original.NutritionInfo,
+		Tips:                append(recipeModel.RecipeTipsJSON{}, original.Tips...),
+		SourceURL:           original.SourceURL,
+		OriginRecipeID:      &originID,
+		GeneratedAt:         now,
+	}
+}
+
+// parseRecipeShareLink reads the token of a share link, given as the token
+// itself or as the public URL or path of the recipe.
+func parseRecipeShareLink(link string) (string, error) {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return "", fmt.Errorf("%w: link is required", recipeDomain.ErrInvalidRequest)
+	}
+	if !strings.Contains(link, "/") {
+		return link, nil
+	}
+
+	path := link
+	if parsed, err := url.Parse(link); err == nil {
+		path = parsed.Path
+	}
+	index := strings.Index(path, recipeSharePathPrefix)
+	if index < 0 {
+		return "", fmt.Errorf("%w: link is not a shared recipe link", recipeDomain.ErrInvalidRequest)
+	}
+	token := strings.Trim(path[index+len(recipeSharePathPrefix):], "/")
+	if token == "" || strings.Contains(token, "/") {
+		return "", fmt.Errorf("%w: link is not a shared recipe link", recipeDomain.ErrInvalidRequest)
+	}
+	return token, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	userModel "github.com/nclsgg/despensa-digital/backend/internal/modules/user/model"
+	"gorm.io/gorm"
+)
+
+type memoryRecipeShareRepository struct {
+	shares map[uuid.UUID]*recipeModel.RecipeShare
+}
+
+func (r *memoryRecipeShareRepository) Create(ctx context.Context, share *recipeModel.RecipeShare) error {
+	if share.ID == uuid.Nil {
+		share.ID = uuid.New()
+	}
+	r.shares[share.ID] = share
+	return nil
+}
+
+func (r *memoryRecipeShareRepository) FindByID(ctx context.Context, id uuid.UUID) (*recipeModel.RecipeShare, error) {
+	share, ok := r.shares[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return share, nil
+}
+
+func (r *memoryRecipeShareRepository) FindByToken(ctx context.Context, token string) (*recipeModel.RecipeShare, error) {
+	for _, share := range r.shares {
+		if share.Token == token {
+			return share, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryRecipeShareRepository) ListByRecipeID(ctx context.Context, recipeID uuid.UUID) ([]*recipeModel.RecipeShare, error) {
+	var shares []*recipeModel.RecipeShare
+	for _, share := range r.shares {
+		if share.RecipeID == recipeID {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+func (r *memoryRecipeShareRepository) Update(ctx context.Context, share *recipeModel.RecipeShare) error {
+	r.shares[share.ID] = share
+	return nil
+}
+
+type stubUserRepository struct {
+	users map[uuid.UUID]*userModel.User
+}
+
+func (r *stubUserRepository) GetUserById(ctx context.Context, id uuid.UUID) (*userModel.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (r *stubUserRepository) GetUserByEmail(ctx context.Context, email string) (*userModel.User, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubUserRepository) GetAllUsers(ctx context.Context) ([]userModel.User, error) {
+	return nil, nil
+}
+
+func (r *stubUserRepository) UpdateUser(ctx context.Context, user *userModel.User) error {
+	return nil
+}
+
+func newShareTestService(now time.Time, users map[uuid.UUID]*userModel.User, recipes ...*recipeModel.Recipe) (*recipeShareService, *memoryRecipeRepository) {
+	repo := newMemoryRecipeRepository(recipes...)
+	return &recipeShareService{
+		recipeRepository: repo,
+		shareRepository:  &memoryRecipeShareRepository{shares: make(map[uuid.UUID]*recipeModel.RecipeShare)},
+		userRepository:   &stubUserRepository{users: users},
+		now:              func() time.Time { return now },
+	}, repo
+}
+
+func sharedTestRecipe(userID uuid.UUID) *recipeModel.Recipe {
+	rating := 5
+	return &recipeModel.Recipe{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Title:        "Pão de queijo",
+		Ingredients:  recipeModel.RecipeIngredientsJSON{{Name: "Polvilho", Amount: cookingAmount(500), Unit: "g", Available: true}},
+		Instructions: recipeModel.RecipeInstructionsJSON{{Step: 1, Description: "Misture e asse"}},
+		Tags:         recipeModel.RecipeTagsJSON{"lanche"},
+		Favorite:     true,
+		Rating:       &rating,
+		Notes:        "Usar queijo meia cura",
+	}
+}
+
+func TestRecipeShareService_SharesRecipePublicly(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	ownerID := uuid.New()
+	recipe := sharedTestRecipe(ownerID)
+	svc, _ := newShareTestService(now, map[uuid.UUID]*userModel.User{ownerID: {FirstName: "Ana", LastName: "Souza"}}, recipe)
+	ctx := context.Background()
+
+	share, err := svc.CreateShare(ctx, ownerID, recipe.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(share.Token) != 2*recipeShareTokenBytes || share.Path != recipeSharePathPrefix+share.Token || !share.Active || share.ExpiresAt != nil {
+		t.Fatalf("expected an active link without expiration, got %+v", share)
+	}
+
+	shared, err := svc.GetSharedRecipe(ctx, share.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shared.Title != "Pão de queijo" || shared.Author != "Ana Souza" || len(shared.Ingredients) != 1 || len(shared.Instructions) != 1 {
+		t.Fatalf("unexpected shared recipe %+v", shared)
+	}
+
+	if _, err := svc.CreateShare(ctx, uuid.New(), recipe.ID, nil); !errors.Is(err, recipeDomain.ErrRecipeNotFound) {
+		t.Fatalf("expected recipe not found for another user, got %v", err)
+	}
+	if _, err := svc.CreateShare(ctx, ownerID, recipe.ID, &recipeDTO.CreateRecipeShareDTO{ExpiresInHours: -1}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected invalid request, got %v", err)
+	}
+	if _, err := svc.GetSharedRecipe(ctx, "unknown"); !errors.Is(err, recipeDomain.ErrShareNotFound) {
+		t.Fatalf("expected share not found, got %v", err)
+	}
+}
+
+func TestRecipeShareService_ExpiresAndRevokesLinks(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	ownerID := uuid.New()
+	recipe := sharedTestRecipe(ownerID)
+	svc, _ := newShareTestService(now, nil, recipe)
+	ctx := context.Background()
+
+	expiring, err := svc.CreateShare(ctx, ownerID, recipe.ID, &recipeDTO.CreateRecipeShareDTO{ExpiresInHours: 24})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	revoked, err := svc.CreateShare(ctx, ownerID, recipe.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := svc.RevokeShare(ctx, ownerID, recipe.ID, uuid.MustParse(revoked.ID)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.RevokeShare(ctx, ownerID, recipe.ID, uuid.MustParse(revoked.ID)); err != nil {
+		t.Fatalf("expected revoking twice to succeed, got %v", err)
+	}
+	if err := svc.RevokeShare(ctx, ownerID, recipe.ID, uuid.New()); !errors.Is(err, recipeDomain.ErrShareNotFound) {
+		t.Fatalf("expected share not found, got %v", err)
+	}
+	if _, err := svc.GetSharedRecipe(ctx, revoked.Token); !errors.Is(err, recipeDomain.ErrShareNotFound) {
+		t.Fatalf("expected a revoked link to be not found, got %v", err)
+	}
+
+	shared, err := svc.GetSharedRecipe(ctx, expiring.Token)
+	if err != nil || shared.Author != "" {
+		t.Fatalf("expected the link to work without an author, got %+v, %v", shared, err)
+	}
+	svc.now = func() time.Time { return now.Add(25 * time.Hour) }
+	if _, err := svc.GetSharedRecipe(ctx, expiring.Token); !errors.Is(err, recipeDomain.ErrShareExpired) {
+		t.Fatalf("expected the link to expire, got %v", err)
+	}
+
+	shares, err := svc.ListShares(ctx, ownerID, recipe.ID)
+	if err != nil || len(shares) != 2 {
+		t.Fatalf("expected both links, got %+v, %v", shares, err)
+	}
+	for _, share := range shares {
+		if share.Active {
+			t.Fatalf("expected no active link, got %+v", share)
+		}
+	}
+}
+
+func TestRecipeShareService_CopySharedRecipe(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	ownerID, readerID := uuid.New(), uuid.New()
+	recipe := sharedTestRecipe(ownerID)
+	svc, repo := newShareTestService(now, map[uuid.UUID]*userModel.User{ownerID: {FirstName: "Ana", LastName: "Souza"}}, recipe)
+	ctx := context.Background()
+
+	share, err := svc.CreateShare(ctx, ownerID, recipe.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	copied, err := svc.CopySharedRecipe(ctx, readerID, "https://despensa.app"+share.Path+"/?utm_source=whatsapp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copied.ID == recipe.ID.String() || copied.Title != recipe.Title {
+		t.Fatalf("expected a new recipe, got %+v", copied)
+	}
+	if copied.Attribution == nil || copied.Attribution.RecipeID != recipe.ID.String() || copied.Attribution.Author != "Ana Souza" {
+		t.Fatalf("expected the original recipe as attribution, got %+v", copied.Attribution)
+	}
+
+	saved := repo.recipes[uuid.MustParse(copied.ID)]
+	if saved.UserID != readerID {
+		t.Fatalf("expected the copy to be owned by the reader, got %s", saved.UserID)
+	}
+	if len(saved.Tags) != 0 || saved.Favorite || saved.Rating != nil || saved.Notes != "" || saved.Ingredients[0].Available {
+		t.Fatalf("expected the personal data of the owner to be left out, got %+v", saved)
+	}
+	saved.Ingredients[0].Name = "Polvilho azedo"
+	if recipe.Ingredients[0].Name != "Polvilho" {
+		t.Fatalf("expected the copy not to share ingredients with the original")
+	}
+
+	if _, err := svc.CopySharedRecipe(ctx, ownerID, share.Token); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected copying an own recipe to be refused, got %v", err)
+	}
+	if _, err := svc.CopySharedRecipe(ctx, readerID, "https://despensa.app/recipes/"+share.Token); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected an unknown link to be refused, got %v", err)
+	}
+}
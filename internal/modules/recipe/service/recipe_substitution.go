@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	recipeData "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/data"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/nclsgg/despensa-digital/backend/pkg/dietary"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+)
+
+var (
+	substitutionTableOnce sync.Once
+	substitutionTable     []substitutionEntry
+	substitutionNames     []string
+	substitutionTableErr  error
+)
+
+// substitutionEntry is a row of the substitution table with its names
+// normalized as ingredient keys.
+type substitutionEntry struct {
+	recipeData.Substitution
+	ingredientKey string
+	substituteKey string
+}
+
+// SuggestSubstitutions lists, for each ingredient of a recipe the pantry does
+// not cover, the substitutes found in the pantry that respect the dietary
+// restrictions of the user and of the recipe
+func (rs *recipeService) SuggestSubstitutions(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, input *recipeDTO.CookRecipeDTO) (*recipeDTO.RecipeSubstitutionsDTO, error) {
+	table, names, err := loadSubstitutionTable()
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to load the substitution table",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "SuggestSubstitutions"),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	recipe, pantryID, servings, plan, err := rs.prepareCooking(ctx, recipeID, userID, input, "SuggestSubstitutions")
+	if err != nil {
+		return nil, err
+	}
+
+	restrictions, err := dietary.ProfileRestrictions(ctx, rs.profileRepository, "recipe", "SuggestSubstitutions")(userID)
+	if err != nil {
+		return nil, err
+	}
+	restrictions = append(restrictions, recipe.DietaryRestrictions...)
+	diets := dietTags(restrictions)
+
+	return &recipeDTO.RecipeSubstitutionsDTO{
+		RecipeID:    recipe.ID.String(),
+		PantryID:    pantryID.String(),
+		Servings:    servings,
+		Diets:       diets,
+		Ingredients: suggestSubstitutions(recipe, plan, diets, restrictions, table, names),
+	}, nil
+}
+
+func loadSubstitutionTable() ([]substitutionEntry, []string, error) {
+	substitutionTableOnce.Do(func() {
+		substitutions, err := recipeData.Substitutions()
+		if err != nil {
+			substitutionTableErr = err
+			return
+		}
+		seen := make(map[string]bool)
+		for _, substitution := range substitutions {
+			entry := substitutionEntry{
+				Substitution:  substitution,
+				ingredientKey: normalizeIngredientName(substitution.Ingredient),
+				substituteKey: normalizeIngredientName(substitution.Substitute),
+			}
+			substitutionTable = append(substitutionTable, entry)
+			for _, key := range []string{entry.ingredientKey, entry.substituteKey} {
+				if !seen[key] {
+					seen[key] = true
+					substitutionNames = append(substitutionNames, key)
+				}
+			}
+		}
+	})
+	return substitutionTable, substitutionNames, substitutionTableErr
+}
+
+// suggestSubstitutions looks up the substitutes of the ingredients a cooking
+// plan could not cover in what the plan leaves in the pantry. Substitutes
+// must meet the diets of the table and have none of the allergens the
+// restrictions forbid.
+func suggestSubstitutions(recipe *recipeModel.Recipe, plan cookingPlan, diets []string, restrictions []string, table []substitutionEntry, names []string) []recipeDTO.IngredientSubstitutionDTO {
+	alternatives := make(map[string]*string, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		alternatives[strings.TrimSpace(ingredient.Name)] = ingredient.Alternative
+	}
+
+	result := []recipeDTO.IngredientSubstitutionDTO{}
+	for _, line := range plan.ingredients {
+		key := normalizeIngredientName(line.Name)
+		status := line.Status
+		switch status {
+		case cookingMissing, cookingInsufficient, cookingIncompatible:
+		case cookingUnmeasured:
+			if len(matchCookingStock(key, plan.stock)) > 0 {
+				continue
+			}
+			status = cookingMissing
+		default:
+			continue
+		}
+
+		unit := line.Unit
+		if unit == "" {
+			unit = "un"
+		}
+		missing := missingAmount(line, unit)
+		entry := recipeDTO.IngredientSubstitutionDTO{
+			Name:        line.Name,
+			Missing:     missing,
+			Unit:        line.Unit,
+			Status:      status,
+			Alternative: alternatives[line.Name],
+			Substitutes: []recipeDTO.IngredientSubstituteDTO{},
+		}
+
+		ingredientKey := substitutionIngredientKey(key, table)
+		for _, substitution := range table {
+			if substitution.ingredientKey != ingredientKey || ingredientKey == "" || !substitution.Meets(diets) {
+				continue
+			}
+			if len(dietary.Check([]string{substitution.Substitute}, restrictions)) > 0 {
+				continue
+			}
+			if substitute, ok := substituteFromStock(substitution, missing, unit, plan.stock, names); ok {
+				entry.Substitutes = append(entry.Substitutes, substitute)
+			}
+		}
+		sort.SliceStable(entry.Substitutes, func(i, j int) bool {
+			return entry.Substitutes[i].Sufficient && !entry.Substitutes[j].Sufficient
+		})
+		result = append(result, entry)
+	}
+	return result
+}
+
+// missingAmount is what the pantry lacks of an ingredient, in the unit of the
+// ingredient, or nil when the recipe gives no amount.
+func missingAmount(line recipeDTO.CookingIngredientDTO, unit string) *float64 {
+	if line.Needed == nil {
+		return nil
+	}
+	missing := *line.Needed
+	for _, deduction := range line.Deductions {
+		if taken, ok := units.Convert(deduction.Quantity, deduction.Unit, unit); ok {
+			missing -= taken
+		}
+	}
+	missing = math.Max(math.Round(missing*100)/100, 0)
+	return &missing
+}
+
+// substitutionIngredientKey finds the ingredient of the substitution table an
+// ingredient of a recipe is: the table name appearing in the ingredient name,
+// the longest one winning, so "creme de leite fresco" is "creme de leite" and
+// not "leite".
+func substitutionIngredientKey(key string, table []substitutionEntry) string {
+	padded := " " + key + " "
+	best := ""
+	for _, substitution := range table {
+		if len(substitution.ingredientKey) > len(best) && strings.Contains(padded, " "+substitution.ingredientKey+" ") {
+			best = substitution.ingredientKey
+		}
+	}
+	return best
+}
+
+// substituteFromStock finds the pantry items of a substitute and how much of
+// it replaces the missing amount. An item is the substitute when the longest
+// name of the table in its own name is the substitute, so "Leite integral" is
+// milk but "Leite de coco" is not.
+func substituteFromStock(substitution substitutionEntry, missing *float64, unit string, stock []*cookingStock, names []string) (recipeDTO.IngredientSubstituteDTO, bool) {
+	substitute := recipeDTO.IngredientSubstituteDTO{
+		Name:        substitution.Substitute,
+		Ratio:       substitution.Ratio,
+		Unit:        unit,
+		Notes:       substitution.Notes,
+		Diets:       substitution.Diets,
+		PantryItems: []recipeDTO.SubstitutionPantryItemDTO{},
+	}
+
+	// A substitute with its own unit is measured per unit of the ingredient,
+	// as in one spoon of flaxseed per egg.
+	if missing != nil {
+		base, ok := *missing, true
+		if substitution.Unit != "" {
+			base, ok = units.Convert(*missing, unit, "un")
+			substitute.Unit = substitution.Unit
+		}
+		if ok {
+			amount := math.Round(base*substitution.Ratio*100) / 100
+			substitute.Amount = &amount
+		}
+	} else if substitution.Unit != "" {
+		substitute.Unit = substitution.Unit
+	}
+
+	available := 0.0
+	for _, entry := range stock {
+		if entry.available <= 0 || longestKnownName(entry.key, names) != substitution.substituteKey {
+			continue
+		}
+		substitute.PantryItems = append(substitute.PantryItems, recipeDTO.SubstitutionPantryItemDTO{
+			PantryItemID: entry.item.ID.String(),
+			Name:         entry.item.Name,
+			Quantity:     entry.available,
+			Unit:         entry.item.Unit,
+		})
+		if quantity, ok := units.Convert(entry.available, entry.item.Unit, substitute.Unit); ok {
+			available += quantity
+		}
+	}
+	if len(substitute.PantryItems) == 0 {
+		return substitute, false
+	}
+
+	switch {
+	case missing == nil:
+		substitute.Sufficient = true
+	case substitute.Amount != nil:
+		substitute.Sufficient = available >= *substitute.Amount*(1-1e-6)
+	}
+	return substitute, true
+}
+
+func longestKnownName(key string, names []string) string {
+	padded := " " + key + " "
+	best := ""
+	for _, name := range names {
+		if len(name) > len(best) && strings.Contains(padded, " "+name+" ") {
+			best = name
+		}
+	}
+	return best
+}
+
+// dietTags maps dietary restrictions to the diet tags of the substitution
+// table, in the order of the table. Avoiding all dairy includes avoiding
+// lactose.
+func dietTags(restrictions []string) []string {
+	found := make(map[string]bool)
+	for _, tag := range dietary.Normalize(restrictions) {
+		if tag == dietary.DairyFree {
+			tag = dietary.LactoseFree
+		}
+		found[tag] = true
+	}
+
+	tags := []string{}
+	for _, diet := range []string{
+		recipeData.DietVegan, recipeData.DietVegetarian, recipeData.DietLactoseFree,
+		recipeData.DietGlutenFree, recipeData.DietEggFree, recipeData.DietNutFree,
+	} {
+		if found[diet] {
+			tags = append(tags, diet)
+		}
+	}
+	return tags
+}
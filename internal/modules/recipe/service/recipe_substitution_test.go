@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	profileModel "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"gorm.io/gorm"
+)
+
+type stubProfileRepository struct {
+	profiles map[uuid.UUID]*profileModel.Profile
+}
+
+func (r *stubProfileRepository) Create(ctx context.Context, profile *profileModel.Profile) error {
+	return nil
+}
+
+func (r *stubProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*profileModel.Profile, error) {
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return profile, nil
+}
+
+func (r *stubProfileRepository) Update(ctx context.Context, profile *profileModel.Profile) error {
+	return nil
+}
+
+func (r *stubProfileRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func substitutionTestService(userID, pantryID uuid.UUID, profiles map[uuid.UUID]*profileModel.Profile) (*recipeService, *recipeModel.Recipe) {
+	servings := 2
+	recipe := &recipeModel.Recipe{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       "Bolo simples",
+		ServingSize: &servings,
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Manteiga", Amount: cookingAmount(0.5), Unit: "xícara"},
+			{Name: "Leite", Amount: cookingAmount(1), Unit: "xícara"},
+			{Name: "Ovos", Amount: cookingAmount(2)},
+			{Name: "Farinha de trigo", Amount: cookingAmount(300), Unit: "g"},
+			{Name: "Canela", Unit: "a gosto"},
+		},
+	}
+	items := []*model.Item{
+		{ID: uuid.New(), PantryID: pantryID, Name: "Óleo de soja", Quantity: 900, Unit: "ml"},
+		{ID: uuid.New(), PantryID: pantryID, Name: "Margarina", Quantity: 250, Unit: "g"},
+		{ID: uuid.New(), PantryID: pantryID, Name: "Leite", Quantity: 100, Unit: "ml"},
+		{ID: uuid.New(), PantryID: pantryID, Name: "Leite de coco", Quantity: 200, Unit: "ml"},
+		{ID: uuid.New(), PantryID: pantryID, Name: "Linhaça dourada", Quantity: 200, Unit: "g"},
+		{ID: uuid.New(), PantryID: pantryID, Name: "Bananas", Quantity: 3, Unit: "un"},
+		{ID: uuid.New(), PantryID: pantryID, Name: "Farinha de trigo", Quantity: 1, Unit: "kg"},
+	}
+	return &recipeService{
+		itemRepository:    &stubItemRepository{items: items},
+		pantryService:     &stubPantryService{},
+		recipeRepository:  newMemoryRecipeRepository(recipe),
+		profileRepository: &stubProfileRepository{profiles: profiles},
+	}, recipe
+}
+
+func TestRecipeService_SuggestSubstitutions(t *testing.T) {
+	userID, pantryID := uuid.New(), uuid.New()
+	profiles := map[uuid.UUID]*profileModel.Profile{userID: {DietaryRestrictions: profileModel.StringArray{"Vegana"}}}
+	svc, recipe := substitutionTestService(userID, pantryID, profiles)
+
+	result, err := svc.SuggestSubstitutions(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: pantryID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Servings != 2 || len(result.Diets) != 1 || result.Diets[0] != "vegan" {
+		t.Fatalf("expected two servings for a vegan, got %+v", result)
+	}
+	if len(result.Ingredients) != 4 {
+		t.Fatalf("expected butter, milk, eggs and cinnamon to lack, got %+v", result.Ingredients)
+	}
+
+	butter := result.Ingredients[0]
+	if butter.Status != cookingMissing || len(butter.Substitutes) != 1 || butter.Substitutes[0].Name != "óleo" {
+		t.Fatalf("expected only oil for a vegan, got %+v", butter)
+	}
+	if oil := butter.Substitutes[0]; *oil.Amount != 0.4 || oil.Unit != "xícara" || !oil.Sufficient || len(oil.PantryItems) != 1 {
+		t.Fatalf("expected 0.4 cup of oil, got %+v", oil)
+	}
+
+	milk := result.Ingredients[1]
+	if milk.Status != cookingInsufficient || *milk.Missing != 0.58 || len(milk.Substitutes) != 1 {
+		t.Fatalf("expected the milk to lack 0.58 cup, got %+v", milk)
+	}
+	if coconut := milk.Substitutes[0]; coconut.Name != "leite de coco" || *coconut.Amount != 0.58 || !coconut.Sufficient {
+		t.Fatalf("expected coconut milk, got %+v", coconut)
+	}
+
+	eggs := result.Ingredients[2]
+	if len(eggs.Substitutes) != 2 || eggs.Substitutes[0].Name != "banana" || eggs.Substitutes[1].Name != "linhaça" {
+		t.Fatalf("expected banana ahead of flaxseed, got %+v", eggs.Substitutes)
+	}
+	if banana, flaxseed := eggs.Substitutes[0], eggs.Substitutes[1]; *banana.Amount != 1 || !banana.Sufficient || *flaxseed.Amount != 2 || flaxseed.Unit != "colher de sopa" || flaxseed.Sufficient {
+		t.Fatalf("unexpected egg substitutes %+v", eggs.Substitutes)
+	}
+
+	if cinnamon := result.Ingredients[3]; cinnamon.Missing != nil || len(cinnamon.Substitutes) != 0 {
+		t.Fatalf("expected cinnamon without substitutes, got %+v", cinnamon)
+	}
+}
+
+func TestRecipeService_SuggestSubstitutions_WithoutRestrictions(t *testing.T) {
+	userID, pantryID := uuid.New(), uuid.New()
+	svc, recipe := substitutionTestService(userID, pantryID, nil)
+
+	result, err := svc.SuggestSubstitutions(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: pantryID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Diets) != 0 || len(result.Ingredients[0].Substitutes) != 2 || result.Ingredients[0].Substitutes[1].Name != "margarina" || result.Ingredients[0].Substitutes[1].Sufficient {
+		t.Fatalf("expected oil and then margarine, which cannot be measured in cups, for the butter, got %+v", result.Ingredients[0])
+	}
+
+	recipe.DietaryRestrictions = recipeModel.RecipeDietaryJSON{"sem lactose"}
+	result, err = svc.SuggestSubstitutions(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{PantryID: pantryID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Diets) != 1 || len(result.Ingredients[0].Substitutes) != 1 {
+		t.Fatalf("expected the restriction of the recipe to leave out margarine, got %+v", result)
+	}
+
+	if _, err := svc.SuggestSubstitutions(context.Background(), recipe.ID, userID, &recipeDTO.CookRecipeDTO{}); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected a pantry to be required, got %v", err)
+	}
+}
+
+func TestDietTags(t *testing.T) {
+	tags := dietTags([]string{"Intolerância à lactose", "celíaco", "sem ovos", "low carb", "vegetariano"})
+	expected := []string{"vegetarian", "lactose_free", "gluten_free", "egg_free"}
+	if len(tags) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, tags)
+	}
+	for i := range expected {
+		if tags[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, tags)
+		}
+	}
+}
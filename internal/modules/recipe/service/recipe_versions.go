@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	versionChangeAdded   = "added"
+	versionChangeRemoved = "removed"
+	versionChangeChanged = "changed"
+)
+
+// ListRecipeVersions lists the versions of the content of a recipe, the
+// latest first
+func (rs *recipeService) ListRecipeVersions(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID) ([]recipeDTO.RecipeVersionSummaryDTO, error) {
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "ListRecipeVersions")
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := rs.recipeRepository.ListVersions(ctx, recipe.ID)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list recipe versions",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, "ListRecipeVersions"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if len(versions) == 0 && recipe.Version == 0 {
+		first := firstRecipeVersion(recipe)
+		versions = []*recipeModel.RecipeVersion{&first}
+	}
+
+	result := make([]recipeDTO.RecipeVersionSummaryDTO, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, convertVersionToSummaryDTO(version, recipe))
+	}
+	return result, nil
+}
+
+// GetRecipeVersion returns the content of a recipe at a version
+func (rs *recipeService) GetRecipeVersion(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, number int) (*recipeDTO.RecipeVersionDTO, error) {
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "GetRecipeVersion")
+	if err != nil {
+		return nil, err
+	}
+	version, err := rs.findRecipeVersion(ctx, recipe, number, "GetRecipeVersion")
+	if err != nil {
+		return nil, err
+	}
+	return convertVersionToDTO(version, recipe), nil
+}
+
+// DiffRecipeVersions compares two versions of a recipe. to defaults to the
+// current version.
+func (rs *recipeService) DiffRecipeVersions(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, from int, to int) (*recipeDTO.RecipeVersionDiffDTO, error) {
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "DiffRecipeVersions")
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = currentRecipeVersion(recipe)
+	}
+
+	older, err := rs.findRecipeVersion(ctx, recipe, from, "DiffRecipeVersions")
+	if err != nil {
+		return nil, err
+	}
+	newer, err := rs.findRecipeVersion(ctx, recipe, to, "DiffRecipeVersions")
+	if err != nil {
+		return nil, err
+	}
+
+	return &recipeDTO.RecipeVersionDiffDTO{
+		RecipeID:     recipe.ID.String(),
+		From:         older.Version,
+		To:           newer.Version,
+		Fields:       diffRecipeFields(older, newer),
+		Ingredients:  diffRecipeIngredients(older.Ingredients, newer.Ingredients),
+		Instructions: diffRecipeInstructions(older.Instructions, newer.Instructions),
+	}, nil
+}
+
+// RestoreRecipeVersion brings back the content of an old version. The history
+// is kept: the restored content becomes a new version.
+func (rs *recipeService) RestoreRecipeVersion(ctx context.Context, recipeID uuid.UUID, userID uuid.UUID, number int) (*recipeDTO.RecipeDetailDTO, error) {
+	recipe, err := findUserRecipe(ctx, rs.recipeRepository, recipeID, userID, "RestoreRecipeVersion")
+	if err != nil {
+		return nil, err
+	}
+	version, err := rs.findRecipeVersion(ctx, recipe, number, "RestoreRecipeVersion")
+	if err != nil {
+		return nil, err
+	}
+
+	previous := snapshotRecipeVersion(recipe)
+	applyRecipeVersion(recipe, version)
+	if restored := recordRecipeVersion(recipe, &previous, userID, time.Now().UTC()); restored != nil {
+		restoredFrom := version.Version
+		restored.RestoredFrom = &restoredFrom
+	}
+
+	return rs.saveRecipeChanges(ctx, recipe, "RestoreRecipeVersion")
+}
+
+// findRecipeVersion finds a version of a recipe. Version 1 of a recipe saved
+// before versions existed and not edited since is its current content.
+func (rs *recipeService) findRecipeVersion(ctx context.Context, recipe *recipeModel.Recipe, number int, function string) (*recipeModel.RecipeVersion, error) {
+	if number < 1 {
+		return nil, fmt.Errorf("%w: version must be a positive number", recipeDomain.ErrInvalidRequest)
+	}
+	if recipe.Version == 0 {
+		if number != 1 {
+			return nil, recipeDomain.ErrVersionNotFound
+		}
+		first := firstRecipeVersion(recipe)
+		return &first, nil
+	}
+
+	version, err := rs.recipeRepository.FindVersion(ctx, recipe.ID, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, recipeDomain.ErrVersionNotFound
+		}
+		appLogger.FromContext(ctx).Error("Failed to get recipe version",
+			zap.String(appLogger.FieldModule, "recipe"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, recipe.UserID.String()),
+			zap.String("recipe_id", recipe.ID.String()),
+			zap.Int("version", number),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return version, nil
+}
+
+// recordRecipeVersion adds the content of a recipe as its next version, to be
+// written when the recipe is saved. previous is the content before the edit,
+// or nil for a new recipe; nothing is recorded when the edit left the content
+// as it was. A recipe saved before versions existed first gets the content it
+// had as version 1.
+func recordRecipeVersion(recipe *recipeModel.Recipe, previous *recipeModel.RecipeVersion, authorID uuid.UUID, at time.Time) *recipeModel.RecipeVersion {
+	current := snapshotRecipeVersion(recipe)
+	if previous != nil {
+		if sameRecipeContent(*previous, current) {
+			return nil
+		}
+		if recipe.Version == 0 {
+			first := *previous
+			first.Version = 1
+			first.AuthorID = recipe.UserID
+			first.CreatedAt = recipe.CreatedAt
+			recipe.Versions = append(recipe.Versions, first)
+			recipe.Version = 1
+		}
+	}
+
+	current.Version = recipe.Version + 1
+	current.AuthorID = authorID
+	current.CreatedAt = at
+	recipe.Versions = append(recipe.Versions, current)
+	recipe.Version = current.Version
+	return &recipe.Versions[len(recipe.Versions)-1]
+}
+
+// currentRecipeVersion is the number of the current version of a recipe.
+// Recipes saved before versions existed are at version 1 until edited.
+func currentRecipeVersion(recipe *recipeModel.Recipe) int {
+	if recipe.Version < 1 {
+		return 1
+	}
+	return recipe.Version
+}
+
+func firstRecipeVersion(recipe *recipeModel.Recipe) recipeModel.RecipeVersion {
+	version := snapshotRecipeVersion(recipe)
+	version.Version = 1
+	version.AuthorID = recipe.UserID
+	version.CreatedAt = recipe.CreatedAt
+	return version
+}
+
+func snapshotRecipeVersion(recipe *recipeModel.Recipe) recipeModel.RecipeVersion {
+	return recipeModel.RecipeVersion{
+		RecipeID:            recipe.ID,
+		Title:               recipe.Title,
+		Description:         recipe.Description,
+		Ingredients:         append(recipeModel.RecipeIngredientsJSON(nil), recipe.Ingredients...),
+		Instructions:        append(recipeModel.RecipeInstructionsJSON(nil), recipe.Instructions...),
+		CookingTime:         recipe.CookingTime,
+		PreparationTime:     recipe.PreparationTime,
+		TotalTime:           recipe.TotalTime,
+		ServingSize:         recipe.ServingSize,
+		Difficulty:          recipe.Difficulty,
+		MealType:            recipe.MealType,
+		Cuisine:             recipe.Cuisine,
+		DietaryRestrictions: append(recipeModel.RecipeDietaryJSON(nil), recipe.DietaryRestrictions...),
+		Tips:                append(recipeModel.RecipeTipsJSON(nil), recipe.Tips...),
+	}
+}
+
+// applyRecipeVersion overwrites the content of a recipe with the one of a
+// version. What is not versioned (tags, favorite, rating, notes, nutrition
+// and cooking history) is kept.
+func applyRecipeVersion(recipe *recipeModel.Recipe, version *recipeModel.RecipeVersion) {
+	recipe.Title = version.Title
+	recipe.Description = version.Description
+	recipe.Ingredients = append(recipeModel.RecipeIngredientsJSON(nil), version.Ingredients...)
+	recipe.Instructions = append(recipeModel.RecipeInstructionsJSON(nil), version.Instructions...)
+	recipe.CookingTime = version.CookingTime
+	recipe.PreparationTime = version.PreparationTime
+	recipe.TotalTime = version.TotalTime
+	recipe.ServingSize = version.ServingSize
+	recipe.Difficulty = version.Difficulty
+	recipe.MealType = version.MealType
+	recipe.Cuisine = version.Cuisine
+	recipe.DietaryRestrictions = append(recipeModel.RecipeDietaryJSON(nil), version.DietaryRestrictions...)
+	recipe.Tips = append(recipeModel.RecipeTipsJSON(nil), version.Tips...)
+}
+
+// sameRecipeContent compares the content of two versions, leaving out who
+// made them and when.
+func sameRecipeContent(a, b recipeModel.RecipeVersion) bool {
+	for _, version := range []*recipeModel.RecipeVersion{&a, &b} {
+		version.ID, version.RecipeID, version.Version = uuid.Nil, uuid.Nil, 0
+		version.AuthorID, version.RestoredFrom, version.CreatedAt = uuid.Nil, nil, time.Time{}
+	}
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// diffRecipeFields lists the fields other than ingredients and steps that
+// changed between two versions.
+func diffRecipeFields(older, newer *recipeModel.RecipeVersion) []recipeDTO.RecipeFieldChangeDTO {
+	type field struct {
+		name     string
+		from, to interface{}
+	}
+	fields := []field{
+		{"title", older.Title, newer.Title},
+		{"description", older.Description, newer.Description},
+		{"cooking_time", older.CookingTime, newer.CookingTime},
+		{"preparation_time", older.PreparationTime, newer.PreparationTime},
+		{"total_time", older.TotalTime, newer.TotalTime},
+		{"serving_size", older.ServingSize, newer.ServingSize},
+		{"difficulty", older.Difficulty, newer.Difficulty},
+		{"meal_type", older.MealType, newer.MealType},
+		{"cuisine", older.Cuisine, newer.Cuisine},
+		{"dietary_restrictions", nonNilStrings(older.DietaryRestrictions), nonNilStrings(newer.DietaryRestrictions)},
+		{"tips", nonNilStrings(older.Tips), nonNilStrings(newer.Tips)},
+	}
+
+	changes := []recipeDTO.RecipeFieldChangeDTO{}
+	for _, f := range fields {
+		from, _ := json.Marshal(f.from)
+		to, _ := json.Marshal(f.to)
+		if string(from) != string(to) {
+			changes = append(changes, recipeDTO.RecipeFieldChangeDTO{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+	return changes
+}
+
+// diffRecipeIngredients pairs the ingredients of two versions by normalized
+// name and lists the ones added, removed, or with another amount, unit or
+// alternative. Changed and added ones come in the order of the newer version,
+// then the removed ones.
+func diffRecipeIngredients(older, newer recipeModel.RecipeIngredientsJSON) []recipeDTO.RecipeIngredientChangeDTO {
+	used := make([]bool, len(older))
+	changes := []recipeDTO.RecipeIngredientChangeDTO{}
+	for _, ingredient := range newer {
+		key := normalizeIngredientName(ingredient.Name)
+		match := -1
+		for i, candidate := range older {
+			if !used[i] && normalizeIngredientName(candidate.Name) == key {
+				match = i
+				break
+			}
+		}
+
+		to := convertIngredientToDetailDTO(ingredient)
+		if match < 0 {
+			changes = append(changes, recipeDTO.RecipeIngredientChangeDTO{Change: versionChangeAdded, Name: ingredient.Name, To: &to})
+			continue
+		}
+		used[match] = true
+		from := convertIngredientToDetailDTO(older[match])
+		if !sameIngredientMeasure(older[match], ingredient) {
+			changes = append(changes, recipeDTO.RecipeIngredientChangeDTO{Change: versionChangeChanged, Name: ingredient.Name, From: &from, To: &to})
+		}
+	}
+	for i, ingredient := range older {
+		if !used[i] {
+			from := convertIngredientToDetailDTO(ingredient)
+			changes = append(changes, recipeDTO.RecipeIngredientChangeDTO{Change: versionChangeRemoved, Name: ingredient.Name, From: &from})
+		}
+	}
+	return changes
+}
+
+func sameIngredientMeasure(a, b recipeModel.RecipeIngredient) bool {
+	sameAmount := (a.Amount == nil) == (b.Amount == nil) && (a.Amount == nil || *a.Amount == *b.Amount)
+	sameAlternative := (a.Alternative == nil) == (b.Alternative == nil) && (a.Alternative == nil || *a.Alternative == *b.Alternative)
+	return sameAmount && sameAlternative && units.Normalize(a.Unit) == units.Normalize(b.Unit)
+}
+
+// diffRecipeInstructions lines up the steps of two versions by their text
+// (the longest common subsequence), so steps only renumbered are not listed.
+// A step removed where another one was added counts as rewritten.
+func diffRecipeInstructions(older, newer recipeModel.RecipeInstructionsJSON) []recipeDTO.RecipeInstructionChangeDTO {
+	text := func(instruction recipeModel.RecipeInstruction) string {
+		return strings.Join(strings.Fields(instruction.Description), " ")
+	}
+
+	// common[i][j] is the length of the common subsequence of older[i:] and
+	// newer[j:].
+	common := make([][]int, len(older)+1)
+	for i := range common {
+		common[i] = make([]int, len(newer)+1)
+	}
+	for i := len(older) - 1; i >= 0; i-- {
+		for j := len(newer) - 1; j >= 0; j-- {
+			if text(older[i]) == text(newer[j]) {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	changes := []recipeDTO.RecipeInstructionChangeDTO{}
+	var removed, added []recipeModel.RecipeInstruction
+	flush := func() {
+		for k := 0; k < len(removed) || k < len(added); k++ {
+			change := recipeDTO.RecipeInstructionChangeDTO{Change: versionChangeChanged}
+			if k < len(removed) {
+				from := convertInstructionToDetailDTO(removed[k])
+				change.From = &from
+			} else {
+				change.Change = versionChangeAdded
+			}
+			if k < len(added) {
+				to := convertInstructionToDetailDTO(added[k])
+				change.To = &to
+			} else {
+				change.Change = versionChangeRemoved
+			}
+			changes = append(changes, change)
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(older) || j < len(newer) {
+		switch {
+		case i < len(older) && j < len(newer) && text(older[i]) == text(newer[j]):
+			flush()
+			if !sameInt(older[i].Time, newer[j].Time) {
+				from, to := convertInstructionToDetailDTO(older[i]), convertInstructionToDetailDTO(newer[j])
+				changes = append(changes, recipeDTO.RecipeInstructionChangeDTO{Change: versionChangeChanged, From: &from, To: &to})
+			}
+			i++
+			j++
+		case j < len(newer) && (i == len(older) || common[i][j+1] >= common[i+1][j]):
+			added = append(added, newer[j])
+			j++
+		default:
+			removed = append(removed, older[i])
+			i++
+		}
+	}
+	flush()
+	return changes
+}
+
+func sameInt(a, b *int) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func convertIngredientToDetailDTO(ingredient recipeModel.RecipeIngredient) recipeDTO.RecipeIngredientDetailDTO {
+	return recipeDTO.RecipeIngredientDetailDTO{
+		Name:        ingredient.Name,
+		Amount:      ingredient.Amount,
+		Unit:        ingredient.Unit,
+		Available:   ingredient.Available,
+		Alternative: ingredient.Alternative,
+	}
+}
+
+func convertInstructionToDetailDTO(instruction recipeModel.RecipeInstruction) recipeDTO.RecipeInstructionDetailDTO {
+	return recipeDTO.RecipeInstructionDetailDTO{
+		Step:        instruction.Step,
+		Description: instruction.Description,
+		Time:        instruction.Time,
+	}
+}
+
+func convertVersionToSummaryDTO(version *recipeModel.RecipeVersion, recipe *recipeModel.Recipe) recipeDTO.RecipeVersionSummaryDTO {
+	return recipeDTO.RecipeVersionSummaryDTO{
+		Version:      version.Version,
+		Title:        version.Title,
+		AuthorID:     version.AuthorID.String(),
+		RestoredFrom: version.RestoredFrom,
+		Current:      version.Version == currentRecipeVersion(recipe),
+		CreatedAt:    version.CreatedAt,
+	}
+}
+
+func convertVersionToDTO(version *recipeModel.RecipeVersion, recipe *recipeModel.Recipe) *recipeDTO.RecipeVersionDTO {
+	result := &recipeDTO.RecipeVersionDTO{
+		RecipeVersionSummaryDTO: convertVersionToSummaryDTO(version, recipe),
+		Description:             version.Description,
+		Ingredients:             make([]recipeDTO.RecipeIngredientDetailDTO, 0, len(version.Ingredients)),
+		Instructions:            make([]recipeDTO.RecipeInstructionDetailDTO, 0, len(version.Instructions)),
+		CookingTime:             version.CookingTime,
+		PreparationTime:         version.PreparationTime,
+		TotalTime:               version.TotalTime,
+		ServingSize:             version.ServingSize,
+		Difficulty:              version.Difficulty,
+		MealType:                version.MealType,
+		Cuisine:                 version.Cuisine,
+		DietaryRestrictions:     nonNilStrings(version.DietaryRestrictions),
+		Tips:                    nonNilStrings(version.Tips),
+	}
+	for _, ingredient := range version.Ingredients {
+		result.Ingredients = append(result.Ingredients, convertIngredientToDetailDTO(ingredient))
+	}
+	for _, instruction := range version.Instructions {
+		result.Instructions = append(result.Instructions, convertInstructionToDetailDTO(instruction))
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+)
+
+func TestRecipeService_RecipeVersions(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+	repo := newMemoryRecipeRepository()
+	svc := &recipeService{recipeRepository: repo}
+
+	input := saveRecipeInput("Bolo de fubá", "Fubá", "Ovos", "Leite")
+	input.Ingredients[0] = recipeDTO.SaveRecipeIngredientDTO{Name: "Fubá", Amount: cookingAmount(2), Unit: "xícara"}
+	input.Instructions = append(input.Instructions, recipeDTO.SaveRecipeInstructionDTO{Step: 2, Description: "Sirva morno"})
+	saved, err := svc.SaveRecipe(ctx, input, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recipeID := uuid.MustParse(saved.ID)
+	if repo.recipes[recipeID].Version != 1 || len(repo.recipes[recipeID].Versions) != 1 {
+		t.Fatalf("expected a saved recipe to start at version 1, got %+v", saved)
+	}
+
+	tags := []string{"lanche"}
+	if updated, err := svc.UpdateRecipe(ctx, recipeID, userID, &recipeDTO.UpdateRecipeDTO{Tags: tags}); err != nil || updated.Version != 1 {
+		t.Fatalf("expected tags not to create a version, got %+v (%v)", updated, err)
+	}
+
+	updated, err := svc.UpdateRecipe(ctx, recipeID, userID, &recipeDTO.UpdateRecipeDTO{
+		Ingredients: []recipeDTO.SaveRecipeIngredientDTO{
+			{Name: "Fubá", Amount: cookingAmount(3), Unit: "xícara"},
+			{Name: "Ovos", Unit: "a gosto"},
+			{Name: "Erva-doce", Unit: "a gosto"},
+		},
+		Instructions: []recipeDTO.SaveRecipeInstructionDTO{
+			{Step: 1, Description: "Misture e asse por 40 minutos"},
+			{Step: 2, Description: "Sirva morno"},
+			{Step: 3, Description: "Guarde coberto"},
+		},
+	})
+	if err != nil || updated.Version != 2 {
+		t.Fatalf("expected the edit to create version 2, got %+v (%v)", updated, err)
+	}
+
+	versions, err := svc.ListRecipeVersions(ctx, recipeID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 || !versions[0].Current || versions[1].Current || versions[0].AuthorID != userID.String() {
+		t.Fatalf("expected versions 2 and 1, got %+v", versions)
+	}
+
+	diff, err := svc.DiffRecipeVersions(ctx, recipeID, userID, 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes := map[string]string{}
+	for _, change := range diff.Ingredients {
+		changes[change.Name] = change.Change
+	}
+	if diff.To != 2 || len(changes) != 3 || changes["Fubá"] != "changed" || changes["Leite"] != "removed" || changes["Erva-doce"] != "added" {
+		t.Fatalf("unexpected ingredient changes %+v", diff.Ingredients)
+	}
+	if len(diff.Instructions) != 2 || diff.Instructions[0].Change != "changed" || diff.Instructions[1].Change != "added" || diff.Instructions[1].To.Description != "Guarde coberto" {
+		t.Fatalf("unexpected instruction changes %+v", diff.Instructions)
+	}
+
+	restored, err := svc.RestoreRecipeVersion(ctx, recipeID, userID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.Version != 3 || len(restored.Ingredients) != 3 || restored.Ingredients[2].Name != "Leite" || len(restored.Tags) != 1 {
+		t.Fatalf("expected version 1 to come back as version 3 with the tags kept, got %+v", restored)
+	}
+	version, err := svc.GetRecipeVersion(ctx, recipeID, userID, 3)
+	if err != nil || version.RestoredFrom == nil || *version.RestoredFrom != 1 {
+		t.Fatalf("expected version 3 to be restored from 1, got %+v (%v)", version, err)
+	}
+
+	if _, err := svc.GetRecipeVersion(ctx, recipeID, userID, 9); !errors.Is(err, recipeDomain.ErrVersionNotFound) {
+		t.Fatalf("expected version not found, got %v", err)
+	}
+	if _, err := svc.DiffRecipeVersions(ctx, recipeID, userID, 0, 2); !errors.Is(err, recipeDomain.ErrInvalidRequest) {
+		t.Fatalf("expected an invalid version to be refused, got %v", err)
+	}
+}
+
+func TestRecipeService_RecipeVersions_RecipeSavedBeforeVersions(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+	created := time.Date(2025, time.May, 3, 12, 0, 0, 0, time.UTC)
+	recipe := &recipeModel.Recipe{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Title:        "Pudim",
+		Ingredients:  recipeModel.RecipeIngredientsJSON{{Name: "Leite condensado"}, {Name: "Ovos"}},
+		Instructions: recipeModel.RecipeInstructionsJSON{{Step: 1, Description: "Asse em banho-maria"}},
+		CreatedAt:    created,
+	}
+	repo := newMemoryRecipeRepository(recipe)
+	svc := &recipeService{recipeRepository: repo}
+
+	versions, err := svc.ListRecipeVersions(ctx, recipe.ID, userID)
+	if err != nil || len(versions) != 1 || versions[0].Version != 1 || !versions[0].Current || !versions[0].CreatedAt.Equal(created) {
+		t.Fatalf("expected the current content as version 1, got %+v (%v)", versions, err)
+	}
+
+	title := "Pudim de leite"
+	if _, err := svc.UpdateRecipe(ctx, recipe.ID, userID, &recipeDTO.UpdateRecipeDTO{Title: &title}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recipe.Version != 2 || len(recipe.Versions) != 2 || recipe.Versions[0].Title != "Pudim" || recipe.Versions[1].Title != title {
+		t.Fatalf("expected the old content to be kept as version 1, got %+v", recipe.Versions)
+	}
+
+	diff, err := svc.DiffRecipeVersions(ctx, recipe.ID, userID, 1, 2)
+	if err != nil || len(diff.Fields) != 1 || diff.Fields[0].Field != "title" || len(diff.Ingredients) != 0 || len(diff.Instructions) != 0 {
+		t.Fatalf("expected only the title to change, got %+v (%v)", diff, err)
+	}
+}
+
+func TestRecipeService_CookingRecordsRecipeVersion(t *testing.T) {
+	userID := uuid.New()
+	recipe := &recipeModel.Recipe{ID: uuid.New(), UserID: userID, Title: "Lasanha", Version: 4}
+	cookings := &stubCookingRepository{}
+	svc := &recipeService{
+		recipeRepository:  newMemoryRecipeRepository(recipe),
+		cookingRepository: cookings,
+	}
+
+	result, err := svc.LogRecipeCooking(context.Background(), recipe.ID, userID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RecipeVersion != 4 || cookings.applied[0].RecipeVersion != 4 {
+		t.Fatalf("expected the cooking of version 4, got %+v", result)
+	}
+
+	recipe.Version = 0
+	if result, err := svc.LogRecipeCooking(context.Background(), recipe.ID, userID, nil); err != nil || result.RecipeVersion != 1 {
+		t.Fatalf("expected a recipe without versions to be cooked at version 1, got %+v (%v)", result, err)
+	}
+}
@@ -11,4 +11,19 @@ var (
 	ErrPromptBuildFailed    = errors.New("shopping_list: prompt build failed")
 	ErrAIResponseInvalid    = errors.New("shopping_list: ai response invalid")
 	ErrAIRequestFailed      = errors.New("shopping_list: ai request failed")
+	ErrStoreNotFound        = errors.New("shopping_list: store not found")
+	ErrReceiptInvalid       = errors.New("shopping_list: receipt invalid")
+	ErrReceiptWithoutItems  = errors.New("shopping_list: receipt without items")
+	ErrShareNotFound        = errors.New("shopping_list: share not found")
+	ErrShareExpired         = errors.New("shopping_list: share expired")
+	ErrUnsupportedFormat    = errors.New("shopping_list: unsupported export format")
+	ErrInvalidMonth         = errors.New("shopping_list: invalid month")
+	ErrAlreadyCheckedOut    = errors.New("shopping_list: already checked out")
+	ErrCheckoutNotFound     = errors.New("shopping_list: checkout not found")
+	ErrInvalidTransition    = errors.New("shopping_list: invalid status transition")
+	ErrBudgetRequired       = errors.New("shopping_list: budget required")
+	ErrSameShoppingList     = errors.New("shopping_list: source and target are the same list")
+	ErrRecipeNotFound       = errors.New("shopping_list: recipe not found")
+	ErrInvalidPeriod        = errors.New("shopping_list: invalid period")
+	ErrShoppingListClosed   = errors.New("shopping_list: list is completed or archived")
 )
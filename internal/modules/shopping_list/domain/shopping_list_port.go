@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
@@ -19,6 +20,18 @@ type ShoppingListRepository interface {
 	DeleteItem(ctx context.Context, itemID uuid.UUID) error
 	GetItemsByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) ([]*model.ShoppingListItem, error)
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// ListPurchaseHistory returns the lists of a pantry completed since the
+	// given time with their purchased items only.
+	ListPurchaseHistory(ctx context.Context, pantryID uuid.UUID, since time.Time) ([]*model.ShoppingList, error)
+	// CreateMerged creates a merged list and deletes the given lists in one
+	// transaction.
+	CreateMerged(ctx context.Context, merged *model.ShoppingList, deleteIDs []uuid.UUID) error
+	// MoveItems saves the moved items, deletes the ones folded into an
+	// existing line and updates the totals of the lists in one transaction.
+	MoveItems(ctx context.Context, saved []*model.ShoppingListItem, deletedIDs []uuid.UUID, lists []*model.ShoppingList) error
+	// SaveItems saves the given items of a list and the list itself in one
+	// transaction.
+	SaveItems(ctx context.Context, list *model.ShoppingList, items []*model.ShoppingListItem) error
 }
 
 type ShoppingListService interface {
@@ -31,4 +44,122 @@ type ShoppingListService interface {
 	UpdateShoppingListItem(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, itemID uuid.UUID, input dto.UpdateShoppingListItemDTO) (*dto.ShoppingListItemResponseDTO, error)
 	DeleteShoppingListItem(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, itemID uuid.UUID) error
 	GenerateAIShoppingList(ctx context.Context, userID uuid.UUID, input dto.GenerateAIShoppingListDTO) (*dto.ShoppingListResponseDTO, error)
+	ImportReceipt(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, input dto.ImportReceiptDTO) (*dto.ReceiptImportResponseDTO, error)
+	ListCheckouts(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID) ([]dto.CheckoutDTO, error)
+	RevertCheckout(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID) (*dto.ShoppingListResponseDTO, error)
+	// TransitionShoppingList moves a list to another status, running the side
+	// effects of the transition. It fails with ErrInvalidTransition when the
+	// current status cannot move to the requested one.
+	TransitionShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.TransitionShoppingListDTO) (*dto.ShoppingListResponseDTO, error)
+	GetStatusHistory(ctx context.Context, userID uuid.UUID, id uuid.UUID) ([]dto.StatusChangeDTO, error)
+	// OptimizeShoppingList cuts the items of a list that do not fit the
+	// budget, saving the result only when input.Apply is set.
+	OptimizeShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.OptimizeShoppingListDTO) (*dto.BudgetOptimizationDTO, error)
+	DuplicateShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.DuplicateShoppingListDTO) (*dto.ShoppingListResponseDTO, error)
+	// MergeShoppingLists creates a list with the items of the given lists,
+	// consolidating lines with the same name and unit.
+	MergeShoppingLists(ctx context.Context, userID uuid.UUID, input dto.MergeShoppingListsDTO) (*dto.ShoppingListResponseDTO, error)
+	MoveShoppingListItems(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.MoveShoppingListItemsDTO) (*dto.MoveShoppingListItemsResponseDTO, error)
+	// AddRecipeToShoppingList adds the ingredients of a saved recipe missing
+	// from the pantry to a list, creating one when no list is given.
+	AddRecipeToShoppingList(ctx context.Context, userID uuid.UUID, input dto.AddRecipeToShoppingListDTO) (*dto.RecipeShoppingResultDTO, error)
+	// GenerateFromMealPlan sums the ingredients of the meals planned for a
+	// pantry in a period and creates one list with what the pantry lacks.
+	GenerateFromMealPlan(ctx context.Context, userID uuid.UUID, input dto.MealPlanShoppingListDTO) (*dto.MealPlanShoppingResultDTO, error)
+}
+
+type StatusHistoryRepository interface {
+	Record(ctx context.Context, change *model.ShoppingListStatusChange) error
+	// ListByShoppingListID returns the status changes of a list, oldest first.
+	ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) ([]*model.ShoppingListStatusChange, error)
+}
+
+type CheckoutRepository interface {
+	// GetActive returns the checkout of a list that was not reverted.
+	GetActive(ctx context.Context, shoppingListID uuid.UUID) (*model.ShoppingListCheckout, error)
+	ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) ([]*model.ShoppingListCheckout, error)
+	// Apply makes the pantry changes of a checkout, records it and saves its
+	// completed list with the purchased items in one transaction, locking the
+	// list so concurrent checkouts of it run one after the other. It fails
+	// with ErrAlreadyCheckedOut when the list already has an active checkout.
+	Apply(ctx context.Context, checkout *model.ShoppingListCheckout, list *model.ShoppingList) error
+	// Revert rolls back the pantry changes of a checkout, marks it reverted
+	// and moves its list back to pending in one transaction.
+	Revert(ctx context.Context, checkout *model.ShoppingListCheckout, userID uuid.UUID, at time.Time) error
+}
+
+type StoreRepository interface {
+	Create(ctx context.Context, store *model.Store) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Store, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Store, error)
+	Update(ctx context.Context, store *model.Store) error
+	ReplaceSections(ctx context.Context, storeID uuid.UUID, sections []model.StoreSection) error
+	UpdateSection(ctx context.Context, section *model.StoreSection) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	RecordPrices(ctx context.Context, prices []*model.StorePrice) error
+	// ListPrices returns the price observations of the given stores, most
+	// recent first. A zero limit returns every observation.
+	ListPrices(ctx context.Context, storeIDs []uuid.UUID, limit int) ([]*model.StorePrice, error)
+}
+
+type StoreService interface {
+	CreateStore(ctx context.Context, userID uuid.UUID, input dto.CreateStoreDTO) (*dto.StoreResponseDTO, error)
+	GetStore(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*dto.StoreResponseDTO, error)
+	ListStores(ctx context.Context, userID uuid.UUID) ([]*dto.StoreResponseDTO, error)
+	UpdateStore(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.UpdateStoreDTO) (*dto.StoreResponseDTO, error)
+	DeleteStore(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	RecordPrices(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, input dto.RecordStorePricesDTO) ([]dto.StorePriceResponseDTO, error)
+	ListPrices(ctx context.Context, userID uuid.UUID, storeID uuid.UUID) ([]dto.StorePriceResponseDTO, error)
+	ComparePrices(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, storeIDs []uuid.UUID) (*dto.PriceComparisonResponseDTO, error)
+}
+
+type ShoppingListShareRepository interface {
+	Create(ctx context.Context, share *model.ShoppingListShare) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.ShoppingListShare, error)
+	GetByToken(ctx context.Context, token string) (*model.ShoppingListShare, error)
+	ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) ([]*model.ShoppingListShare, error)
+	Update(ctx context.Context, share *model.ShoppingListShare) error
+}
+
+// ShoppingListShareService exports shopping lists and manages their public
+// read-only links.
+type ShoppingListShareService interface {
+	ExportShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, format string) (*dto.ShoppingListExportDTO, error)
+	CreateShare(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, input dto.CreateShoppingListShareDTO) (*dto.ShoppingListShareDTO, error)
+	ListShares(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID) ([]dto.ShoppingListShareDTO, error)
+	RevokeShare(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, shareID uuid.UUID) error
+	GetSharedShoppingList(ctx context.Context, token string) (*dto.SharedShoppingListDTO, error)
+	ExportSharedShoppingList(ctx context.Context, token string, format string) (*dto.ShoppingListExportDTO, error)
+}
+
+type BudgetRepository interface {
+	GetPantryBudget(ctx context.Context, pantryID uuid.UUID) (*model.PantryBudget, error)
+	SavePantryBudget(ctx context.Context, budget *model.PantryBudget) error
+	// ListCompletedByUser returns the lists of a user completed in [from, to).
+	ListCompletedByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*model.ShoppingList, error)
+	// ListCompletedByPantry returns the lists of a pantry completed in [from, to).
+	ListCompletedByPantry(ctx context.Context, pantryID uuid.UUID, from, to time.Time) ([]*model.ShoppingList, error)
+}
+
+// BudgetService keeps the monthly budget ledger of a user or pantry from the
+// actual cost of completed shopping lists.
+type BudgetService interface {
+	GetLedger(ctx context.Context, userID uuid.UUID, pantryID *uuid.UUID, month string) (*dto.BudgetLedgerDTO, error)
+	SetPantryBudget(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID, input dto.SetPantryBudgetDTO) (*dto.PantryBudgetDTO, error)
+	// CheckSpend reports the monthly budgets that a new spend of amount would
+	// exceed for the user and, when given, the pantry.
+	CheckSpend(ctx context.Context, userID uuid.UUID, pantryID *uuid.UUID, amount float64) ([]dto.BudgetWarningDTO, error)
+}
+
+type ParLevelRepository interface {
+	ListByPantryID(ctx context.Context, pantryID uuid.UUID) ([]*model.ParLevel, error)
+	// ReplaceForPantry replaces every par level of a pantry in one transaction.
+	ReplaceForPantry(ctx context.Context, pantryID uuid.UUID, levels []*model.ParLevel) error
+}
+
+// ParLevelService manages the stock each pantry wants to keep, used by the
+// rule based shopping list generator.
+type ParLevelService interface {
+	ListParLevels(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID) ([]dto.ParLevelDTO, error)
+	SetParLevels(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID, input dto.SetParLevelsDTO) ([]dto.ParLevelDTO, error)
 }
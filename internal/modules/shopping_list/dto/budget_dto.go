@@ -0,0 +1,52 @@
+package dto
+
+type SetPantryBudgetDTO struct {
+	MonthlyAmount float64 `json:"monthly_amount" binding:"min=0"`
+}
+
+type PantryBudgetDTO struct {
+	PantryID      string  `json:"pantry_id"`
+	MonthlyAmount float64 `json:"monthly_amount"`
+	UpdatedBy     string  `json:"updated_by"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type BudgetLedgerEntryDTO struct {
+	ShoppingListID string  `json:"shopping_list_id"`
+	Name           string  `json:"name"`
+	PantryID       *string `json:"pantry_id,omitempty"`
+	ActualCost     float64 `json:"actual_cost"`
+	CompletedAt    string  `json:"completed_at"`
+}
+
+// BudgetLedgerDTO is the spending of a month against the monthly budget of a
+// user (scope "user") or pantry (scope "pantry"). Forecast extrapolates the
+// daily run rate to the end of the month; past months forecast what was spent.
+type BudgetLedgerDTO struct {
+	Scope              string                 `json:"scope"`
+	PantryID           *string                `json:"pantry_id,omitempty"`
+	Month              string                 `json:"month"`
+	Budget             float64                `json:"budget"`
+	Spent              float64                `json:"spent"`
+	Remaining          float64                `json:"remaining"`
+	DaysElapsed        int                    `json:"days_elapsed"`
+	DaysInMonth        int                    `json:"days_in_month"`
+	DailyRunRate       float64                `json:"daily_run_rate"`
+	Forecast           float64                `json:"forecast"`
+	OverBudget         bool                   `json:"over_budget"`
+	ForecastOverBudget bool                   `json:"forecast_over_budget"`
+	Lists              []BudgetLedgerEntryDTO `json:"lists"`
+}
+
+// BudgetWarningDTO tells that a planned spend is larger than what is left of
+// a monthly budget. Warnings never block the operation.
+type BudgetWarningDTO struct {
+	Scope     string  `json:"scope"`
+	PantryID  *string `json:"pantry_id,omitempty"`
+	Month     string  `json:"month"`
+	Budget    float64 `json:"budget"`
+	Remaining float64 `json:"remaining"`
+	Planned   float64 `json:"planned"`
+	Excess    float64 `json:"excess"`
+	Message   string  `json:"message"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+type OptimizeShoppingListDTO struct {
+	// Budget defaults to the total budget of the list.
+	Budget *float64 `json:"budget,omitempty" binding:"omitempty,gt=0"`
+	// Apply saves the optimized quantities; otherwise the result is a preview.
+	Apply bool `json:"apply,omitempty"`
+}
+
+type BudgetCutDTO struct {
+	ItemID       string  `json:"item_id,omitempty"`
+	Name         string  `json:"name"`
+	Action       string  `json:"action"` // removed, reduced
+	Priority     int     `json:"priority"`
+	FromQuantity float64 `json:"from_quantity"`
+	ToQuantity   float64 `json:"to_quantity"`
+	Unit         string  `json:"unit"`
+	Saved        float64 `json:"saved"`
+	Reason       string  `json:"reason"`
+}
+
+type BudgetOptimizationDTO struct {
+	Budget        float64                  `json:"budget"`
+	OriginalCost  float64                  `json:"original_cost"`
+	OptimizedCost float64                  `json:"optimized_cost"`
+	WithinBudget  bool                     `json:"within_budget"`
+	Applied       bool                     `json:"applied"`
+	Cuts          []BudgetCutDTO           `json:"cuts"`
+	ShoppingList  *ShoppingListResponseDTO `json:"shopping_list,omitempty"`
+}
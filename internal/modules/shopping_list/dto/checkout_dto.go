@@ -0,0 +1,25 @@
+package dto
+
+type CheckoutChangeDTO struct {
+	ShoppingListItemID   string  `json:"shopping_list_item_id"`
+	PantryItemID         string  `json:"pantry_item_id"`
+	Action               string  `json:"action"`
+	Name                 string  `json:"name"`
+	Quantity             float64 `json:"quantity"`
+	Unit                 string  `json:"unit"`
+	PricePerUnit         float64 `json:"price_per_unit"`
+	PreviousPricePerUnit float64 `json:"previous_price_per_unit"`
+}
+
+// CheckoutDTO is a checkout of a shopping list with the pantry changes it
+// made. Only the active checkout can be reverted.
+type CheckoutDTO struct {
+	ID             string              `json:"id"`
+	ShoppingListID string              `json:"shopping_list_id"`
+	PantryID       *string             `json:"pantry_id,omitempty"`
+	ActualCost     float64             `json:"actual_cost"`
+	Active         bool                `json:"active"`
+	Changes        []CheckoutChangeDTO `json:"changes"`
+	RevertedAt     *string             `json:"reverted_at,omitempty"`
+	CreatedAt      string              `json:"created_at"`
+}
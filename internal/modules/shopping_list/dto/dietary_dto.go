@@ -0,0 +1,14 @@
+package dto
+
+// DietaryWarningDTO tells that an item breaks a dietary restriction. Source is
+// "list" for the restrictions of the list itself and "member" for those of
+// someone of the household, identified by UserID and Email. Warnings never
+// block the operation.
+type DietaryWarningDTO struct {
+	Item        string   `json:"item"`
+	Restriction string   `json:"restriction"`
+	Allergens   []string `json:"allergens"`
+	Source      string   `json:"source"`
+	UserID      string   `json:"user_id,omitempty"`
+	Email       string   `json:"email,omitempty"`
+}
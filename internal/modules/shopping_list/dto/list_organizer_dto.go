@@ -0,0 +1,29 @@
+package dto
+
+import "github.com/google/uuid"
+
+type DuplicateShoppingListDTO struct {
+	// Name defaults to the original name followed by "(cópia)".
+	Name string `json:"name,omitempty"`
+	// ResetPurchased unmarks the purchased items and clears their actual price.
+	ResetPurchased bool `json:"reset_purchased,omitempty"`
+	// ResetPrices clears the estimated and actual prices of every item.
+	ResetPrices bool `json:"reset_prices,omitempty"`
+}
+
+type MergeShoppingListsDTO struct {
+	Name            string      `json:"name" binding:"required"`
+	ShoppingListIDs []uuid.UUID `json:"shopping_list_ids" binding:"required,min=2,max=10,unique"`
+	// KeepSources keeps the merged lists; by default they are deleted.
+	KeepSources bool `json:"keep_sources,omitempty"`
+}
+
+type MoveShoppingListItemsDTO struct {
+	TargetListID uuid.UUID   `json:"target_list_id" binding:"required"`
+	ItemIDs      []uuid.UUID `json:"item_ids" binding:"required,min=1,unique"`
+}
+
+type MoveShoppingListItemsResponseDTO struct {
+	Source *ShoppingListResponseDTO `json:"source"`
+	Target *ShoppingListResponseDTO `json:"target"`
+}
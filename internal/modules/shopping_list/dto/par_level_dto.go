@@ -0,0 +1,24 @@
+package dto
+
+type ParLevelInputDTO struct {
+	Name      string  `json:"name" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
+	Unit      string  `json:"unit" binding:"required"`
+	PerPerson bool    `json:"per_person"`
+	Category  string  `json:"category,omitempty"`
+}
+
+type SetParLevelsDTO struct {
+	ParLevels []ParLevelInputDTO `json:"par_levels" binding:"dive"`
+}
+
+type ParLevelDTO struct {
+	ID        string  `json:"id"`
+	PantryID  string  `json:"pantry_id"`
+	Name      string  `json:"name"`
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit"`
+	PerPerson bool    `json:"per_person"`
+	Category  string  `json:"category,omitempty"`
+	UpdatedAt string  `json:"updated_at"`
+}
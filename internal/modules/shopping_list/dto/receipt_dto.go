@@ -0,0 +1,40 @@
+package dto
+
+// ImportReceiptDTO imports an NFC-e into a shopping list. Content is the XML,
+// the SEFAZ consultation page text or the QR code content; Format is detected
+// when omitted. Checkout defaults to true.
+type ImportReceiptDTO struct {
+	Content      string `json:"content" form:"content"`
+	Format       string `json:"format,omitempty" form:"format" binding:"omitempty,oneof=xml text qrcode"`
+	Checkout     *bool  `json:"checkout,omitempty" form:"checkout"`
+	AddUnmatched bool   `json:"add_unmatched,omitempty" form:"add_unmatched"`
+}
+
+// ReceiptLineMatchDTO reports what a receipt line was matched to. MatchType
+// is "shopping_list_item", "pantry_item", "added" or "unmatched".
+type ReceiptLineMatchDTO struct {
+	LineNumber   int     `json:"line_number"`
+	Description  string  `json:"description"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	UnitPrice    float64 `json:"unit_price"`
+	Total        float64 `json:"total"`
+	MatchType    string  `json:"match_type"`
+	ItemID       *string `json:"item_id,omitempty"`
+	PantryItemID *string `json:"pantry_item_id,omitempty"`
+	MatchedName  string  `json:"matched_name,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+type ReceiptImportResponseDTO struct {
+	AccessKey      string                   `json:"access_key,omitempty"`
+	IssuerName     string                   `json:"issuer_name,omitempty"`
+	IssuerCNPJ     string                   `json:"issuer_cnpj,omitempty"`
+	IssuedAt       *string                  `json:"issued_at,omitempty"`
+	ReceiptTotal   float64                  `json:"receipt_total"`
+	Lines          []ReceiptLineMatchDTO    `json:"lines"`
+	MatchedLines   int                      `json:"matched_lines"`
+	UnmatchedLines int                      `json:"unmatched_lines"`
+	CheckedOut     bool                     `json:"checked_out"`
+	ShoppingList   *ShoppingListResponseDTO `json:"shopping_list"`
+}
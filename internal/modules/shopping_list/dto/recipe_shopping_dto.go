@@ -0,0 +1,58 @@
+package dto
+
+import "github.com/google/uuid"
+
+type AddRecipeToShoppingListDTO struct {
+	RecipeID uuid.UUID `json:"recipe_id" binding:"required"`
+	// Servings defaults to the serving size of the recipe.
+	Servings int `json:"servings,omitempty" binding:"omitempty,min=1,max=100"`
+	// ShoppingListID is the list that receives the ingredients; without it a
+	// new list is created.
+	ShoppingListID *uuid.UUID `json:"shopping_list_id,omitempty"`
+	// PantryID is the pantry checked for stock. It defaults to the pantry of
+	// the list.
+	PantryID *uuid.UUID `json:"pantry_id,omitempty"`
+	// Name of the new list, defaulting to the recipe title.
+	Name string `json:"name,omitempty"`
+}
+
+// RecipeIngredientNeedDTO compares what the recipe needs of an ingredient with
+// the pantry stock. Status is covered, insufficient or missing.
+type RecipeIngredientNeedDTO struct {
+	Name    string   `json:"name"`
+	Needed  *float64 `json:"needed,omitempty"`
+	Unit    string   `json:"unit"`
+	InStock float64  `json:"in_stock"`
+	Status  string   `json:"status"`
+	ToBuy   float64  `json:"to_buy"`
+	BuyUnit string   `json:"buy_unit,omitempty"`
+	Merged  bool     `json:"merged,omitempty"`
+}
+
+type RecipeShoppingResultDTO struct {
+	RecipeID     string                    `json:"recipe_id"`
+	Servings     int                       `json:"servings"`
+	Created      bool                      `json:"created"`
+	Ingredients  []RecipeIngredientNeedDTO `json:"ingredients"`
+	ShoppingList *ShoppingListResponseDTO  `json:"shopping_list"`
+}
+
+// MealPlanShoppingListDTO asks for the list of the meals planned for a pantry.
+// From and To are YYYY-MM-DD and default to the seven days starting today.
+type MealPlanShoppingListDTO struct {
+	PantryID uuid.UUID `json:"pantry_id" binding:"required"`
+	From     string    `json:"from,omitempty"`
+	To       string    `json:"to,omitempty"`
+	// Name of the new list, defaulting to the period.
+	Name string `json:"name,omitempty"`
+}
+
+type MealPlanShoppingResultDTO struct {
+	PantryID     string                    `json:"pantry_id"`
+	From         string                    `json:"from"`
+	To           string                    `json:"to"`
+	Meals        int                       `json:"meals"`
+	Created      bool                      `json:"created"`
+	Ingredients  []RecipeIngredientNeedDTO `json:"ingredients"`
+	ShoppingList *ShoppingListResponseDTO  `json:"shopping_list"`
+}
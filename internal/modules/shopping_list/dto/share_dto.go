@@ -0,0 +1,51 @@
+package dto
+
+// CreateShoppingListShareDTO creates a public link. ExpiresInHours defaults
+// to one week.
+type CreateShoppingListShareDTO struct {
+	ExpiresInHours int `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+type ShoppingListShareDTO struct {
+	ID             string  `json:"id"`
+	ShoppingListID string  `json:"shopping_list_id"`
+	Token          string  `json:"token"`
+	Path           string  `json:"path"`
+	ExpiresAt      string  `json:"expires_at"`
+	RevokedAt      *string `json:"revoked_at,omitempty"`
+	Active         bool    `json:"active"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+type SharedShoppingListItemDTO struct {
+	Name           string  `json:"name"`
+	Quantity       float64 `json:"quantity"`
+	Unit           string  `json:"unit"`
+	EstimatedPrice float64 `json:"estimated_price"`
+	Purchased      bool    `json:"purchased"`
+}
+
+type SharedShoppingListCategoryDTO struct {
+	Name           string                      `json:"name"`
+	EstimatedTotal float64                     `json:"estimated_total"`
+	Items          []SharedShoppingListItemDTO `json:"items"`
+}
+
+// SharedShoppingListDTO is the read-only view of a shared list. It leaves
+// out owner and pantry identifiers.
+type SharedShoppingListDTO struct {
+	Name          string                          `json:"name"`
+	Status        string                          `json:"status"`
+	TotalBudget   float64                         `json:"total_budget"`
+	EstimatedCost float64                         `json:"estimated_cost"`
+	Categories    []SharedShoppingListCategoryDTO `json:"categories"`
+	ExpiresAt     string                          `json:"expires_at"`
+	UpdatedAt     string                          `json:"updated_at"`
+}
+
+// ShoppingListExportDTO is a rendered export ready to be downloaded.
+type ShoppingListExportDTO struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
@@ -17,7 +17,9 @@ type ShoppingListPreferencesDTO struct {
 type CreateShoppingListDTO struct {
 	Name        string                              `json:"name" binding:"required"`
 	PantryID    *uuid.UUID                          `json:"pantry_id,omitempty"`
+	StoreID     *uuid.UUID                          `json:"store_id,omitempty"`
 	TotalBudget float64                             `json:"total_budget" binding:"required,min=0"`
+	Draft       bool                                `json:"draft,omitempty"`
 	Items       []CreateShoppingListItemDTO         `json:"items"`
 	Preferences *ShoppingListPreferencesOverrideDTO `json:"preferences,omitempty"`
 }
@@ -34,7 +36,8 @@ type CreateShoppingListItemDTO struct {
 
 type UpdateShoppingListDTO struct {
 	Name        *string                             `json:"name,omitempty"`
-	Status      *string                             `json:"status,omitempty" binding:"omitempty,oneof=pending completed cancelled"`
+	StoreID     *uuid.UUID                          `json:"store_id,omitempty"`
+	ClearStore  bool                                `json:"clear_store,omitempty"`
 	TotalBudget *float64                            `json:"total_budget,omitempty" binding:"omitempty,min=0"`
 	ActualCost  *float64                            `json:"actual_cost,omitempty" binding:"omitempty,min=0"`
 	Preferences *ShoppingListPreferencesOverrideDTO `json:"preferences,omitempty"`
@@ -63,23 +66,42 @@ type GenerateAIShoppingListDTO struct {
 	ExcludeItems  []string                            `json:"exclude_items,omitempty"`
 	Notes         string                              `json:"notes,omitempty"`
 	Preferences   *ShoppingListPreferencesOverrideDTO `json:"preferences,omitempty"`
+	// Mode selects the generator: "ai" (default, costs a credit) or "rules".
+	Mode  string             `json:"mode,omitempty" binding:"omitempty,oneof=ai rules"`
+	Rules *GeneratorRulesDTO `json:"rules,omitempty"`
+}
+
+// GeneratorRulesDTO overrides the defaults of the rule based generator.
+type GeneratorRulesDTO struct {
+	HorizonDays        *int `json:"horizon_days,omitempty" binding:"omitempty,min=1,max=90"`
+	ExpiringWithinDays *int `json:"expiring_within_days,omitempty" binding:"omitempty,min=0,max=60"`
+	LookbackDays       *int `json:"lookback_days,omitempty" binding:"omitempty,min=7,max=365"`
+	MinPurchases       *int `json:"min_purchases,omitempty" binding:"omitempty,min=1,max=20"`
 }
 
 type ShoppingListResponseDTO struct {
-	ID            string                        `json:"id"`
-	UserID        string                        `json:"user_id"`
-	PantryID      *string                       `json:"pantry_id,omitempty"`
-	PantryName    string                        `json:"pantry_name,omitempty"`
-	Name          string                        `json:"name"`
-	Status        string                        `json:"status"`
-	TotalBudget   float64                       `json:"total_budget"`
-	EstimatedCost float64                       `json:"estimated_cost"`
-	ActualCost    float64                       `json:"actual_cost"`
-	GeneratedBy   string                        `json:"generated_by"`
-	Items         []ShoppingListItemResponseDTO `json:"items"`
-	Preferences   ShoppingListPreferencesDTO    `json:"preferences"`
-	CreatedAt     string                        `json:"created_at"`
-	UpdatedAt     string                        `json:"updated_at"`
+	ID              string                        `json:"id"`
+	UserID          string                        `json:"user_id"`
+	PantryID        *string                       `json:"pantry_id,omitempty"`
+	PantryName      string                        `json:"pantry_name,omitempty"`
+	StoreID         *string                       `json:"store_id,omitempty"`
+	StoreName       string                        `json:"store_name,omitempty"`
+	Name            string                        `json:"name"`
+	Status          string                        `json:"status"`
+	Transitions     []string                      `json:"transitions"`
+	TotalBudget     float64                       `json:"total_budget"`
+	EstimatedCost   float64                       `json:"estimated_cost"`
+	ActualCost      float64                       `json:"actual_cost"`
+	GeneratedBy     string                        `json:"generated_by"`
+	Items           []ShoppingListItemResponseDTO `json:"items"`
+	Sections        []ShoppingListSectionDTO      `json:"sections,omitempty"`
+	Preferences     ShoppingListPreferencesDTO    `json:"preferences"`
+	BudgetWarnings  []BudgetWarningDTO            `json:"budget_warnings,omitempty"`
+	BudgetCuts      []BudgetCutDTO                `json:"budget_cuts,omitempty"`
+	DietaryWarnings []DietaryWarningDTO           `json:"dietary_warnings,omitempty"`
+	CompletedAt     *string                       `json:"completed_at,omitempty"`
+	CreatedAt       string                        `json:"created_at"`
+	UpdatedAt       string                        `json:"updated_at"`
 }
 
 type ShoppingListItemResponseDTO struct {
@@ -95,14 +117,19 @@ type ShoppingListItemResponseDTO struct {
 	Purchased      bool    `json:"purchased"`
 	Source         string  `json:"source"`
 	PantryItemID   *string `json:"pantry_item_id,omitempty"`
+	RecipeID       *string `json:"recipe_id,omitempty"`
+	PurchasedAt    *string `json:"purchased_at,omitempty"`
 	CreatedAt      string  `json:"created_at"`
 	UpdatedAt      string  `json:"updated_at"`
+	// DietaryWarnings are set when an item is renamed.
+	DietaryWarnings []DietaryWarningDTO `json:"dietary_warnings,omitempty"`
 }
 
 type ShoppingListSummaryDTO struct {
 	ID             string                     `json:"id"`
 	PantryID       *string                    `json:"pantry_id,omitempty"`
 	PantryName     string                     `json:"pantry_name,omitempty"`
+	StoreID        *string                    `json:"store_id,omitempty"`
 	Name           string                     `json:"name"`
 	Status         string                     `json:"status"`
 	TotalBudget    float64                    `json:"total_budget"`
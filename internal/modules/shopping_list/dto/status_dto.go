@@ -0,0 +1,16 @@
+package dto
+
+type TransitionShoppingListDTO struct {
+	Status     string   `json:"status" binding:"required,oneof=draft pending in_progress completed cancelled archived"`
+	Note       string   `json:"note,omitempty" binding:"max=500"`
+	ActualCost *float64 `json:"actual_cost,omitempty" binding:"omitempty,min=0"`
+}
+
+type StatusChangeDTO struct {
+	ID         string `json:"id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	ChangedBy  string `json:"changed_by"`
+	Note       string `json:"note,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
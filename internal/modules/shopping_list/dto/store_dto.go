@@ -0,0 +1,130 @@
+package dto
+
+type StoreSectionInputDTO struct {
+	Name       string   `json:"name" binding:"required"`
+	Categories []string `json:"categories"`
+}
+
+// CreateStoreDTO creates a store. Sections are listed in walking order.
+type CreateStoreDTO struct {
+	Name     string                 `json:"name" binding:"required"`
+	Address  string                 `json:"address,omitempty"`
+	Sections []StoreSectionInputDTO `json:"sections" binding:"dive"`
+}
+
+// UpdateStoreDTO updates a store. When Sections is present it replaces the
+// current layout, keeping what was learned for sections with the same name.
+type UpdateStoreDTO struct {
+	Name     *string                 `json:"name,omitempty"`
+	Address  *string                 `json:"address,omitempty"`
+	Sections *[]StoreSectionInputDTO `json:"sections,omitempty" binding:"omitempty,dive"`
+}
+
+type StoreSectionResponseDTO struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Position        int      `json:"position"`
+	Categories      []string `json:"categories"`
+	LearnedPosition float64  `json:"learned_position"`
+	LearnedSamples  int      `json:"learned_samples"`
+}
+
+type StoreResponseDTO struct {
+	ID        string                    `json:"id"`
+	UserID    string                    `json:"user_id"`
+	Name      string                    `json:"name"`
+	Address   string                    `json:"address,omitempty"`
+	Sections  []StoreSectionResponseDTO `json:"sections"`
+	CreatedAt string                    `json:"created_at"`
+	UpdatedAt string                    `json:"updated_at"`
+}
+
+// ShoppingListSectionDTO groups the items of a shopping list that belong to
+// the same store section. Items without a matching section are returned in a
+// trailing group with an empty SectionID.
+type ShoppingListSectionDTO struct {
+	SectionID string                        `json:"section_id,omitempty"`
+	Name      string                        `json:"name"`
+	Items     []ShoppingListItemResponseDTO `json:"items"`
+}
+
+// RecordStorePriceInputDTO is a price seen at a store. Price is per Unit.
+type RecordStorePriceInputDTO struct {
+	Name  string  `json:"name" binding:"required"`
+	Unit  string  `json:"unit" binding:"required"`
+	Price float64 `json:"price" binding:"required,gt=0"`
+}
+
+type RecordStorePricesDTO struct {
+	Prices []RecordStorePriceInputDTO `json:"prices" binding:"required,min=1,dive"`
+}
+
+type StorePriceResponseDTO struct {
+	ID             string  `json:"id"`
+	StoreID        string  `json:"store_id"`
+	Name           string  `json:"name"`
+	Unit           string  `json:"unit"`
+	Price          float64 `json:"price"`
+	Source         string  `json:"source"`
+	ShoppingListID *string `json:"shopping_list_id,omitempty"`
+	ObservedAt     string  `json:"observed_at"`
+}
+
+// PricedItemDTO is a shopping list item priced with the latest observation of
+// a store. PriceUnit is the unit of the observation, which may differ from the
+// item unit when the two are convertible (e.g. g and kg).
+type PricedItemDTO struct {
+	ItemID     string  `json:"item_id"`
+	Name       string  `json:"name"`
+	Quantity   float64 `json:"quantity"`
+	Unit       string  `json:"unit"`
+	UnitPrice  float64 `json:"unit_price"`
+	PriceUnit  string  `json:"price_unit"`
+	Total      float64 `json:"total"`
+	ObservedAt string  `json:"observed_at"`
+}
+
+// MissingPriceItemDTO flags an item without usable price data. Reason is
+// "no_price" when the store never had the product, or "unit_mismatch" when
+// the recorded price uses a unit that cannot be converted to the item unit.
+type MissingPriceItemDTO struct {
+	ItemID string `json:"item_id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// StorePriceTotalDTO is the cost of a whole shopping list at one store.
+// KnownTotal only covers priced items; EstimatedTotal adds the list's own
+// estimated price for the missing ones.
+type StorePriceTotalDTO struct {
+	StoreID        string                `json:"store_id"`
+	StoreName      string                `json:"store_name"`
+	KnownTotal     float64               `json:"known_total"`
+	EstimatedTotal float64               `json:"estimated_total"`
+	Complete       bool                  `json:"complete"`
+	Items          []PricedItemDTO       `json:"items"`
+	MissingItems   []MissingPriceItemDTO `json:"missing_items"`
+}
+
+type PriceSplitStoreDTO struct {
+	StoreID   string          `json:"store_id"`
+	StoreName string          `json:"store_name"`
+	Subtotal  float64         `json:"subtotal"`
+	Items     []PricedItemDTO `json:"items"`
+}
+
+// CheapestSplitDTO buys every item at the store where it is cheapest.
+type CheapestSplitDTO struct {
+	KnownTotal     float64              `json:"known_total"`
+	EstimatedTotal float64              `json:"estimated_total"`
+	Savings        float64              `json:"savings"`
+	Stores         []PriceSplitStoreDTO `json:"stores"`
+}
+
+type PriceComparisonResponseDTO struct {
+	ShoppingListID  string                `json:"shopping_list_id"`
+	Stores          []StorePriceTotalDTO  `json:"stores"`
+	CheapestStoreID string                `json:"cheapest_store_id,omitempty"`
+	CheapestSplit   CheapestSplitDTO      `json:"cheapest_split"`
+	UnpricedItems   []MissingPriceItemDTO `json:"unpriced_items"`
+}
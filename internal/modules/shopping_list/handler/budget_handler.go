@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type BudgetHandler struct {
+	budgetService domain.BudgetService
+}
+
+func NewBudgetHandler(budgetService domain.BudgetService) *BudgetHandler {
+	return &BudgetHandler{budgetService: budgetService}
+}
+
+// GetLedger godoc
+// @Summary Monthly budget ledger
+// @Description Spending of a month against the user's preferred budget or, with pantry_id, the pantry budget, with remaining amount and month-end forecast
+// @Tags budget
+// @Produce json
+// @Param month query string false "Month as YYYY-MM (default current month)"
+// @Param pantry_id query string false "Pantry ID"
+// @Success 200 {object} response.APIResponse{data=dto.BudgetLedgerDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /budgets/ledger [get]
+// @Security BearerAuth
+func (h *BudgetHandler) GetLedger(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	var pantryID *uuid.UUID
+	if raw := c.Query("pantry_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid pantry ID")
+			return
+		}
+		pantryID = &parsed
+	}
+
+	ledger, err := h.budgetService.GetLedger(c.Request.Context(), userUUID, pantryID, c.Query("month"))
+	if err != nil {
+		writeBudgetError(c, err, "Failed to fetch budget ledger")
+		return
+	}
+
+	response.OK(c, ledger)
+}
+
+// SetPantryBudget godoc
+// @Summary Set pantry budget
+// @Description Set the monthly grocery budget shared by the members of a pantry. Only the owner can change it
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Param pantryId path string true "Pantry ID"
+// @Param budget body dto.SetPantryBudgetDTO true "Monthly budget"
+// @Success 200 {object} response.APIResponse{data=dto.PantryBudgetDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /budgets/pantries/{pantryId} [put]
+// @Security BearerAuth
+func (h *BudgetHandler) SetPantryBudget(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.SetPantryBudgetDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid pantry budget request",
+			zap.String(appLogger.FieldModule, "budget"),
+			zap.String(appLogger.FieldFunction, "SetPantryBudget"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	pantryID, err := uuid.Parse(c.Param("pantryId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid pantry ID")
+		return
+	}
+
+	budget, err := h.budgetService.SetPantryBudget(c.Request.Context(), userUUID, pantryID, input)
+	if err != nil {
+		writeBudgetError(c, err, "Failed to update pantry budget")
+		return
+	}
+
+	response.OK(c, budget)
+}
+
+func writeBudgetError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMonth):
+		response.BadRequest(c, "Invalid month; use YYYY-MM")
+	case errors.Is(err, domain.ErrPantryNotFound):
+		response.Fail(c, http.StatusNotFound, "PANTRY_NOT_FOUND", "Pantry not found")
+	case errors.Is(err, domain.ErrPantryAccessDenied):
+		response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this pantry")
+	default:
+		response.InternalError(c, fallback)
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type ParLevelHandler struct {
+	parLevelService domain.ParLevelService
+}
+
+func NewParLevelHandler(parLevelService domain.ParLevelService) *ParLevelHandler {
+	return &ParLevelHandler{parLevelService: parLevelService}
+}
+
+// ListParLevels godoc
+// @Summary List par levels
+// @Description List the stock a pantry wants to keep, used by the rule based shopping list generator
+// @Tags par-level
+// @Produce json
+// @Param pantryId path string true "Pantry ID"
+// @Success 200 {object} response.APIResponse{data=[]dto.ParLevelDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /par-levels/pantries/{pantryId} [get]
+// @Security BearerAuth
+func (h *ParLevelHandler) ListParLevels(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	pantryID, err := uuid.Parse(c.Param("pantryId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid pantry ID")
+		return
+	}
+
+	levels, err := h.parLevelService.ListParLevels(c.Request.Context(), userUUID, pantryID)
+	if err != nil {
+		writeParLevelError(c, err, "Failed to fetch par levels")
+		return
+	}
+
+	response.OK(c, levels)
+}
+
+// SetParLevels godoc
+// @Summary Set par levels
+// @Description Replace the par levels of a pantry. Per person levels are multiplied by the household size
+// @Tags par-level
+// @Accept json
+// @Produce json
+// @Param pantryId path string true "Pantry ID"
+// @Param levels body dto.SetParLevelsDTO true "Par levels"
+// @Success 200 {object} response.APIResponse{data=[]dto.ParLevelDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /par-levels/pantries/{pantryId} [put]
+// @Security BearerAuth
+func (h *ParLevelHandler) SetParLevels(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.SetParLevelsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid par levels request",
+			zap.String(appLogger.FieldModule, "par_level"),
+			zap.String(appLogger.FieldFunction, "SetParLevels"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	pantryID, err := uuid.Parse(c.Param("pantryId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid pantry ID")
+		return
+	}
+
+	levels, err := h.parLevelService.SetParLevels(c.Request.Context(), userUUID, pantryID, input)
+	if err != nil {
+		writeParLevelError(c, err, "Failed to update par levels")
+		return
+	}
+
+	response.OK(c, levels)
+}
+
+func writeParLevelError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrPantryNotFound):
+		response.Fail(c, http.StatusNotFound, "PANTRY_NOT_FOUND", "Pantry not found")
+	case errors.Is(err, domain.ErrPantryAccessDenied):
+		response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this pantry")
+	default:
+		response.InternalError(c, fallback)
+	}
+}
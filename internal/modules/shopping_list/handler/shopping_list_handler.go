@@ -2,10 +2,13 @@ package handler
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	creditsDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/credits/domain"
 	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
@@ -15,6 +18,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxReceiptSize bounds uploaded receipt files; NFC-e XMLs are a few KB.
+const maxReceiptSize = 2 << 20
+
 type ShoppingListHandler struct {
 	shoppingListService domain.ShoppingListService
 	creditService       creditsDomain.CreditService
@@ -70,6 +76,8 @@ func (h *ShoppingListHandler) CreateShoppingList(c *gin.Context) {
 			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this pantry")
 		case errors.Is(err, domain.ErrPantryNotFound):
 			response.Fail(c, http.StatusNotFound, "PANTRY_NOT_FOUND", "Pantry not found")
+		case errors.Is(err, domain.ErrStoreNotFound):
+			response.Fail(c, http.StatusNotFound, "STORE_NOT_FOUND", "Store not found")
 		default:
 			response.InternalError(c, "Failed to create shopping list")
 		}
@@ -276,6 +284,8 @@ func (h *ShoppingListHandler) UpdateShoppingList(c *gin.Context) {
 			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
 		case errors.Is(err, domain.ErrUnauthorized):
 			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+		case errors.Is(err, domain.ErrStoreNotFound):
+			response.Fail(c, http.StatusNotFound, "STORE_NOT_FOUND", "Store not found")
 		default:
 			response.InternalError(c, "Failed to update shopping list")
 		}
@@ -603,9 +613,19 @@ func (h *ShoppingListHandler) DeleteShoppingListItem(c *gin.Context) {
 	response.OK(c, gin.H{"message": "Shopping list item deleted successfully"})
 }
 
+// IsRuleBasedGeneration tells the credit guard that a generation request asks
+// for the free rule based generator. The body stays available to the handler.
+func IsRuleBasedGeneration(c *gin.Context) bool {
+	var input dto.GenerateAIShoppingListDTO
+	if err := c.ShouldBindBodyWith(&input, binding.JSON); err != nil {
+		return false
+	}
+	return input.Mode == "rules"
+}
+
 // GenerateAIShoppingList godoc
 // @Summary Generate AI shopping list
-// @Description Generate a shopping list using AI based on user profile and specific pantry history
+// @Description Generate a shopping list for a pantry. The "ai" mode (default) uses the LLM and costs one credit; the "rules" mode builds the list from pantry stock, par levels, expiring items and purchase frequency for free. When the LLM fails the rules are used instead and no credit is charged
 // @Tags shopping-list
 // @Accept json
 // @Produce json
@@ -613,6 +633,7 @@ func (h *ShoppingListHandler) DeleteShoppingListItem(c *gin.Context) {
 // @Success 201 {object} response.APIResponse{data=dto.ShoppingListResponseDTO}
 // @Failure 400 {object} response.APIResponse
 // @Failure 401 {object} response.APIResponse
+// @Failure 402 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /shopping-lists/generate [post]
@@ -621,7 +642,7 @@ func (h *ShoppingListHandler) GenerateAIShoppingList(c *gin.Context) {
 	logger := appLogger.FromContext(c.Request.Context())
 
 	var input dto.GenerateAIShoppingListDTO
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := c.ShouldBindBodyWith(&input, binding.JSON); err != nil {
 		logger.Warn("Invalid AI shopping list generation request",
 			zap.String(appLogger.FieldModule, "shopping_list"),
 			zap.String(appLogger.FieldFunction, "GenerateAIShoppingList"),
@@ -661,6 +682,17 @@ func (h *ShoppingListHandler) GenerateAIShoppingList(c *gin.Context) {
 		return
 	}
 
+	if shoppingList.GeneratedBy != "ai" {
+		logger.Info("Shopping list generated by rules",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "GenerateAIShoppingList"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("mode", input.Mode),
+		)
+		response.Success(c, http.StatusCreated, shoppingList)
+		return
+	}
+
 	if creditErr := h.creditService.ConsumeCredit(c.Request.Context(), userUUID, "AI request - shopping_list generation"); creditErr != nil {
 		switch {
 		case errors.Is(creditErr, creditsDomain.ErrInsufficientCredits):
@@ -690,3 +722,656 @@ func (h *ShoppingListHandler) GenerateAIShoppingList(c *gin.Context) {
 
 	response.Success(c, http.StatusCreated, shoppingList)
 }
+
+// ImportReceipt godoc
+// @Summary Import NFC-e receipt
+// @Description Import an NFC-e (XML, SEFAZ consultation text or QR code content) into a shopping list. Lines are matched to list or pantry items, prices and quantities are updated, items are marked purchased and checkout runs unless disabled
+// @Tags shopping-list
+// @Accept json
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Param receipt body dto.ImportReceiptDTO false "Receipt content"
+// @Param file formData file false "Receipt file (XML or text)"
+// @Success 200 {object} response.APIResponse{data=dto.ReceiptImportResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 422 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/receipt [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) ImportReceipt(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.ImportReceiptDTO
+	if c.ContentType() == "multipart/form-data" {
+		if err := c.ShouldBind(&input); err != nil {
+			response.BadRequest(c, "Invalid input: "+err.Error())
+			return
+		}
+		if fileHeader, err := c.FormFile("file"); err == nil {
+			file, err := fileHeader.Open()
+			if err != nil {
+				response.BadRequest(c, "Invalid receipt file")
+				return
+			}
+			defer file.Close()
+			content, err := io.ReadAll(io.LimitReader(file, maxReceiptSize))
+			if err != nil {
+				response.BadRequest(c, "Invalid receipt file")
+				return
+			}
+			input.Content = string(content)
+		}
+	} else if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid receipt import request",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "ImportReceipt"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(input.Content) == "" {
+		response.BadRequest(c, "Receipt content is required")
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	result, err := h.shoppingListService.ImportReceipt(c.Request.Context(), userUUID, shoppingListID, input)
+	if err != nil {
+		logger.Error("Failed to import receipt",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "ImportReceipt"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		switch {
+		case errors.Is(err, domain.ErrShoppingListNotFound):
+			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+		case errors.Is(err, domain.ErrUnauthorized):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+		case errors.Is(err, domain.ErrReceiptInvalid):
+			response.Fail(c, http.StatusBadRequest, "INVALID_RECEIPT", "Could not read the receipt")
+		case errors.Is(err, domain.ErrReceiptWithoutItems):
+			response.Fail(c, http.StatusUnprocessableEntity, "RECEIPT_WITHOUT_ITEMS", "The QR code only identifies the receipt; upload the XML or the consultation page text")
+		case errors.Is(err, domain.ErrInvalidTransition):
+			response.Fail(c, http.StatusConflict, "INVALID_STATUS_TRANSITION", err.Error())
+		case errors.Is(err, domain.ErrAlreadyCheckedOut):
+			response.Fail(c, http.StatusConflict, "ALREADY_CHECKED_OUT", "Revert the checkout of the shopping list before importing a receipt")
+		default:
+			response.InternalError(c, "Failed to import receipt")
+		}
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// ListCheckouts godoc
+// @Summary List checkouts
+// @Description List the checkouts of a shopping list with the pantry changes each one made, newest first
+// @Tags shopping-list
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Success 200 {object} response.APIResponse{data=[]dto.CheckoutDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/checkouts [get]
+// @Security BearerAuth
+func (h *ShoppingListHandler) ListCheckouts(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	checkouts, err := h.shoppingListService.ListCheckouts(c.Request.Context(), userUUID, shoppingListID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrShoppingListNotFound):
+			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+		case errors.Is(err, domain.ErrUnauthorized):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+		default:
+			response.InternalError(c, "Failed to fetch checkouts")
+		}
+		return
+	}
+
+	response.OK(c, checkouts)
+}
+
+// RevertCheckout godoc
+// @Summary Revert checkout
+// @Description Roll back the pantry changes of the active checkout in one transaction and move the list back to pending
+// @Tags shopping-list
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Success 200 {object} response.APIResponse{data=dto.ShoppingListResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/checkout/revert [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) RevertCheckout(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	shoppingList, err := h.shoppingListService.RevertCheckout(c.Request.Context(), userUUID, shoppingListID)
+	if err != nil {
+		logger.Error("Failed to revert checkout",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "RevertCheckout"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		switch {
+		case errors.Is(err, domain.ErrShoppingListNotFound):
+			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+		case errors.Is(err, domain.ErrUnauthorized):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+		case errors.Is(err, domain.ErrCheckoutNotFound):
+			response.Fail(c, http.StatusNotFound, "CHECKOUT_NOT_FOUND", "Shopping list has no checkout to revert")
+		case errors.Is(err, domain.ErrInvalidTransition):
+			response.Fail(c, http.StatusConflict, "INVALID_STATUS_TRANSITION", err.Error())
+		default:
+			response.InternalError(c, "Failed to revert checkout")
+		}
+		return
+	}
+
+	response.OK(c, shoppingList)
+}
+
+// TransitionShoppingList godoc
+// @Summary Change shopping list status
+// @Description Move a shopping list to another status. Allowed transitions: draft to pending or cancelled; pending to draft, in_progress, completed or cancelled; in_progress to pending, completed or cancelled; completed to pending or archived; cancelled to pending or archived. Completing checks the list out into the pantry and moving a completed list back to pending reverts that checkout
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Param transition body dto.TransitionShoppingListDTO true "Target status"
+// @Success 200 {object} response.APIResponse{data=dto.ShoppingListResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/transitions [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) TransitionShoppingList(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.TransitionShoppingListDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid shopping list transition request",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "TransitionShoppingList"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	shoppingList, err := h.shoppingListService.TransitionShoppingList(c.Request.Context(), userUUID, shoppingListID, input)
+	if err != nil {
+		logger.Error("Failed to change shopping list status",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "TransitionShoppingList"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.String("to_status", input.Status),
+			zap.Error(err),
+		)
+		switch {
+		case errors.Is(err, domain.ErrShoppingListNotFound):
+			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+		case errors.Is(err, domain.ErrUnauthorized):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+		case errors.Is(err, domain.ErrInvalidTransition):
+			response.Fail(c, http.StatusConflict, "INVALID_STATUS_TRANSITION", err.Error())
+		case errors.Is(err, domain.ErrAlreadyCheckedOut):
+			response.Fail(c, http.StatusConflict, "ALREADY_CHECKED_OUT", "Shopping list was already checked out")
+		default:
+			response.InternalError(c, "Failed to change shopping list status")
+		}
+		return
+	}
+
+	response.OK(c, shoppingList)
+}
+
+// GetStatusHistory godoc
+// @Summary Shopping list status history
+// @Description List the status changes of a shopping list, oldest first
+// @Tags shopping-list
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Success 200 {object} response.APIResponse{data=[]dto.StatusChangeDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/transitions [get]
+// @Security BearerAuth
+func (h *ShoppingListHandler) GetStatusHistory(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	history, err := h.shoppingListService.GetStatusHistory(c.Request.Context(), userUUID, shoppingListID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrShoppingListNotFound):
+			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+		case errors.Is(err, domain.ErrUnauthorized):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+		default:
+			response.InternalError(c, "Failed to fetch status history")
+		}
+		return
+	}
+
+	response.OK(c, history)
+}
+
+// OptimizeShoppingList godoc
+// @Summary Fit a shopping list into a budget
+// @Description Reduce or remove the items of a list, lowest priority first, until its estimated cost fits the budget (default: the total budget of the list). Essential items (priority 1) are only reduced to the household need and purchased items are kept. Returns what was cut and why; set apply to save the result
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Param optimization body dto.OptimizeShoppingListDTO false "Budget and whether to apply the cuts"
+// @Success 200 {object} response.APIResponse{data=dto.BudgetOptimizationDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/optimize [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) OptimizeShoppingList(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.OptimizeShoppingListDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			logger.Warn("Invalid shopping list optimization request",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "OptimizeShoppingList"),
+				zap.Error(err),
+			)
+			response.BadRequest(c, "Invalid input: "+err.Error())
+			return
+		}
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	result, err := h.shoppingListService.OptimizeShoppingList(c.Request.Context(), userUUID, shoppingListID, input)
+	if err != nil {
+		logger.Error("Failed to optimize shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "OptimizeShoppingList"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		switch {
+		case errors.Is(err, domain.ErrShoppingListNotFound):
+			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+		case errors.Is(err, domain.ErrUnauthorized):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+		case errors.Is(err, domain.ErrBudgetRequired):
+			response.Fail(c, http.StatusBadRequest, "BUDGET_REQUIRED", "Inform a budget or set the total budget of the list")
+		default:
+			response.InternalError(c, "Failed to optimize shopping list")
+		}
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// DuplicateShoppingList godoc
+// @Summary Duplicate a shopping list
+// @Description Create a pending copy of a shopping list, optionally unmarking the purchased items and clearing the prices
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Param duplicate body dto.DuplicateShoppingListDTO false "Name and reset options"
+// @Success 201 {object} response.APIResponse{data=dto.ShoppingListResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/duplicate [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) DuplicateShoppingList(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.DuplicateShoppingListDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			logger.Warn("Invalid shopping list duplicate request",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "DuplicateShoppingList"),
+				zap.Error(err),
+			)
+			response.BadRequest(c, "Invalid input: "+err.Error())
+			return
+		}
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	shoppingList, err := h.shoppingListService.DuplicateShoppingList(c.Request.Context(), userUUID, shoppingListID, input)
+	if err != nil {
+		logger.Error("Failed to duplicate shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "DuplicateShoppingList"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		writeListOrganizerError(c, err, "Failed to duplicate shopping list")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, shoppingList)
+}
+
+// MergeShoppingLists godoc
+// @Summary Merge shopping lists
+// @Description Create a list with the items of several lists, consolidating items with the same name and unit. The merged list keeps the pantry, store and preferences of the first list and sums the budgets. The source lists are deleted unless keep_sources is set
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param merge body dto.MergeShoppingListsDTO true "Lists to merge"
+// @Success 201 {object} response.APIResponse{data=dto.ShoppingListResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/merge [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) MergeShoppingLists(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.MergeShoppingListsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid shopping list merge request",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "MergeShoppingLists"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingList, err := h.shoppingListService.MergeShoppingLists(c.Request.Context(), userUUID, input)
+	if err != nil {
+		logger.Error("Failed to merge shopping lists",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "MergeShoppingLists"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.Int(appLogger.FieldCount, len(input.ShoppingListIDs)),
+			zap.Error(err),
+		)
+		writeListOrganizerError(c, err, "Failed to merge shopping lists")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, shoppingList)
+}
+
+// MoveShoppingListItems godoc
+// @Summary Move items to another shopping list
+// @Description Move the selected items to another list of the user. Items with the same name and unit as a line of the target list are added to that line
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param id path string true "Source shopping list ID"
+// @Param move body dto.MoveShoppingListItemsDTO true "Target list and items"
+// @Success 200 {object} response.APIResponse{data=dto.MoveShoppingListItemsResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/items/move [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) MoveShoppingListItems(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.MoveShoppingListItemsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid shopping list item move request",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "MoveShoppingListItems"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	result, err := h.shoppingListService.MoveShoppingListItems(c.Request.Context(), userUUID, shoppingListID, input)
+	if err != nil {
+		logger.Error("Failed to move shopping list items",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "MoveShoppingListItems"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.String("target_list_id", input.TargetListID.String()),
+			zap.Error(err),
+		)
+		writeListOrganizerError(c, err, "Failed to move shopping list items")
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// AddRecipeToShoppingList godoc
+// @Summary Add recipe ingredients to a shopping list
+// @Description Compare the ingredients of a saved recipe, scaled to the servings, with the pantry stock and add the missing or insufficient ones to a list. Ingredients with the same name and unit as a pending line are added to that line. Without shopping_list_id a new list named after the recipe is created
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param recipe body dto.AddRecipeToShoppingListDTO true "Recipe, servings and target list"
+// @Success 200 {object} response.APIResponse{data=dto.RecipeShoppingResultDTO}
+// @Success 201 {object} response.APIResponse{data=dto.RecipeShoppingResultDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/from-recipe [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) AddRecipeToShoppingList(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.AddRecipeToShoppingListDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid recipe shopping request",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "AddRecipeToShoppingList"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	result, err := h.shoppingListService.AddRecipeToShoppingList(c.Request.Context(), userUUID, input)
+	if err != nil {
+		logger.Error("Failed to add recipe to shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "AddRecipeToShoppingList"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("recipe_id", input.RecipeID.String()),
+			zap.Error(err),
+		)
+		switch {
+		case errors.Is(err, domain.ErrRecipeNotFound):
+			response.Fail(c, http.StatusNotFound, "RECIPE_NOT_FOUND", "Recipe not found")
+		case errors.Is(err, domain.ErrPantryAccessDenied):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this pantry")
+		default:
+			writeListOrganizerError(c, err, "Failed to add recipe to shopping list")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+	response.Success(c, status, result)
+}
+
+// GenerateFromMealPlan godoc
+// @Summary Create a shopping list from the meal plan
+// @Description Sum the ingredients of every meal planned for a pantry in the period, scaled to the planned servings, subtract the pantry stock and create one list with what is missing. Nothing is created when the pantry covers every ingredient
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param plan body dto.MealPlanShoppingListDTO true "Pantry and period"
+// @Success 200 {object} response.APIResponse{data=dto.MealPlanShoppingResultDTO}
+// @Success 201 {object} response.APIResponse{data=dto.MealPlanShoppingResultDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/from-meal-plan [post]
+// @Security BearerAuth
+func (h *ShoppingListHandler) GenerateFromMealPlan(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.MealPlanShoppingListDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid meal plan shopping request",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "GenerateFromMealPlan"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	result, err := h.shoppingListService.GenerateFromMealPlan(c.Request.Context(), userUUID, input)
+	if err != nil {
+		logger.Error("Failed to create shopping list from meal plan",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "GenerateFromMealPlan"),
+			zap.String(appLogger.FieldUserID, userUUID.String()),
+			zap.String("pantry_id", input.PantryID.String()),
+			zap.Error(err),
+		)
+		switch {
+		case errors.Is(err, domain.ErrInvalidPeriod):
+			response.BadRequest(c, strings.TrimPrefix(err.Error(), domain.ErrInvalidPeriod.Error()+": "))
+		case errors.Is(err, domain.ErrPantryAccessDenied):
+			response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this pantry")
+		default:
+			response.InternalError(c, "Failed to create shopping list from meal plan")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+	response.Success(c, status, result)
+}
+
+func writeListOrganizerError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrShoppingListNotFound):
+		response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+	case errors.Is(err, domain.ErrItemNotFound):
+		response.Fail(c, http.StatusNotFound, "ITEM_NOT_FOUND", "Item not found")
+	case errors.Is(err, domain.ErrUnauthorized):
+		response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+	case errors.Is(err, domain.ErrSameShoppingList):
+		response.BadRequest(c, "Source and target must be different shopping lists")
+	case errors.Is(err, domain.ErrShoppingListClosed):
+		response.Fail(c, http.StatusConflict, "SHOPPING_LIST_CLOSED", "Completed or archived shopping lists cannot be merged or have items moved")
+	default:
+		response.InternalError(c, fallback)
+	}
+}
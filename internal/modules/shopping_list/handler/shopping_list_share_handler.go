@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type ShoppingListShareHandler struct {
+	shareService domain.ShoppingListShareService
+}
+
+func NewShoppingListShareHandler(shareService domain.ShoppingListShareService) *ShoppingListShareHandler {
+	return &ShoppingListShareHandler{shareService: shareService}
+}
+
+// ExportShoppingList godoc
+// @Summary Export shopping list
+// @Description Download a shopping list grouped by category with estimated totals and budget
+// @Tags shopping-list
+// @Produce plain
+// @Produce text/csv
+// @Produce application/pdf
+// @Param id path string true "Shopping list ID"
+// @Param format query string false "text, whatsapp, csv or pdf (default text)"
+// @Success 200 {file} file
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/export [get]
+// @Security BearerAuth
+func (h *ShoppingListShareHandler) ExportShoppingList(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	export, err := h.shareService.ExportShoppingList(c.Request.Context(), userUUID, shoppingListID, c.Query("format"))
+	if err != nil {
+		writeShareError(c, err, "Failed to export shopping list")
+		return
+	}
+
+	writeExport(c, export)
+}
+
+// CreateShare godoc
+// @Summary Share shopping list
+// @Description Create a public read-only link to a shopping list
+// @Tags shopping-list
+// @Accept json
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Param share body dto.CreateShoppingListShareDTO false "Share options"
+// @Success 201 {object} response.APIResponse{data=dto.ShoppingListShareDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/shares [post]
+// @Security BearerAuth
+func (h *ShoppingListShareHandler) CreateShare(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.CreateShoppingListShareDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			logger.Warn("Invalid shopping list share request",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "CreateShare"),
+				zap.Error(err),
+			)
+			response.BadRequest(c, "Invalid input: "+err.Error())
+			return
+		}
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	share, err := h.shareService.CreateShare(c.Request.Context(), userUUID, shoppingListID, input)
+	if err != nil {
+		writeShareError(c, err, "Failed to share shopping list")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, share)
+}
+
+// ListShares godoc
+// @Summary List shopping list shares
+// @Description List the public links of a shopping list, including expired and revoked ones
+// @Tags shopping-list
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Success 200 {object} response.APIResponse{data=[]dto.ShoppingListShareDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/shares [get]
+// @Security BearerAuth
+func (h *ShoppingListShareHandler) ListShares(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	shares, err := h.shareService.ListShares(c.Request.Context(), userUUID, shoppingListID)
+	if err != nil {
+		writeShareError(c, err, "Failed to fetch shares")
+		return
+	}
+
+	response.OK(c, shares)
+}
+
+// RevokeShare godoc
+// @Summary Revoke shopping list share
+// @Description Revoke a public link so it stops working immediately
+// @Tags shopping-list
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Param shareId path string true "Share ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/shares/{shareId} [delete]
+// @Security BearerAuth
+func (h *ShoppingListShareHandler) RevokeShare(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	shoppingListID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+	shareID, err := uuid.Parse(c.Param("shareId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid share ID")
+		return
+	}
+
+	if err := h.shareService.RevokeShare(c.Request.Context(), userUUID, shoppingListID, shareID); err != nil {
+		writeShareError(c, err, "Failed to revoke share")
+		return
+	}
+
+	response.OK(c, gin.H{"message": "Share revoked successfully"})
+}
+
+// GetSharedShoppingList godoc
+// @Summary Get shared shopping list
+// @Description Read-only view of a shopping list through a public link. No authentication required
+// @Tags public
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} response.APIResponse{data=dto.SharedShoppingListDTO}
+// @Failure 404 {object} response.APIResponse
+// @Failure 410 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /public/shopping-lists/{token} [get]
+func (h *ShoppingListShareHandler) GetSharedShoppingList(c *gin.Context) {
+	shoppingList, err := h.shareService.GetSharedShoppingList(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeShareError(c, err, "Failed to fetch shared shopping list")
+		return
+	}
+
+	response.OK(c, shoppingList)
+}
+
+// ExportSharedShoppingList godoc
+// @Summary Export shared shopping list
+// @Description Download a shared shopping list. No authentication required
+// @Tags public
+// @Produce plain
+// @Produce text/csv
+// @Produce application/pdf
+// @Param token path string true "Share token"
+// @Param format query string false "text, whatsapp, csv or pdf (default text)"
+// @Success 200 {file} file
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 410 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /public/shopping-lists/{token}/export [get]
+func (h *ShoppingListShareHandler) ExportSharedShoppingList(c *gin.Context) {
+	export, err := h.shareService.ExportSharedShoppingList(c.Request.Context(), c.Param("token"), c.Query("format"))
+	if err != nil {
+		writeShareError(c, err, "Failed to export shared shopping list")
+		return
+	}
+
+	writeExport(c, export)
+}
+
+func writeExport(c *gin.Context, export *dto.ShoppingListExportDTO) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+	c.Data(http.StatusOK, export.ContentType, export.Content)
+}
+
+func writeShareError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrShoppingListNotFound):
+		response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+	case errors.Is(err, domain.ErrUnauthorized):
+		response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this shopping list")
+	case errors.Is(err, domain.ErrShareNotFound):
+		response.Fail(c, http.StatusNotFound, "SHARE_NOT_FOUND", "Shared shopping list not found")
+	case errors.Is(err, domain.ErrShareExpired):
+		response.Fail(c, http.StatusGone, "SHARE_EXPIRED", "This link has expired")
+	case errors.Is(err, domain.ErrUnsupportedFormat):
+		response.BadRequest(c, "Unsupported format; use text, whatsapp, csv or pdf")
+	default:
+		response.InternalError(c, fallback)
+	}
+}
@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type StoreHandler struct {
+	storeService domain.StoreService
+}
+
+func NewStoreHandler(storeService domain.StoreService) *StoreHandler {
+	return &StoreHandler{storeService: storeService}
+}
+
+// CreateStore godoc
+// @Summary Create store
+// @Description Create a store with its sections listed in walking order
+// @Tags store
+// @Accept json
+// @Produce json
+// @Param store body dto.CreateStoreDTO true "Store data"
+// @Success 201 {object} response.APIResponse{data=dto.StoreResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /stores [post]
+// @Security BearerAuth
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.CreateStoreDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid store creation request",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "CreateStore"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	store, err := h.storeService.CreateStore(c.Request.Context(), userUUID, input)
+	if err != nil {
+		response.InternalError(c, "Failed to create store")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, store)
+}
+
+// ListStores godoc
+// @Summary List stores
+// @Description List the stores of the authenticated user
+// @Tags store
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=[]dto.StoreResponseDTO}
+// @Failure 401 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /stores [get]
+// @Security BearerAuth
+func (h *StoreHandler) ListStores(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	stores, err := h.storeService.ListStores(c.Request.Context(), userUUID)
+	if err != nil {
+		response.InternalError(c, "Failed to fetch stores")
+		return
+	}
+
+	response.OK(c, stores)
+}
+
+// GetStore godoc
+// @Summary Get store by ID
+// @Description Get a store with its sections
+// @Tags store
+// @Produce json
+// @Param id path string true "Store ID"
+// @Success 200 {object} response.APIResponse{data=dto.StoreResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /stores/{id} [get]
+// @Security BearerAuth
+func (h *StoreHandler) GetStore(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	storeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid store ID")
+		return
+	}
+
+	store, err := h.storeService.GetStore(c.Request.Context(), userUUID, storeID)
+	if err != nil {
+		writeStoreError(c, err, "Failed to fetch store")
+		return
+	}
+
+	response.OK(c, store)
+}
+
+// UpdateStore godoc
+// @Summary Update store
+// @Description Update a store. Sending sections replaces the layout, keeping the learned order of sections with the same name
+// @Tags store
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID"
+// @Param store body dto.UpdateStoreDTO true "Store update data"
+// @Success 200 {object} response.APIResponse{data=dto.StoreResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /stores/{id} [put]
+// @Security BearerAuth
+func (h *StoreHandler) UpdateStore(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.UpdateStoreDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid store update request",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "UpdateStore"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	storeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid store ID")
+		return
+	}
+
+	store, err := h.storeService.UpdateStore(c.Request.Context(), userUUID, storeID, input)
+	if err != nil {
+		writeStoreError(c, err, "Failed to update store")
+		return
+	}
+
+	response.OK(c, store)
+}
+
+// DeleteStore godoc
+// @Summary Delete store
+// @Description Delete a store and detach it from shopping lists
+// @Tags store
+// @Produce json
+// @Param id path string true "Store ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /stores/{id} [delete]
+// @Security BearerAuth
+func (h *StoreHandler) DeleteStore(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	storeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid store ID")
+		return
+	}
+
+	if err := h.storeService.DeleteStore(c.Request.Context(), userUUID, storeID); err != nil {
+		writeStoreError(c, err, "Failed to delete store")
+		return
+	}
+
+	response.OK(c, gin.H{"message": "Store deleted successfully"})
+}
+
+// RecordPrices godoc
+// @Summary Record store prices
+// @Description Record prices seen at a store. Each price refers to one unit of the given unit
+// @Tags store
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID"
+// @Param prices body dto.RecordStorePricesDTO true "Observed prices"
+// @Success 201 {object} response.APIResponse{data=[]dto.StorePriceResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /stores/{id}/prices [post]
+// @Security BearerAuth
+func (h *StoreHandler) RecordPrices(c *gin.Context) {
+	logger := appLogger.FromContext(c.Request.Context())
+
+	var input dto.RecordStorePricesDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Warn("Invalid store prices request",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "RecordPrices"),
+			zap.Error(err),
+		)
+		response.BadRequest(c, "Invalid input: "+err.Error())
+		return
+	}
+
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	storeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid store ID")
+		return
+	}
+
+	prices, err := h.storeService.RecordPrices(c.Request.Context(), userUUID, storeID, input)
+	if err != nil {
+		writeStoreError(c, err, "Failed to record prices")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, prices)
+}
+
+// ListPrices godoc
+// @Summary List store prices
+// @Description List the latest known price of each product at a store
+// @Tags store
+// @Produce json
+// @Param id path string true "Store ID"
+// @Success 200 {object} response.APIResponse{data=[]dto.StorePriceResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /stores/{id}/prices [get]
+// @Security BearerAuth
+func (h *StoreHandler) ListPrices(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	storeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid store ID")
+		return
+	}
+
+	prices, err := h.storeService.ListPrices(c.Request.Context(), userUUID, storeID)
+	if err != nil {
+		writeStoreError(c, err, "Failed to fetch prices")
+		return
+	}
+
+	response.OK(c, prices)
+}
+
+// ComparePrices godoc
+// @Summary Compare shopping list prices between stores
+// @Description Estimate the cost of a shopping list at each store from recorded prices, the cheapest split between stores, and items without price data
+// @Tags shopping-list
+// @Produce json
+// @Param id path string true "Shopping list ID"
+// @Param store_ids query string false "Comma separated store IDs (defaults to all stores of the user)"
+// @Success 200 {object} response.APIResponse{data=dto.PriceComparisonResponseDTO}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /shopping-lists/{id}/price-comparison [get]
+// @Security BearerAuth
+func (h *StoreHandler) ComparePrices(c *gin.Context) {
+	rawID, _ := c.Get("userID")
+	userUUID := rawID.(uuid.UUID)
+
+	listID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid shopping list ID")
+		return
+	}
+
+	var storeIDs []uuid.UUID
+	if raw := strings.TrimSpace(c.Query("store_ids")); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			storeID, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				response.BadRequest(c, "Invalid store ID")
+				return
+			}
+			storeIDs = append(storeIDs, storeID)
+		}
+	}
+
+	comparison, err := h.storeService.ComparePrices(c.Request.Context(), userUUID, listID, storeIDs)
+	if err != nil {
+		if errors.Is(err, domain.ErrShoppingListNotFound) {
+			response.Fail(c, http.StatusNotFound, "SHOPPING_LIST_NOT_FOUND", "Shopping list not found")
+			return
+		}
+		writeStoreError(c, err, "Failed to compare prices")
+		return
+	}
+
+	response.OK(c, comparison)
+}
+
+func writeStoreError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrStoreNotFound):
+		response.Fail(c, http.StatusNotFound, "STORE_NOT_FOUND", "Store not found")
+	case errors.Is(err, domain.ErrUnauthorized):
+		response.Fail(c, http.StatusForbidden, "FORBIDDEN", "Access denied to this store")
+	default:
+		response.InternalError(c, fallback)
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PantryBudget is the monthly grocery budget shared by the members of a
+// pantry. Personal budgets come from Profile.PreferredBudget.
+type PantryBudget struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	PantryID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"pantry_id"`
+	MonthlyAmount float64   `gorm:"type:numeric;not null" json:"monthly_amount"`
+	UpdatedBy     uuid.UUID `gorm:"type:uuid;not null" json:"updated_by"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (b *PantryBudget) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"b": b, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*PantryBudget.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*PantryBudget.BeforeCreate"), zap.Any("params", __logParams))
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return
+}
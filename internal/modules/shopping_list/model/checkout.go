@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	CheckoutActionIncrement = "increment"
+	CheckoutActionCreate    = "create"
+)
+
+// ShoppingListCheckout records a checkout of a shopping list together with
+// the exact pantry changes it made, so it can be reverted. A list has at most
+// one checkout that is not reverted.
+type ShoppingListCheckout struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
+	ShoppingListID uuid.UUID        `gorm:"type:uuid;not null;index" json:"shopping_list_id"`
+	UserID         uuid.UUID        `gorm:"type:uuid;not null" json:"user_id"`
+	PantryID       *uuid.UUID       `gorm:"type:uuid;index" json:"pantry_id"`
+	ActualCost     float64          `gorm:"type:numeric" json:"actual_cost"`
+	Changes        []CheckoutChange `gorm:"foreignKey:CheckoutID" json:"changes"`
+	RevertedAt     *time.Time       `json:"reverted_at"`
+	RevertedBy     *uuid.UUID       `gorm:"type:uuid" json:"reverted_by"`
+	CreatedAt      time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CheckoutChange is the change made to one pantry item by a checkout: either
+// Quantity added to an existing item or a new item created with Quantity.
+// The previous price and unit are kept to restore them on revert.
+type CheckoutChange struct {
+	ID                   uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	CheckoutID           uuid.UUID `gorm:"type:uuid;not null;index" json:"checkout_id"`
+	ShoppingListItemID   uuid.UUID `gorm:"type:uuid;not null" json:"shopping_list_item_id"`
+	PantryItemID         uuid.UUID `gorm:"type:uuid;not null" json:"pantry_item_id"`
+	Action               string    `gorm:"not null" json:"action"` // increment, create
+	Name                 string    `gorm:"not null" json:"name"`
+	Quantity             float64   `gorm:"not null" json:"quantity"`
+	Unit                 string    `json:"unit"`
+	PricePerUnit         float64   `gorm:"type:numeric" json:"price_per_unit"`
+	PreviousUnit         string    `json:"previous_unit"`
+	PreviousPricePerUnit float64   `gorm:"type:numeric" json:"previous_price_per_unit"`
+	CreatedAt            time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (c *ShoppingListCheckout) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"c": c, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*ShoppingListCheckout.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*ShoppingListCheckout.BeforeCreate"), zap.Any("params", __logParams))
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+func (c *CheckoutChange) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"c": c, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*CheckoutChange.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*CheckoutChange.BeforeCreate"), zap.Any("params", __logParams))
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ParLevel is the quantity of a product a pantry wants to keep in stock. With
+// PerPerson the quantity is multiplied by the household size.
+type ParLevel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	PantryID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_par_level_pantry_name,priority:1" json:"pantry_id"`
+	Name           string    `gorm:"not null" json:"name"`
+	NormalizedName string    `gorm:"not null;uniqueIndex:idx_par_level_pantry_name,priority:2" json:"normalized_name"`
+	Quantity       float64   `gorm:"not null" json:"quantity"`
+	Unit           string    `gorm:"not null" json:"unit"`
+	PerPerson      bool      `gorm:"default:false" json:"per_person"`
+	Category       string    `json:"category"`
+	UpdatedBy      uuid.UUID `gorm:"type:uuid;not null" json:"updated_by"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (p *ParLevel) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"p": p, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*ParLevel.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*ParLevel.BeforeCreate"), zap.Any("params", __logParams))
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
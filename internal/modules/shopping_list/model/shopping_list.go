@@ -57,8 +57,9 @@ type ShoppingList struct {
 	ID                  uuid.UUID          `gorm:"type:uuid;primary_key" json:"id"`
 	UserID              uuid.UUID          `gorm:"type:uuid;not null;index:idx_shopping_list_user,priority:1" json:"user_id"`
 	PantryID            *uuid.UUID         `gorm:"type:uuid;index" json:"pantry_id"`
+	StoreID             *uuid.UUID         `gorm:"type:uuid;index" json:"store_id"`
 	Name                string             `gorm:"not null" json:"name"`
-	Status              string             `gorm:"default:'pending';index" json:"status"` // pending, completed, cancelled
+	Status              string             `gorm:"default:'pending';index" json:"status"` // draft, pending, in_progress, completed, cancelled, archived
 	TotalBudget         float64            `gorm:"type:numeric" json:"total_budget"`
 	EstimatedCost       float64            `gorm:"type:numeric" json:"estimated_cost"`
 	ActualCost          float64            `gorm:"type:numeric" json:"actual_cost"`
@@ -66,6 +67,7 @@ type ShoppingList struct {
 	HouseholdSize       int                `gorm:"default:1" json:"household_size"`
 	MonthlyIncome       float64            `gorm:"type:numeric" json:"monthly_income"`
 	DietaryRestrictions StringArray        `gorm:"type:text" json:"dietary_restrictions"`
+	CompletedAt         *time.Time         `gorm:"index" json:"completed_at"`
 	Items               []ShoppingListItem `gorm:"foreignKey:ShoppingListID" json:"items"`
 	CreatedAt           time.Time          `gorm:"autoCreateTime;index:idx_shopping_list_user,priority:2" json:"created_at"`
 	UpdatedAt           time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
@@ -83,8 +85,10 @@ type ShoppingListItem struct {
 	Category       string         `json:"category"`
 	Priority       int            `gorm:"default:3" json:"priority"` // 1=high, 2=medium, 3=low
 	Purchased      bool           `gorm:"default:false;index:idx_shopping_item_list,priority:2" json:"purchased"`
-	Source         string         `json:"source"` // pantry_history, ai_suggestion, manual
+	Source         string         `json:"source"` // pantry_history, ai_suggestion, manual, receipt, recipe
 	PantryItemID   *uuid.UUID     `gorm:"type:uuid;index" json:"pantry_item_id"`
+	RecipeID       *uuid.UUID     `gorm:"type:uuid;index" json:"recipe_id"`
+	PurchasedAt    *time.Time     `json:"purchased_at"`
 	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ShoppingListShare is a public read-only link to a shopping list. The link
+// stops working once it expires or is revoked.
+type ShoppingListShare struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	ShoppingListID uuid.UUID  `gorm:"type:uuid;not null;index" json:"shopping_list_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Token          string     `gorm:"not null;uniqueIndex" json:"token"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (s *ShoppingListShare) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"s": s, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*ShoppingListShare.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*ShoppingListShare.BeforeCreate"), zap.Any("params", __logParams))
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// Active reports whether the link can still be used.
+func (s *ShoppingListShare) Active(now time.Time) (result0 bool) {
+	__logParams := map[string]any{"s": s, "now": now}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*ShoppingListShare.Active"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*ShoppingListShare.Active"), zap.Any("params", __logParams))
+	result0 = s.RevokedAt == nil && now.Before(s.ExpiresAt)
+	return
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	StatusDraft      = "draft"
+	StatusPending    = "pending"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusCancelled  = "cancelled"
+	StatusArchived   = "archived"
+)
+
+// ShoppingListStatusChange records one status transition of a shopping list.
+// The first entry of a list has an empty FromStatus.
+type ShoppingListStatusChange struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	ShoppingListID uuid.UUID `gorm:"type:uuid;not null;index" json:"shopping_list_id"`
+	FromStatus     string    `json:"from_status"`
+	ToStatus       string    `gorm:"not null" json:"to_status"`
+	ChangedBy      uuid.UUID `gorm:"type:uuid;not null" json:"changed_by"`
+	Note           string    `json:"note"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (c *ShoppingListStatusChange) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"c": c, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*ShoppingListStatusChange.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*ShoppingListStatusChange.BeforeCreate"), zap.Any("params", __logParams))
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Store is a supermarket configured by the user with its own walking order.
+type Store struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name      string         `gorm:"not null" json:"name"`
+	Address   string         `json:"address"`
+	Sections  []StoreSection `gorm:"foreignKey:StoreID" json:"sections"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// StoreSection is an aisle or area of a store. Position is the order defined
+// by the user, while LearnedPosition is refined from the order in which items
+// of the section are marked as purchased.
+type StoreSection struct {
+	ID              uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	StoreID         uuid.UUID   `gorm:"type:uuid;not null;index:idx_store_section_store,priority:1" json:"store_id"`
+	Name            string      `gorm:"not null" json:"name"`
+	Position        int         `gorm:"not null;index:idx_store_section_store,priority:2" json:"position"`
+	Categories      StringArray `gorm:"type:text" json:"categories"`
+	LearnedPosition float64     `gorm:"type:numeric;default:0" json:"learned_position"`
+	LearnedSamples  int         `gorm:"default:0" json:"learned_samples"`
+	CreatedAt       time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (s *Store) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"s": s, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*Store.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*Store.BeforeCreate"), zap.Any("params", __logParams))
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+func (s *StoreSection) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"s": s, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*StoreSection.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*StoreSection.BeforeCreate"), zap.Any("params", __logParams))
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// StorePrice is a price observed for a product at a store. Prices are kept as
+// a history of observations; comparisons use the most recent one per product.
+type StorePrice struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	StoreID        uuid.UUID  `gorm:"type:uuid;not null;index:idx_store_price_lookup,priority:1" json:"store_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name           string     `gorm:"not null" json:"name"`
+	NormalizedName string     `gorm:"not null;index:idx_store_price_lookup,priority:2" json:"normalized_name"`
+	Unit           string     `gorm:"not null" json:"unit"`
+	Price          float64    `gorm:"type:numeric;not null" json:"price"`
+	Source         string     `gorm:"not null;default:'manual'" json:"source"`
+	ShoppingListID *uuid.UUID `gorm:"type:uuid" json:"shopping_list_id,omitempty"`
+	ObservedAt     time.Time  `gorm:"not null;index" json:"observed_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (p *StorePrice) BeforeCreate(tx *gorm.DB) (err error) {
+	__logParams := map[string]any{"p": p, "tx": tx}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*StorePrice.BeforeCreate"), zap.Any("result", err), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*StorePrice.BeforeCreate"), zap.Any("params", __logParams))
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	if p.ObservedAt.IsZero() {
+		p.ObservedAt = time.Now()
+	}
+	return
+}
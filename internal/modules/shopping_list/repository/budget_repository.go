@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetRepository(db *gorm.DB) (result0 domain.BudgetRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewBudgetRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewBudgetRepository"), zap.Any("params", __logParams))
+	result0 = &budgetRepository{db: db}
+	return
+}
+
+func (r *budgetRepository) GetPantryBudget(ctx context.Context, pantryID uuid.UUID) (result0 *model.PantryBudget, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "pantryID": pantryID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*budgetRepository.GetPantryBudget"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*budgetRepository.GetPantryBudget"), zap.Any("params", __logParams))
+	var budget model.PantryBudget
+	if err := r.db.WithContext(ctx).Where("pantry_id = ?", pantryID).First(&budget).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*budgetRepository.GetPantryBudget"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &budget
+	result1 = nil
+	return
+}
+
+func (r *budgetRepository) SavePantryBudget(ctx context.Context, budget *model.PantryBudget) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "budget": budget}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*budgetRepository.SavePantryBudget"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*budgetRepository.SavePantryBudget"), zap.Any("params", __logParams))
+	if budget.ID == uuid.Nil {
+		result0 = r.db.WithContext(ctx).Create(budget).Error
+		return
+	}
+	result0 = r.db.WithContext(ctx).Save(budget).Error
+	return
+}
+
+// Lists completed before completed_at existed fall back to updated_at.
+const completedAtColumn = "COALESCE(completed_at, updated_at)"
+
+func (r *budgetRepository) ListCompletedByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) (result0 []*model.ShoppingList, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "userID": userID, "from": from, "to": to}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*budgetRepository.ListCompletedByUser"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*budgetRepository.ListCompletedByUser"), zap.Any("params", __logParams))
+	var lists []*model.ShoppingList
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, model.StatusCompleted).
+		Where(completedAtColumn+" >= ? AND "+completedAtColumn+" < ?", from, to).
+		Order(completedAtColumn + " ASC").
+		Find(&lists).Error
+	result0 = lists
+	result1 = err
+	return
+}
+
+func (r *budgetRepository) ListCompletedByPantry(ctx context.Context, pantryID uuid.UUID, from, to time.Time) (result0 []*model.ShoppingList, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "pantryID": pantryID, "from": from, "to": to}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*budgetRepository.ListCompletedByPantry"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*budgetRepository.ListCompletedByPantry"), zap.Any("params", __logParams))
+	var lists []*model.ShoppingList
+	err := r.db.WithContext(ctx).
+		Where("pantry_id = ? AND status = ?", pantryID, model.StatusCompleted).
+		Where(completedAtColumn+" >= ? AND "+completedAtColumn+" < ?", from, to).
+		Order(completedAtColumn + " ASC").
+		Find(&lists).Error
+	result0 = lists
+	result1 = err
+	return
+}
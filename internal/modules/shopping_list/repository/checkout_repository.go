@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type checkoutRepository struct {
+	db *gorm.DB
+}
+
+func NewCheckoutRepository(db *gorm.DB) (result0 domain.CheckoutRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewCheckoutRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewCheckoutRepository"), zap.Any("params", __logParams))
+	result0 = &checkoutRepository{db: db}
+	return
+}
+
+func (r *checkoutRepository) GetActive(ctx context.Context, shoppingListID uuid.UUID) (result0 *model.ShoppingListCheckout, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "shoppingListID": shoppingListID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*checkoutRepository.GetActive"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*checkoutRepository.GetActive"), zap.Any("params", __logParams))
+	var checkout model.ShoppingListCheckout
+	err := r.db.WithContext(ctx).
+		Preload("Changes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("shopping_list_id = ? AND reverted_at IS NULL", shoppingListID).
+		First(&checkout).Error
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*checkoutRepository.GetActive"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &checkout
+	result1 = nil
+	return
+}
+
+func (r *checkoutRepository) ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) (result0 []*model.ShoppingListCheckout, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "shoppingListID": shoppingListID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*checkoutRepository.ListByShoppingListID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*checkoutRepository.ListByShoppingListID"), zap.Any("params", __logParams))
+	var checkouts []*model.ShoppingListCheckout
+	err := r.db.WithContext(ctx).
+		Preload("Changes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("shopping_list_id = ?", shoppingListID).
+		Order("created_at DESC").
+		Find(&checkouts).Error
+	result0 = checkouts
+	result1 = err
+	return
+}
+
+func (r *checkoutRepository) Apply(ctx context.Context, checkout *model.ShoppingListCheckout, list *model.ShoppingList) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "checkout": checkout, "list": list}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*checkoutRepository.Apply"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*checkoutRepository.Apply"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the list so concurrent checkouts of it wait for this one and
+		// then see it as active.
+		var locked model.ShoppingList
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", checkout.ShoppingListID).
+			First(&locked).Error; err != nil {
+			return err
+		}
+
+		var active int64
+		if err := tx.Model(&model.ShoppingListCheckout{}).
+			Where("shopping_list_id = ? AND reverted_at IS NULL", checkout.ShoppingListID).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return domain.ErrAlreadyCheckedOut
+		}
+
+		for _, change := range checkout.Changes {
+			switch change.Action {
+			case model.CheckoutActionCreate:
+				if checkout.PantryID == nil {
+					return errors.New("checkout without pantry cannot create items")
+				}
+				item := &itemModel.Item{
+					ID:           change.PantryItemID,
+					PantryID:     *checkout.PantryID,
+					AddedBy:      checkout.UserID,
+					Name:         change.Name,
+					Quantity:     change.Quantity,
+					PricePerUnit: change.PricePerUnit,
+					Unit:         change.Unit,
+				}
+				if err := tx.Create(item).Error; err != nil {
+					return err
+				}
+			case model.CheckoutActionIncrement:
+				result := tx.Model(&itemModel.Item{}).
+					Where("id = ?", change.PantryItemID).
+					Updates(map[string]interface{}{
+						"quantity":       gorm.Expr("quantity + ?", change.Quantity),
+						"price_per_unit": change.PricePerUnit,
+						"unit":           change.Unit,
+					})
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					return fmt.Errorf("pantry item %s: %w", change.PantryItemID, gorm.ErrRecordNotFound)
+				}
+			default:
+				return fmt.Errorf("unknown checkout action %q", change.Action)
+			}
+		}
+
+		if err := tx.Create(checkout).Error; err != nil {
+			return err
+		}
+
+		for idx := range list.Items {
+			if !list.Items[idx].Purchased {
+				continue
+			}
+			if err := tx.Save(&list.Items[idx]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit(clause.Associations).Save(list).Error
+	})
+	return
+}
+
+// Revert undoes the changes newest first, so a pantry item changed twice ends
+// with its original price. Quantities never go below zero when the stock was
+// already consumed, and items created by the checkout are removed once empty.
+func (r *checkoutRepository) Revert(ctx context.Context, checkout *model.ShoppingListCheckout, userID uuid.UUID, at time.Time) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "checkout": checkout, "userID": userID, "at": at}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*checkoutRepository.Revert"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*checkoutRepository.Revert"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.ShoppingListCheckout{}).
+			Where("id = ? AND reverted_at IS NULL", checkout.ID).
+			Updates(map[string]interface{}{"reverted_at": at, "reverted_by": userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrCheckoutNotFound
+		}
+
+		for idx := len(checkout.Changes) - 1; idx >= 0; idx-- {
+			change := checkout.Changes[idx]
+			if err := tx.Model(&itemModel.Item{}).
+				Where("id = ?", change.PantryItemID).
+				Update("quantity", gorm.Expr("CASE WHEN quantity > ? THEN quantity - ? ELSE 0 END", change.Quantity, change.Quantity)).Error; err != nil {
+				return err
+			}
+
+			if change.Action == model.CheckoutActionCreate {
+				if err := tx.Where("id = ? AND quantity <= 0", change.PantryItemID).Delete(&itemModel.Item{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Model(&itemModel.Item{}).
+				Where("id = ? AND price_per_unit = ?", change.PantryItemID, change.PricePerUnit).
+				Update("price_per_unit", change.PreviousPricePerUnit).Error; err != nil {
+				return err
+			}
+			if change.PreviousUnit != "" && change.PreviousUnit != change.Unit {
+				if err := tx.Model(&itemModel.Item{}).
+					Where("id = ? AND unit = ?", change.PantryItemID, change.Unit).
+					Update("unit", change.PreviousUnit).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Model(&model.ShoppingList{}).
+			Where("id = ?", checkout.ShoppingListID).
+			Updates(map[string]interface{}{"status": model.StatusPending, "completed_at": nil}).Error
+	})
+	return
+}
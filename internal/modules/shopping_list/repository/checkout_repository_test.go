@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupCheckoutTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// Every connection to :memory: opens a new database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(
+		&itemModel.Item{},
+		&model.ShoppingList{},
+		&model.ShoppingListItem{},
+		&model.ShoppingListCheckout{},
+		&model.CheckoutChange{},
+	))
+
+	return db
+}
+
+func TestCheckoutRepositoryApplyAndRevert(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewCheckoutRepository(db)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	pantryID := uuid.New()
+	rice := &itemModel.Item{ID: uuid.New(), PantryID: pantryID, AddedBy: userID, Name: "Arroz", Quantity: 2, PricePerUnit: 5, Unit: "kg"}
+	require.NoError(t, db.Create(rice).Error)
+
+	list := &model.ShoppingList{ID: uuid.New(), UserID: userID, PantryID: &pantryID, Name: "Mercado", Status: "pending"}
+	require.NoError(t, db.Create(list).Error)
+	coffeeLine := model.ShoppingListItem{ID: uuid.New(), ShoppingListID: list.ID, Name: "Café", Quantity: 1, Unit: "un", EstimatedPrice: 25}
+	require.NoError(t, db.Create(&coffeeLine).Error)
+
+	coffeeID := uuid.New()
+	checkout := &model.ShoppingListCheckout{
+		ShoppingListID: list.ID,
+		UserID:         userID,
+		PantryID:       &pantryID,
+		ActualCost:     34,
+		Changes: []model.CheckoutChange{
+			{ShoppingListItemID: uuid.New(), PantryItemID: rice.ID, Action: model.CheckoutActionIncrement, Name: "Arroz", Quantity: 1, Unit: "kg", PricePerUnit: 6, PreviousUnit: "kg", PreviousPricePerUnit: 5},
+			{ShoppingListItemID: uuid.New(), PantryItemID: coffeeID, Action: model.CheckoutActionCreate, Name: "Café", Quantity: 1, Unit: "un", PricePerUnit: 28},
+		},
+	}
+	completedAt := time.Now().UTC()
+	list.Status = "completed"
+	list.CompletedAt = &completedAt
+	list.ActualCost = 34
+	coffeeLine.Purchased = true
+	coffeeLine.ActualPrice = 28
+	coffeeLine.PantryItemID = &coffeeID
+	list.Items = []model.ShoppingListItem{coffeeLine}
+	require.NoError(t, repo.Apply(ctx, checkout, list))
+
+	var completed model.ShoppingList
+	require.NoError(t, db.Preload("Items").First(&completed, "id = ?", list.ID).Error)
+	require.Equal(t, "completed", completed.Status)
+	require.NotNil(t, completed.CompletedAt)
+	require.InDelta(t, 34.0, completed.ActualCost, 1e-9)
+	require.Len(t, completed.Items, 1)
+	require.True(t, completed.Items[0].Purchased)
+	require.Equal(t, coffeeID, *completed.Items[0].PantryItemID)
+
+	var stored itemModel.Item
+	require.NoError(t, db.First(&stored, "id = ?", rice.ID).Error)
+	require.InDelta(t, 3.0, stored.Quantity, 1e-9)
+	require.InDelta(t, 6.0, stored.PricePerUnit, 1e-9)
+	var coffee itemModel.Item
+	require.NoError(t, db.First(&coffee, "id = ?", coffeeID).Error)
+	require.Equal(t, pantryID, coffee.PantryID)
+
+	again := &model.ShoppingListCheckout{ShoppingListID: list.ID, UserID: userID, PantryID: &pantryID}
+	require.True(t, errors.Is(repo.Apply(ctx, again, list), domain.ErrAlreadyCheckedOut))
+
+	active, err := repo.GetActive(ctx, list.ID)
+	require.NoError(t, err)
+	require.Len(t, active.Changes, 2)
+
+	require.NoError(t, repo.Revert(ctx, active, userID, time.Now().UTC()))
+
+	var reverted itemModel.Item
+	require.NoError(t, db.First(&reverted, "id = ?", rice.ID).Error)
+	require.InDelta(t, 2.0, reverted.Quantity, 1e-9)
+	require.InDelta(t, 5.0, reverted.PricePerUnit, 1e-9)
+	require.ErrorIs(t, db.First(&itemModel.Item{}, "id = ?", coffeeID).Error, gorm.ErrRecordNotFound)
+
+	var reloaded model.ShoppingList
+	require.NoError(t, db.First(&reloaded, "id = ?", list.ID).Error)
+	require.Equal(t, "pending", reloaded.Status)
+	require.Nil(t, reloaded.CompletedAt)
+
+	_, err = repo.GetActive(ctx, list.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	require.ErrorIs(t, repo.Revert(ctx, active, userID, time.Now().UTC()), domain.ErrCheckoutNotFound)
+
+	checkouts, err := repo.ListByShoppingListID(ctx, list.ID)
+	require.NoError(t, err)
+	require.Len(t, checkouts, 1)
+	require.NotNil(t, checkouts[0].RevertedAt)
+}
+
+func TestCheckoutRepositoryRevertKeepsConsumedStockAtZero(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewCheckoutRepository(db)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	pantryID := uuid.New()
+	milk := &itemModel.Item{ID: uuid.New(), PantryID: pantryID, AddedBy: userID, Name: "Leite", Quantity: 1, PricePerUnit: 4, Unit: "l"}
+	require.NoError(t, db.Create(milk).Error)
+	list := &model.ShoppingList{ID: uuid.New(), UserID: userID, PantryID: &pantryID, Name: "Padaria", Status: "completed"}
+	require.NoError(t, db.Create(list).Error)
+
+	checkout := &model.ShoppingListCheckout{
+		ShoppingListID: list.ID,
+		UserID:         userID,
+		PantryID:       &pantryID,
+		Changes: []model.CheckoutChange{
+			{ShoppingListItemID: uuid.New(), PantryItemID: milk.ID, Action: model.CheckoutActionIncrement, Name: "Leite", Quantity: 6, Unit: "l", PricePerUnit: 4, PreviousUnit: "l", PreviousPricePerUnit: 4},
+		},
+	}
+	require.NoError(t, repo.Apply(ctx, checkout, list))
+	require.NoError(t, db.Model(&itemModel.Item{}).Where("id = ?", milk.ID).Update("quantity", 2).Error)
+
+	require.NoError(t, repo.Revert(ctx, checkout, userID, time.Now().UTC()))
+
+	var stored itemModel.Item
+	require.NoError(t, db.First(&stored, "id = ?", milk.ID).Error)
+	require.Zero(t, stored.Quantity)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type parLevelRepository struct {
+	db *gorm.DB
+}
+
+func NewParLevelRepository(db *gorm.DB) (result0 domain.ParLevelRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewParLevelRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewParLevelRepository"), zap.Any("params", __logParams))
+	result0 = &parLevelRepository{db: db}
+	return
+}
+
+func (r *parLevelRepository) ListByPantryID(ctx context.Context, pantryID uuid.UUID) (result0 []*model.ParLevel, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "pantryID": pantryID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*parLevelRepository.ListByPantryID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*parLevelRepository.ListByPantryID"), zap.Any("params", __logParams))
+	var levels []*model.ParLevel
+	err := r.db.WithContext(ctx).
+		Where("pantry_id = ?", pantryID).
+		Order("normalized_name ASC").
+		Find(&levels).Error
+	result0 = levels
+	result1 = err
+	return
+}
+
+func (r *parLevelRepository) ReplaceForPantry(ctx context.Context, pantryID uuid.UUID, levels []*model.ParLevel) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "pantryID": pantryID, "levels": levels}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*parLevelRepository.ReplaceForPantry"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*parLevelRepository.ReplaceForPantry"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("pantry_id = ?", pantryID).Delete(&model.ParLevel{}).Error; err != nil {
+			return err
+		}
+		if len(levels) == 0 {
+			return nil
+		}
+		return tx.Create(&levels).Error
+	})
+	return
+}
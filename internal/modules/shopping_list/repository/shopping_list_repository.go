@@ -9,6 +9,7 @@ import (
 	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type shoppingListRepository struct {
@@ -174,3 +175,92 @@ func (r *shoppingListRepository) CountByUserID(ctx context.Context, userID uuid.
 	result1 = err
 	return
 }
+
+func (r *shoppingListRepository) ListPurchaseHistory(ctx context.Context, pantryID uuid.UUID, since time.Time) (result0 []*model.ShoppingList, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "pantryID": pantryID, "since": since}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListRepository.ListPurchaseHistory"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListRepository.ListPurchaseHistory"), zap.Any("params", __logParams))
+	var lists []*model.ShoppingList
+	err := r.db.WithContext(ctx).
+		Preload("Items", "purchased = ?", true).
+		Where("pantry_id = ? AND status = ?", pantryID, model.StatusCompleted).
+		Where("COALESCE(completed_at, updated_at) >= ?", since).
+		Order("COALESCE(completed_at, updated_at) ASC").
+		Find(&lists).Error
+	result0 = lists
+	result1 = err
+	return
+}
+
+func (r *shoppingListRepository) CreateMerged(ctx context.Context, merged *model.ShoppingList, deleteIDs []uuid.UUID) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "merged": merged, "deleteIDs": deleteIDs}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListRepository.CreateMerged"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListRepository.CreateMerged"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(merged).Error; err != nil {
+			return err
+		}
+		if len(deleteIDs) == 0 {
+			return nil
+		}
+		if err := tx.Where("shopping_list_id IN ?", deleteIDs).Delete(&model.ShoppingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", deleteIDs).Delete(&model.ShoppingList{}).Error
+	})
+	return
+}
+
+func (r *shoppingListRepository) MoveItems(ctx context.Context, saved []*model.ShoppingListItem, deletedIDs []uuid.UUID, lists []*model.ShoppingList) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "saved": saved, "deletedIDs": deletedIDs, "lists": lists}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListRepository.MoveItems"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListRepository.MoveItems"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range saved {
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+		}
+		if len(deletedIDs) > 0 {
+			if err := tx.Where("id IN ?", deletedIDs).Delete(&model.ShoppingListItem{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, list := range lists {
+			if err := tx.Model(&model.ShoppingList{}).
+				Where("id = ?", list.ID).
+				Updates(map[string]interface{}{"estimated_cost": list.EstimatedCost, "actual_cost": list.ActualCost}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (r *shoppingListRepository) SaveItems(ctx context.Context, list *model.ShoppingList, items []*model.ShoppingListItem) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "list": list, "items": items}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListRepository.SaveItems"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListRepository.SaveItems"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit(clause.Associations).Save(list).Error
+	})
+	return
+}
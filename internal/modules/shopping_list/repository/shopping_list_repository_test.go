@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShoppingListRepositoryCreateMergedDeletesSources(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewShoppingListRepository(db)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	first := &model.ShoppingList{UserID: userID, Name: "A", Items: []model.ShoppingListItem{{Name: "Arroz", Quantity: 1, Unit: "kg"}}}
+	second := &model.ShoppingList{UserID: userID, Name: "B", Items: []model.ShoppingListItem{{Name: "Feijão", Quantity: 1, Unit: "kg"}}}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+
+	merged := &model.ShoppingList{UserID: userID, Name: "A+B", Items: []model.ShoppingListItem{
+		{Name: "Arroz", Quantity: 1, Unit: "kg"},
+		{Name: "Feijão", Quantity: 1, Unit: "kg"},
+	}}
+	require.NoError(t, repo.CreateMerged(ctx, merged, []uuid.UUID{first.ID, second.ID}))
+
+	count, err := repo.CountByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	reloaded, err := repo.GetByID(ctx, merged.ID)
+	require.NoError(t, err)
+	require.Len(t, reloaded.Items, 2)
+
+	orphans, err := repo.GetItemsByShoppingListID(ctx, first.ID)
+	require.NoError(t, err)
+	require.Empty(t, orphans)
+}
+
+func TestShoppingListRepositoryMoveItems(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewShoppingListRepository(db)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	source := &model.ShoppingList{UserID: userID, Name: "Origem", EstimatedCost: 30, Items: []model.ShoppingListItem{
+		{Name: "Leite", Quantity: 2, Unit: "l", EstimatedPrice: 5},
+		{Name: "Pão", Quantity: 1, Unit: "un", EstimatedPrice: 20},
+	}}
+	target := &model.ShoppingList{UserID: userID, Name: "Destino", EstimatedCost: 5, Items: []model.ShoppingListItem{
+		{Name: "Leite", Quantity: 1, Unit: "l", EstimatedPrice: 5},
+	}}
+	require.NoError(t, repo.Create(ctx, source))
+	require.NoError(t, repo.Create(ctx, target))
+
+	targetMilk := target.Items[0]
+	targetMilk.Quantity = 3
+	bread := source.Items[1]
+	bread.ShoppingListID = target.ID
+	source.EstimatedCost = 0
+	target.EstimatedCost = 35
+
+	require.NoError(t, repo.MoveItems(ctx,
+		[]*model.ShoppingListItem{&targetMilk, &bread},
+		[]uuid.UUID{source.Items[0].ID},
+		[]*model.ShoppingList{source, target},
+	))
+
+	reloadedSource, err := repo.GetByID(ctx, source.ID)
+	require.NoError(t, err)
+	require.Empty(t, reloadedSource.Items)
+	require.Zero(t, reloadedSource.EstimatedCost)
+
+	reloadedTarget, err := repo.GetByID(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, reloadedTarget.Items, 2)
+	require.Equal(t, 35.0, reloadedTarget.EstimatedCost)
+}
+
+func TestShoppingListRepositorySaveItems(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewShoppingListRepository(db)
+	ctx := context.Background()
+
+	list := &model.ShoppingList{UserID: uuid.New(), Name: "Mercado", Items: []model.ShoppingListItem{
+		{Name: "Arroz", Quantity: 1, Unit: "kg", EstimatedPrice: 6},
+	}}
+	require.NoError(t, repo.Create(ctx, list))
+
+	rice := list.Items[0]
+	rice.Purchased = true
+	rice.ActualPrice = 5.5
+	bag := model.ShoppingListItem{ID: uuid.New(), ShoppingListID: list.ID, Name: "Sacola", Quantity: 1, Unit: "un", ActualPrice: 0.1, Purchased: true}
+	list.ActualCost = 5.6
+
+	require.NoError(t, repo.SaveItems(ctx, list, []*model.ShoppingListItem{&rice, &bag}))
+
+	reloaded, err := repo.GetByID(ctx, list.ID)
+	require.NoError(t, err)
+	require.Len(t, reloaded.Items, 2)
+	require.Equal(t, 5.6, reloaded.ActualCost)
+	for _, item := range reloaded.Items {
+		require.True(t, item.Purchased, item.Name)
+	}
+}
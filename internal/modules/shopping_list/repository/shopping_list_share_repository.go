@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type shoppingListShareRepository struct {
+	db *gorm.DB
+}
+
+func NewShoppingListShareRepository(db *gorm.DB) (result0 domain.ShoppingListShareRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewShoppingListShareRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewShoppingListShareRepository"), zap.Any("params", __logParams))
+	result0 = &shoppingListShareRepository{db: db}
+	return
+}
+
+func (r *shoppingListShareRepository) Create(ctx context.Context, share *model.ShoppingListShare) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "share": share}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListShareRepository.Create"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListShareRepository.Create"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Create(share).Error
+	return
+}
+
+func (r *shoppingListShareRepository) GetByID(ctx context.Context, id uuid.UUID) (result0 *model.ShoppingListShare, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "id": id}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListShareRepository.GetByID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListShareRepository.GetByID"), zap.Any("params", __logParams))
+	var share model.ShoppingListShare
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&share).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*shoppingListShareRepository.GetByID"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &share
+	result1 = nil
+	return
+}
+
+func (r *shoppingListShareRepository) GetByToken(ctx context.Context, token string) (result0 *model.ShoppingListShare, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "token": token}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListShareRepository.GetByToken"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListShareRepository.GetByToken"), zap.Any("params", __logParams))
+	var share model.ShoppingListShare
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&share).Error; err != nil {
+		zap.L().Error("function.error", zap.String("func", "*shoppingListShareRepository.GetByToken"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &share
+	result1 = nil
+	return
+}
+
+func (r *shoppingListShareRepository) ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) (result0 []*model.ShoppingListShare, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "shoppingListID": shoppingListID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListShareRepository.ListByShoppingListID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListShareRepository.ListByShoppingListID"), zap.Any("params", __logParams))
+	var shares []*model.ShoppingListShare
+	err := r.db.WithContext(ctx).
+		Where("shopping_list_id = ?", shoppingListID).
+		Order("created_at DESC").
+		Find(&shares).Error
+	result0 = shares
+	result1 = err
+	return
+}
+
+func (r *shoppingListShareRepository) Update(ctx context.Context, share *model.ShoppingListShare) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "share": share}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListShareRepository.Update"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListShareRepository.Update"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Save(share).Error
+	return
+}
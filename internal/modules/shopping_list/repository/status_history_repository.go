@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type statusHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewStatusHistoryRepository(db *gorm.DB) (result0 domain.StatusHistoryRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewStatusHistoryRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewStatusHistoryRepository"), zap.Any("params", __logParams))
+	result0 = &statusHistoryRepository{db: db}
+	return
+}
+
+func (r *statusHistoryRepository) Record(ctx context.Context, change *model.ShoppingListStatusChange) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "change": change}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*statusHistoryRepository.Record"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*statusHistoryRepository.Record"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Create(change).Error
+	return
+}
+
+func (r *statusHistoryRepository) ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) (result0 []*model.ShoppingListStatusChange, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "shoppingListID": shoppingListID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*statusHistoryRepository.ListByShoppingListID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*statusHistoryRepository.ListByShoppingListID"), zap.Any("params", __logParams))
+	var changes []*model.ShoppingListStatusChange
+	err := r.db.WithContext(ctx).
+		Where("shopping_list_id = ?", shoppingListID).
+		Order("created_at ASC").
+		Find(&changes).Error
+	result0 = changes
+	result1 = err
+	return
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type storeRepository struct {
+	db *gorm.DB
+}
+
+func NewStoreRepository(db *gorm.DB) (result0 domain.StoreRepository) {
+	__logParams := map[string]any{"db": db}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "NewStoreRepository"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "NewStoreRepository"), zap.Any("params", __logParams))
+	result0 = &storeRepository{db: db}
+	return
+}
+
+func (r *storeRepository) Create(ctx context.Context, store *model.Store) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "store": store}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.Create"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.Create"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Create(store).Error
+	return
+}
+
+func (r *storeRepository) GetByID(ctx context.Context, id uuid.UUID) (result0 *model.Store, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "id": id}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.GetByID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.GetByID"), zap.Any("params", __logParams))
+	var store model.Store
+	err := r.db.WithContext(ctx).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("id = ?", id).
+		First(&store).Error
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*storeRepository.GetByID"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = err
+		return
+	}
+	result0 = &store
+	result1 = nil
+	return
+}
+
+func (r *storeRepository) ListByUserID(ctx context.Context, userID uuid.UUID) (result0 []*model.Store, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "userID": userID}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.ListByUserID"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.ListByUserID"), zap.Any("params", __logParams))
+	var stores []*model.Store
+	err := r.db.WithContext(ctx).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&stores).Error
+	result0 = stores
+	result1 = err
+	return
+}
+
+func (r *storeRepository) Update(ctx context.Context, store *model.Store) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "store": store}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.Update"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.Update"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Omit("Sections").Save(store).Error
+	return
+}
+
+func (r *storeRepository) ReplaceSections(ctx context.Context, storeID uuid.UUID, sections []model.StoreSection) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "storeID": storeID, "sections": sections}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.ReplaceSections"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.ReplaceSections"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("store_id = ?", storeID).Delete(&model.StoreSection{}).Error; err != nil {
+			return err
+		}
+		if len(sections) == 0 {
+			return nil
+		}
+		for idx := range sections {
+			sections[idx].StoreID = storeID
+		}
+		return tx.Create(&sections).Error
+	})
+	return
+}
+
+func (r *storeRepository) UpdateSection(ctx context.Context, section *model.StoreSection) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "section": section}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.UpdateSection"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.UpdateSection"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Save(section).Error
+	return
+}
+
+func (r *storeRepository) Delete(ctx context.Context, id uuid.UUID) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "id": id}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.Delete"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.Delete"), zap.Any("params", __logParams))
+	result0 = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ShoppingList{}).Where("store_id = ?", id).Update("store_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("store_id = ?", id).Delete(&model.StoreSection{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("store_id = ?", id).Delete(&model.StorePrice{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Store{}, id).Error
+	})
+	return
+}
+
+func (r *storeRepository) RecordPrices(ctx context.Context, prices []*model.StorePrice) (result0 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "prices": prices}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.RecordPrices"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.RecordPrices"), zap.Any("params", __logParams))
+	if len(prices) == 0 {
+		result0 = nil
+		return
+	}
+	result0 = r.db.WithContext(ctx).Create(&prices).Error
+	return
+}
+
+func (r *storeRepository) ListPrices(ctx context.Context, storeIDs []uuid.UUID, limit int) (result0 []*model.StorePrice, result1 error) {
+	__logParams := map[string]any{"r": r, "ctx": ctx, "storeIDs": storeIDs, "limit": limit}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*storeRepository.ListPrices"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*storeRepository.ListPrices"), zap.Any("params", __logParams))
+	var prices []*model.StorePrice
+	if len(storeIDs) == 0 {
+		result0 = prices
+		result1 = nil
+		return
+	}
+	query := r.db.WithContext(ctx).
+		Where("store_id IN ?", storeIDs).
+		Order("observed_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&prices).Error
+	result0 = prices
+	result1 = err
+	return
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+)
+
+func TestBuildBudgetLedgerForecastsFromRunRate(t *testing.T) {
+	start := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, time.April, 3, 18, 0, 0, 0, time.UTC)
+	lists := []*shoppingModel.ShoppingList{
+		{ID: uuid.New(), Name: "Feira", ActualCost: 120, CompletedAt: &completedAt},
+		{ID: uuid.New(), Name: "Mercado", ActualCost: 180, UpdatedAt: completedAt},
+	}
+
+	ledger := buildBudgetLedger(lists, 1000, start, time.Date(2026, time.April, 10, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, "2026-04", ledger.Month)
+	require.Equal(t, budgetScopeUser, ledger.Scope)
+	require.InDelta(t, 300.0, ledger.Spent, 1e-9)
+	require.InDelta(t, 700.0, ledger.Remaining, 1e-9)
+	require.Equal(t, 10, ledger.DaysElapsed)
+	require.Equal(t, 30, ledger.DaysInMonth)
+	require.InDelta(t, 30.0, ledger.DailyRunRate, 1e-9)
+	require.InDelta(t, 900.0, ledger.Forecast, 1e-9)
+	require.False(t, ledger.OverBudget)
+	require.False(t, ledger.ForecastOverBudget)
+	require.Len(t, ledger.Lists, 2)
+	require.Equal(t, completedAt.Format(time.RFC3339), ledger.Lists[1].CompletedAt)
+
+	ledger = buildBudgetLedger(lists, 800, start, time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC))
+	require.InDelta(t, 1800.0, ledger.Forecast, 1e-9)
+	require.False(t, ledger.OverBudget)
+	require.True(t, ledger.ForecastOverBudget)
+}
+
+func TestBuildBudgetLedgerClosedAndFutureMonths(t *testing.T) {
+	start := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	lists := []*shoppingModel.ShoppingList{{ID: uuid.New(), ActualCost: 450}}
+
+	closed := buildBudgetLedger(lists, 400, start, time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, 28, closed.DaysInMonth)
+	require.Equal(t, 28, closed.DaysElapsed)
+	require.InDelta(t, 450.0, closed.Forecast, 1e-9)
+	require.InDelta(t, -50.0, closed.Remaining, 1e-9)
+	require.True(t, closed.OverBudget)
+
+	future := buildBudgetLedger(nil, 400, start, time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, 0, future.DaysElapsed)
+	require.Zero(t, future.DailyRunRate)
+	require.Zero(t, future.Forecast)
+	require.NotNil(t, future.Lists)
+}
+
+func TestBudgetWarning(t *testing.T) {
+	start := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, time.April, 10, 0, 0, 0, 0, time.UTC)
+	ledger := buildBudgetLedger([]*shoppingModel.ShoppingList{{ActualCost: 900}}, 1000, start, now)
+
+	_, ok := budgetWarning(ledger, 100)
+	require.False(t, ok)
+
+	warning, ok := budgetWarning(ledger, 150)
+	require.True(t, ok)
+	require.Equal(t, budgetScopeUser, warning.Scope)
+	require.InDelta(t, 100.0, warning.Remaining, 1e-9)
+	require.InDelta(t, 50.0, warning.Excess, 1e-9)
+	require.Contains(t, warning.Message, "R$ 150,00")
+
+	overspent := buildBudgetLedger([]*shoppingModel.ShoppingList{{ActualCost: 1100}}, 1000, start, now)
+	warning, ok = budgetWarning(overspent, 40)
+	require.True(t, ok)
+	require.InDelta(t, 40.0, warning.Excess, 1e-9)
+
+	unbudgeted := buildBudgetLedger(nil, 0, start, now)
+	_, ok = budgetWarning(unbudgeted, 5000)
+	require.False(t, ok)
+}
+
+func TestParseBudgetMonth(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 15, 0, 0, 0, time.UTC)
+
+	start, err := parseBudgetMonth("", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), start)
+
+	start, err = parseBudgetMonth("2025-12", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), start)
+
+	_, err = parseBudgetMonth("12/2025", now)
+	require.ErrorIs(t, err, domain.ErrInvalidMonth)
+}
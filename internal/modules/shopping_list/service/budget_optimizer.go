@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+)
+
+const (
+	budgetCutRemoved = "removed"
+	budgetCutReduced = "reduced"
+
+	// essentialPriority items are reduced to the household need but never
+	// removed.
+	essentialPriority = 1
+	lowestPriority    = 3
+
+	budgetEpsilon = 0.005
+)
+
+// budgetOptimization is the result of fitting a list into a budget. Items
+// keeps every item not removed, with the reduced quantities.
+type budgetOptimization struct {
+	Items         []shoppingModel.ShoppingListItem
+	Removed       []shoppingModel.ShoppingListItem
+	Cuts          []dto.BudgetCutDTO
+	OriginalCost  float64
+	OptimizedCost float64
+}
+
+func (o budgetOptimization) withinBudget(budget float64) bool {
+	return o.OptimizedCost <= budget+budgetEpsilon
+}
+
+func (s *shoppingListService) OptimizeShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.OptimizeShoppingListDTO) (*dto.BudgetOptimizationDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	shoppingList, err := s.loadOwnedList(ctx, userID, id, "OptimizeShoppingList")
+	if err != nil {
+		return nil, err
+	}
+
+	budget := shoppingList.TotalBudget
+	if input.Budget != nil {
+		budget = *input.Budget
+	}
+	if budget <= 0 {
+		return nil, domain.ErrBudgetRequired
+	}
+
+	householdSize := shoppingList.HouseholdSize
+	if householdSize <= 0 {
+		householdSize = 1
+	}
+	optimization := optimizeForBudget(shoppingList.Items, budget, householdSize)
+
+	result := &dto.BudgetOptimizationDTO{
+		Budget:        budget,
+		OriginalCost:  optimization.OriginalCost,
+		OptimizedCost: optimization.OptimizedCost,
+		WithinBudget:  optimization.withinBudget(budget),
+		Cuts:          optimization.Cuts,
+	}
+	if !input.Apply || len(optimization.Cuts) == 0 {
+		return result, nil
+	}
+
+	for _, item := range optimization.Removed {
+		if err := s.shoppingListRepo.DeleteItem(ctx, item.ID); err != nil {
+			return nil, fmt.Errorf("delete shopping list item: %w", err)
+		}
+	}
+	kept := make(map[uuid.UUID]float64, len(optimization.Items))
+	for _, item := range optimization.Items {
+		kept[item.ID] = item.Quantity
+	}
+	for idx := range shoppingList.Items {
+		item := &shoppingList.Items[idx]
+		quantity, ok := kept[item.ID]
+		if !ok || quantity == item.Quantity {
+			continue
+		}
+		item.Quantity = quantity
+		if err := s.shoppingListRepo.UpdateItem(ctx, item); err != nil {
+			return nil, fmt.Errorf("update shopping list item: %w", err)
+		}
+	}
+
+	shoppingList.Items = optimization.Items
+	shoppingList.EstimatedCost, shoppingList.ActualCost = calculateListTotals(shoppingList.Items)
+	if input.Budget != nil {
+		shoppingList.TotalBudget = budget
+	}
+	if err := s.shoppingListRepo.Update(ctx, shoppingList); err != nil {
+		logger.Error("Failed to save optimized shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "OptimizeShoppingList"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("update shopping list: %w", err)
+	}
+
+	logger.Info("Shopping list optimized for budget",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "OptimizeShoppingList"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", id.String()),
+		zap.Float64("budget", budget),
+		zap.Int(appLogger.FieldCount, len(optimization.Cuts)),
+	)
+
+	reloaded, err := s.reloadShoppingList(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	result.ShoppingList = reloaded
+	return result, nil
+}
+
+// optimizeForBudget cuts the estimated cost of the items down to the budget,
+// starting from the lowest priority. Within a priority the most expensive
+// lines are first reduced to the household need; when that is not enough the
+// items of the priority are removed, preferring the cheapest one that closes
+// the gap. Essential items are only reduced, and purchased items are never
+// touched, so the result may still be over budget.
+func optimizeForBudget(items []shoppingModel.ShoppingListItem, budget float64, householdSize int) budgetOptimization {
+	original, _ := calculateListTotals(items)
+	working := make([]shoppingModel.ShoppingListItem, len(items))
+	copy(working, items)
+
+	removed := make([]bool, len(working))
+	cutByItem := make(map[int]int)
+	var cuts []dto.BudgetCutDTO
+	recordCut := func(idx int, action string, from, to, saved float64, reason string) {
+		item := working[idx]
+		if pos, ok := cutByItem[idx]; ok {
+			// An item reduced and later removed reports a single cut.
+			cuts[pos].Action = action
+			cuts[pos].ToQuantity = to
+			cuts[pos].Saved = roundCurrency(cuts[pos].Saved + saved)
+			cuts[pos].Reason = reason
+			return
+		}
+		cut := dto.BudgetCutDTO{
+			Name:         item.Name,
+			Action:       action,
+			Priority:     itemPriority(item),
+			FromQuantity: from,
+			ToQuantity:   to,
+			Unit:         item.Unit,
+			Saved:        roundCurrency(saved),
+			Reason:       reason,
+		}
+		if item.ID != uuid.Nil {
+			cut.ItemID = item.ID.String()
+		}
+		cutByItem[idx] = len(cuts)
+		cuts = append(cuts, cut)
+	}
+
+	over := original - budget
+	for priority := lowestPriority; priority >= essentialPriority && over > budgetEpsilon; priority-- {
+		var candidates []int
+		for idx, item := range working {
+			if item.Purchased || item.EstimatedPrice <= 0 || item.Quantity <= 0 || itemPriority(item) != priority {
+				continue
+			}
+			candidates = append(candidates, idx)
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			ci, cj := itemCost(working[candidates[i]]), itemCost(working[candidates[j]])
+			if ci != cj {
+				return ci > cj
+			}
+			return normalizeItemName(working[candidates[i]].Name) < normalizeItemName(working[candidates[j]].Name)
+		})
+
+		for _, idx := range candidates {
+			if over <= budgetEpsilon {
+				break
+			}
+			item := &working[idx]
+			need := householdNeed(*item, householdSize)
+			if item.Quantity <= need {
+				continue
+			}
+			target := roundDownQuantity(item.Quantity-over/item.EstimatedPrice, item.Unit)
+			reason := "reduced to fit the budget"
+			if target <= need {
+				target = need
+				reason = "reduced to the household need"
+			}
+			if target >= item.Quantity {
+				continue
+			}
+			saved := (item.Quantity - target) * item.EstimatedPrice
+			recordCut(idx, budgetCutReduced, item.Quantity, target, saved, fmt.Sprintf("priority %d, %s", priority, reason))
+			item.Quantity = target
+			over -= saved
+		}
+
+		if priority == essentialPriority {
+			break
+		}
+		for over > budgetEpsilon {
+			pick := -1
+			for _, idx := range candidates {
+				if removed[idx] {
+					continue
+				}
+				cost := itemCost(working[idx])
+				if pick < 0 {
+					pick = idx
+					continue
+				}
+				best := itemCost(working[pick])
+				switch {
+				case cost >= over && (best < over || cost < best):
+					pick = idx
+				case cost < over && best < over && cost > best:
+					pick = idx
+				}
+			}
+			if pick < 0 {
+				break
+			}
+			cost := itemCost(working[pick])
+			from := working[pick].Quantity
+			if pos, ok := cutByItem[pick]; ok {
+				from = cuts[pos].FromQuantity
+			}
+			recordCut(pick, budgetCutRemoved, from, 0, cost, fmt.Sprintf("priority %d, removed after reducing every priority %d item", priority, priority))
+			removed[pick] = true
+			over -= cost
+		}
+	}
+
+	result := budgetOptimization{Cuts: cuts, OriginalCost: roundCurrency(original)}
+	if result.Cuts == nil {
+		result.Cuts = []dto.BudgetCutDTO{}
+	}
+	for idx, item := range working {
+		if removed[idx] {
+			result.Removed = append(result.Removed, items[idx])
+			continue
+		}
+		result.Items = append(result.Items, item)
+	}
+	optimized, _ := calculateListTotals(result.Items)
+	result.OptimizedCost = roundCurrency(optimized)
+	return result
+}
+
+func itemPriority(item shoppingModel.ShoppingListItem) int {
+	if item.Priority < essentialPriority || item.Priority > lowestPriority {
+		return lowestPriority
+	}
+	return item.Priority
+}
+
+func itemCost(item shoppingModel.ShoppingListItem) float64 {
+	return clampNonNegative(item.Quantity) * item.EstimatedPrice
+}
+
+// householdNeed is the quantity an item keeps when reduced: essential items
+// keep half of it and at least one unit per person, the others a quarter.
+// Items sold by the unit (or in unknown packages) keep whole units.
+func householdNeed(item shoppingModel.ShoppingListItem, householdSize int) float64 {
+	share := 0.25
+	if itemPriority(item) == essentialPriority {
+		share = 0.5
+	}
+	need := item.Quantity * share
+	if !isCountUnit(item.Unit) {
+		return math.Round(need*100) / 100
+	}
+
+	need = math.Max(math.Ceil(need-1e-9), 1)
+	if itemPriority(item) == essentialPriority {
+		need = math.Max(need, float64(householdSize))
+	}
+	return math.Min(need, item.Quantity)
+}
+
+func roundDownQuantity(quantity float64, unit string) float64 {
+	if isCountUnit(unit) {
+		return math.Floor(quantity + 1e-9)
+	}
+	return math.Floor(quantity*100+1e-9) / 100
+}
+
+func isCountUnit(unit string) bool {
+	u, ok := units.Lookup(unit)
+	return !ok || u.Dimension == units.DimensionCount
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimizeForBudgetKeepsListWithinBudget(t *testing.T) {
+	items := []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), Name: "Arroz", Quantity: 2, Unit: "un", EstimatedPrice: 25, Priority: 1},
+	}
+
+	result := optimizeForBudget(items, 100, 2)
+
+	require.Empty(t, result.Cuts)
+	require.Equal(t, items, result.Items)
+	require.Equal(t, 50.0, result.OptimizedCost)
+}
+
+func TestOptimizeForBudgetReducesLowPriorityBeforeRemoving(t *testing.T) {
+	items := []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), Name: "Arroz", Quantity: 2, Unit: "un", EstimatedPrice: 25, Priority: 1},
+		{ID: uuid.New(), Name: "Biscoito", Quantity: 4, Unit: "un", EstimatedPrice: 5, Priority: 3},
+		{ID: uuid.New(), Name: "Refrigerante", Quantity: 2, Unit: "un", EstimatedPrice: 10, Priority: 3},
+	}
+
+	// 90 planned: the biscuits go down to one unit (saving 15) and the soda
+	// loses a whole bottle for the remaining 5.
+	result := optimizeForBudget(items, 70, 2)
+
+	require.Equal(t, 65.0, result.OptimizedCost)
+	require.Len(t, result.Items, 3)
+	require.Empty(t, result.Removed)
+	require.Len(t, result.Cuts, 2)
+	require.Equal(t, "Biscoito", result.Cuts[0].Name)
+	require.Equal(t, budgetCutReduced, result.Cuts[0].Action)
+	require.Equal(t, 1.0, result.Cuts[0].ToQuantity)
+	require.Equal(t, 15.0, result.Cuts[0].Saved)
+	require.Equal(t, "Refrigerante", result.Cuts[1].Name)
+	require.Equal(t, 1.0, result.Cuts[1].ToQuantity)
+	require.Equal(t, 2.0, result.Items[0].Quantity)
+}
+
+func TestOptimizeForBudgetRemovesLowPriorityAndSparesEssentials(t *testing.T) {
+	items := []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), Name: "Feijão", Quantity: 2, Unit: "kg", EstimatedPrice: 10, Priority: 1},
+		{ID: uuid.New(), Name: "Chocolate", Quantity: 1, Unit: "un", EstimatedPrice: 12, Priority: 3},
+		{ID: uuid.New(), Name: "Sorvete", Quantity: 1, Unit: "un", EstimatedPrice: 30, Priority: 3},
+		{ID: uuid.New(), Name: "Queijo", Quantity: 1, Unit: "un", EstimatedPrice: 20, Priority: 2, Purchased: true},
+	}
+
+	// 82 planned: removing the chocolate (the cheapest that closes the gap
+	// of 10) is enough.
+	result := optimizeForBudget(items, 72, 4)
+
+	require.Equal(t, 70.0, result.OptimizedCost)
+	require.Len(t, result.Removed, 1)
+	require.Equal(t, "Chocolate", result.Removed[0].Name)
+	require.Equal(t, budgetCutRemoved, result.Cuts[0].Action)
+	require.Equal(t, 12.0, result.Cuts[0].Saved)
+	require.Equal(t, items[1].ID.String(), result.Cuts[0].ItemID)
+}
+
+func TestOptimizeForBudgetReducesEssentialsToHouseholdNeed(t *testing.T) {
+	items := []shoppingModel.ShoppingListItem{
+		{Name: "Leite", Quantity: 12, Unit: "un", EstimatedPrice: 5, Priority: 1},
+		{Name: "Carne", Quantity: 2, Unit: "kg", EstimatedPrice: 40, Priority: 1},
+		{Name: "Queijo", Quantity: 1, Unit: "un", EstimatedPrice: 20, Priority: 2},
+	}
+
+	result := optimizeForBudget(items, 10, 4)
+
+	require.False(t, result.withinBudget(10))
+	require.Len(t, result.Items, 2)
+	require.Equal(t, "Queijo", result.Removed[0].Name)
+	for _, item := range result.Items {
+		switch item.Name {
+		case "Leite":
+			require.Equal(t, 6.0, item.Quantity)
+		case "Carne":
+			require.Equal(t, 1.0, item.Quantity)
+		}
+	}
+	require.Equal(t, 70.0, result.OptimizedCost)
+	for _, cut := range result.Cuts {
+		require.Empty(t, cut.ItemID)
+	}
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pantryDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/domain"
+	profileDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	budgetScopeUser   = "user"
+	budgetScopePantry = "pantry"
+
+	budgetMonthLayout = "2006-01"
+)
+
+type budgetService struct {
+	budgetRepo  domain.BudgetRepository
+	pantryRepo  pantryDomain.PantryRepository
+	profileRepo profileDomain.ProfileRepository
+	now         func() time.Time
+}
+
+func NewBudgetService(budgetRepo domain.BudgetRepository, pantryRepo pantryDomain.PantryRepository, profileRepo profileDomain.ProfileRepository) domain.BudgetService {
+	return &budgetService{
+		budgetRepo:  budgetRepo,
+		pantryRepo:  pantryRepo,
+		profileRepo: profileRepo,
+		now:         time.Now,
+	}
+}
+
+func (s *budgetService) GetLedger(ctx context.Context, userID uuid.UUID, pantryID *uuid.UUID, month string) (*dto.BudgetLedgerDTO, error) {
+	start, err := parseBudgetMonth(month, s.now())
+	if err != nil {
+		return nil, err
+	}
+	return s.ledger(ctx, userID, pantryID, start, "GetLedger")
+}
+
+func (s *budgetService) SetPantryBudget(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID, input dto.SetPantryBudgetDTO) (*dto.PantryBudgetDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := s.pantryRepo.GetByID(ctx, pantryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPantryNotFound
+		}
+		return nil, fmt.Errorf("get pantry: %w", err)
+	}
+	isOwner, err := s.pantryRepo.IsUserOwner(ctx, pantryID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("check pantry owner: %w", err)
+	}
+	if !isOwner {
+		logger.Warn("Pantry budget update denied",
+			zap.String(appLogger.FieldModule, "budget"),
+			zap.String(appLogger.FieldFunction, "SetPantryBudget"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+		)
+		return nil, domain.ErrPantryAccessDenied
+	}
+
+	budget, err := s.budgetRepo.GetPantryBudget(ctx, pantryID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get pantry budget: %w", err)
+		}
+		budget = &shoppingModel.PantryBudget{PantryID: pantryID}
+	}
+	budget.MonthlyAmount = roundCurrency(input.MonthlyAmount)
+	budget.UpdatedBy = userID
+
+	if err := s.budgetRepo.SavePantryBudget(ctx, budget); err != nil {
+		logger.Error("Failed to save pantry budget",
+			zap.String(appLogger.FieldModule, "budget"),
+			zap.String(appLogger.FieldFunction, "SetPantryBudget"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("save pantry budget: %w", err)
+	}
+
+	logger.Info("Pantry budget updated",
+		zap.String(appLogger.FieldModule, "budget"),
+		zap.String(appLogger.FieldFunction, "SetPantryBudget"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("pantry_id", pantryID.String()),
+		zap.Float64("monthly_amount", budget.MonthlyAmount),
+	)
+
+	return &dto.PantryBudgetDTO{
+		PantryID:      budget.PantryID.String(),
+		MonthlyAmount: budget.MonthlyAmount,
+		UpdatedBy:     budget.UpdatedBy.String(),
+		UpdatedAt:     budget.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *budgetService) CheckSpend(ctx context.Context, userID uuid.UUID, pantryID *uuid.UUID, amount float64) ([]dto.BudgetWarningDTO, error) {
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	start := startOfMonth(s.now())
+	scopes := []*uuid.UUID{nil}
+	if pantryID != nil {
+		scopes = append(scopes, pantryID)
+	}
+
+	var warnings []dto.BudgetWarningDTO
+	for _, scope := range scopes {
+		ledger, err := s.ledger(ctx, userID, scope, start, "CheckSpend")
+		if err != nil {
+			return nil, err
+		}
+		if warning, ok := budgetWarning(ledger, amount); ok {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
+}
+
+// ledger builds the ledger of the month starting at start. Without a pantry
+// the budget is the user's preferred budget and every list they completed
+// counts; with a pantry it is the pantry budget and the lists of all members.
+func (s *budgetService) ledger(ctx context.Context, userID uuid.UUID, pantryID *uuid.UUID, start time.Time, function string) (*dto.BudgetLedgerDTO, error) {
+	end := start.AddDate(0, 1, 0)
+
+	var (
+		budget float64
+		lists  []*shoppingModel.ShoppingList
+	)
+	if pantryID == nil {
+		profile, err := s.profileRepo.GetByUserID(ctx, userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get user profile: %w", err)
+		}
+		if err == nil && profile != nil {
+			budget = profile.PreferredBudget
+		}
+		lists, err = s.budgetRepo.ListCompletedByUser(ctx, userID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("list completed shopping lists: %w", err)
+		}
+	} else {
+		hasAccess, err := s.pantryRepo.IsUserInPantry(ctx, *pantryID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("check pantry access: %w", err)
+		}
+		if !hasAccess {
+			return nil, domain.ErrPantryAccessDenied
+		}
+		pantryBudget, err := s.budgetRepo.GetPantryBudget(ctx, *pantryID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get pantry budget: %w", err)
+		}
+		if err == nil {
+			budget = pantryBudget.MonthlyAmount
+		}
+		lists, err = s.budgetRepo.ListCompletedByPantry(ctx, *pantryID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("list completed shopping lists: %w", err)
+		}
+	}
+
+	result := buildBudgetLedger(lists, budget, start, s.now())
+	if pantryID != nil {
+		id := pantryID.String()
+		result.Scope = budgetScopePantry
+		result.PantryID = &id
+	}
+
+	appLogger.FromContext(ctx).Debug("Budget ledger computed",
+		zap.String(appLogger.FieldModule, "budget"),
+		zap.String(appLogger.FieldFunction, function),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("month", result.Month),
+		zap.Int(appLogger.FieldCount, len(result.Lists)),
+	)
+	return result, nil
+}
+
+// buildBudgetLedger sums the actual cost of the completed lists of the month
+// starting at start. The forecast extrapolates the daily run rate of the
+// current month; closed months forecast what was spent and future months
+// nothing.
+func buildBudgetLedger(lists []*shoppingModel.ShoppingList, budget float64, start time.Time, now time.Time) *dto.BudgetLedgerDTO {
+	end := start.AddDate(0, 1, 0)
+	daysInMonth := end.AddDate(0, 0, -1).Day()
+
+	result := &dto.BudgetLedgerDTO{
+		Scope:       budgetScopeUser,
+		Month:       start.Format(budgetMonthLayout),
+		Budget:      budget,
+		DaysInMonth: daysInMonth,
+		Lists:       make([]dto.BudgetLedgerEntryDTO, 0, len(lists)),
+	}
+
+	spent := 0.0
+	for _, sl := range lists {
+		spent += sl.ActualCost
+		completedAt := sl.UpdatedAt
+		if sl.CompletedAt != nil {
+			completedAt = *sl.CompletedAt
+		}
+		entry := dto.BudgetLedgerEntryDTO{
+			ShoppingListID: sl.ID.String(),
+			Name:           sl.Name,
+			ActualCost:     sl.ActualCost,
+			CompletedAt:    completedAt.Format(time.RFC3339),
+		}
+		if sl.PantryID != nil {
+			id := sl.PantryID.String()
+			entry.PantryID = &id
+		}
+		result.Lists = append(result.Lists, entry)
+	}
+	result.Spent = roundCurrency(spent)
+	result.Remaining = roundCurrency(budget - spent)
+
+	now = now.UTC()
+	switch {
+	case now.Before(start):
+		result.DaysElapsed = 0
+		result.Forecast = result.Spent
+	case !now.Before(end):
+		result.DaysElapsed = daysInMonth
+		result.Forecast = result.Spent
+	default:
+		result.DaysElapsed = now.Day()
+		result.Forecast = roundCurrency(spent / float64(result.DaysElapsed) * float64(daysInMonth))
+	}
+	if result.DaysElapsed > 0 {
+		result.DailyRunRate = roundCurrency(spent / float64(result.DaysElapsed))
+	}
+
+	if budget > 0 {
+		result.OverBudget = result.Spent > budget
+		result.ForecastOverBudget = result.Forecast > budget
+	}
+	return result
+}
+
+// budgetWarning reports whether spending amount exceeds what is left of the
+// ledger budget. Ledgers without a budget never warn.
+func budgetWarning(ledger *dto.BudgetLedgerDTO, amount float64) (dto.BudgetWarningDTO, bool) {
+	if ledger.Budget <= 0 || amount <= ledger.Remaining {
+		return dto.BudgetWarningDTO{}, false
+	}
+
+	remaining := ledger.Remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	subject := "your monthly budget"
+	if ledger.Scope == budgetScopePantry {
+		subject = "the pantry monthly budget"
+	}
+	return dto.BudgetWarningDTO{
+		Scope:     ledger.Scope,
+		PantryID:  ledger.PantryID,
+		Month:     ledger.Month,
+		Budget:    ledger.Budget,
+		Remaining: ledger.Remaining,
+		Planned:   roundCurrency(amount),
+		Excess:    roundCurrency(amount - remaining),
+		Message:   fmt.Sprintf("This list (%s) exceeds what is left of %s (%s)", formatBRL(amount), subject, formatBRL(remaining)),
+	}, true
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// parseBudgetMonth parses a YYYY-MM month, defaulting to the current one.
+func parseBudgetMonth(month string, now time.Time) (time.Time, error) {
+	month = strings.TrimSpace(month)
+	if month == "" {
+		return startOfMonth(now), nil
+	}
+	parsed, err := time.Parse(budgetMonthLayout, month)
+	if err != nil {
+		return time.Time{}, domain.ErrInvalidMonth
+	}
+	return parsed, nil
+}
+
+// checkBudget returns the budget warnings for a list about to cost amount.
+// Budget checks are advisory, so failures are logged and ignored.
+func (s *shoppingListService) checkBudget(ctx context.Context, userID uuid.UUID, pantryID *uuid.UUID, amount float64) []dto.BudgetWarningDTO {
+	if s.budgetService == nil {
+		return nil
+	}
+
+	warnings, err := s.budgetService.CheckSpend(ctx, userID, pantryID, amount)
+	if err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to check monthly budget",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "checkBudget"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return warnings
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (s *shoppingListService) ListCheckouts(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID) ([]dto.CheckoutDTO, error) {
+	if _, err := s.loadOwnedList(ctx, userID, shoppingListID, "ListCheckouts"); err != nil {
+		return nil, err
+	}
+	if s.checkoutRepo == nil {
+		return []dto.CheckoutDTO{}, nil
+	}
+
+	checkouts, err := s.checkoutRepo.ListByShoppingListID(ctx, shoppingListID)
+	if err != nil {
+		return nil, fmt.Errorf("list checkouts: %w", err)
+	}
+
+	result := make([]dto.CheckoutDTO, 0, len(checkouts))
+	for _, checkout := range checkouts {
+		result = append(result, convertCheckoutToDTO(checkout))
+	}
+	return result, nil
+}
+
+func (s *shoppingListService) RevertCheckout(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID) (*dto.ShoppingListResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	shoppingList, err := s.loadOwnedList(ctx, userID, shoppingListID, "RevertCheckout")
+	if err != nil {
+		return nil, err
+	}
+	if s.checkoutRepo == nil {
+		return nil, domain.ErrCheckoutNotFound
+	}
+	status := listStatus(shoppingList)
+	if status != shoppingModel.StatusPending && !canTransition(status, shoppingModel.StatusPending) {
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrInvalidTransition, status, shoppingModel.StatusPending)
+	}
+
+	checkout, err := s.checkoutRepo.GetActive(ctx, shoppingListID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCheckoutNotFound
+		}
+		return nil, fmt.Errorf("get active checkout: %w", err)
+	}
+	if err := s.checkoutRepo.Revert(ctx, checkout, userID, time.Now().UTC()); err != nil {
+		logger.Error("Failed to revert checkout",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "RevertCheckout"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.String("checkout_id", checkout.ID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("revert checkout: %w", err)
+	}
+
+	logger.Info("Checkout reverted",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "RevertCheckout"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", shoppingListID.String()),
+		zap.String("checkout_id", checkout.ID.String()),
+		zap.Int(appLogger.FieldCount, len(checkout.Changes)),
+	)
+	if status != shoppingModel.StatusPending {
+		s.recordStatusChange(ctx, userID, shoppingList.ID, status, shoppingModel.StatusPending, "checkout reverted")
+	}
+
+	return s.reloadShoppingList(ctx, shoppingList.ID)
+}
+
+// revertActiveCheckout rolls back the checkout of a list that leaves the
+// completed status. Lists completed before checkouts were recorded have
+// nothing to revert.
+func (s *shoppingListService) revertActiveCheckout(ctx context.Context, userID uuid.UUID, sl *shoppingModel.ShoppingList) error {
+	if s.checkoutRepo == nil {
+		return nil
+	}
+
+	checkout, err := s.checkoutRepo.GetActive(ctx, sl.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get active checkout: %w", err)
+	}
+	if err := s.checkoutRepo.Revert(ctx, checkout, userID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("revert checkout: %w", err)
+	}
+	return nil
+}
+
+func (s *shoppingListService) loadOwnedList(ctx context.Context, userID uuid.UUID, id uuid.UUID, function string) (*shoppingModel.ShoppingList, error) {
+	return loadOwnedList(ctx, s.shoppingListRepo, userID, id, function)
+}
+
+// loadOwnedList loads a shopping list and refuses it when userID does not own
+// it. Both the list and the share services go through it.
+func loadOwnedList(ctx context.Context, repo domain.ShoppingListRepository, userID uuid.UUID, id uuid.UUID, function string) (*shoppingModel.ShoppingList, error) {
+	shoppingList, err := repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShoppingListNotFound
+		}
+		appLogger.FromContext(ctx).Error("Failed to get shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get shopping list: %w", err)
+	}
+	if shoppingList.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+	return shoppingList, nil
+}
+
+func (s *shoppingListService) reloadShoppingList(ctx context.Context, id uuid.UUID) (*dto.ShoppingListResponseDTO, error) {
+	reloaded, err := s.shoppingListRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShoppingListNotFound
+		}
+		return nil, fmt.Errorf("reload shopping list: %w", err)
+	}
+	return s.convertToResponseDTO(ctx, reloaded), nil
+}
+
+func convertCheckoutToDTO(checkout *shoppingModel.ShoppingListCheckout) dto.CheckoutDTO {
+	result := dto.CheckoutDTO{
+		ID:             checkout.ID.String(),
+		ShoppingListID: checkout.ShoppingListID.String(),
+		ActualCost:     checkout.ActualCost,
+		Active:         checkout.RevertedAt == nil,
+		Changes:        make([]dto.CheckoutChangeDTO, 0, len(checkout.Changes)),
+		CreatedAt:      checkout.CreatedAt.Format(time.RFC3339),
+	}
+	if checkout.PantryID != nil {
+		id := checkout.PantryID.String()
+		result.PantryID = &id
+	}
+	if checkout.RevertedAt != nil {
+		revertedAt := checkout.RevertedAt.Format(time.RFC3339)
+		result.RevertedAt = &revertedAt
+	}
+	for _, change := range checkout.Changes {
+		result.Changes = append(result.Changes, dto.CheckoutChangeDTO{
+			ShoppingListItemID:   change.ShoppingListItemID.String(),
+			PantryItemID:         change.PantryItemID.String(),
+			Action:               change.Action,
+			Name:                 change.Name,
+			Quantity:             change.Quantity,
+			Unit:                 change.Unit,
+			PricePerUnit:         change.PricePerUnit,
+			PreviousPricePerUnit: change.PreviousPricePerUnit,
+		})
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"github.com/nclsgg/despensa-digital/backend/pkg/dietary"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const dietarySourceList = "list"
+
+// checkDietary returns the dietary warnings for items added to a list: the
+// items that break the restrictions of a member of the pantry of the list, or
+// of its owner when it has no pantry, and the restrictions of the list no
+// member shares. Dietary checks are advisory, so failures are logged and only
+// the restrictions of the list are checked.
+func (s *shoppingListService) checkDietary(ctx context.Context, sl *shoppingModel.ShoppingList, items []string) []dto.DietaryWarningDTO {
+	members, err := s.householdMembers(ctx, sl)
+	if err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to get the dietary restrictions of the household",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "checkDietary"),
+			zap.String(appLogger.FieldUserID, sl.UserID.String()),
+			zap.String("shopping_list_id", sl.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	var warnings []dto.DietaryWarningDTO
+	for _, warning := range dietary.CheckHousehold(items, sl.DietaryRestrictions, dietarySourceList, members) {
+		warnings = append(warnings, convertDietaryWarning(warning))
+	}
+	return warnings
+}
+
+// householdMembers lists the members of the pantry of a list, or its owner
+// when it has no pantry, with the dietary restrictions of their profiles.
+func (s *shoppingListService) householdMembers(ctx context.Context, sl *shoppingModel.ShoppingList) ([]dietary.Member, error) {
+	var users []dietary.Member
+	if sl.PantryID != nil && s.pantryRepo != nil {
+		pantryUsers, err := s.pantryRepo.ListUsersInPantry(ctx, *sl.PantryID)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range pantryUsers {
+			users = append(users, dietary.Member{UserID: user.UserID, Email: user.Email})
+		}
+	}
+
+	return dietary.Household(sl.UserID, users, dietary.ProfileRestrictions(ctx, s.profileRepo, "shopping_list", "checkDietary"))
+}
+
+func convertDietaryWarning(warning dietary.Warning) dto.DietaryWarningDTO {
+	result := dto.DietaryWarningDTO{
+		Item:        warning.Ingredient,
+		Restriction: warning.Restriction,
+		Allergens:   warning.Allergens,
+		Source:      warning.Source,
+	}
+	if warning.Member != nil {
+		result.UserID = warning.Member.UserID.String()
+		result.Email = warning.Member.Email
+	}
+	return result
+}
+
+func itemNames(items []shoppingModel.ShoppingListItem) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (s *shoppingListService) DuplicateShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.DuplicateShoppingListDTO) (*dto.ShoppingListResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	original, err := s.loadOwnedList(ctx, userID, id, "DuplicateShoppingList")
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = original.Name + " (cópia)"
+	}
+	duplicate := copyListSettings(original, name)
+	duplicate.TotalBudget = original.TotalBudget
+	duplicate.GeneratedBy = original.GeneratedBy
+	// The purchases of a checked out list are already in the pantry, so its
+	// copy starts unpurchased or completing it would add them again.
+	resetPurchased := input.ResetPurchased || isCheckedOut(original)
+	for _, item := range original.Items {
+		copied := copyListItem(item)
+		if resetPurchased {
+			copied.Purchased = false
+			copied.PurchasedAt = nil
+			copied.ActualPrice = 0
+		}
+		if input.ResetPrices {
+			copied.EstimatedPrice = 0
+			copied.ActualPrice = 0
+		}
+		duplicate.Items = append(duplicate.Items, copied)
+	}
+	duplicate.EstimatedCost, duplicate.ActualCost = calculateListTotals(duplicate.Items)
+
+	if err := s.shoppingListRepo.Create(ctx, duplicate); err != nil {
+		logger.Error("Failed to duplicate shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "DuplicateShoppingList"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("create shopping list: %w", err)
+	}
+	s.recordStatusChange(ctx, userID, duplicate.ID, "", duplicate.Status, "duplicated from "+original.ID.String())
+
+	logger.Info("Shopping list duplicated",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "DuplicateShoppingList"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", id.String()),
+		zap.String("duplicate_id", duplicate.ID.String()),
+		zap.Int(appLogger.FieldCount, len(duplicate.Items)),
+	)
+
+	return s.reloadNewList(ctx, userID, duplicate.ID)
+}
+
+func (s *shoppingListService) MergeShoppingLists(ctx context.Context, userID uuid.UUID, input dto.MergeShoppingListsDTO) (*dto.ShoppingListResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	sources := make([]*shoppingModel.ShoppingList, 0, len(input.ShoppingListIDs))
+	for _, id := range input.ShoppingListIDs {
+		source, err := s.loadOwnedList(ctx, userID, id, "MergeShoppingLists")
+		if err != nil {
+			return nil, err
+		}
+		if isCheckedOut(source) {
+			logger.Warn("Cannot merge a checked out shopping list",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "MergeShoppingLists"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("shopping_list_id", id.String()),
+				zap.String("status", source.Status),
+			)
+			return nil, domain.ErrShoppingListClosed
+		}
+		sources = append(sources, source)
+	}
+
+	// The merged list keeps the pantry, store and preferences of the first
+	// list and the sum of the budgets.
+	merged := copyListSettings(sources[0], strings.TrimSpace(input.Name))
+	merged.GeneratedBy = "manual"
+	var items []shoppingModel.ShoppingListItem
+	for _, source := range sources {
+		merged.TotalBudget += source.TotalBudget
+		for _, item := range source.Items {
+			items = append(items, copyListItem(item))
+		}
+	}
+	merged.Items = consolidateListItems(items)
+	merged.EstimatedCost, merged.ActualCost = calculateListTotals(merged.Items)
+
+	var deleteIDs []uuid.UUID
+	if !input.KeepSources {
+		deleteIDs = input.ShoppingListIDs
+	}
+	if err := s.shoppingListRepo.CreateMerged(ctx, merged, deleteIDs); err != nil {
+		logger.Error("Failed to merge shopping lists",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "MergeShoppingLists"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("merge shopping lists: %w", err)
+	}
+	s.recordStatusChange(ctx, userID, merged.ID, "", merged.Status, fmt.Sprintf("merged from %d lists", len(sources)))
+
+	logger.Info("Shopping lists merged",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "MergeShoppingLists"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", merged.ID.String()),
+		zap.Int("source_count", len(sources)),
+		zap.Int(appLogger.FieldCount, len(merged.Items)),
+	)
+
+	return s.reloadNewList(ctx, userID, merged.ID)
+}
+
+func (s *shoppingListService) MoveShoppingListItems(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.MoveShoppingListItemsDTO) (*dto.MoveShoppingListItemsResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if input.TargetListID == id {
+		return nil, domain.ErrSameShoppingList
+	}
+	source, err := s.loadOwnedList(ctx, userID, id, "MoveShoppingListItems")
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.loadOwnedList(ctx, userID, input.TargetListID, "MoveShoppingListItems")
+	if err != nil {
+		return nil, err
+	}
+	if isCheckedOut(source) || isCheckedOut(target) {
+		logger.Warn("Cannot move items of a checked out shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "MoveShoppingListItems"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", id.String()),
+			zap.String("target_list_id", input.TargetListID.String()),
+		)
+		return nil, domain.ErrShoppingListClosed
+	}
+
+	selected := make(map[uuid.UUID]bool, len(input.ItemIDs))
+	for _, itemID := range input.ItemIDs {
+		selected[itemID] = true
+	}
+
+	// Room for every moved item, so the pointers below stay valid.
+	target.Items = append(make([]shoppingModel.ShoppingListItem, 0, len(target.Items)+len(input.ItemIDs)), target.Items...)
+	targetLines := make(map[string]*shoppingModel.ShoppingListItem, len(target.Items))
+	for idx := range target.Items {
+		targetLines[itemMergeKey(target.Items[idx])] = &target.Items[idx]
+	}
+
+	var saved []*shoppingModel.ShoppingListItem
+	var deletedIDs []uuid.UUID
+	changed := make(map[*shoppingModel.ShoppingListItem]bool)
+	save := func(item *shoppingModel.ShoppingListItem) {
+		if !changed[item] {
+			changed[item] = true
+			saved = append(saved, item)
+		}
+	}
+	remaining := make([]shoppingModel.ShoppingListItem, 0, len(source.Items))
+	for idx := range source.Items {
+		item := source.Items[idx]
+		if !selected[item.ID] {
+			remaining = append(remaining, item)
+			continue
+		}
+		delete(selected, item.ID)
+
+		if line, ok := targetLines[itemMergeKey(item)]; ok {
+			mergeListItem(line, item)
+			deletedIDs = append(deletedIDs, item.ID)
+			save(line)
+			continue
+		}
+		item.ShoppingListID = target.ID
+		target.Items = append(target.Items, item)
+		moved := &target.Items[len(target.Items)-1]
+		targetLines[itemMergeKey(item)] = moved
+		save(moved)
+	}
+	if len(selected) > 0 {
+		return nil, domain.ErrItemNotFound
+	}
+	source.Items = remaining
+
+	source.EstimatedCost, source.ActualCost = calculateListTotals(source.Items)
+	target.EstimatedCost, target.ActualCost = calculateListTotals(target.Items)
+	if err := s.shoppingListRepo.MoveItems(ctx, saved, deletedIDs, []*shoppingModel.ShoppingList{source, target}); err != nil {
+		logger.Error("Failed to move shopping list items",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "MoveShoppingListItems"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", id.String()),
+			zap.String("target_list_id", target.ID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("move shopping list items: %w", err)
+	}
+
+	logger.Info("Shopping list items moved",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "MoveShoppingListItems"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", id.String()),
+		zap.String("target_list_id", target.ID.String()),
+		zap.Int(appLogger.FieldCount, len(input.ItemIDs)),
+	)
+
+	sourceDTO, err := s.reloadShoppingList(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	targetDTO, err := s.reloadShoppingList(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MoveShoppingListItemsResponseDTO{Source: sourceDTO, Target: targetDTO}, nil
+}
+
+// reloadNewList reloads a list created from others, with the budget warnings
+// of a new list.
+func (s *shoppingListService) reloadNewList(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*dto.ShoppingListResponseDTO, error) {
+	created, err := s.shoppingListRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShoppingListNotFound
+		}
+		return nil, fmt.Errorf("reload shopping list: %w", err)
+	}
+
+	planned := created.EstimatedCost
+	if planned <= 0 {
+		planned = created.TotalBudget
+	}
+	result := s.convertToResponseDTO(ctx, created)
+	result.BudgetWarnings = s.checkBudget(ctx, userID, created.PantryID, planned)
+	return result, nil
+}
+
+// copyListSettings starts a new pending list with the owner, pantry, store
+// and preferences of another one.
+func copyListSettings(sl *shoppingModel.ShoppingList, name string) *shoppingModel.ShoppingList {
+	return &shoppingModel.ShoppingList{
+		UserID:              sl.UserID,
+		PantryID:            sl.PantryID,
+		StoreID:             sl.StoreID,
+		Name:                name,
+		Status:              shoppingModel.StatusPending,
+		HouseholdSize:       sl.HouseholdSize,
+		MonthlyIncome:       sl.MonthlyIncome,
+		DietaryRestrictions: append(shoppingModel.StringArray(nil), sl.DietaryRestrictions...),
+	}
+}
+
+// copyListItem copies an item without its identity, so it can be created in
+// another list.
+func copyListItem(item shoppingModel.ShoppingListItem) shoppingModel.ShoppingListItem {
+	item.ID = uuid.Nil
+	item.ShoppingListID = uuid.Nil
+	item.CreatedAt = time.Time{}
+	item.UpdatedAt = time.Time{}
+	return item
+}
+
+// itemMergeKey groups the items that are the same product bought in the same
+// unit. Purchased and pending items are never folded together.
+func itemMergeKey(item shoppingModel.ShoppingListItem) string {
+	return fmt.Sprintf("%s|%s|%t", normalizeItemName(item.Name), units.Normalize(item.Unit), item.Purchased)
+}
+
+// consolidateListItems folds the items with the same merge key into the first
+// one, keeping the order of first appearance.
+func consolidateListItems(items []shoppingModel.ShoppingListItem) []shoppingModel.ShoppingListItem {
+	result := make([]shoppingModel.ShoppingListItem, 0, len(items))
+	positions := make(map[string]int, len(items))
+	for _, item := range items {
+		key := itemMergeKey(item)
+		if pos, ok := positions[key]; ok {
+			mergeListItem(&result[pos], item)
+			continue
+		}
+		positions[key] = len(result)
+		result = append(result, item)
+	}
+	return result
+}
+
+// mergeListItem adds other to line. Prices are averaged by quantity, the
+// highest priority wins and missing details are taken from other.
+func mergeListItem(line *shoppingModel.ShoppingListItem, other shoppingModel.ShoppingListItem) {
+	line.EstimatedPrice = roundCurrency(weightedPrice(line.EstimatedPrice, line.Quantity, other.EstimatedPrice, other.Quantity))
+	line.ActualPrice = roundCurrency(weightedPrice(line.ActualPrice, line.Quantity, other.ActualPrice, other.Quantity))
+	line.Quantity += other.Quantity
+	if itemPriority(other) < itemPriority(*line) {
+		line.Priority = other.Priority
+	}
+	if line.Category == "" {
+		line.Category = other.Category
+	}
+	if line.PantryItemID == nil {
+		line.PantryItemID = other.PantryItemID
+	}
+	if other.PurchasedAt != nil && (line.PurchasedAt == nil || other.PurchasedAt.After(*line.PurchasedAt)) {
+		line.PurchasedAt = other.PurchasedAt
+	}
+}
+
+// weightedPrice averages two unit prices by quantity, ignoring the ones not
+// informed.
+func weightedPrice(price float64, quantity float64, otherPrice float64, otherQuantity float64) float64 {
+	switch {
+	case price <= 0:
+		return otherPrice
+	case otherPrice <= 0:
+		return price
+	}
+	total := quantity + otherQuantity
+	if total <= 0 {
+		return price
+	}
+	return (price*quantity + otherPrice*otherQuantity) / total
+}
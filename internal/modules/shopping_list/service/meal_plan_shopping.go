@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const itemSourceMealPlan = "meal_plan"
+
+func (s *shoppingListService) GenerateFromMealPlan(ctx context.Context, userID uuid.UUID, input dto.MealPlanShoppingListDTO) (*dto.MealPlanShoppingResultDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	from, to, err := mealPlanPeriod(input.From, input.To, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	hasAccess, err := s.pantryRepo.IsUserInPantry(ctx, input.PantryID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("check pantry access: %w", err)
+	}
+	if !hasAccess {
+		return nil, domain.ErrPantryAccessDenied
+	}
+
+	result := &dto.MealPlanShoppingResultDTO{
+		PantryID:    input.PantryID.String(),
+		From:        from.Format(recipeDomain.MealPlanDateLayout),
+		To:          to.Format(recipeDomain.MealPlanDateLayout),
+		Ingredients: []dto.RecipeIngredientNeedDTO{},
+	}
+	if s.mealPlanRepo == nil || s.recipeRepo == nil {
+		return result, nil
+	}
+
+	entries, err := s.mealPlanRepo.ListByPantry(ctx, input.PantryID, from, to)
+	if err != nil {
+		logger.Error("Failed to list meal plan",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "GenerateFromMealPlan"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", input.PantryID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list meal plan: %w", err)
+	}
+
+	var needs []ingredientNeed
+	positions := make(map[string][]int)
+	for _, entry := range entries {
+		// Recipes are read with the user that planned them, as a pantry
+		// plan can hold the recipes of every member.
+		recipe, err := s.recipeRepo.FindByID(ctx, entry.RecipeID, entry.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// The recipe was removed after being planned.
+				continue
+			}
+			return nil, fmt.Errorf("get recipe: %w", err)
+		}
+		servings := entry.Servings
+		if servings <= 0 {
+			servings = recipe.ServingCount()
+		}
+		needs = appendRecipeNeeds(needs, positions, recipe, servings)
+		result.Meals++
+	}
+	if result.Meals == 0 {
+		return result, nil
+	}
+
+	pantryItems, err := s.itemRepo.ListByPantryID(ctx, input.PantryID)
+	if err != nil {
+		return nil, fmt.Errorf("list pantry items: %w", err)
+	}
+	items, report := planRecipePurchases(needs, indexRuleStock(pantryItems, time.Time{}))
+	for idx := range items {
+		items[idx].Source = itemSourceMealPlan
+	}
+	result.Ingredients = report
+	if len(items) == 0 {
+		// Everything is in the pantry: there is nothing to create.
+		return result, nil
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = fmt.Sprintf("Cardápio de %s a %s", from.Format("02/01"), to.Format("02/01"))
+	}
+	pantryID := input.PantryID
+	created, err := s.createSourcedList(ctx, userID, &pantryID, name, itemSourceMealPlan,
+		"created from meal plan "+result.From+" to "+result.To, items, "GenerateFromMealPlan")
+	if err != nil {
+		return nil, err
+	}
+	result.Created = true
+	result.ShoppingList = created
+	return result, nil
+}
+
+// mealPlanPeriod parses the period of a meal plan, defaulting to the seven
+// days starting on the day of now.
+func mealPlanPeriod(from string, to string, now time.Time) (time.Time, time.Time, error) {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if strings.TrimSpace(from) != "" {
+		parsed, err := time.Parse(recipeDomain.MealPlanDateLayout, strings.TrimSpace(from))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be a date in the YYYY-MM-DD format", domain.ErrInvalidPeriod)
+		}
+		start = parsed
+	}
+	end := start.AddDate(0, 0, recipeDomain.DefaultMealPlanDays-1)
+	if strings.TrimSpace(to) != "" {
+		parsed, err := time.Parse(recipeDomain.MealPlanDateLayout, strings.TrimSpace(to))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be a date in the YYYY-MM-DD format", domain.ErrInvalidPeriod)
+		}
+		end = parsed
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to cannot be before from", domain.ErrInvalidPeriod)
+	}
+	if end.Sub(start) > recipeDomain.MaxMealPlanRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: the period can have at most %d days", domain.ErrInvalidPeriod, recipeDomain.MaxMealPlanRangeDays)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pantryDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type parLevelService struct {
+	parLevelRepo domain.ParLevelRepository
+	pantryRepo   pantryDomain.PantryRepository
+}
+
+func NewParLevelService(parLevelRepo domain.ParLevelRepository, pantryRepo pantryDomain.PantryRepository) domain.ParLevelService {
+	return &parLevelService{
+		parLevelRepo: parLevelRepo,
+		pantryRepo:   pantryRepo,
+	}
+}
+
+func (s *parLevelService) ListParLevels(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID) ([]dto.ParLevelDTO, error) {
+	if err := s.ensurePantryMember(ctx, userID, pantryID); err != nil {
+		return nil, err
+	}
+
+	levels, err := s.parLevelRepo.ListByPantryID(ctx, pantryID)
+	if err != nil {
+		return nil, fmt.Errorf("list par levels: %w", err)
+	}
+	return convertParLevelsToDTO(levels), nil
+}
+
+func (s *parLevelService) SetParLevels(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID, input dto.SetParLevelsDTO) ([]dto.ParLevelDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if err := s.ensurePantryMember(ctx, userID, pantryID); err != nil {
+		return nil, err
+	}
+
+	// A product listed twice keeps its last par level.
+	byName := make(map[string]*shoppingModel.ParLevel, len(input.ParLevels))
+	levels := make([]*shoppingModel.ParLevel, 0, len(input.ParLevels))
+	for _, entry := range input.ParLevels {
+		name := strings.TrimSpace(entry.Name)
+		key := normalizeItemName(name)
+		level, ok := byName[key]
+		if !ok {
+			level = &shoppingModel.ParLevel{PantryID: pantryID, NormalizedName: key}
+			byName[key] = level
+			levels = append(levels, level)
+		}
+		level.Name = name
+		level.Quantity = entry.Quantity
+		level.Unit = strings.TrimSpace(entry.Unit)
+		level.PerPerson = entry.PerPerson
+		level.Category = strings.TrimSpace(entry.Category)
+		level.UpdatedBy = userID
+	}
+
+	if err := s.parLevelRepo.ReplaceForPantry(ctx, pantryID, levels); err != nil {
+		logger.Error("Failed to save par levels",
+			zap.String(appLogger.FieldModule, "par_level"),
+			zap.String(appLogger.FieldFunction, "SetParLevels"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("pantry_id", pantryID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("save par levels: %w", err)
+	}
+
+	logger.Info("Par levels updated",
+		zap.String(appLogger.FieldModule, "par_level"),
+		zap.String(appLogger.FieldFunction, "SetParLevels"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("pantry_id", pantryID.String()),
+		zap.Int(appLogger.FieldCount, len(levels)),
+	)
+
+	return s.ListParLevels(ctx, userID, pantryID)
+}
+
+func (s *parLevelService) ensurePantryMember(ctx context.Context, userID uuid.UUID, pantryID uuid.UUID) error {
+	if _, err := s.pantryRepo.GetByID(ctx, pantryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrPantryNotFound
+		}
+		return fmt.Errorf("get pantry: %w", err)
+	}
+	hasAccess, err := s.pantryRepo.IsUserInPantry(ctx, pantryID, userID)
+	if err != nil {
+		return fmt.Errorf("check pantry access: %w", err)
+	}
+	if !hasAccess {
+		return domain.ErrPantryAccessDenied
+	}
+	return nil
+}
+
+func convertParLevelsToDTO(levels []*shoppingModel.ParLevel) []dto.ParLevelDTO {
+	result := make([]dto.ParLevelDTO, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, dto.ParLevelDTO{
+			ID:        level.ID.String(),
+			PantryID:  level.PantryID.String(),
+			Name:      level.Name,
+			Quantity:  level.Quantity,
+			Unit:      level.Unit,
+			PerPerson: level.PerPerson,
+			Category:  level.Category,
+			UpdatedAt: level.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	return result
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/nfce"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// receiptMatchThreshold is the minimum score for a receipt line to be
+	// matched to a list or pantry item.
+	receiptMatchThreshold = 0.6
+
+	receiptMatchListItem   = "shopping_list_item"
+	receiptMatchPantryItem = "pantry_item"
+	receiptMatchAdded      = "added"
+	receiptMatchUnmatched  = "unmatched"
+
+	itemSourceReceipt = "receipt"
+)
+
+var receiptSizeToken = regexp.MustCompile(`^\d+([.,]\d+)?(kg|g|gr|mg|ml|l|lt|un|x)?$`)
+
+// receiptTokens splits a product name into comparable words, dropping
+// punctuation and package sizes such as "5kg".
+func receiptTokens(name string) []string {
+	fields := strings.FieldsFunc(normalizeItemName(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len(field) < 2 || receiptSizeToken.MatchString(field) {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// receiptTokensMatch accepts the abbreviations printed on receipts, so "integ"
+// matches "integral" and "ref" matches "refinado".
+func receiptTokensMatch(a, b string) bool {
+	if a == b {
+		return true
+	}
+	shorter, longer := a, b
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+	return len(shorter) >= 3 && strings.HasPrefix(longer, shorter)
+}
+
+func countMatchingTokens(source, target []string) int {
+	matched := 0
+	for _, token := range source {
+		for _, candidate := range target {
+			if receiptTokensMatch(token, candidate) {
+				matched++
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// receiptMatchScore scores how well a receipt description describes an item
+// name. Covering the item name weighs most; covering the description breaks
+// ties between "Leite" matching "LEITE UHT" and "LEITE CONDENSADO".
+func receiptMatchScore(name, description string) float64 {
+	nameTokens := receiptTokens(name)
+	descriptionTokens := receiptTokens(description)
+	if len(nameTokens) == 0 || len(descriptionTokens) == 0 {
+		return 0
+	}
+	nameCoverage := float64(countMatchingTokens(nameTokens, descriptionTokens)) / float64(len(nameTokens))
+	if nameCoverage < 0.5 {
+		return 0
+	}
+	descriptionCoverage := float64(countMatchingTokens(descriptionTokens, nameTokens)) / float64(len(descriptionTokens))
+	return 0.8*nameCoverage + 0.2*descriptionCoverage
+}
+
+// assignReceiptLines matches each line to at most one candidate and each
+// candidate to at most one line, best scores first. Unassigned lines get -1.
+func assignReceiptLines(lines []nfce.Line, candidates []string) ([]int, []float64) {
+	type pair struct {
+		line      int
+		candidate int
+		score     float64
+	}
+	var pairs []pair
+	for lineIdx, line := range lines {
+		for candidateIdx, candidate := range candidates {
+			if score := receiptMatchScore(candidate, line.Description); score >= receiptMatchThreshold {
+				pairs = append(pairs, pair{line: lineIdx, candidate: candidateIdx, score: score})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].score > pairs[j].score
+	})
+
+	assigned := make([]int, len(lines))
+	scores := make([]float64, len(lines))
+	for idx := range assigned {
+		assigned[idx] = -1
+	}
+	used := make(map[int]bool, len(candidates))
+	for _, p := range pairs {
+		if assigned[p.line] >= 0 || used[p.candidate] {
+			continue
+		}
+		assigned[p.line] = p.candidate
+		scores[p.line] = p.score
+		used[p.candidate] = true
+	}
+	return assigned, scores
+}
+
+// mergeReceiptLines combines repeated lines of the same product, which is
+// how most cashiers register several units scanned one by one.
+func mergeReceiptLines(lines []nfce.Line) []nfce.Line {
+	merged := make([]nfce.Line, 0, len(lines))
+	index := make(map[string]int, len(lines))
+	for _, line := range lines {
+		key := "ean:" + line.EAN
+		if line.EAN == "" {
+			key = "desc:" + normalizeItemName(line.Description) + "|" + units.Normalize(line.Unit)
+		}
+		if idx, ok := index[key]; ok {
+			merged[idx].Quantity += line.Quantity
+			merged[idx].Total += line.Total
+			if merged[idx].Quantity > 0 {
+				merged[idx].UnitPrice = merged[idx].Total / merged[idx].Quantity
+			}
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, line)
+	}
+	return merged
+}
+
+// convertReceiptQuantity expresses a receipt line in the unit of the matched
+// item. Lines sold per package ("LEITE 1L", UN) are converted through the
+// package size printed in the description. When no conversion is possible the
+// receipt unit is kept.
+func convertReceiptQuantity(line nfce.Line, targetUnit string) (float64, float64, string) {
+	if strings.TrimSpace(targetUnit) != "" {
+		if quantity, ok := units.Convert(line.Quantity, line.Unit, targetUnit); ok && quantity > 0 {
+			return quantity, line.Total / quantity, targetUnit
+		}
+		lineUnit, _ := units.Lookup(line.Unit)
+		if lineUnit.Dimension == units.DimensionCount || lineUnit.Dimension == units.DimensionUnknown {
+			if amount, packageUnit, ok := line.PackageSize(); ok {
+				if quantity, ok := units.Convert(line.Quantity*amount, packageUnit, targetUnit); ok && quantity > 0 {
+					return quantity, line.Total / quantity, targetUnit
+				}
+			}
+		}
+	}
+	return line.Quantity, line.UnitPrice, units.Normalize(line.Unit)
+}
+
+func (s *shoppingListService) ImportReceipt(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, input dto.ImportReceiptDTO) (*dto.ReceiptImportResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	shoppingList, err := s.shoppingListRepo.GetByID(ctx, shoppingListID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShoppingListNotFound
+		}
+		logger.Error("Failed to get shopping list for receipt import",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "ImportReceipt"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get shopping list: %w", err)
+	}
+	if shoppingList.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	// A completed list already stocked the pantry; its checkout has to be
+	// reverted before the receipt changes what was bought.
+	previousStatus := listStatus(shoppingList)
+	if previousStatus == shoppingModel.StatusCompleted {
+		return nil, domain.ErrAlreadyCheckedOut
+	}
+	checkout := input.Checkout == nil || *input.Checkout
+	if checkout && !canTransition(previousStatus, shoppingModel.StatusCompleted) {
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrInvalidTransition, previousStatus, shoppingModel.StatusCompleted)
+	}
+
+	receipt, err := nfce.Parse(input.Content, nfce.Format(input.Format))
+	if err != nil {
+		logger.Warn("Invalid receipt",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "ImportReceipt"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("%w: %v", domain.ErrReceiptInvalid, err)
+	}
+	if len(receipt.Lines) == 0 {
+		return nil, domain.ErrReceiptWithoutItems
+	}
+
+	purchasedAt := time.Now().UTC()
+	if receipt.IssuedAt != nil {
+		purchasedAt = receipt.IssuedAt.UTC()
+	}
+
+	lines := mergeReceiptLines(receipt.Lines)
+	matches := make([]dto.ReceiptLineMatchDTO, len(lines))
+	for idx, line := range lines {
+		matches[idx] = dto.ReceiptLineMatchDTO{
+			LineNumber:  line.Number,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			Unit:        line.Unit,
+			UnitPrice:   roundCurrency(line.UnitPrice),
+			Total:       roundCurrency(line.Total),
+			MatchType:   receiptMatchUnmatched,
+		}
+	}
+
+	candidates := make([]string, len(shoppingList.Items))
+	for idx, item := range shoppingList.Items {
+		candidates[idx] = item.Name
+	}
+	assigned, scores := assignReceiptLines(lines, candidates)
+
+	var remaining, changed []int
+	for lineIdx, itemIdx := range assigned {
+		if itemIdx < 0 {
+			remaining = append(remaining, lineIdx)
+			continue
+		}
+		item := &shoppingList.Items[itemIdx]
+		item.Quantity, item.ActualPrice, item.Unit = convertReceiptQuantity(lines[lineIdx], item.Unit)
+		item.Purchased = true
+		at := purchasedAt
+		item.PurchasedAt = &at
+		changed = append(changed, itemIdx)
+
+		itemID := item.ID.String()
+		matches[lineIdx].MatchType = receiptMatchListItem
+		matches[lineIdx].ItemID = &itemID
+		matches[lineIdx].MatchedName = item.Name
+		matches[lineIdx].Score = scores[lineIdx]
+	}
+
+	if len(remaining) > 0 {
+		added, err := s.addReceiptLinesToList(ctx, shoppingList, lines, remaining, matches, purchasedAt, input.AddUnmatched)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range added {
+			changed = append(changed, len(shoppingList.Items)+idx)
+		}
+		shoppingList.Items = append(shoppingList.Items, added...)
+	}
+	items := make([]*shoppingModel.ShoppingListItem, len(changed))
+	for idx, itemIdx := range changed {
+		items[idx] = &shoppingList.Items[itemIdx]
+	}
+
+	estimatedTotal, actualTotal := calculateListTotals(shoppingList.Items)
+	shoppingList.EstimatedCost = estimatedTotal
+	shoppingList.ActualCost = actualTotal
+
+	// The checkout saves the purchased items and the list with its totals in
+	// the transaction that completes it, so a failed checkout leaves the list
+	// as it was.
+	checkedOut := false
+	if checkout && shoppingList.Status != shoppingModel.StatusCompleted {
+		if err := s.performCheckout(ctx, userID, shoppingList, purchasedAt, nil); err != nil {
+			logger.Error("Failed to perform checkout after receipt import",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "ImportReceipt"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("shopping_list_id", shoppingListID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		checkedOut = true
+	} else if err := s.shoppingListRepo.SaveItems(ctx, shoppingList, items); err != nil {
+		return nil, fmt.Errorf("save shopping list items: %w", err)
+	}
+	if checkedOut {
+		s.recordStatusChange(ctx, userID, shoppingList.ID, previousStatus, shoppingModel.StatusCompleted, "receipt imported")
+	}
+	for _, item := range items {
+		s.recordPurchasePrice(ctx, shoppingList, *item)
+	}
+
+	updated, err := s.shoppingListRepo.GetByID(ctx, shoppingList.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShoppingListNotFound
+		}
+		return nil, fmt.Errorf("reload shopping list: %w", err)
+	}
+
+	result := &dto.ReceiptImportResponseDTO{
+		AccessKey:    receipt.AccessKey,
+		IssuerName:   receipt.IssuerName,
+		IssuerCNPJ:   receipt.IssuerCNPJ,
+		ReceiptTotal: receipt.Total,
+		Lines:        matches,
+		CheckedOut:   checkedOut,
+		ShoppingList: s.convertToResponseDTO(ctx, updated),
+	}
+	if receipt.IssuedAt != nil {
+		issuedAt := receipt.IssuedAt.Format(time.RFC3339)
+		result.IssuedAt = &issuedAt
+	}
+	for _, match := range matches {
+		if match.MatchType == receiptMatchUnmatched {
+			result.UnmatchedLines++
+		} else {
+			result.MatchedLines++
+		}
+	}
+
+	logger.Info("Receipt imported successfully",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "ImportReceipt"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", shoppingListID.String()),
+		zap.Int("matched_lines", result.MatchedLines),
+		zap.Int("unmatched_lines", result.UnmatchedLines),
+		zap.Bool("checkout_performed", checkedOut),
+	)
+
+	return result, nil
+}
+
+// addReceiptLinesToList builds new purchased items for the receipt lines that
+// are not on the list; they are saved with the list. Lines matching a pantry
+// item are linked to it so checkout restocks that item; other lines are only
+// added when addUnmatched is set.
+func (s *shoppingListService) addReceiptLinesToList(ctx context.Context, sl *shoppingModel.ShoppingList, lines []nfce.Line, remaining []int, matches []dto.ReceiptLineMatchDTO, purchasedAt time.Time, addUnmatched bool) ([]shoppingModel.ShoppingListItem, error) {
+	var pantryItems []*itemModel.Item
+	if sl.PantryID != nil && s.itemRepo != nil {
+		items, err := s.itemRepo.ListByPantryID(ctx, *sl.PantryID)
+		if err != nil {
+			return nil, fmt.Errorf("list pantry items: %w", err)
+		}
+		pantryItems = items
+	}
+
+	remainingLines := make([]nfce.Line, len(remaining))
+	for idx, lineIdx := range remaining {
+		remainingLines[idx] = lines[lineIdx]
+	}
+	pantryNames := make([]string, len(pantryItems))
+	for idx, pantryItem := range pantryItems {
+		pantryNames[idx] = pantryItem.Name
+	}
+	assigned, scores := assignReceiptLines(remainingLines, pantryNames)
+
+	var added []shoppingModel.ShoppingListItem
+	for idx, lineIdx := range remaining {
+		line := lines[lineIdx]
+		at := purchasedAt
+		item := shoppingModel.ShoppingListItem{
+			ID:             uuid.New(),
+			ShoppingListID: sl.ID,
+			Name:           line.Description,
+			Priority:       3,
+			Purchased:      true,
+			PurchasedAt:    &at,
+			Source:         itemSourceReceipt,
+		}
+
+		if pantryIdx := assigned[idx]; pantryIdx >= 0 {
+			pantryItem := pantryItems[pantryIdx]
+			pantryItemID := pantryItem.ID
+			item.Name = pantryItem.Name
+			item.PantryItemID = &pantryItemID
+			item.Quantity, item.ActualPrice, item.Unit = convertReceiptQuantity(line, pantryItem.Unit)
+
+			pantryItemIDStr := pantryItemID.String()
+			matches[lineIdx].MatchType = receiptMatchPantryItem
+			matches[lineIdx].PantryItemID = &pantryItemIDStr
+			matches[lineIdx].MatchedName = pantryItem.Name
+			matches[lineIdx].Score = scores[idx]
+		} else if addUnmatched {
+			item.Quantity, item.ActualPrice, item.Unit = convertReceiptQuantity(line, "")
+			matches[lineIdx].MatchType = receiptMatchAdded
+		} else {
+			continue
+		}
+		item.EstimatedPrice = item.ActualPrice
+
+		itemID := item.ID.String()
+		matches[lineIdx].ItemID = &itemID
+		added = append(added, item)
+	}
+	return added, nil
+}
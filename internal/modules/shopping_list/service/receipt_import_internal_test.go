@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nclsgg/despensa-digital/backend/pkg/nfce"
+)
+
+func loadReceiptFixture(t *testing.T) *nfce.Receipt {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "pkg", "nfce", "testdata", "nfce_proc.xml"))
+	require.NoError(t, err)
+	receipt, err := nfce.ParseXML(data)
+	require.NoError(t, err)
+	return receipt
+}
+
+func TestAssignReceiptLinesMatchesAbbreviatedDescriptions(t *testing.T) {
+	receipt := loadReceiptFixture(t)
+	names := []string{"Leite integral", "Feijão carioca", "Arroz", "Banana", "Sabão em pó"}
+
+	assigned, scores := assignReceiptLines(receipt.Lines, names)
+	require.Equal(t, []int{2, 1, 3, 0, -1}, assigned)
+	for idx, candidate := range assigned {
+		if candidate >= 0 {
+			require.GreaterOrEqual(t, scores[idx], receiptMatchThreshold)
+		}
+	}
+}
+
+func TestReceiptMatchScorePrefersCloserDescription(t *testing.T) {
+	uht := receiptMatchScore("Leite", "LEITE UHT INTEG ITALAC 1L")
+	condensed := receiptMatchScore("Leite condensado", "LEITE COND MOCOCA 395G")
+	require.Greater(t, condensed, uht)
+	require.Zero(t, receiptMatchScore("Café", "LEITE UHT INTEG ITALAC 1L"))
+}
+
+func TestConvertReceiptQuantity(t *testing.T) {
+	receipt := loadReceiptFixture(t)
+
+	quantity, price, unit := convertReceiptQuantity(receipt.Lines[2], "g")
+	require.InDelta(t, 1235, quantity, 1e-9)
+	require.InDelta(t, 8.63/1235, price, 1e-9)
+	require.Equal(t, "g", unit)
+
+	quantity, price, unit = convertReceiptQuantity(receipt.Lines[3], "l")
+	require.InDelta(t, 6, quantity, 1e-9)
+	require.InDelta(t, 5, price, 1e-9)
+	require.Equal(t, "l", unit)
+
+	quantity, price, unit = convertReceiptQuantity(receipt.Lines[1], "")
+	require.InDelta(t, 2, quantity, 1e-9)
+	require.InDelta(t, 8.49, price, 1e-9)
+	require.Equal(t, "un", unit)
+}
+
+func TestMergeReceiptLinesCombinesRepeatedProducts(t *testing.T) {
+	lines := []nfce.Line{
+		{Number: 1, EAN: "789", Description: "IOGURTE", Unit: "UN", Quantity: 1, UnitPrice: 3, Total: 3},
+		{Number: 2, Description: "PAO FRANCES", Unit: "KG", Quantity: 0.5, UnitPrice: 16, Total: 8},
+		{Number: 3, EAN: "789", Description: "IOGURTE", Unit: "UN", Quantity: 1, UnitPrice: 3, Total: 3},
+	}
+	merged := mergeReceiptLines(lines)
+	require.Len(t, merged, 2)
+	require.Equal(t, 2.0, merged[0].Quantity)
+	require.InDelta(t, 6, merged[0].Total, 1e-9)
+}
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	itemSourceRecipe = "recipe"
+
+	ingredientCovered      = "covered"
+	ingredientInsufficient = "insufficient"
+	ingredientMissing      = "missing"
+)
+
+// ingredientNeed is the amount of an ingredient a recipe needs for the
+// requested servings. Amount is nil for ingredients used to taste.
+type ingredientNeed struct {
+	Name   string
+	Unit   string
+	Amount *float64
+}
+
+func (s *shoppingListService) AddRecipeToShoppingList(ctx context.Context, userID uuid.UUID, input dto.AddRecipeToShoppingListDTO) (*dto.RecipeShoppingResultDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if s.recipeRepo == nil {
+		return nil, domain.ErrRecipeNotFound
+	}
+	recipe, err := s.recipeRepo.FindByID(ctx, input.RecipeID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrRecipeNotFound
+		}
+		logger.Error("Failed to get recipe",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "AddRecipeToShoppingList"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("recipe_id", input.RecipeID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get recipe: %w", err)
+	}
+
+	var target *shoppingModel.ShoppingList
+	if input.ShoppingListID != nil {
+		target, err = s.loadOwnedList(ctx, userID, *input.ShoppingListID, "AddRecipeToShoppingList")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pantryID := input.PantryID
+	if pantryID == nil && target != nil {
+		pantryID = target.PantryID
+	}
+	var stock map[string]*ruleStock
+	if pantryID != nil {
+		hasAccess, err := s.pantryRepo.IsUserInPantry(ctx, *pantryID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("check pantry access: %w", err)
+		}
+		if !hasAccess {
+			return nil, domain.ErrPantryAccessDenied
+		}
+		items, err := s.itemRepo.ListByPantryID(ctx, *pantryID)
+		if err != nil {
+			return nil, fmt.Errorf("list pantry items: %w", err)
+		}
+		stock = indexRuleStock(items, time.Time{})
+	}
+
+	servings := input.Servings
+	if servings <= 0 {
+		servings = recipe.ServingCount()
+	}
+	needs := scaleRecipeIngredients(recipe, servings)
+	items, report := planRecipePurchases(needs, stock)
+	for idx := range items {
+		recipeID := recipe.ID
+		items[idx].RecipeID = &recipeID
+	}
+
+	result := &dto.RecipeShoppingResultDTO{
+		RecipeID:    recipe.ID.String(),
+		Servings:    servings,
+		Ingredients: report,
+	}
+
+	if target == nil {
+		if len(items) == 0 {
+			// Everything is in the pantry: there is nothing to create.
+			return result, nil
+		}
+		created, err := s.createRecipeList(ctx, userID, recipe, pantryID, input.Name, items)
+		if err != nil {
+			return nil, err
+		}
+		result.Created = true
+		result.ShoppingList = created
+		return result, nil
+	}
+
+	existing := len(target.Items)
+	lines := addItemsToList(target, items)
+	// The items follow the order of the ingredients not covered.
+	next := 0
+	for idx := range result.Ingredients {
+		if result.Ingredients[idx].Status == ingredientCovered {
+			continue
+		}
+		result.Ingredients[idx].Merged = lines[next] < existing
+		next++
+	}
+	if len(items) > 0 {
+		seen := make(map[int]bool, len(lines))
+		saved := make([]*shoppingModel.ShoppingListItem, 0, len(lines))
+		for _, pos := range lines {
+			if seen[pos] {
+				continue
+			}
+			seen[pos] = true
+			saved = append(saved, &target.Items[pos])
+		}
+		target.EstimatedCost, target.ActualCost = calculateListTotals(target.Items)
+		if err := s.shoppingListRepo.MoveItems(ctx, saved, nil, []*shoppingModel.ShoppingList{target}); err != nil {
+			logger.Error("Failed to add recipe ingredients to shopping list",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "AddRecipeToShoppingList"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("shopping_list_id", target.ID.String()),
+				zap.String("recipe_id", recipe.ID.String()),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("save shopping list items: %w", err)
+		}
+	}
+
+	logger.Info("Recipe ingredients added to shopping list",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "AddRecipeToShoppingList"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", target.ID.String()),
+		zap.String("recipe_id", recipe.ID.String()),
+		zap.Int(appLogger.FieldCount, len(items)),
+	)
+
+	result.ShoppingList, err = s.reloadShoppingList(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) > 0 {
+		result.ShoppingList.DietaryWarnings = s.checkDietary(ctx, target, itemNames(items))
+	}
+	return result, nil
+}
+
+func (s *shoppingListService) createRecipeList(ctx context.Context, userID uuid.UUID, recipe *recipeModel.Recipe, pantryID *uuid.UUID, name string, items []shoppingModel.ShoppingListItem) (*dto.ShoppingListResponseDTO, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = recipe.Title
+	}
+	return s.createSourcedList(ctx, userID, pantryID, name, itemSourceRecipe, "created from recipe "+recipe.ID.String(), items, "AddRecipeToShoppingList")
+}
+
+// createSourcedList creates a pending list with the items, marked as generated
+// by source. Reason is recorded in the status history.
+func (s *shoppingListService) createSourcedList(ctx context.Context, userID uuid.UUID, pantryID *uuid.UUID, name string, source string, reason string, items []shoppingModel.ShoppingListItem, function string) (*dto.ShoppingListResponseDTO, error) {
+	preferences, err := s.resolvePreferences(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	shoppingList := &shoppingModel.ShoppingList{
+		UserID:              userID,
+		PantryID:            pantryID,
+		Name:                name,
+		Status:              shoppingModel.StatusPending,
+		GeneratedBy:         source,
+		HouseholdSize:       preferences.HouseholdSize,
+		MonthlyIncome:       preferences.MonthlyIncome,
+		DietaryRestrictions: shoppingModel.StringArray(normalizeStringSlice(preferences.DietaryRestrictions)),
+		Items:               items,
+	}
+	shoppingList.EstimatedCost, shoppingList.ActualCost = calculateListTotals(shoppingList.Items)
+
+	if err := s.shoppingListRepo.Create(ctx, shoppingList); err != nil {
+		appLogger.FromContext(ctx).Error("Failed to create generated shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("generated_by", source),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("create shopping list: %w", err)
+	}
+	s.recordStatusChange(ctx, userID, shoppingList.ID, "", shoppingList.Status, reason)
+
+	appLogger.FromContext(ctx).Info("Generated shopping list created",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, function),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", shoppingList.ID.String()),
+		zap.String("generated_by", source),
+		zap.Int(appLogger.FieldCount, len(items)),
+	)
+
+	result, err := s.reloadNewList(ctx, userID, shoppingList.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.DietaryWarnings = s.checkDietary(ctx, shoppingList, itemNames(shoppingList.Items))
+	return result, nil
+}
+
+// addItemsToList folds the items into the pending lines of the list with the
+// same name and unit, appending the others. Lines that received an item keep
+// their source and are linked to the recipe when they were not linked yet.
+// It returns the position of the line each item went to.
+func addItemsToList(sl *shoppingModel.ShoppingList, items []shoppingModel.ShoppingListItem) []int {
+	positions := make(map[string]int, len(sl.Items))
+	for idx, line := range sl.Items {
+		if line.Purchased {
+			continue
+		}
+		if _, ok := positions[itemMergeKey(line)]; !ok {
+			positions[itemMergeKey(line)] = idx
+		}
+	}
+
+	lines := make([]int, len(items))
+	for idx, item := range items {
+		key := itemMergeKey(item)
+		if pos, ok := positions[key]; ok {
+			line := &sl.Items[pos]
+			mergeListItem(line, item)
+			if line.RecipeID == nil {
+				line.RecipeID = item.RecipeID
+			}
+			lines[idx] = pos
+			continue
+		}
+		item.ShoppingListID = sl.ID
+		positions[key] = len(sl.Items)
+		lines[idx] = len(sl.Items)
+		sl.Items = append(sl.Items, item)
+	}
+	return lines
+}
+
+// scaleRecipeIngredients scales the ingredients of a recipe to the servings,
+// summing the ingredients listed more than once in convertible units.
+func scaleRecipeIngredients(recipe *recipeModel.Recipe, servings int) []ingredientNeed {
+	return appendRecipeNeeds(nil, make(map[string][]int), recipe, servings)
+}
+
+// appendRecipeNeeds adds the ingredients of a recipe scaled to the servings to
+// the needs, summing them with the needs of the same ingredient in a
+// convertible unit. Positions indexes the needs by normalized name.
+func appendRecipeNeeds(needs []ingredientNeed, positions map[string][]int, recipe *recipeModel.Recipe, servings int) []ingredientNeed {
+	scale := float64(servings) / float64(recipe.ServingCount())
+
+	for _, ingredient := range recipe.Ingredients {
+		key := normalizeItemName(ingredient.Name)
+		if key == "" {
+			continue
+		}
+		need := ingredientNeed{Name: strings.TrimSpace(ingredient.Name), Unit: strings.TrimSpace(ingredient.Unit)}
+		if ingredient.Amount != nil && *ingredient.Amount > 0 {
+			amount := *ingredient.Amount * scale
+			need.Amount = &amount
+		}
+
+		folded := false
+		for _, pos := range positions[key] {
+			existing := &needs[pos]
+			if need.Amount == nil {
+				folded = true
+				break
+			}
+			if existing.Amount == nil {
+				continue
+			}
+			if converted, ok := units.Convert(*need.Amount, need.Unit, existing.Unit); ok {
+				total := *existing.Amount + converted
+				existing.Amount = &total
+				folded = true
+				break
+			}
+		}
+		if folded {
+			continue
+		}
+		positions[key] = append(positions[key], len(needs))
+		needs = append(needs, need)
+	}
+	return needs
+}
+
+// planRecipePurchases compares the needs with the pantry stock and builds the
+// items to buy. Quantities are bought in the unit of the pantry item when it
+// is convertible and otherwise in the most readable unit of the recipe unit,
+// so kitchen measures become millilitres. An ingredient in stock in a unit
+// that cannot be compared is taken as covered.
+func planRecipePurchases(needs []ingredientNeed, stock map[string]*ruleStock) ([]shoppingModel.ShoppingListItem, []dto.RecipeIngredientNeedDTO) {
+	items := make([]shoppingModel.ShoppingListItem, 0, len(needs))
+	report := make([]dto.RecipeIngredientNeedDTO, 0, len(needs))
+	for _, need := range needs {
+		entry := stock[normalizeItemName(need.Name)]
+		line := dto.RecipeIngredientNeedDTO{Name: need.Name, Unit: need.Unit, Status: ingredientMissing}
+		if need.Amount != nil {
+			amount := math.Round(*need.Amount*100) / 100
+			line.Needed = &amount
+		}
+
+		var toBuy float64
+		var buyUnit string
+		switch {
+		case need.Amount == nil:
+			if entry != nil && entry.Available > 0 {
+				line.InStock = entry.Available
+				line.Status = ingredientCovered
+				break
+			}
+			toBuy, buyUnit = 1, "un"
+			if entry != nil {
+				buyUnit = entry.Unit
+			}
+		case entry != nil && entry.Available > 0:
+			have, ok := units.Convert(entry.Available, entry.Unit, need.Unit)
+			if !ok {
+				line.InStock = entry.Available
+				line.Status = ingredientCovered
+				break
+			}
+			line.InStock = math.Round(have*100) / 100
+			missing := *need.Amount - have
+			if missing <= 1e-9 {
+				line.Status = ingredientCovered
+				break
+			}
+			line.Status = ingredientInsufficient
+			toBuy, _ = units.Convert(missing, need.Unit, entry.Unit)
+			buyUnit = entry.Unit
+		case entry != nil:
+			if converted, ok := units.Convert(*need.Amount, need.Unit, entry.Unit); ok {
+				toBuy, buyUnit = converted, entry.Unit
+				break
+			}
+			toBuy, buyUnit = purchaseQuantity(*need.Amount, need.Unit)
+		default:
+			toBuy, buyUnit = purchaseQuantity(*need.Amount, need.Unit)
+		}
+
+		if line.Status != ingredientCovered {
+			toBuy = roundRuleQuantity(toBuy, buyUnit)
+			line.ToBuy = toBuy
+			line.BuyUnit = buyUnit
+			item := shoppingModel.ShoppingListItem{
+				Name:           need.Name,
+				Quantity:       toBuy,
+				Unit:           buyUnit,
+				EstimatedPrice: roundCurrency(ruleUnitPrice(entry, nil, buyUnit)),
+				Priority:       2,
+				Source:         itemSourceRecipe,
+			}
+			if entry != nil {
+				itemID := entry.ItemID
+				item.PantryItemID = &itemID
+				item.Name = entry.Name
+			}
+			items = append(items, item)
+		}
+		report = append(report, line)
+	}
+	return items, report
+}
+
+// purchaseQuantity expresses a recipe amount in a unit sold in stores.
+func purchaseQuantity(amount float64, unit string) (float64, string) {
+	if strings.TrimSpace(unit) == "" {
+		return amount, "un"
+	}
+	base, baseUnit := units.ToBase(amount, unit)
+	return units.Humanize(base, baseUnit.Symbol)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRecipePurchasesComparesScaledNeedsWithStock(t *testing.T) {
+	amount := func(v float64) *float64 { return &v }
+	servingSize := 2
+	recipe := &recipeModel.Recipe{
+		ServingSize: &servingSize,
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Arroz", Amount: amount(200), Unit: "g"},
+			{Name: "Leite", Amount: amount(1), Unit: "xícara"},
+			{Name: "Ovo", Amount: amount(2), Unit: "un"},
+			{Name: "ovo", Amount: amount(1), Unit: "unidade"},
+			{Name: "Sal", Unit: "a gosto"},
+			{Name: "Cebolinha"},
+		},
+	}
+	riceID := uuid.New()
+	stock := indexRuleStock([]*itemModel.Item{
+		{ID: riceID, Name: "arroz", Quantity: 0.1, Unit: "kg", PricePerUnit: 6},
+		{ID: uuid.New(), Name: "Ovo", Quantity: 12, Unit: "un"},
+		{ID: uuid.New(), Name: "Sal", Quantity: 1, Unit: "kg"},
+	}, time.Time{})
+
+	items, report := planRecipePurchases(scaleRecipeIngredients(recipe, 4), stock)
+
+	require.Len(t, report, 5)
+	require.Equal(t, ingredientInsufficient, report[0].Status)
+	require.Equal(t, 400.0, *report[0].Needed)
+	require.Equal(t, 100.0, report[0].InStock)
+	require.Equal(t, ingredientMissing, report[1].Status)
+	require.Equal(t, ingredientCovered, report[2].Status)
+	require.Equal(t, 6.0, *report[2].Needed)
+	require.Equal(t, ingredientCovered, report[3].Status)
+	require.Equal(t, ingredientMissing, report[4].Status)
+
+	require.Len(t, items, 3)
+	require.Equal(t, "arroz", items[0].Name)
+	require.Equal(t, 0.3, items[0].Quantity)
+	require.Equal(t, "kg", items[0].Unit)
+	require.Equal(t, 6.0, items[0].EstimatedPrice)
+	require.Equal(t, &riceID, items[0].PantryItemID)
+	require.Equal(t, itemSourceRecipe, items[0].Source)
+
+	require.Equal(t, "Leite", items[1].Name)
+	require.Equal(t, 480.0, items[1].Quantity)
+	require.Equal(t, "ml", items[1].Unit)
+
+	require.Equal(t, "Cebolinha", items[2].Name)
+	require.Equal(t, 1.0, items[2].Quantity)
+	require.Equal(t, "un", items[2].Unit)
+}
+
+func TestAddItemsToListFoldsIntoPendingLines(t *testing.T) {
+	listID, recipeID, milkID := uuid.New(), uuid.New(), uuid.New()
+	sl := &shoppingModel.ShoppingList{ID: listID, Items: []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), ShoppingListID: listID, Name: "Leite", Quantity: 1, Unit: "ml", Purchased: true},
+		{ID: milkID, ShoppingListID: listID, Name: "leite", Quantity: 500, Unit: "mililitros", EstimatedPrice: 0.01, Source: "manual"},
+	}}
+
+	lines := addItemsToList(sl, []shoppingModel.ShoppingListItem{
+		{Name: "Leite", Quantity: 480, Unit: "ml", Source: itemSourceRecipe, RecipeID: &recipeID},
+		{Name: "Cebolinha", Quantity: 1, Unit: "un", Source: itemSourceRecipe, RecipeID: &recipeID},
+	})
+
+	require.Equal(t, []int{1, 2}, lines)
+	require.Len(t, sl.Items, 3)
+	require.Equal(t, 980.0, sl.Items[1].Quantity)
+	require.Equal(t, "manual", sl.Items[1].Source)
+	require.Equal(t, &recipeID, sl.Items[1].RecipeID)
+	require.Equal(t, listID, sl.Items[2].ShoppingListID)
+	require.Equal(t, itemSourceRecipe, sl.Items[2].Source)
+}
+
+func TestAppendRecipeNeedsSumsPlannedMeals(t *testing.T) {
+	amount := func(v float64) *float64 { return &v }
+	servingSize := 2
+	stroganoff := &recipeModel.Recipe{
+		ServingSize: &servingSize,
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Frango", Amount: amount(300), Unit: "g"},
+			{Name: "Creme de leite", Amount: amount(1), Unit: "caixa"},
+		},
+	}
+	grilled := &recipeModel.Recipe{
+		Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "frango", Amount: amount(0.2), Unit: "kg"},
+			{Name: "Limão", Amount: amount(1), Unit: "un"},
+		},
+	}
+
+	var needs []ingredientNeed
+	positions := make(map[string][]int)
+	needs = appendRecipeNeeds(needs, positions, stroganoff, 4)
+	needs = appendRecipeNeeds(needs, positions, grilled, 2)
+
+	require.Len(t, needs, 3)
+	require.Equal(t, "Frango", needs[0].Name)
+	require.InDelta(t, 1000.0, *needs[0].Amount, 1e-9)
+	require.Equal(t, 2.0, *needs[1].Amount)
+	require.Equal(t, 2.0, *needs[2].Amount)
+
+	stock := indexRuleStock([]*itemModel.Item{{ID: uuid.New(), Name: "Frango", Quantity: 0.5, Unit: "kg"}}, time.Time{})
+	items, report := planRecipePurchases(needs, stock)
+	require.Equal(t, ingredientInsufficient, report[0].Status)
+	require.Len(t, items, 3)
+	require.Equal(t, 0.5, items[0].Quantity)
+	require.Equal(t, "kg", items[0].Unit)
+}
+
+func TestMealPlanPeriodDefaultsToTheWeek(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 15, 30, 0, 0, time.UTC)
+
+	from, to, err := mealPlanPeriod("", "", now)
+	require.NoError(t, err)
+	require.Equal(t, "2026-03-02", from.Format(recipeDomain.MealPlanDateLayout))
+	require.Equal(t, "2026-03-08", to.Format(recipeDomain.MealPlanDateLayout))
+
+	_, _, err = mealPlanPeriod("2026-03-10", "2026-03-02", now)
+	require.ErrorIs(t, err, domain.ErrInvalidPeriod)
+	_, _, err = mealPlanPeriod("02/03/2026", "", now)
+	require.ErrorIs(t, err, domain.ErrInvalidPeriod)
+}
@@ -0,0 +1,455 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	profileModel "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/model"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+)
+
+const (
+	generatorModeAI    = "ai"
+	generatorModeRules = "rules"
+
+	generatedByRules = "rules"
+
+	ruleSourceParLevel   = "rule_par_level"
+	ruleSourceOutOfStock = "rule_out_of_stock"
+	ruleSourceFrequency  = "rule_frequency"
+	ruleSourceExpiring   = "rule_expiring"
+)
+
+// ruleGeneratorConfig holds the rules of the deterministic generator.
+type ruleGeneratorConfig struct {
+	// HorizonDays is how long the list has to last.
+	HorizonDays int
+	// HouseholdSize multiplies the per person par levels.
+	HouseholdSize int
+	// ExpiringWithinDays is how close to its expiration an item stops
+	// counting as stock and is replaced.
+	ExpiringWithinDays int
+	// LookbackDays is how far back purchases count for frequency.
+	LookbackDays int
+	// MinPurchases is how many purchases make a product a regular one.
+	MinPurchases int
+}
+
+var defaultRuleGeneratorConfig = ruleGeneratorConfig{
+	HorizonDays:        7,
+	HouseholdSize:      1,
+	ExpiringWithinDays: 3,
+	LookbackDays:       90,
+	MinPurchases:       2,
+}
+
+// horizonDays is how long a list of the given shopping type has to last,
+// falling back to the shopping frequency of the profile.
+func horizonDays(shoppingType string, profile *profileModel.Profile) int {
+	switch shoppingType {
+	case "emergency":
+		return 3
+	case "weekly":
+		return 7
+	case "monthly":
+		return 30
+	case "stock_up":
+		return 60
+	}
+	if profile != nil {
+		switch profile.ShoppingFrequency {
+		case "biweekly":
+			return 14
+		case "monthly":
+			return 30
+		}
+	}
+	return defaultRuleGeneratorConfig.HorizonDays
+}
+
+func resolveRuleGeneratorConfig(input dto.GenerateAIShoppingListDTO, preferences shoppingPreferences, profile *profileModel.Profile) ruleGeneratorConfig {
+	cfg := defaultRuleGeneratorConfig
+	cfg.HorizonDays = horizonDays(input.ShoppingType, profile)
+	if preferences.HouseholdSize > 0 {
+		cfg.HouseholdSize = preferences.HouseholdSize
+	}
+	if input.PeopleCount != nil && *input.PeopleCount > 0 {
+		cfg.HouseholdSize = *input.PeopleCount
+	}
+	if rules := input.Rules; rules != nil {
+		if rules.HorizonDays != nil {
+			cfg.HorizonDays = *rules.HorizonDays
+		}
+		if rules.ExpiringWithinDays != nil {
+			cfg.ExpiringWithinDays = *rules.ExpiringWithinDays
+		}
+		if rules.LookbackDays != nil {
+			cfg.LookbackDays = *rules.LookbackDays
+		}
+		if rules.MinPurchases != nil {
+			cfg.MinPurchases = *rules.MinPurchases
+		}
+	}
+	return cfg
+}
+
+// generateRuleBasedList builds a list from the pantry stock, its par levels
+// and purchase history without calling the LLM.
+func (s *shoppingListService) generateRuleBasedList(ctx context.Context, userID uuid.UUID, input dto.GenerateAIShoppingListDTO, budget float64, preferences shoppingPreferences, profile *profileModel.Profile) (*shoppingModel.ShoppingList, error) {
+	cfg := resolveRuleGeneratorConfig(input, preferences, profile)
+	now := time.Now().UTC()
+
+	var stock []*itemModel.Item
+	if s.itemRepo != nil {
+		items, err := s.itemRepo.ListByPantryID(ctx, input.PantryID)
+		if err != nil {
+			return nil, fmt.Errorf("list pantry items: %w", err)
+		}
+		stock = items
+	}
+
+	var parLevels []*shoppingModel.ParLevel
+	if s.parLevelRepo != nil {
+		levels, err := s.parLevelRepo.ListByPantryID(ctx, input.PantryID)
+		if err != nil {
+			return nil, fmt.Errorf("list par levels: %w", err)
+		}
+		parLevels = levels
+	}
+
+	history, err := s.shoppingListRepo.ListPurchaseHistory(ctx, input.PantryID, now.AddDate(0, 0, -cfg.LookbackDays))
+	if err != nil {
+		return nil, fmt.Errorf("list purchase history: %w", err)
+	}
+
+	pantryID := input.PantryID
+	shoppingList := &shoppingModel.ShoppingList{
+		UserID:              userID,
+		PantryID:            &pantryID,
+		Name:                input.Name,
+		Status:              shoppingModel.StatusPending,
+		TotalBudget:         budget,
+		GeneratedBy:         generatedByRules,
+		HouseholdSize:       preferences.HouseholdSize,
+		MonthlyIncome:       preferences.MonthlyIncome,
+		DietaryRestrictions: shoppingModel.StringArray(normalizeStringSlice(preferences.DietaryRestrictions)),
+		Items:               buildRuleBasedItems(stock, parLevels, history, input.ExcludeItems, cfg, now),
+	}
+	shoppingList.EstimatedCost, shoppingList.ActualCost = calculateListTotals(shoppingList.Items)
+
+	appLogger.FromContext(ctx).Info("Rule based shopping list generated",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "generateRuleBasedList"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("pantry_id", input.PantryID.String()),
+		zap.Int(appLogger.FieldCount, len(shoppingList.Items)),
+	)
+	return shoppingList, nil
+}
+
+// ruleStock is the stock of one product in the pantry, in the unit of the
+// first pantry item with its name.
+type ruleStock struct {
+	Name         string
+	Unit         string
+	Available    float64
+	Expiring     float64
+	PricePerUnit float64
+	ItemID       uuid.UUID
+}
+
+// rulePurchases summarizes how a product was bought in the lookback window,
+// in the unit of its most recent purchase.
+type rulePurchases struct {
+	Name         string
+	Unit         string
+	Category     string
+	Total        float64
+	Count        int
+	First        time.Time
+	Last         time.Time
+	PricePerUnit float64
+}
+
+func (p *rulePurchases) averageQuantity() float64 {
+	if p.Count == 0 {
+		return 0
+	}
+	return p.Total / float64(p.Count)
+}
+
+// intervalDays is the average number of days between two purchases.
+func (p *rulePurchases) intervalDays() float64 {
+	if p.Count < 2 {
+		return 0
+	}
+	days := p.Last.Sub(p.First).Hours() / 24 / float64(p.Count-1)
+	return math.Max(days, 1)
+}
+
+// buildRuleBasedItems applies the generator rules in order of importance,
+// each product being added by the first rule that asks for it:
+//
+//  1. par levels: top up the stock not about to expire to the par level;
+//  2. out of stock: pantry items that ran out;
+//  3. purchase frequency: products bought at least MinPurchases times, for
+//     as many purchase cycles as fit in the horizon, minus the stock;
+//  4. expiring: replace the stock that expires within ExpiringWithinDays.
+//
+// The result is sorted by priority and name, so the same inputs always build
+// the same list.
+func buildRuleBasedItems(stock []*itemModel.Item, parLevels []*shoppingModel.ParLevel, history []*shoppingModel.ShoppingList, exclude []string, cfg ruleGeneratorConfig, now time.Time) []shoppingModel.ShoppingListItem {
+	stockByName := indexRuleStock(stock, now.AddDate(0, 0, cfg.ExpiringWithinDays))
+	purchasesByName := indexRulePurchases(history)
+
+	excluded := make([]string, 0, len(exclude))
+	for _, name := range exclude {
+		if key := normalizeItemName(name); key != "" {
+			excluded = append(excluded, key)
+		}
+	}
+
+	added := make(map[string]bool)
+	var items []shoppingModel.ShoppingListItem
+	add := func(key, name string, quantity float64, unit, category string, priority int, source string, pantryItemID *uuid.UUID) {
+		if added[key] || quantity <= 0 {
+			return
+		}
+		for _, skip := range excluded {
+			if strings.Contains(key, skip) {
+				return
+			}
+		}
+		added[key] = true
+		items = append(items, shoppingModel.ShoppingListItem{
+			Name:           name,
+			Quantity:       roundRuleQuantity(quantity, unit),
+			Unit:           unit,
+			EstimatedPrice: roundCurrency(ruleUnitPrice(stockByName[key], purchasesByName[key], unit)),
+			Category:       category,
+			Priority:       priority,
+			Source:         source,
+			PantryItemID:   pantryItemID,
+		})
+	}
+
+	for _, level := range parLevels {
+		key := level.NormalizedName
+		if key == "" {
+			key = normalizeItemName(level.Name)
+		}
+		target := level.Quantity
+		if level.PerPerson {
+			target *= float64(cfg.HouseholdSize)
+		}
+		have := 0.0
+		var itemID *uuid.UUID
+		if entry, ok := stockByName[key]; ok {
+			if converted, ok := units.Convert(entry.Available, entry.Unit, level.Unit); ok {
+				have = converted
+			}
+			id := entry.ItemID
+			itemID = &id
+		}
+		add(key, level.Name, target-have, level.Unit, level.Category, 1, ruleSourceParLevel, itemID)
+	}
+
+	for _, key := range sortedKeys(stockByName) {
+		entry := stockByName[key]
+		if entry.Available+entry.Expiring > 0 {
+			continue
+		}
+		quantity, unit, category := 1.0, entry.Unit, ""
+		if purchases, ok := purchasesByName[key]; ok {
+			if converted, ok := units.Convert(purchases.averageQuantity(), purchases.Unit, entry.Unit); ok {
+				quantity = converted
+			}
+			category = purchases.Category
+		}
+		id := entry.ItemID
+		add(key, entry.Name, quantity, unit, category, 1, ruleSourceOutOfStock, &id)
+	}
+
+	for _, key := range sortedKeys(purchasesByName) {
+		purchases := purchasesByName[key]
+		if purchases.Count < cfg.MinPurchases {
+			continue
+		}
+		interval := purchases.intervalDays()
+		cycles := math.Max(1, math.Ceil(float64(cfg.HorizonDays)/interval))
+		need := purchases.averageQuantity() * cycles
+
+		entry, tracked := stockByName[key]
+		var itemID *uuid.UUID
+		if tracked {
+			if converted, ok := units.Convert(entry.Available, entry.Unit, purchases.Unit); ok {
+				need -= converted
+			}
+			id := entry.ItemID
+			itemID = &id
+		} else if now.Sub(purchases.Last).Hours()/24+float64(cfg.HorizonDays) < interval {
+			// Not in the pantry and not due before the horizon ends.
+			continue
+		}
+		add(key, purchases.Name, need, purchases.Unit, purchases.Category, 2, ruleSourceFrequency, itemID)
+	}
+
+	for _, key := range sortedKeys(stockByName) {
+		entry := stockByName[key]
+		if entry.Expiring <= 0 {
+			continue
+		}
+		priority, category := 3, ""
+		if purchases, ok := purchasesByName[key]; ok {
+			category = purchases.Category
+			if purchases.Count >= cfg.MinPurchases {
+				priority = 2
+			}
+		}
+		id := entry.ItemID
+		add(key, entry.Name, entry.Expiring, entry.Unit, category, priority, ruleSourceExpiring, &id)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Priority != items[j].Priority {
+			return items[i].Priority < items[j].Priority
+		}
+		return normalizeItemName(items[i].Name) < normalizeItemName(items[j].Name)
+	})
+	return items
+}
+
+// indexRuleStock sums the pantry items by name. Items expiring before
+// expiringBefore are counted apart because they will not last.
+func indexRuleStock(stock []*itemModel.Item, expiringBefore time.Time) map[string]*ruleStock {
+	result := make(map[string]*ruleStock)
+	for _, item := range stock {
+		key := normalizeItemName(item.Name)
+		if key == "" {
+			continue
+		}
+		entry, ok := result[key]
+		if !ok {
+			entry = &ruleStock{Name: item.Name, Unit: item.Unit, PricePerUnit: item.PricePerUnit, ItemID: item.ID}
+			result[key] = entry
+		}
+		quantity, ok := units.Convert(math.Max(item.Quantity, 0), item.Unit, entry.Unit)
+		if !ok {
+			continue
+		}
+		if item.ExpiresAt != nil && item.ExpiresAt.Before(expiringBefore) {
+			entry.Expiring += quantity
+		} else {
+			entry.Available += quantity
+		}
+	}
+	return result
+}
+
+// indexRulePurchases summarizes the purchased items of the history by name,
+// counting one purchase per list.
+func indexRulePurchases(history []*shoppingModel.ShoppingList) map[string]*rulePurchases {
+	result := make(map[string]*rulePurchases)
+	for _, sl := range history {
+		listDate := sl.UpdatedAt
+		if sl.CompletedAt != nil {
+			listDate = *sl.CompletedAt
+		}
+		seen := make(map[string]bool)
+		for _, item := range sl.Items {
+			if !item.Purchased {
+				continue
+			}
+			key := normalizeItemName(item.Name)
+			if key == "" {
+				continue
+			}
+			date := listDate
+			if item.PurchasedAt != nil {
+				date = *item.PurchasedAt
+			}
+
+			entry, ok := result[key]
+			if !ok {
+				entry = &rulePurchases{Name: item.Name, Unit: item.Unit, First: date, Last: date}
+				result[key] = entry
+			}
+			quantity := item.Quantity
+			if converted, ok := units.Convert(item.Quantity, item.Unit, entry.Unit); ok {
+				quantity = converted
+			} else if date.After(entry.Last) {
+				// A later purchase in another dimension starts over.
+				*entry = rulePurchases{Name: item.Name, Unit: item.Unit, First: date, Last: date}
+				seen[key] = false
+			} else {
+				continue
+			}
+
+			entry.Total += quantity
+			if !seen[key] {
+				entry.Count++
+				seen[key] = true
+			}
+			if date.Before(entry.First) {
+				entry.First = date
+			}
+			if !date.Before(entry.Last) {
+				entry.Last = date
+				entry.Name = item.Name
+				if item.Category != "" {
+					entry.Category = item.Category
+				}
+				if price := resolveUnitPrice(item.ActualPrice, item.EstimatedPrice); price > 0 {
+					if converted, ok := units.Convert(1, entry.Unit, item.Unit); ok {
+						entry.PricePerUnit = price * converted
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// ruleUnitPrice estimates the price of one unit, preferring the last
+// purchase over the pantry price.
+func ruleUnitPrice(stock *ruleStock, purchases *rulePurchases, unit string) float64 {
+	if purchases != nil && purchases.PricePerUnit > 0 {
+		if factor, ok := units.Convert(1, unit, purchases.Unit); ok {
+			return purchases.PricePerUnit * factor
+		}
+	}
+	if stock != nil && stock.PricePerUnit > 0 {
+		if factor, ok := units.Convert(1, unit, stock.Unit); ok {
+			return stock.PricePerUnit * factor
+		}
+	}
+	return 0
+}
+
+// roundRuleQuantity rounds countable units up to whole units and the others
+// to two decimals.
+func roundRuleQuantity(quantity float64, unit string) float64 {
+	if u, ok := units.Lookup(unit); ok && u.Dimension == units.DimensionCount {
+		return math.Ceil(quantity - 1e-9)
+	}
+	return math.Round(quantity*100) / 100
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRuleBasedItemsAppliesRulesInOrder(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	soon := now.AddDate(0, 0, 1)
+	later := now.AddDate(0, 1, 0)
+
+	stock := []*itemModel.Item{
+		{ID: uuid.New(), Name: "Leite", Quantity: 1, Unit: "l", PricePerUnit: 5, ExpiresAt: &later},
+		{ID: uuid.New(), Name: "Arroz", Quantity: 0, Unit: "kg", PricePerUnit: 6},
+		{ID: uuid.New(), Name: "Iogurte", Quantity: 2, Unit: "un", PricePerUnit: 3, ExpiresAt: &soon},
+		{ID: uuid.New(), Name: "Sal", Quantity: 1, Unit: "kg"},
+	}
+	parLevels := []*shoppingModel.ParLevel{
+		{Name: "Leite", NormalizedName: "leite", Quantity: 2, Unit: "l", PerPerson: true, Category: "Laticínios"},
+	}
+	completed := func(daysAgo int, items ...shoppingModel.ShoppingListItem) *shoppingModel.ShoppingList {
+		at := now.AddDate(0, 0, -daysAgo)
+		return &shoppingModel.ShoppingList{CompletedAt: &at, Items: items}
+	}
+	cafe := func(quantity float64) shoppingModel.ShoppingListItem {
+		return shoppingModel.ShoppingListItem{Name: "Café", Quantity: quantity, Unit: "g", Category: "Mercearia", Purchased: true, ActualPrice: 0.04}
+	}
+	history := []*shoppingModel.ShoppingList{
+		completed(21, cafe(500)),
+		completed(14, cafe(500)),
+		completed(7, cafe(500), shoppingModel.ShoppingListItem{Name: "Refrigerante", Quantity: 2, Unit: "l", Purchased: true}),
+	}
+
+	cfg := defaultRuleGeneratorConfig
+	cfg.HouseholdSize = 3
+
+	items := buildRuleBasedItems(stock, parLevels, history, []string{"refrigerante"}, cfg, now)
+
+	require.Len(t, items, 4)
+
+	require.Equal(t, "Arroz", items[0].Name)
+	require.Equal(t, ruleSourceOutOfStock, items[0].Source)
+	require.Equal(t, 1, items[0].Priority)
+	require.Equal(t, 6.0, items[0].EstimatedPrice)
+
+	require.Equal(t, "Leite", items[1].Name)
+	require.Equal(t, ruleSourceParLevel, items[1].Source)
+	require.Equal(t, 5.0, items[1].Quantity)
+	require.Equal(t, "Laticínios", items[1].Category)
+
+	require.Equal(t, "Café", items[2].Name)
+	require.Equal(t, ruleSourceFrequency, items[2].Source)
+	require.Equal(t, 2, items[2].Priority)
+	require.Equal(t, 500.0, items[2].Quantity)
+	require.Equal(t, "g", items[2].Unit)
+
+	require.Equal(t, "Iogurte", items[3].Name)
+	require.Equal(t, ruleSourceExpiring, items[3].Source)
+	require.Equal(t, 3, items[3].Priority)
+	require.Equal(t, 2.0, items[3].Quantity)
+}
+
+func TestBuildRuleBasedItemsIsDeterministic(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	var stock []*itemModel.Item
+	for _, name := range []string{"Feijão", "Açúcar", "Óleo", "Farinha"} {
+		stock = append(stock, &itemModel.Item{ID: uuid.New(), Name: name, Unit: "kg"})
+	}
+
+	first := buildRuleBasedItems(stock, nil, nil, nil, defaultRuleGeneratorConfig, now)
+	for i := 0; i < 5; i++ {
+		require.Equal(t, first, buildRuleBasedItems(stock, nil, nil, nil, defaultRuleGeneratorConfig, now))
+	}
+	require.Len(t, first, 4)
+}
+
+func TestBuildRuleBasedItemsSkipsProductsAboveParLevel(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	stock := []*itemModel.Item{{ID: uuid.New(), Name: "Ovos", Quantity: 24, Unit: "un"}}
+	parLevels := []*shoppingModel.ParLevel{{Name: "Ovos", NormalizedName: "ovos", Quantity: 12, Unit: "un"}}
+
+	items := buildRuleBasedItems(stock, parLevels, nil, nil, defaultRuleGeneratorConfig, now)
+
+	require.Empty(t, items)
+}
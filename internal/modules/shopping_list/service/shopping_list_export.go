@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/pdf"
+	"github.com/nclsgg/despensa-digital/backend/pkg/slug"
+	"go.uber.org/zap"
+)
+
+const (
+	exportFormatText     = "text"
+	exportFormatWhatsApp = "whatsapp"
+	exportFormatCSV      = "csv"
+	exportFormatPDF      = "pdf"
+
+	uncategorizedLabel = "Outros"
+)
+
+type categoryGroup struct {
+	Name           string
+	Items          []shoppingModel.ShoppingListItem
+	EstimatedTotal float64
+}
+
+// groupItemsByCategory groups items by category in alphabetical order, with
+// uncategorized items last.
+func groupItemsByCategory(items []shoppingModel.ShoppingListItem) []categoryGroup {
+	index := make(map[string]int)
+	var groups []categoryGroup
+	for _, item := range items {
+		name := strings.TrimSpace(item.Category)
+		if name == "" {
+			name = uncategorizedLabel
+		}
+		key := normalizeItemName(name)
+		idx, ok := index[key]
+		if !ok {
+			idx = len(groups)
+			index[key] = idx
+			groups = append(groups, categoryGroup{Name: name})
+		}
+		groups[idx].Items = append(groups[idx].Items, item)
+		groups[idx].EstimatedTotal += item.EstimatedPrice * clampNonNegative(item.Quantity)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].Name == uncategorizedLabel) != (groups[j].Name == uncategorizedLabel) {
+			return groups[j].Name == uncategorizedLabel
+		}
+		return normalizeItemName(groups[i].Name) < normalizeItemName(groups[j].Name)
+	})
+	for idx := range groups {
+		sortItemsByName(groups[idx].Items)
+		groups[idx].EstimatedTotal = roundCurrency(groups[idx].EstimatedTotal)
+	}
+	return groups
+}
+
+// formatBRL formats a value as Brazilian currency, e.g. R$ 1.234,56.
+func formatBRL(value float64) string {
+	negative := value < 0
+	cents := int64(math.Round(math.Abs(value) * 100))
+	integer := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for idx, digit := range integer {
+		if idx > 0 && (len(integer)-idx)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	result := fmt.Sprintf("R$ %s,%02d", grouped.String(), cents%100)
+	if negative {
+		return "-" + result
+	}
+	return result
+}
+
+// formatQuantity prints a quantity with a decimal comma and no trailing zeros.
+func formatQuantity(quantity float64, unit string) string {
+	value := strconv.FormatFloat(math.Round(quantity*1000)/1000, 'f', -1, 64)
+	value = strings.ReplaceAll(value, ".", ",")
+	if unit = strings.TrimSpace(unit); unit != "" {
+		return value + " " + unit
+	}
+	return value
+}
+
+func exportSummaryLines(sl *shoppingModel.ShoppingList) []string {
+	estimated, _ := calculateListTotals(sl.Items)
+	lines := []string{"Total estimado: " + formatBRL(estimated)}
+	if sl.TotalBudget > 0 {
+		lines = append(lines, fmt.Sprintf("Orçamento: %s (saldo %s)", formatBRL(sl.TotalBudget), formatBRL(sl.TotalBudget-estimated)))
+	}
+	return lines
+}
+
+func itemExportLine(item shoppingModel.ShoppingListItem) string {
+	line := item.Name + " — " + formatQuantity(item.Quantity, item.Unit)
+	if item.EstimatedPrice > 0 {
+		line += " (" + formatBRL(item.EstimatedPrice*clampNonNegative(item.Quantity)) + ")"
+	}
+	return line
+}
+
+func renderShoppingListText(sl *shoppingModel.ShoppingList) string {
+	var b strings.Builder
+	b.WriteString(sl.Name + "\n")
+	for _, group := range groupItemsByCategory(sl.Items) {
+		fmt.Fprintf(&b, "\n%s\n", strings.ToUpper(group.Name))
+		for _, item := range group.Items {
+			mark := "[ ]"
+			if item.Purchased {
+				mark = "[x]"
+			}
+			fmt.Fprintf(&b, "%s %s\n", mark, itemExportLine(item))
+		}
+	}
+	b.WriteString("\n")
+	for _, line := range exportSummaryLines(sl) {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// renderShoppingListWhatsApp uses WhatsApp formatting: *bold* headings and
+// emoji checkboxes, which render on every phone.
+func renderShoppingListWhatsApp(sl *shoppingModel.ShoppingList) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🛒 *%s*\n", sl.Name)
+	for _, group := range groupItemsByCategory(sl.Items) {
+		fmt.Fprintf(&b, "\n*%s*\n", group.Name)
+		for _, item := range group.Items {
+			mark := "⬜"
+			text := itemExportLine(item)
+			if item.Purchased {
+				mark = "✅"
+				text = "~" + text + "~"
+			}
+			fmt.Fprintf(&b, "%s %s\n", mark, text)
+		}
+	}
+	b.WriteString("\n")
+	for _, line := range exportSummaryLines(sl) {
+		b.WriteString("_" + line + "_\n")
+	}
+	return b.String()
+}
+
+func renderShoppingListCSV(sl *shoppingModel.ShoppingList) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	formatNumber := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	rows := [][]string{{"category", "name", "quantity", "unit", "estimated_price", "estimated_total", "actual_price", "purchased", "priority"}}
+	for _, group := range groupItemsByCategory(sl.Items) {
+		for _, item := range group.Items {
+			rows = append(rows, []string{
+				group.Name,
+				item.Name,
+				formatNumber(item.Quantity),
+				item.Unit,
+				formatNumber(item.EstimatedPrice),
+				formatNumber(roundCurrency(item.EstimatedPrice * clampNonNegative(item.Quantity))),
+				formatNumber(item.ActualPrice),
+				strconv.FormatBool(item.Purchased),
+				strconv.Itoa(item.Priority),
+			})
+		}
+	}
+	estimated, _ := calculateListTotals(sl.Items)
+	rows = append(rows, []string{"", "TOTAL", "", "", "", formatNumber(roundCurrency(estimated)), "", "", ""})
+	if sl.TotalBudget > 0 {
+		rows = append(rows, []string{"", "BUDGET", "", "", "", formatNumber(sl.TotalBudget), "", "", ""})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("write csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func renderShoppingListPDF(sl *shoppingModel.ShoppingList) []byte {
+	doc := pdf.New()
+	doc.Text(sl.Name, pdf.Title)
+	doc.Space(6)
+	for _, group := range groupItemsByCategory(sl.Items) {
+		doc.Space(8)
+		doc.Text(fmt.Sprintf("%s — %s", group.Name, formatBRL(group.EstimatedTotal)), pdf.Heading)
+		for _, item := range group.Items {
+			mark := "[  ]"
+			if item.Purchased {
+				mark = "[x]"
+			}
+			doc.Text(mark+" "+itemExportLine(item), pdf.Style{Size: pdf.Body.Size, Indent: 10})
+		}
+	}
+	doc.Space(12)
+	for _, line := range exportSummaryLines(sl) {
+		doc.Text(line, pdf.Style{Size: 11, Bold: true})
+	}
+	return doc.Bytes()
+}
+
+// renderShoppingListExport renders a list in one of the export formats.
+func renderShoppingListExport(ctx context.Context, sl *shoppingModel.ShoppingList, format string) (*dto.ShoppingListExportDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", exportFormatText:
+		return &dto.ShoppingListExportDTO{
+			FileName:    slug.FileName(sl.Name, "lista-de-compras", "txt"),
+			ContentType: "text/plain; charset=utf-8",
+			Content:     []byte(renderShoppingListText(sl)),
+		}, nil
+	case exportFormatWhatsApp:
+		return &dto.ShoppingListExportDTO{
+			FileName:    slug.FileName(sl.Name, "lista-de-compras", "txt"),
+			ContentType: "text/plain; charset=utf-8",
+			Content:     []byte(renderShoppingListWhatsApp(sl)),
+		}, nil
+	case exportFormatCSV:
+		content, err := renderShoppingListCSV(sl)
+		if err != nil {
+			logger.Error("Failed to render shopping list csv",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "renderShoppingListExport"),
+				zap.String(appLogger.FieldUserID, sl.UserID.String()),
+				zap.String("shopping_list_id", sl.ID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		return &dto.ShoppingListExportDTO{
+			FileName:    slug.FileName(sl.Name, "lista-de-compras", "csv"),
+			ContentType: "text/csv; charset=utf-8",
+			Content:     content,
+		}, nil
+	case exportFormatPDF:
+		return &dto.ShoppingListExportDTO{
+			FileName:    slug.FileName(sl.Name, "lista-de-compras", "pdf"),
+			ContentType: "application/pdf",
+			Content:     renderShoppingListPDF(sl),
+		}, nil
+	default:
+		logger.Warn("Unsupported shopping list export format",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "renderShoppingListExport"),
+			zap.String(appLogger.FieldUserID, sl.UserID.String()),
+			zap.String("shopping_list_id", sl.ID.String()),
+			zap.String("format", format),
+		)
+		return nil, domain.ErrUnsupportedFormat
+	}
+}
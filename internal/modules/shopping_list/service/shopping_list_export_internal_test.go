@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+)
+
+func newExportTestList() *shoppingModel.ShoppingList {
+	return &shoppingModel.ShoppingList{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		Name:        "Compras da Semana",
+		TotalBudget: 100,
+		Items: []shoppingModel.ShoppingListItem{
+			{Name: "Feijão", Quantity: 2, Unit: "kg", EstimatedPrice: 8.5, Category: "Grãos"},
+			{Name: "Detergente", Quantity: 1, Unit: "un", EstimatedPrice: 2.49},
+			{Name: "Arroz", Quantity: 1, Unit: "kg", EstimatedPrice: 5.5, Category: "grãos", Purchased: true},
+			{Name: "Banana", Quantity: 1.5, Unit: "kg", EstimatedPrice: 6, Category: "Frutas"},
+		},
+	}
+}
+
+func TestGroupItemsByCategory(t *testing.T) {
+	groups := groupItemsByCategory(newExportTestList().Items)
+	require.Len(t, groups, 3)
+	require.Equal(t, "Frutas", groups[0].Name)
+	require.Equal(t, "Grãos", groups[1].Name)
+	require.Equal(t, "Arroz", groups[1].Items[0].Name)
+	require.InDelta(t, 22.5, groups[1].EstimatedTotal, 1e-9)
+	require.Equal(t, uncategorizedLabel, groups[2].Name)
+}
+
+func TestRenderShoppingListFormats(t *testing.T) {
+	sl := newExportTestList()
+
+	text := renderShoppingListText(sl)
+	require.Contains(t, text, "GRÃOS\n[x] Arroz — 1 kg (R$ 5,50)\n[ ] Feijão — 2 kg (R$ 17,00)")
+	require.Contains(t, text, "Total estimado: R$ 33,99")
+	require.Contains(t, text, "Orçamento: R$ 100,00 (saldo R$ 66,01)")
+
+	whatsapp := renderShoppingListWhatsApp(sl)
+	require.Contains(t, whatsapp, "*Compras da Semana*")
+	require.Contains(t, whatsapp, "✅ ~Arroz — 1 kg (R$ 5,50)~")
+	require.Contains(t, whatsapp, "⬜ Banana — 1,5 kg (R$ 9,00)")
+
+	content, err := renderShoppingListCSV(sl)
+	require.NoError(t, err)
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 7)
+	require.Equal(t, []string{"Frutas", "Banana", "1.5", "kg", "6", "9", "0", "false", "0"}, rows[1])
+	require.Equal(t, "33.99", rows[5][5])
+	require.Equal(t, "BUDGET", rows[6][1])
+
+	export, err := renderShoppingListExport(context.Background(), sl, "pdf")
+	require.NoError(t, err)
+	require.Equal(t, "compras-da-semana.pdf", export.FileName)
+	require.True(t, bytes.HasPrefix(export.Content, []byte("%PDF")))
+
+	_, err = renderShoppingListExport(context.Background(), sl, "docx")
+	require.ErrorIs(t, err, domain.ErrUnsupportedFormat)
+}
+
+func TestFormatBRL(t *testing.T) {
+	require.Equal(t, "R$ 1.234,56", formatBRL(1234.56))
+	require.Equal(t, "R$ 0,10", formatBRL(0.1))
+	require.Equal(t, "-R$ 12,00", formatBRL(-12))
+}
+
+type fakeShareRepository struct {
+	shares map[string]*shoppingModel.ShoppingListShare
+}
+
+func (f *fakeShareRepository) Create(ctx context.Context, share *shoppingModel.ShoppingListShare) error {
+	share.ID = uuid.New()
+	f.shares[share.Token] = share
+	return nil
+}
+
+func (f *fakeShareRepository) GetByID(ctx context.Context, id uuid.UUID) (*shoppingModel.ShoppingListShare, error) {
+	for _, share := range f.shares {
+		if share.ID == id {
+			return share, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeShareRepository) GetByToken(ctx context.Context, token string) (*shoppingModel.ShoppingListShare, error) {
+	if share, ok := f.shares[token]; ok {
+		return share, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeShareRepository) ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) ([]*shoppingModel.ShoppingListShare, error) {
+	var result []*shoppingModel.ShoppingListShare
+	for _, share := range f.shares {
+		if share.ShoppingListID == shoppingListID {
+			result = append(result, share)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeShareRepository) Update(ctx context.Context, share *shoppingModel.ShoppingListShare) error {
+	f.shares[share.Token] = share
+	return nil
+}
+
+type fakeListRepository struct {
+	domain.ShoppingListRepository
+	list *shoppingModel.ShoppingList
+}
+
+func (f *fakeListRepository) GetByID(ctx context.Context, id uuid.UUID) (*shoppingModel.ShoppingList, error) {
+	if f.list != nil && f.list.ID == id {
+		return f.list, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestShoppingListShareLifecycle(t *testing.T) {
+	ctx := context.Background()
+	sl := newExportTestList()
+	now := time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+	svc := &shoppingListShareService{
+		shoppingListRepo: &fakeListRepository{list: sl},
+		shareRepo:        &fakeShareRepository{shares: map[string]*shoppingModel.ShoppingListShare{}},
+		now:              func() time.Time { return now },
+	}
+
+	_, err := svc.CreateShare(ctx, uuid.New(), sl.ID, dto.CreateShoppingListShareDTO{})
+	require.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	share, err := svc.CreateShare(ctx, sl.UserID, sl.ID, dto.CreateShoppingListShareDTO{ExpiresInHours: 24})
+	require.NoError(t, err)
+	require.True(t, share.Active)
+	require.True(t, strings.HasSuffix(share.Path, share.Token))
+
+	shared, err := svc.GetSharedShoppingList(ctx, share.Token)
+	require.NoError(t, err)
+	require.Equal(t, "Compras da Semana", shared.Name)
+	require.Len(t, shared.Categories, 3)
+
+	now = now.Add(25 * time.Hour)
+	_, err = svc.GetSharedShoppingList(ctx, share.Token)
+	require.ErrorIs(t, err, domain.ErrShareExpired)
+
+	now = now.Add(-25 * time.Hour)
+	shareID := uuid.MustParse(share.ID)
+	require.NoError(t, svc.RevokeShare(ctx, sl.UserID, sl.ID, shareID))
+	_, err = svc.ExportSharedShoppingList(ctx, share.Token, "text")
+	require.ErrorIs(t, err, domain.ErrShareNotFound)
+
+	_, err = svc.GetSharedShoppingList(ctx, "unknown")
+	require.ErrorIs(t, err, domain.ErrShareNotFound)
+}
@@ -16,6 +16,7 @@ import (
 	pantryModel "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/model"
 	profileDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/domain"
 	profileModel "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/model"
+	recipeDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/domain"
 	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
 	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
 	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
@@ -70,6 +71,13 @@ type shoppingListService struct {
 	itemRepo         itemDomain.ItemRepository
 	profileRepo      profileDomain.ProfileRepository
 	llmService       llmDomain.LLMService
+	storeRepo        domain.StoreRepository
+	budgetService    domain.BudgetService
+	checkoutRepo     domain.CheckoutRepository
+	statusRepo       domain.StatusHistoryRepository
+	parLevelRepo     domain.ParLevelRepository
+	recipeRepo       recipeDomain.RecipeRepository
+	mealPlanRepo     recipeDomain.MealPlanRepository
 }
 
 func NewShoppingListService(
@@ -78,6 +86,13 @@ func NewShoppingListService(
 	itemRepo itemDomain.ItemRepository,
 	profileRepo profileDomain.ProfileRepository,
 	llmService llmDomain.LLMService,
+	storeRepo domain.StoreRepository,
+	budgetService domain.BudgetService,
+	checkoutRepo domain.CheckoutRepository,
+	statusRepo domain.StatusHistoryRepository,
+	parLevelRepo domain.ParLevelRepository,
+	recipeRepo recipeDomain.RecipeRepository,
+	mealPlanRepo recipeDomain.MealPlanRepository,
 ) domain.ShoppingListService {
 	return &shoppingListService{
 		shoppingListRepo: shoppingListRepo,
@@ -85,6 +100,13 @@ func NewShoppingListService(
 		itemRepo:         itemRepo,
 		profileRepo:      profileRepo,
 		llmService:       llmService,
+		storeRepo:        storeRepo,
+		budgetService:    budgetService,
+		checkoutRepo:     checkoutRepo,
+		statusRepo:       statusRepo,
+		parLevelRepo:     parLevelRepo,
+		recipeRepo:       recipeRepo,
+		mealPlanRepo:     mealPlanRepo,
 	}
 }
 
@@ -105,14 +127,18 @@ func (s *shoppingListService) CreateShoppingList(ctx context.Context, userID uui
 	shoppingList := &shoppingModel.ShoppingList{
 		UserID:              userID,
 		PantryID:            input.PantryID,
+		StoreID:             input.StoreID,
 		Name:                input.Name,
 		TotalBudget:         input.TotalBudget,
-		Status:              "pending",
+		Status:              shoppingModel.StatusPending,
 		GeneratedBy:         "manual",
 		HouseholdSize:       preferences.HouseholdSize,
 		MonthlyIncome:       preferences.MonthlyIncome,
 		DietaryRestrictions: shoppingModel.StringArray(normalizeStringSlice(preferences.DietaryRestrictions)),
 	}
+	if input.Draft {
+		shoppingList.Status = shoppingModel.StatusDraft
+	}
 
 	if input.PantryID != nil {
 		hasAccess, err := s.pantryRepo.IsUserInPantry(ctx, *input.PantryID, userID)
@@ -137,6 +163,19 @@ func (s *shoppingListService) CreateShoppingList(ctx context.Context, userID uui
 		}
 	}
 
+	if input.StoreID != nil {
+		if err := s.ensureStoreOwnership(ctx, userID, *input.StoreID); err != nil {
+			logger.Warn("Invalid store for shopping list",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "CreateShoppingList"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("store_id", input.StoreID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+	}
+
 	for _, itemDto := range input.Items {
 		item := &shoppingModel.ShoppingListItem{
 			Name:           itemDto.Name,
@@ -169,6 +208,7 @@ func (s *shoppingListService) CreateShoppingList(ctx context.Context, userID uui
 		)
 		return nil, fmt.Errorf("create shopping list: %w", err)
 	}
+	s.recordStatusChange(ctx, userID, shoppingList.ID, "", shoppingList.Status, "")
 
 	created, err := s.shoppingListRepo.GetByID(ctx, shoppingList.ID)
 	if err != nil {
@@ -193,7 +233,14 @@ func (s *shoppingListService) CreateShoppingList(ctx context.Context, userID uui
 		zap.Int(appLogger.FieldCount, len(created.Items)),
 	)
 
-	return s.convertToResponseDTO(ctx, created), nil
+	planned := created.EstimatedCost
+	if planned <= 0 {
+		planned = created.TotalBudget
+	}
+	result := s.convertToResponseDTO(ctx, created)
+	result.BudgetWarnings = s.checkBudget(ctx, userID, created.PantryID, planned)
+	result.DietaryWarnings = s.checkDietary(ctx, created, itemNames(created.Items))
+	return result, nil
 }
 
 func (s *shoppingListService) GetShoppingListByID(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*dto.ShoppingListResponseDTO, error) {
@@ -279,10 +326,17 @@ func (s *shoppingListService) GetShoppingListsByUserID(ctx context.Context, user
 			}
 		}
 
+		var storeID *string
+		if sl.StoreID != nil {
+			idStr := sl.StoreID.String()
+			storeID = &idStr
+		}
+
 		summaries = append(summaries, &dto.ShoppingListSummaryDTO{
 			ID:             sl.ID.String(),
 			PantryID:       pantryID,
 			PantryName:     pantryName,
+			StoreID:        storeID,
 			Name:           sl.Name,
 			Status:         sl.Status,
 			TotalBudget:    sl.TotalBudget,
@@ -348,6 +402,22 @@ func (s *shoppingListService) UpdateShoppingList(ctx context.Context, userID uui
 	if input.TotalBudget != nil {
 		shoppingList.TotalBudget = *input.TotalBudget
 	}
+	if input.ClearStore {
+		shoppingList.StoreID = nil
+	} else if input.StoreID != nil {
+		if err := s.ensureStoreOwnership(ctx, userID, *input.StoreID); err != nil {
+			logger.Warn("Invalid store for shopping list",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "UpdateShoppingList"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("store_id", input.StoreID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		storeID := *input.StoreID
+		shoppingList.StoreID = &storeID
+	}
 
 	if input.Preferences != nil {
 		prefs := shoppingPreferences{
@@ -361,32 +431,8 @@ func (s *shoppingListService) UpdateShoppingList(ctx context.Context, userID uui
 		shoppingList.DietaryRestrictions = shoppingModel.StringArray(normalizeStringSlice(prefs.DietaryRestrictions))
 	}
 
-	checkoutPerformed := false
-	checkoutCost := 0.0
-	if input.Status != nil {
-		targetStatus := *input.Status
-		if targetStatus == "completed" && shoppingList.Status != "completed" {
-			cost, err := s.performCheckout(ctx, userID, shoppingList)
-			if err != nil {
-				logger.Error("Failed to perform checkout",
-					zap.String(appLogger.FieldModule, "shopping_list"),
-					zap.String(appLogger.FieldFunction, "UpdateShoppingList"),
-					zap.String(appLogger.FieldUserID, userID.String()),
-					zap.String("shopping_list_id", id.String()),
-					zap.Error(err),
-				)
-				return nil, err
-			}
-			checkoutPerformed = true
-			checkoutCost = cost
-		}
-		shoppingList.Status = targetStatus
-	}
-
 	if input.ActualCost != nil {
 		shoppingList.ActualCost = *input.ActualCost
-	} else if checkoutPerformed {
-		shoppingList.ActualCost = checkoutCost
 	}
 
 	if err := s.shoppingListRepo.Update(ctx, shoppingList); err != nil {
@@ -420,7 +466,6 @@ func (s *shoppingListService) UpdateShoppingList(ctx context.Context, userID uui
 		zap.String(appLogger.FieldFunction, "UpdateShoppingList"),
 		zap.String(appLogger.FieldUserID, userID.String()),
 		zap.String("shopping_list_id", id.String()),
-		zap.Bool("checkout_performed", checkoutPerformed),
 	)
 
 	return s.convertToResponseDTO(ctx, updated), nil
@@ -589,7 +634,9 @@ func (s *shoppingListService) CreateShoppingListItem(ctx context.Context, userID
 	)
 
 	// Return the full shopping list with the new item
-	return s.convertToResponseDTO(ctx, shoppingList), nil
+	result := s.convertToResponseDTO(ctx, shoppingList)
+	result.DietaryWarnings = s.checkDietary(ctx, shoppingList, []string{newItem.Name})
+	return result, nil
 }
 
 func (s *shoppingListService) UpdateShoppingListItem(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, itemID uuid.UUID, input dto.UpdateShoppingListItemDTO) (result0 *dto.ShoppingListItemResponseDTO, result1 error) {
@@ -636,7 +683,9 @@ func (s *shoppingListService) UpdateShoppingListItem(ctx context.Context, userID
 		return
 	}
 
+	renamed := false
 	if input.Name != nil {
+		renamed = *input.Name != targetItem.Name
 		targetItem.Name = *input.Name
 	}
 	if input.Quantity != nil {
@@ -657,7 +706,15 @@ func (s *shoppingListService) UpdateShoppingListItem(ctx context.Context, userID
 	if input.Priority != nil {
 		targetItem.Priority = *input.Priority
 	}
+	newlyPurchased := false
 	if input.Purchased != nil {
+		if *input.Purchased && !targetItem.Purchased {
+			now := time.Now().UTC()
+			targetItem.PurchasedAt = &now
+			newlyPurchased = true
+		} else if !*input.Purchased {
+			targetItem.PurchasedAt = nil
+		}
 		targetItem.Purchased = *input.Purchased
 	}
 	if input.PantryItemID != nil {
@@ -686,17 +743,26 @@ func (s *shoppingListService) UpdateShoppingListItem(ctx context.Context, userID
 		return
 	}
 
+	if newlyPurchased {
+		s.learnStoreOrder(ctx, shoppingList, *targetItem)
+	}
+	if newlyPurchased || (targetItem.Purchased && input.ActualPrice != nil) {
+		s.recordPurchasePrice(ctx, shoppingList, *targetItem)
+	}
+
+	result0 = s.convertItemToResponseDTO(targetItem)
 	reloadedItems, err := s.shoppingListRepo.GetItemsByShoppingListID(ctx, shoppingListID)
 	if err == nil {
 		for _, item := range reloadedItems {
 			if item.ID == itemID {
 				result0 = s.convertItemToResponseDTO(item)
-				result1 = nil
-				return
+				break
 			}
 		}
 	}
-	result0 = s.convertItemToResponseDTO(targetItem)
+	if renamed {
+		result0.DietaryWarnings = s.checkDietary(ctx, shoppingList, []string{targetItem.Name})
+	}
 	result1 = nil
 	return
 }
@@ -812,33 +878,31 @@ func (s *shoppingListService) GenerateAIShoppingList(ctx context.Context, userID
 		return
 	}
 
-	prompt, err := s.buildShoppingListPrompt(input, preferences, profile, pantryInsights, budget, includeBasics)
-	if err != nil {
-		zap.L().Error("function.error", zap.String("func", "*shoppingListService.GenerateAIShoppingList"), zap.Error(err), zap.Any("params", __logParams))
-		result0 = nil
-		result1 = fmt.Errorf("%w: %v", domain.ErrPromptBuildFailed, err)
-		return
+	var shoppingList *shoppingModel.ShoppingList
+	if input.Mode != generatorModeRules {
+		shoppingList, err = s.generateWithLLM(ctx, userID, input, budget, preferences, profile, pantryInsights, includeBasics)
+		if err != nil {
+			// The rule based generator needs no LLM, so the user still gets
+			// a list when the provider is unavailable or answers badly.
+			appLogger.FromContext(ctx).Warn("AI shopping list generation failed, falling back to rules",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "GenerateAIShoppingList"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.Error(err),
+			)
+		}
 	}
-
-	llmResponse, err := s.llmService.GenerateText(ctx, prompt, map[string]interface{}{
-		"max_tokens":      2000,
-		"temperature":     0.7,
-		"response_format": "json",
-	})
-	if err != nil {
-		zap.L().Error("function.error", zap.String("func", "*shoppingListService.GenerateAIShoppingList"), zap.Error(err), zap.Any("params", __logParams))
-		result0 = nil
-		result1 = fmt.Errorf("%w: %v", domain.ErrAIRequestFailed, err)
-		return
+	if shoppingList == nil {
+		shoppingList, err = s.generateRuleBasedList(ctx, userID, input, budget, preferences, profile)
+		if err != nil {
+			zap.L().Error("function.error", zap.String("func", "*shoppingListService.GenerateAIShoppingList"), zap.Error(err), zap.Any("params", __logParams))
+			result0 = nil
+			result1 = err
+			return
+		}
 	}
 
-	shoppingList, err := s.parseAIResponse(ctx, userID, input, budget, preferences, llmResponse.Response)
-	if err != nil {
-		zap.L().Error("function.error", zap.String("func", "*shoppingListService.GenerateAIShoppingList"), zap.Error(err), zap.Any("params", __logParams))
-		result0 = nil
-		result1 = err
-		return
-	}
+	budgetCuts := s.fitGeneratedListToBudget(ctx, userID, input, shoppingList, budget, preferences.HouseholdSize)
 
 	if err := s.shoppingListRepo.Create(ctx, shoppingList); err != nil {
 		zap.L().Error("function.error", zap.String("func", "*shoppingListService.GenerateAIShoppingList"), zap.Error(err), zap.Any("params", __logParams))
@@ -846,6 +910,7 @@ func (s *shoppingListService) GenerateAIShoppingList(ctx context.Context, userID
 		result1 = fmt.Errorf("create ai shopping list: %w", err)
 		return
 	}
+	s.recordStatusChange(ctx, userID, shoppingList.ID, "", shoppingList.Status, "")
 
 	created, err := s.shoppingListRepo.GetByID(ctx, shoppingList.ID)
 	if err != nil {
@@ -860,10 +925,81 @@ func (s *shoppingListService) GenerateAIShoppingList(ctx context.Context, userID
 		return
 	}
 	result0 = s.convertToResponseDTO(ctx, created)
+	result0.BudgetWarnings = s.checkBudget(ctx, userID, created.PantryID, created.EstimatedCost)
+	result0.BudgetCuts = budgetCuts
+	result0.DietaryWarnings = s.checkDietary(ctx, created, itemNames(created.Items))
 	result1 = nil
 	return
 }
 
+// fitGeneratedListToBudget optimizes a generated list that overshoots the
+// max budget asked by the user and returns what was cut.
+func (s *shoppingListService) fitGeneratedListToBudget(ctx context.Context, userID uuid.UUID, input dto.GenerateAIShoppingListDTO, shoppingList *shoppingModel.ShoppingList, budget float64, householdSize int) []dto.BudgetCutDTO {
+	if input.MaxBudget == nil || budget <= 0 {
+		return nil
+	}
+	if cost, _ := calculateListTotals(shoppingList.Items); cost <= budget+budgetEpsilon {
+		return nil
+	}
+	if householdSize <= 0 {
+		householdSize = 1
+	}
+
+	optimization := optimizeForBudget(shoppingList.Items, budget, householdSize)
+	shoppingList.Items = optimization.Items
+	shoppingList.EstimatedCost, shoppingList.ActualCost = calculateListTotals(shoppingList.Items)
+
+	appLogger.FromContext(ctx).Info("Generated shopping list cut to fit the budget",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "GenerateAIShoppingList"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.Float64("budget", budget),
+		zap.Float64("original_cost", optimization.OriginalCost),
+		zap.Float64("optimized_cost", optimization.OptimizedCost),
+		zap.Int(appLogger.FieldCount, len(optimization.Cuts)),
+	)
+	return optimization.Cuts
+}
+
+// generateWithLLM asks the LLM for the list. Any failure is returned so the
+// caller can fall back to the rule based generator.
+func (s *shoppingListService) generateWithLLM(ctx context.Context, userID uuid.UUID, input dto.GenerateAIShoppingListDTO, budget float64, preferences shoppingPreferences, profile *profileModel.Profile, pantryInsights *PantryInsights, includeBasics bool) (result0 *shoppingModel.ShoppingList, result1 error) {
+	__logParams := map[string]any{"s": s, "ctx": ctx, "userID": userID, "input": input, "budget": budget, "preferences": preferences, "profile": profile, "pantryInsights": pantryInsights, "includeBasics": includeBasics}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListService.generateWithLLM"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "*shoppingListService.generateWithLLM"), zap.Any("params", __logParams))
+	if s.llmService == nil {
+		result0 = nil
+		result1 = fmt.Errorf("%w: no llm service configured", domain.ErrAIRequestFailed)
+		return
+	}
+
+	prompt, err := s.buildShoppingListPrompt(input, preferences, profile, pantryInsights, budget, includeBasics)
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*shoppingListService.generateWithLLM"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = fmt.Errorf("%w: %v", domain.ErrPromptBuildFailed, err)
+		return
+	}
+
+	llmResponse, err := s.llmService.GenerateText(ctx, prompt, map[string]interface{}{
+		"max_tokens":      2000,
+		"temperature":     0.7,
+		"response_format": "json",
+	})
+	if err != nil {
+		zap.L().Error("function.error", zap.String("func", "*shoppingListService.generateWithLLM"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = nil
+		result1 = fmt.Errorf("%w: %v", domain.ErrAIRequestFailed, err)
+		return
+	}
+
+	result0, result1 = s.parseAIResponse(ctx, userID, input, budget, preferences, llmResponse.Response)
+	return
+}
+
 // Helper methods
 
 func (s *shoppingListService) prepareAIJSONPayload(ctx context.Context, rawResponse string, input dto.GenerateAIShoppingListDTO, budget float64, preferences shoppingPreferences) (result0 string, result1 error) {
@@ -1120,29 +1256,63 @@ func convertPreferencesToDTO(sl *shoppingModel.ShoppingList) (result0 dto.Shoppi
 	return
 }
 
-func (s *shoppingListService) performCheckout(ctx context.Context, userID uuid.UUID, sl *shoppingModel.ShoppingList) (result0 float64, result1 error) {
-	__logParams := map[string]any{"s": s, "ctx": ctx, "userID": userID, "sl": sl}
+// performCheckout completes a list at completedAt: it adds the purchased items
+// to the pantry and saves the list as completed, with actualCost or else the
+// cost of the purchases, in one transaction.
+func (s *shoppingListService) performCheckout(ctx context.Context, userID uuid.UUID, sl *shoppingModel.ShoppingList, completedAt time.Time, actualCost *float64) (result0 error) {
+	__logParams := map[string]any{"s": s, "ctx": ctx, "userID": userID, "sl": sl, "completedAt": completedAt, "actualCost": actualCost}
 	__logStart := time.Now()
 	defer func() {
-		zap.L().Info("function.exit", zap.String("func", "*shoppingListService.performCheckout"), zap.Any("result", map[string]any{"result0": result0, "result1": result1}), zap.Duration("duration", time.Since(__logStart)))
+		zap.L().Info("function.exit", zap.String("func", "*shoppingListService.performCheckout"), zap.Any("result", result0), zap.Duration("duration", time.Since(__logStart)))
 	}()
 	zap.L().Info("function.entry", zap.String("func", "*shoppingListService.performCheckout"), zap.Any("params", __logParams))
 
-	actualCost := 0.0
+	complete := func(cost float64) {
+		sl.Status = shoppingModel.StatusCompleted
+		sl.CompletedAt = &completedAt
+		sl.ActualCost = cost
+		if actualCost != nil {
+			sl.ActualCost = *actualCost
+		}
+	}
+
+	// A list is checked out at most once until its checkout is reverted, so
+	// completing it again must not add the purchases to the pantry twice.
+	if s.checkoutRepo != nil {
+		active, err := s.checkoutRepo.GetActive(ctx, sl.ID)
+		if err == nil {
+			complete(active.ActualCost)
+			if err := s.shoppingListRepo.Update(ctx, sl); err != nil {
+				zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
+				result0 = fmt.Errorf("update shopping list: %w", err)
+				return
+			}
+			result0 = nil
+			return
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
+			result0 = fmt.Errorf("get active checkout: %w", err)
+			return
+		}
+	}
+
+	purchasedCost := 0.0
 
 	normalizeName := func(name string) string {
 		return strings.ToLower(strings.TrimSpace(name))
 	}
 
+	updatePantry := sl.PantryID != nil && s.itemRepo != nil && s.checkoutRepo != nil
+
 	var pantryItemsByID map[uuid.UUID]*itemModel.Item
 	var pantryItemsByName map[string]*itemModel.Item
 
-	if sl.PantryID != nil && s.itemRepo != nil {
+	if updatePantry {
 		items, err := s.itemRepo.ListByPantryID(ctx, *sl.PantryID)
 		if err != nil {
 			zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
-			result0 = 0
-			result1 = fmt.Errorf("list pantry items: %w", err)
+			result0 = fmt.Errorf("list pantry items: %w", err)
 			return
 		}
 		pantryItemsByID = make(map[uuid.UUID]*itemModel.Item, len(items))
@@ -1153,11 +1323,19 @@ func (s *shoppingListService) performCheckout(ctx context.Context, userID uuid.U
 		}
 	}
 
+	checkout := &shoppingModel.ShoppingListCheckout{
+		ShoppingListID: sl.ID,
+		UserID:         userID,
+		PantryID:       sl.PantryID,
+	}
+	var purchased []*shoppingModel.ShoppingListItem
+
 	for idx := range sl.Items {
 		item := &sl.Items[idx]
 		if !item.Purchased {
 			continue
 		}
+		purchased = append(purchased, item)
 
 		basePrice := resolveUnitPrice(item.ActualPrice, item.EstimatedPrice)
 		quantityFactor := item.Quantity
@@ -1165,95 +1343,110 @@ func (s *shoppingListService) performCheckout(ctx context.Context, userID uuid.U
 			quantityFactor = 0
 		}
 		item.ActualPrice = basePrice
-		actualCost += basePrice * quantityFactor
+		purchasedCost += basePrice * quantityFactor
 
 		perUnitPrice := basePrice
 
+		if !updatePantry {
+			continue
+		}
+
 		var matchedPantryItem *itemModel.Item
-		if sl.PantryID != nil && s.itemRepo != nil {
-			if item.PantryItemID != nil {
-				if pantryItemsByID != nil {
-					if cached, ok := pantryItemsByID[*item.PantryItemID]; ok {
-						matchedPantryItem = cached
-					}
-				}
-				if matchedPantryItem == nil {
-					found, err := s.itemRepo.FindByID(ctx, *item.PantryItemID)
-					if err == nil {
-						matchedPantryItem = found
-						if pantryItemsByID != nil {
-							pantryItemsByID[found.ID] = found
-							pantryItemsByName[normalizeName(found.Name)] = found
-						}
-					}
-				}
-				if matchedPantryItem != nil && matchedPantryItem.ID == uuid.Nil {
-					matchedPantryItem = nil
-				}
-			} else if pantryItemsByName != nil {
-				if candidate, ok := pantryItemsByName[normalizeName(item.Name)]; ok {
-					matchedPantryItem = candidate
-					copied := candidate.ID
-					item.PantryItemID = &copied
-				}
+		if item.PantryItemID != nil {
+			if cached, ok := pantryItemsByID[*item.PantryItemID]; ok {
+				matchedPantryItem = cached
 			}
-			if matchedPantryItem != nil && matchedPantryItem.ID == uuid.Nil {
-				matchedPantryItem = nil
+			if matchedPantryItem == nil {
+				found, err := s.itemRepo.FindByID(ctx, *item.PantryItemID)
+				if err == nil && found.PantryID == *sl.PantryID {
+					matchedPantryItem = found
+					pantryItemsByID[found.ID] = found
+					pantryItemsByName[normalizeName(found.Name)] = found
+				}
 			}
+		} else if candidate, ok := pantryItemsByName[normalizeName(item.Name)]; ok {
+			matchedPantryItem = candidate
+			copied := candidate.ID
+			item.PantryItemID = &copied
+		}
+		if matchedPantryItem != nil && matchedPantryItem.ID == uuid.Nil {
+			matchedPantryItem = nil
+		}
 
-			if matchedPantryItem != nil {
-				matchedPantryItem.Quantity += item.Quantity
-				if perUnitPrice > 0 {
-					matchedPantryItem.PricePerUnit = perUnitPrice
-				}
-				if matchedPantryItem.Unit == "" {
-					matchedPantryItem.Unit = item.Unit
-				}
-				if err := s.itemRepo.Update(ctx, matchedPantryItem); err != nil {
-					zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
-					result0 = 0
-					result1 = fmt.Errorf("update pantry item: %w", err)
-					return
-				}
-				if pantryItemsByID != nil {
-					pantryItemsByID[matchedPantryItem.ID] = matchedPantryItem
-					pantryItemsByName[normalizeName(matchedPantryItem.Name)] = matchedPantryItem
-				}
-			} else if sl.PantryID != nil {
-				newItem := &itemModel.Item{
-					ID:           uuid.New(),
-					PantryID:     *sl.PantryID,
-					AddedBy:      userID,
-					Name:         item.Name,
-					Quantity:     item.Quantity,
-					PricePerUnit: perUnitPrice,
-					Unit:         item.Unit,
-				}
-				if err := s.itemRepo.Create(ctx, newItem); err != nil {
-					zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
-					result0 = 0
-					result1 = fmt.Errorf("create pantry item: %w", err)
-					return
-				}
-				copied := newItem.ID
-				item.PantryItemID = &copied
-				if pantryItemsByID != nil {
-					pantryItemsByID[newItem.ID] = newItem
-					pantryItemsByName[normalizeName(newItem.Name)] = newItem
-				}
+		if matchedPantryItem != nil {
+			change := shoppingModel.CheckoutChange{
+				ShoppingListItemID:   item.ID,
+				PantryItemID:         matchedPantryItem.ID,
+				Action:               shoppingModel.CheckoutActionIncrement,
+				Name:                 matchedPantryItem.Name,
+				Quantity:             item.Quantity,
+				PreviousUnit:         matchedPantryItem.Unit,
+				PreviousPricePerUnit: matchedPantryItem.PricePerUnit,
 			}
+			// Keep the cached item in step so a second line for the same
+			// product records the state left by the first one.
+			matchedPantryItem.Quantity += item.Quantity
+			if perUnitPrice > 0 {
+				matchedPantryItem.PricePerUnit = perUnitPrice
+			}
+			if matchedPantryItem.Unit == "" {
+				matchedPantryItem.Unit = item.Unit
+			}
+			change.Unit = matchedPantryItem.Unit
+			change.PricePerUnit = matchedPantryItem.PricePerUnit
+			checkout.Changes = append(checkout.Changes, change)
+		} else {
+			newItem := &itemModel.Item{
+				ID:           uuid.New(),
+				PantryID:     *sl.PantryID,
+				AddedBy:      userID,
+				Name:         item.Name,
+				Quantity:     item.Quantity,
+				PricePerUnit: perUnitPrice,
+				Unit:         item.Unit,
+			}
+			checkout.Changes = append(checkout.Changes, shoppingModel.CheckoutChange{
+				ShoppingListItemID: item.ID,
+				PantryItemID:       newItem.ID,
+				Action:             shoppingModel.CheckoutActionCreate,
+				Name:               newItem.Name,
+				Quantity:           newItem.Quantity,
+				Unit:               newItem.Unit,
+				PricePerUnit:       newItem.PricePerUnit,
+			})
+			copied := newItem.ID
+			item.PantryItemID = &copied
+			pantryItemsByID[newItem.ID] = newItem
+			pantryItemsByName[normalizeName(newItem.Name)] = newItem
 		}
+	}
 
+	complete(purchasedCost)
+	if s.checkoutRepo != nil {
+		checkout.ActualCost = purchasedCost
+		if err := s.checkoutRepo.Apply(ctx, checkout, sl); err != nil {
+			zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
+			result0 = fmt.Errorf("apply checkout: %w", err)
+			return
+		}
+		result0 = nil
+		return
+	}
+
+	for _, item := range purchased {
 		if err := s.shoppingListRepo.UpdateItem(ctx, item); err != nil {
 			zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
-			result0 = 0
-			result1 = fmt.Errorf("update shopping list item: %w", err)
+			result0 = fmt.Errorf("update shopping list item: %w", err)
 			return
 		}
 	}
+	if err := s.shoppingListRepo.Update(ctx, sl); err != nil {
+		zap.L().Error("function.error", zap.String("func", "*shoppingListService.performCheckout"), zap.Error(err), zap.Any("params", __logParams))
+		result0 = fmt.Errorf("update shopping list: %w", err)
+		return
+	}
 
-	result0 = actualCost
-	result1 = nil
+	result0 = nil
 	return
 }
 
@@ -1445,22 +1638,45 @@ func (s *shoppingListService) convertToResponseDTO(ctx context.Context, sl *shop
 		pantryID = &idStr
 		pantryName = s.lookupPantryName(ctx, *sl.PantryID)
 	}
+
+	var (
+		storeID   *string
+		storeName string
+		sections  []dto.ShoppingListSectionDTO
+	)
+	if sl.StoreID != nil {
+		idStr := sl.StoreID.String()
+		storeID = &idStr
+		if store := s.lookupStore(ctx, *sl.StoreID); store != nil {
+			storeName = store.Name
+			items, sections = s.groupItemsByStore(store, sl.Items)
+		}
+	}
+
 	result0 = &dto.ShoppingListResponseDTO{
 		ID:            sl.ID.String(),
 		UserID:        sl.UserID.String(),
 		PantryID:      pantryID,
 		PantryName:    pantryName,
+		StoreID:       storeID,
+		StoreName:     storeName,
 		Name:          sl.Name,
 		Status:        sl.Status,
+		Transitions:   allowedTransitions(sl.Status),
 		TotalBudget:   sl.TotalBudget,
 		EstimatedCost: sl.EstimatedCost,
 		ActualCost:    sl.ActualCost,
 		GeneratedBy:   sl.GeneratedBy,
 		Items:         items,
+		Sections:      sections,
 		Preferences:   convertPreferencesToDTO(sl),
 		CreatedAt:     sl.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:     sl.UpdatedAt.Format(time.RFC3339),
 	}
+	if sl.CompletedAt != nil {
+		completedAt := sl.CompletedAt.Format(time.RFC3339)
+		result0.CompletedAt = &completedAt
+	}
 	return
 }
 
@@ -1493,6 +1709,16 @@ func (s *shoppingListService) convertItemToResponseDTO(item *shoppingModel.Shopp
 		id := item.PantryItemID.String()
 		pantryItemID = &id
 	}
+	var recipeID *string
+	if item.RecipeID != nil {
+		id := item.RecipeID.String()
+		recipeID = &id
+	}
+	var purchasedAt *string
+	if item.PurchasedAt != nil {
+		formatted := item.PurchasedAt.Format(time.RFC3339)
+		purchasedAt = &formatted
+	}
 	result0 = &dto.ShoppingListItemResponseDTO{
 		ID:             item.ID.String(),
 		ShoppingListID: item.ShoppingListID.String(),
@@ -1506,6 +1732,8 @@ func (s *shoppingListService) convertItemToResponseDTO(item *shoppingModel.Shopp
 		Purchased:      item.Purchased,
 		Source:         item.Source,
 		PantryItemID:   pantryItemID,
+		RecipeID:       recipeID,
+		PurchasedAt:    purchasedAt,
 		CreatedAt:      item.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:      item.UpdatedAt.Format(time.RFC3339),
 	}
@@ -1691,7 +1919,7 @@ func (s *shoppingListService) parseAIResponse(ctx context.Context, userID uuid.U
 		UserID:              userID,
 		PantryID:            &pantryID,
 		Name:                input.Name,
-		Status:              "pending",
+		Status:              shoppingModel.StatusPending,
 		TotalBudget:         budget,
 		EstimatedCost:       aiList.EstimatedTotal,
 		GeneratedBy:         "ai",
@@ -15,6 +15,8 @@ import (
 	llmDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/llm/dto"
 	pantryModel "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/model"
 	profileModel "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/model"
+	recipeDTO "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/dto"
+	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
 	shoppingDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
 	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
 	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
@@ -164,6 +166,30 @@ func (m *mockShoppingListRepository) CountByUserID(ctx context.Context, userID u
 	return
 }
 
+func (m *mockShoppingListRepository) CreateMerged(ctx context.Context, merged *shoppingModel.ShoppingList, deleteIDs []uuid.UUID) error {
+	args := m.Called(ctx, merged, deleteIDs)
+	return args.Error(0)
+}
+
+func (m *mockShoppingListRepository) MoveItems(ctx context.Context, saved []*shoppingModel.ShoppingListItem, deletedIDs []uuid.UUID, lists []*shoppingModel.ShoppingList) error {
+	args := m.Called(ctx, saved, deletedIDs, lists)
+	return args.Error(0)
+}
+
+func (m *mockShoppingListRepository) SaveItems(ctx context.Context, list *shoppingModel.ShoppingList, items []*shoppingModel.ShoppingListItem) error {
+	args := m.Called(ctx, list, items)
+	return args.Error(0)
+}
+
+func (m *mockShoppingListRepository) ListPurchaseHistory(ctx context.Context, pantryID uuid.UUID, since time.Time) ([]*shoppingModel.ShoppingList, error) {
+	args := m.Called(ctx, pantryID, since)
+	var lists []*shoppingModel.ShoppingList
+	if v := args.Get(0); v != nil {
+		lists = v.([]*shoppingModel.ShoppingList)
+	}
+	return lists, args.Error(1)
+}
+
 type mockPantryRepository struct {
 	mock.Mock
 }
@@ -408,7 +434,7 @@ func newService(repo *mockShoppingListRepository, pantryRepo *mockPantryReposito
 	zap.L().Info("function.entry", zap.String("func", "newService"), zap.Any("params", __logParams))
 	profileRepo := new(mockProfileRepository)
 	profileRepo.On("GetByUserID", mock.Anything, mock.Anything).Return((*profileModel.Profile)(nil), gorm.ErrRecordNotFound).Maybe()
-	result0 = service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil)
+	result0 = service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil, nil, nil, nil, nil, nil, nil, nil)
 	return
 }
 
@@ -602,13 +628,13 @@ func TestShoppingListService_UpdateShoppingListItem_RecalculateTotals(t *testing
 	pantryRepo.AssertExpectations(t)
 }
 
-func TestShoppingListService_UpdateShoppingList_FinalizePurchasedOnly(t *testing.T) {
+func TestShoppingListService_TransitionShoppingList_FinalizePurchasedOnly(t *testing.T) {
 	__logParams := map[string]any{"t": t}
 	__logStart := time.Now()
 	defer func() {
-		zap.L().Info("function.exit", zap.String("func", "TestShoppingListService_UpdateShoppingList_FinalizePurchasedOnly"), zap.Any("result", nil), zap.Duration("duration", time.Since(__logStart)))
+		zap.L().Info("function.exit", zap.String("func", "TestShoppingListService_TransitionShoppingList_FinalizePurchasedOnly"), zap.Any("result", nil), zap.Duration("duration", time.Since(__logStart)))
 	}()
-	zap.L().Info("function.entry", zap.String("func", "TestShoppingListService_UpdateShoppingList_FinalizePurchasedOnly"), zap.Any("params", __logParams))
+	zap.L().Info("function.entry", zap.String("func", "TestShoppingListService_TransitionShoppingList_FinalizePurchasedOnly"), zap.Any("params", __logParams))
 	repo := new(mockShoppingListRepository)
 	pantryRepo := new(mockPantryRepository)
 	service := newService(repo, pantryRepo)
@@ -665,8 +691,7 @@ func TestShoppingListService_UpdateShoppingList_FinalizePurchasedOnly(t *testing
 	})).Return(nil).Once()
 	repo.On("GetByID", mock.Anything, listID).Return(updatedList, nil).Once()
 
-	statusCompleted := "completed"
-	result, err := service.UpdateShoppingList(context.Background(), userID, listID, dto.UpdateShoppingListDTO{Status: &statusCompleted})
+	result, err := service.TransitionShoppingList(context.Background(), userID, listID, dto.TransitionShoppingListDTO{Status: "completed"})
 	require.NoError(t, err)
 	require.NotNil(t, result)
 	require.InEpsilon(t, 12, result.ActualCost, 1e-6)
@@ -699,12 +724,13 @@ func TestShoppingListService_GenerateAIShoppingList_PopulatesItems(t *testing.T)
 	profileRepo.On("GetByUserID", mock.Anything, userID).Return((*profileModel.Profile)(nil), gorm.ErrRecordNotFound).Maybe()
 	pantryRepo.On("GetByID", mock.Anything, pantryID).Return(pantry, nil).Maybe()
 	pantryRepo.On("IsUserInPantry", mock.Anything, pantryID, userID).Return(true, nil).Once()
+	pantryRepo.On("ListUsersInPantry", mock.Anything, pantryID).Return([]*pantryModel.PantryUserInfo{}, nil).Maybe()
 
 	aiResponse := `{"items":[{"name":"Arroz","quantity":2,"unit":"kg","estimated_price":30,"category":"Grãos","priority":1,"reason":"Reposição"},{"name":"Feijao","quantity":3,"unit":"un","estimated_price":15,"category":"Grãos","priority":2,"reason":"Consumo semanal"}],"reasoning":"Lista gerada para teste","estimated_total":45}`
 	llmStub := &fakeLLMService{
 		response: &llmDTO.LLMResponseDTO{Response: aiResponse},
 	}
-	service := service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, llmStub)
+	service := service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, llmStub, nil, nil, nil, nil, nil, nil, nil)
 
 	var capturedList *shoppingModel.ShoppingList
 	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
@@ -821,3 +847,776 @@ func ptrFloat64(value float64) (result0 *float64) {
 	result0 = &value
 	return
 }
+
+const riceAndBagReceipt = `<nfeProc><NFe><infNFe Id="NFe35240912345678000190650010000123451000123456">
+	<ide><dhEmi>2024-09-15T18:32:10-03:00</dhEmi></ide>
+	<emit><CNPJ>12345678000190</CNPJ><xNome>MERCADO EXEMPLO</xNome></emit>
+	<det nItem="1"><prod><cProd>1</cProd><cEAN>7893500018469</cEAN><xProd>ARROZ T.JOAO T1 5KG</xProd><uCom>UN</uCom><qCom>1.0000</qCom><vUnCom>25.90</vUnCom><vProd>25.90</vProd></prod></det>
+	<det nItem="2"><prod><cProd>2</cProd><cEAN>SEM GTIN</cEAN><xProd>SACOLA PLASTICA</xProd><uCom>UN</uCom><qCom>1.0000</qCom><vUnCom>0.10</vUnCom><vProd>0.10</vProd></prod></det>
+	<total><ICMSTot><vNF>26.00</vNF></ICMSTot></total>
+</infNFe></NFe></nfeProc>`
+
+func TestShoppingListService_ImportReceipt_MarksPurchasedAndChecksOut(t *testing.T) {
+	__logParams := map[string]any{"t": t}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "TestShoppingListService_ImportReceipt_MarksPurchasedAndChecksOut"), zap.Any("result", nil), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "TestShoppingListService_ImportReceipt_MarksPurchasedAndChecksOut"), zap.Any("params", __logParams))
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	service := newService(repo, pantryRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	riceID := uuid.New()
+	coffeeID := uuid.New()
+
+	shoppingList := &shoppingModel.ShoppingList{
+		ID:     listID,
+		UserID: userID,
+		Status: "pending",
+		Items: []shoppingModel.ShoppingListItem{
+			{ID: riceID, ShoppingListID: listID, Name: "Arroz", Quantity: 1, Unit: "un", EstimatedPrice: 28},
+			{ID: coffeeID, ShoppingListID: listID, Name: "Café", Quantity: 1, Unit: "un", EstimatedPrice: 18},
+		},
+	}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Once()
+	repo.On("UpdateItem", mock.Anything, mock.MatchedBy(func(item *shoppingModel.ShoppingListItem) bool {
+		return item.ID == riceID && item.Purchased && math.Abs(item.ActualPrice-25.9) < 1e-6 && item.PurchasedAt != nil
+	})).Return(nil).Once()
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(list *shoppingModel.ShoppingList) bool {
+		return list.Status == "completed" && math.Abs(list.ActualCost-25.9) < 1e-6
+	})).Return(nil).Once()
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Once()
+
+	result, err := service.ImportReceipt(context.Background(), userID, listID, dto.ImportReceiptDTO{Content: riceAndBagReceipt})
+	require.NoError(t, err)
+	require.True(t, result.CheckedOut)
+	require.Equal(t, "35240912345678000190650010000123451000123456", result.AccessKey)
+	require.Equal(t, 1, result.MatchedLines)
+	require.Equal(t, 1, result.UnmatchedLines)
+	require.Equal(t, "shopping_list_item", result.Lines[0].MatchType)
+	require.Equal(t, "unmatched", result.Lines[1].MatchType)
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.MatchedBy(func(item *shoppingModel.ShoppingListItem) bool {
+		return item.ID == coffeeID
+	}))
+}
+
+func TestShoppingListService_ImportReceipt_RejectsInvalidReceipt(t *testing.T) {
+	__logParams := map[string]any{"t": t}
+	__logStart := time.Now()
+	defer func() {
+		zap.L().Info("function.exit", zap.String("func", "TestShoppingListService_ImportReceipt_RejectsInvalidReceipt"), zap.Any("result", nil), zap.Duration("duration", time.Since(__logStart)))
+	}()
+	zap.L().Info("function.entry", zap.String("func", "TestShoppingListService_ImportReceipt_RejectsInvalidReceipt"), zap.Any("params", __logParams))
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	service := newService(repo, pantryRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	repo.On("GetByID", mock.Anything, listID).Return(&shoppingModel.ShoppingList{ID: listID, UserID: userID}, nil).Twice()
+
+	_, err := service.ImportReceipt(context.Background(), userID, listID, dto.ImportReceiptDTO{Content: "not a receipt"})
+	require.ErrorIs(t, err, shoppingDomain.ErrReceiptInvalid)
+
+	qrCode := "https://www.nfce.fazenda.sp.gov.br/qrcode?p=35240912345678000190650010000123451000123456|2|1|1|ABCDEF"
+	_, err = service.ImportReceipt(context.Background(), userID, listID, dto.ImportReceiptDTO{Content: qrCode})
+	require.ErrorIs(t, err, shoppingDomain.ErrReceiptWithoutItems)
+}
+
+type mockCheckoutRepository struct {
+	mock.Mock
+}
+
+func (m *mockCheckoutRepository) GetActive(ctx context.Context, shoppingListID uuid.UUID) (*shoppingModel.ShoppingListCheckout, error) {
+	args := m.Called(ctx, shoppingListID)
+	if checkout, ok := args.Get(0).(*shoppingModel.ShoppingListCheckout); ok {
+		return checkout, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockCheckoutRepository) ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) ([]*shoppingModel.ShoppingListCheckout, error) {
+	args := m.Called(ctx, shoppingListID)
+	if checkouts, ok := args.Get(0).([]*shoppingModel.ShoppingListCheckout); ok {
+		return checkouts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockCheckoutRepository) Apply(ctx context.Context, checkout *shoppingModel.ShoppingListCheckout, list *shoppingModel.ShoppingList) error {
+	args := m.Called(ctx, checkout, list)
+	return args.Error(0)
+}
+
+func (m *mockCheckoutRepository) Revert(ctx context.Context, checkout *shoppingModel.ShoppingListCheckout, userID uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, checkout, userID, at)
+	return args.Error(0)
+}
+
+func newServiceWithCheckouts(repo *mockShoppingListRepository, pantryRepo *mockPantryRepository, checkoutRepo *mockCheckoutRepository) shoppingDomain.ShoppingListService {
+	profileRepo := new(mockProfileRepository)
+	profileRepo.On("GetByUserID", mock.Anything, mock.Anything).Return((*profileModel.Profile)(nil), gorm.ErrRecordNotFound).Maybe()
+	return service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil, nil, nil, checkoutRepo, nil, nil, nil, nil)
+}
+
+func TestShoppingListService_ImportReceipt_FailedCheckoutWritesNothing(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	checkoutRepo := new(mockCheckoutRepository)
+	service := newServiceWithCheckouts(repo, pantryRepo, checkoutRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	shoppingList := &shoppingModel.ShoppingList{
+		ID:     listID,
+		UserID: userID,
+		Status: "pending",
+		Items:  []shoppingModel.ShoppingListItem{{ID: uuid.New(), ShoppingListID: listID, Name: "Arroz", Quantity: 1, Unit: "un"}},
+	}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Once()
+	checkoutRepo.On("GetActive", mock.Anything, listID).Return(nil, gorm.ErrRecordNotFound).Once()
+	checkoutRepo.On("Apply", mock.Anything, mock.Anything, mock.MatchedBy(func(list *shoppingModel.ShoppingList) bool {
+		return len(list.Items) == 2 && list.Items[1].Name == "SACOLA PLASTICA"
+	})).Return(shoppingDomain.ErrAlreadyCheckedOut).Once()
+
+	_, err := service.ImportReceipt(context.Background(), userID, listID, dto.ImportReceiptDTO{Content: riceAndBagReceipt, AddUnmatched: true})
+	require.ErrorIs(t, err, shoppingDomain.ErrAlreadyCheckedOut)
+
+	repo.AssertExpectations(t)
+	checkoutRepo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "SaveItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShoppingListService_ImportReceipt_SavesItemsWithoutCheckout(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	service := newService(repo, pantryRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	riceID := uuid.New()
+	shoppingList := &shoppingModel.ShoppingList{
+		ID:     listID,
+		UserID: userID,
+		Status: "pending",
+		Items:  []shoppingModel.ShoppingListItem{{ID: riceID, ShoppingListID: listID, Name: "Arroz", Quantity: 1, Unit: "un"}},
+	}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Twice()
+	repo.On("SaveItems", mock.Anything, shoppingList, mock.MatchedBy(func(items []*shoppingModel.ShoppingListItem) bool {
+		return len(items) == 2 && items[0].ID == riceID && items[0].Purchased &&
+			items[1].ID != uuid.Nil && items[1].Source == "receipt" && items[1].Purchased
+	})).Return(nil).Once()
+
+	checkout := false
+	result, err := service.ImportReceipt(context.Background(), userID, listID, dto.ImportReceiptDTO{Content: riceAndBagReceipt, AddUnmatched: true, Checkout: &checkout})
+	require.NoError(t, err)
+	require.False(t, result.CheckedOut)
+	require.Equal(t, 2, result.MatchedLines)
+	require.NotNil(t, result.Lines[1].ItemID)
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+}
+
+func TestShoppingListService_ImportReceipt_RefusesCompletedList(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	service := newService(repo, pantryRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	repo.On("GetByID", mock.Anything, listID).Return(&shoppingModel.ShoppingList{ID: listID, UserID: userID, Status: "completed"}, nil).Once()
+
+	_, err := service.ImportReceipt(context.Background(), userID, listID, dto.ImportReceiptDTO{Content: riceAndBagReceipt})
+	require.ErrorIs(t, err, shoppingDomain.ErrAlreadyCheckedOut)
+	repo.AssertNotCalled(t, "SaveItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShoppingListService_TransitionShoppingList_CheckoutIsIdempotent(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	checkoutRepo := new(mockCheckoutRepository)
+	service := newServiceWithCheckouts(repo, pantryRepo, checkoutRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	shoppingList := &shoppingModel.ShoppingList{
+		ID:     listID,
+		UserID: userID,
+		Status: "pending",
+		Items: []shoppingModel.ShoppingListItem{
+			{ID: uuid.New(), ShoppingListID: listID, Name: "Cafe", Quantity: 1, Unit: "un", ActualPrice: 12, Purchased: true},
+		},
+	}
+	active := &shoppingModel.ShoppingListCheckout{ID: uuid.New(), ShoppingListID: listID, ActualCost: 12}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Twice()
+	checkoutRepo.On("GetActive", mock.Anything, listID).Return(active, nil).Once()
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(list *shoppingModel.ShoppingList) bool {
+		return list.Status == "completed" && math.Abs(list.ActualCost-12) < 1e-6
+	})).Return(nil).Once()
+
+	_, err := service.TransitionShoppingList(context.Background(), userID, listID, dto.TransitionShoppingListDTO{Status: "completed"})
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	checkoutRepo.AssertExpectations(t)
+	checkoutRepo.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
+func TestShoppingListService_TransitionShoppingList_CompletesInCheckoutTransaction(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	checkoutRepo := new(mockCheckoutRepository)
+	service := newServiceWithCheckouts(repo, pantryRepo, checkoutRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	shoppingList := &shoppingModel.ShoppingList{
+		ID:     listID,
+		UserID: userID,
+		Status: "pending",
+		Items: []shoppingModel.ShoppingListItem{
+			{ID: uuid.New(), ShoppingListID: listID, Name: "Cafe", Quantity: 2, Unit: "un", EstimatedPrice: 12, Purchased: true},
+		},
+	}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Twice()
+	checkoutRepo.On("GetActive", mock.Anything, listID).Return(nil, gorm.ErrRecordNotFound).Once()
+	checkoutRepo.On("Apply", mock.Anything, mock.MatchedBy(func(checkout *shoppingModel.ShoppingListCheckout) bool {
+		return math.Abs(checkout.ActualCost-24) < 1e-6
+	}), mock.MatchedBy(func(list *shoppingModel.ShoppingList) bool {
+		return list.Status == "completed" && list.CompletedAt != nil && math.Abs(list.ActualCost-24) < 1e-6
+	})).Return(nil).Once()
+
+	_, err := service.TransitionShoppingList(context.Background(), userID, listID, dto.TransitionShoppingListDTO{Status: "completed"})
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	checkoutRepo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
+func TestShoppingListService_TransitionShoppingList_ReopeningRevertsCheckout(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	checkoutRepo := new(mockCheckoutRepository)
+	service := newServiceWithCheckouts(repo, pantryRepo, checkoutRepo)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	completedAt := time.Now().UTC()
+	shoppingList := &shoppingModel.ShoppingList{ID: listID, UserID: userID, Status: "completed", CompletedAt: &completedAt}
+	active := &shoppingModel.ShoppingListCheckout{ID: uuid.New(), ShoppingListID: listID}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Twice()
+	checkoutRepo.On("GetActive", mock.Anything, listID).Return(active, nil).Once()
+	checkoutRepo.On("Revert", mock.Anything, active, userID, mock.Anything).Return(nil).Once()
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(list *shoppingModel.ShoppingList) bool {
+		return list.Status == "pending" && list.CompletedAt == nil
+	})).Return(nil).Once()
+
+	_, err := service.TransitionShoppingList(context.Background(), userID, listID, dto.TransitionShoppingListDTO{Status: "pending"})
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	checkoutRepo.AssertExpectations(t)
+}
+
+type mockStatusHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *mockStatusHistoryRepository) Record(ctx context.Context, change *shoppingModel.ShoppingListStatusChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *mockStatusHistoryRepository) ListByShoppingListID(ctx context.Context, shoppingListID uuid.UUID) ([]*shoppingModel.ShoppingListStatusChange, error) {
+	args := m.Called(ctx, shoppingListID)
+	if changes, ok := args.Get(0).([]*shoppingModel.ShoppingListStatusChange); ok {
+		return changes, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestShoppingListService_TransitionShoppingList_RecordsHistory(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	statusRepo := new(mockStatusHistoryRepository)
+	profileRepo := new(mockProfileRepository)
+	service := service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil, nil, nil, nil, statusRepo, nil, nil, nil)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	shoppingList := &shoppingModel.ShoppingList{ID: listID, UserID: userID, Status: "pending"}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Twice()
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(list *shoppingModel.ShoppingList) bool {
+		return list.Status == "in_progress"
+	})).Return(nil).Once()
+	statusRepo.On("Record", mock.Anything, mock.MatchedBy(func(change *shoppingModel.ShoppingListStatusChange) bool {
+		return change.ShoppingListID == listID && change.FromStatus == "pending" && change.ToStatus == "in_progress" &&
+			change.ChangedBy == userID && change.Note == "no mercado"
+	})).Return(nil).Once()
+
+	result, err := service.TransitionShoppingList(context.Background(), userID, listID, dto.TransitionShoppingListDTO{Status: "in_progress", Note: "no mercado"})
+	require.NoError(t, err)
+	require.Equal(t, "in_progress", result.Status)
+	require.ElementsMatch(t, []string{"pending", "completed", "cancelled"}, result.Transitions)
+
+	repo.AssertExpectations(t)
+	statusRepo.AssertExpectations(t)
+}
+
+func TestShoppingListService_TransitionShoppingList_RejectsInvalidTransition(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	statusRepo := new(mockStatusHistoryRepository)
+	profileRepo := new(mockProfileRepository)
+	service := service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil, nil, nil, nil, statusRepo, nil, nil, nil)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	shoppingList := &shoppingModel.ShoppingList{ID: listID, UserID: userID, Status: "archived"}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Once()
+
+	result, err := service.TransitionShoppingList(context.Background(), userID, listID, dto.TransitionShoppingListDTO{Status: "pending"})
+	require.ErrorIs(t, err, shoppingDomain.ErrInvalidTransition)
+	require.Nil(t, result)
+
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	statusRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
+
+func TestShoppingListService_GenerateAIShoppingList_FallsBackToRules(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	profileRepo := new(mockProfileRepository)
+	service := service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	pantryID := uuid.New()
+	profileRepo.On("GetByUserID", mock.Anything, userID).Return((*profileModel.Profile)(nil), gorm.ErrRecordNotFound).Maybe()
+	pantryRepo.On("GetByID", mock.Anything, pantryID).Return(&pantryModel.Pantry{ID: pantryID, Name: "Casa"}, nil).Maybe()
+	pantryRepo.On("IsUserInPantry", mock.Anything, pantryID, userID).Return(true, nil).Once()
+	pantryRepo.On("ListUsersInPantry", mock.Anything, pantryID).Return([]*pantryModel.PantryUserInfo{}, nil).Maybe()
+
+	purchasedAt := func(daysAgo int) *shoppingModel.ShoppingList {
+		at := time.Now().AddDate(0, 0, -daysAgo)
+		return &shoppingModel.ShoppingList{CompletedAt: &at, Items: []shoppingModel.ShoppingListItem{
+			{Name: "Café", Quantity: 1, Unit: "un", Purchased: true, ActualPrice: 18},
+		}}
+	}
+	repo.On("ListPurchaseHistory", mock.Anything, pantryID, mock.Anything).
+		Return([]*shoppingModel.ShoppingList{purchasedAt(10), purchasedAt(3)}, nil).Once()
+
+	var created *shoppingModel.ShoppingList
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*shoppingModel.ShoppingList)
+		created.ID = uuid.New()
+		repo.On("GetByID", mock.Anything, created.ID).Return(created, nil).Once()
+	}).Once()
+
+	result, err := service.GenerateAIShoppingList(context.Background(), userID, dto.GenerateAIShoppingListDTO{
+		Name:     "Semana",
+		PantryID: pantryID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "rules", result.GeneratedBy)
+	require.Len(t, result.Items, 1)
+	require.Equal(t, "Café", result.Items[0].Name)
+	require.Equal(t, "rule_frequency", created.Items[0].Source)
+	require.InEpsilon(t, 18, result.EstimatedCost, 1e-6)
+
+	repo.AssertExpectations(t)
+	pantryRepo.AssertExpectations(t)
+}
+
+func TestShoppingListService_OptimizeShoppingList_AppliesCuts(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	profileRepo := new(mockProfileRepository)
+	service := service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	riceID, candyID, sodaID := uuid.New(), uuid.New(), uuid.New()
+	shoppingList := &shoppingModel.ShoppingList{
+		ID:            listID,
+		UserID:        userID,
+		Status:        "pending",
+		TotalBudget:   100,
+		HouseholdSize: 2,
+		EstimatedCost: 100,
+		Items: []shoppingModel.ShoppingListItem{
+			{ID: riceID, ShoppingListID: listID, Name: "Arroz", Quantity: 2, Unit: "un", EstimatedPrice: 25, Priority: 1},
+			{ID: candyID, ShoppingListID: listID, Name: "Bala", Quantity: 1, Unit: "un", EstimatedPrice: 10, Priority: 3},
+			{ID: sodaID, ShoppingListID: listID, Name: "Refrigerante", Quantity: 4, Unit: "un", EstimatedPrice: 10, Priority: 2},
+		},
+	}
+
+	repo.On("GetByID", mock.Anything, listID).Return(shoppingList, nil).Twice()
+	repo.On("DeleteItem", mock.Anything, candyID).Return(nil).Once()
+	repo.On("UpdateItem", mock.Anything, mock.MatchedBy(func(item *shoppingModel.ShoppingListItem) bool {
+		return item.ID == sodaID && item.Quantity == 2
+	})).Return(nil).Once()
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(list *shoppingModel.ShoppingList) bool {
+		return list.TotalBudget == 70 && math.Abs(list.EstimatedCost-70) < 1e-6 && len(list.Items) == 2
+	})).Return(nil).Once()
+
+	budget := 70.0
+	result, err := service.OptimizeShoppingList(context.Background(), userID, listID, dto.OptimizeShoppingListDTO{Budget: &budget, Apply: true})
+	require.NoError(t, err)
+	require.True(t, result.Applied)
+	require.True(t, result.WithinBudget)
+	require.InEpsilon(t, 100, result.OriginalCost, 1e-6)
+	require.InEpsilon(t, 70, result.OptimizedCost, 1e-6)
+	require.Len(t, result.Cuts, 2)
+	require.Equal(t, "removed", result.Cuts[0].Action)
+	require.Equal(t, "reduced", result.Cuts[1].Action)
+	require.NotNil(t, result.ShoppingList)
+
+	repo.AssertExpectations(t)
+}
+
+func TestShoppingListService_OptimizeShoppingList_RequiresBudget(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	service := service.NewShoppingListService(repo, new(mockPantryRepository), nil, new(mockProfileRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	listID := uuid.New()
+	repo.On("GetByID", mock.Anything, listID).Return(&shoppingModel.ShoppingList{ID: listID, UserID: userID}, nil).Once()
+
+	_, err := service.OptimizeShoppingList(context.Background(), userID, listID, dto.OptimizeShoppingListDTO{})
+	require.ErrorIs(t, err, shoppingDomain.ErrBudgetRequired)
+}
+
+func TestShoppingListService_MergeShoppingLists_ConsolidatesItems(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	service := service.NewShoppingListService(repo, pantryRepo, nil, new(mockProfileRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	pantryID := uuid.New()
+	pantryRepo.On("GetByID", mock.Anything, pantryID).Return(&pantryModel.Pantry{ID: pantryID, Name: "Casa"}, nil).Maybe()
+	firstID, secondID := uuid.New(), uuid.New()
+	first := &shoppingModel.ShoppingList{ID: firstID, UserID: userID, PantryID: &pantryID, Name: "Feira", TotalBudget: 50, HouseholdSize: 2, Items: []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), ShoppingListID: firstID, Name: "Tomate", Quantity: 1, Unit: "kg", EstimatedPrice: 8, Priority: 3},
+		{ID: uuid.New(), ShoppingListID: firstID, Name: "Alface", Quantity: 2, Unit: "un", EstimatedPrice: 3, Priority: 2},
+	}}
+	second := &shoppingModel.ShoppingList{ID: secondID, UserID: userID, Name: "Mercado", TotalBudget: 30, Items: []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), ShoppingListID: secondID, Name: "tomate", Quantity: 2, Unit: "quilos", EstimatedPrice: 11, Priority: 1},
+		{ID: uuid.New(), ShoppingListID: secondID, Name: "Tomate", Quantity: 500, Unit: "g", EstimatedPrice: 0.01},
+	}}
+	repo.On("GetByID", mock.Anything, firstID).Return(first, nil).Once()
+	repo.On("GetByID", mock.Anything, secondID).Return(second, nil).Once()
+
+	var merged *shoppingModel.ShoppingList
+	repo.On("CreateMerged", mock.Anything, mock.Anything, []uuid.UUID{firstID, secondID}).Return(nil).Run(func(args mock.Arguments) {
+		merged = args.Get(1).(*shoppingModel.ShoppingList)
+		merged.ID = uuid.New()
+		repo.On("GetByID", mock.Anything, merged.ID).Return(merged, nil).Once()
+	}).Once()
+
+	result, err := service.MergeShoppingLists(context.Background(), userID, dto.MergeShoppingListsDTO{
+		Name:            "Sábado",
+		ShoppingListIDs: []uuid.UUID{firstID, secondID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Sábado", result.Name)
+	require.Equal(t, "pending", result.Status)
+	require.InEpsilon(t, 80, result.TotalBudget, 1e-6)
+	require.Equal(t, &pantryID, merged.PantryID)
+	require.Len(t, merged.Items, 3)
+
+	tomato := merged.Items[0]
+	require.Equal(t, "Tomate", tomato.Name)
+	require.Equal(t, 3.0, tomato.Quantity)
+	require.Equal(t, 10.0, tomato.EstimatedPrice)
+	require.Equal(t, 1, tomato.Priority)
+	require.Equal(t, uuid.Nil, tomato.ID)
+	require.Equal(t, "g", merged.Items[2].Unit)
+	require.InEpsilon(t, 41, result.EstimatedCost, 1e-6)
+
+	repo.AssertExpectations(t)
+}
+
+func TestShoppingListService_MergeShoppingLists_RefusesCheckedOutLists(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	service := service.NewShoppingListService(repo, new(mockPantryRepository), nil, new(mockProfileRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	firstID, secondID := uuid.New(), uuid.New()
+	repo.On("GetByID", mock.Anything, firstID).Return(&shoppingModel.ShoppingList{ID: firstID, UserID: userID, Status: "pending"}, nil).Once()
+	repo.On("GetByID", mock.Anything, secondID).Return(&shoppingModel.ShoppingList{ID: secondID, UserID: userID, Status: "completed"}, nil).Once()
+
+	_, err := service.MergeShoppingLists(context.Background(), userID, dto.MergeShoppingListsDTO{
+		Name:            "Sábado",
+		ShoppingListIDs: []uuid.UUID{firstID, secondID},
+	})
+	require.ErrorIs(t, err, shoppingDomain.ErrShoppingListClosed)
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CreateMerged", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShoppingListService_DuplicateShoppingList_ResetsPurchasesOfCheckedOutList(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	service := service.NewShoppingListService(repo, new(mockPantryRepository), nil, new(mockProfileRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID, listID := uuid.New(), uuid.New()
+	purchasedAt := time.Now()
+	original := &shoppingModel.ShoppingList{ID: listID, UserID: userID, Name: "Feira", Status: "completed", Items: []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), ShoppingListID: listID, Name: "Tomate", Quantity: 1, Unit: "kg", EstimatedPrice: 8, ActualPrice: 9, Purchased: true, PurchasedAt: &purchasedAt},
+	}}
+	repo.On("GetByID", mock.Anything, listID).Return(original, nil).Once()
+
+	var duplicate *shoppingModel.ShoppingList
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		duplicate = args.Get(1).(*shoppingModel.ShoppingList)
+		duplicate.ID = uuid.New()
+		repo.On("GetByID", mock.Anything, duplicate.ID).Return(duplicate, nil).Once()
+	}).Once()
+
+	_, err := service.DuplicateShoppingList(context.Background(), userID, listID, dto.DuplicateShoppingListDTO{})
+	require.NoError(t, err)
+	require.Equal(t, "pending", duplicate.Status)
+	require.Len(t, duplicate.Items, 1)
+	require.False(t, duplicate.Items[0].Purchased)
+	require.Nil(t, duplicate.Items[0].PurchasedAt)
+	require.Zero(t, duplicate.Items[0].ActualPrice)
+	require.Equal(t, 8.0, duplicate.Items[0].EstimatedPrice)
+
+	repo.AssertExpectations(t)
+}
+
+func TestShoppingListService_MoveShoppingListItems_FoldsIntoTargetLine(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	service := service.NewShoppingListService(repo, new(mockPantryRepository), nil, new(mockProfileRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	sourceID, targetID := uuid.New(), uuid.New()
+	milkID, breadID, keptID, targetMilkID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	source := &shoppingModel.ShoppingList{ID: sourceID, UserID: userID, Items: []shoppingModel.ShoppingListItem{
+		{ID: milkID, ShoppingListID: sourceID, Name: "Leite", Quantity: 2, Unit: "l", EstimatedPrice: 5},
+		{ID: breadID, ShoppingListID: sourceID, Name: "Pão", Quantity: 1, Unit: "un", EstimatedPrice: 8},
+		{ID: keptID, ShoppingListID: sourceID, Name: "Café", Quantity: 1, Unit: "un", EstimatedPrice: 20},
+	}}
+	target := &shoppingModel.ShoppingList{ID: targetID, UserID: userID, Items: []shoppingModel.ShoppingListItem{
+		{ID: targetMilkID, ShoppingListID: targetID, Name: "leite", Quantity: 1, Unit: "litro", EstimatedPrice: 5},
+	}}
+	repo.On("GetByID", mock.Anything, sourceID).Return(source, nil).Twice()
+	repo.On("GetByID", mock.Anything, targetID).Return(target, nil).Twice()
+	repo.On("MoveItems", mock.Anything, mock.MatchedBy(func(saved []*shoppingModel.ShoppingListItem) bool {
+		return len(saved) == 2 &&
+			saved[0].ID == targetMilkID && saved[0].Quantity == 3 &&
+			saved[1].ID == breadID && saved[1].ShoppingListID == targetID
+	}), []uuid.UUID{milkID}, mock.MatchedBy(func(lists []*shoppingModel.ShoppingList) bool {
+		return len(lists) == 2 && lists[0].EstimatedCost == 20 && lists[1].EstimatedCost == 23
+	})).Return(nil).Once()
+
+	result, err := service.MoveShoppingListItems(context.Background(), userID, sourceID, dto.MoveShoppingListItemsDTO{
+		TargetListID: targetID,
+		ItemIDs:      []uuid.UUID{milkID, breadID},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Source.Items, 1)
+	require.Len(t, result.Target.Items, 2)
+
+	repo.AssertExpectations(t)
+}
+
+func TestShoppingListService_MoveShoppingListItems_RejectsUnknownItem(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	service := service.NewShoppingListService(repo, new(mockPantryRepository), nil, new(mockProfileRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	sourceID, targetID := uuid.New(), uuid.New()
+	repo.On("GetByID", mock.Anything, sourceID).Return(&shoppingModel.ShoppingList{ID: sourceID, UserID: userID}, nil).Once()
+	repo.On("GetByID", mock.Anything, targetID).Return(&shoppingModel.ShoppingList{ID: targetID, UserID: userID}, nil).Once()
+
+	_, err := service.MoveShoppingListItems(context.Background(), userID, sourceID, dto.MoveShoppingListItemsDTO{
+		TargetListID: targetID,
+		ItemIDs:      []uuid.UUID{uuid.New()},
+	})
+	require.ErrorIs(t, err, shoppingDomain.ErrItemNotFound)
+	repo.AssertNotCalled(t, "MoveItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+type stubRecipeRepository struct {
+	recipes map[uuid.UUID]*recipeModel.Recipe
+}
+
+func (r *stubRecipeRepository) Create(ctx context.Context, recipe *recipeModel.Recipe) error {
+	return nil
+}
+
+func (r *stubRecipeRepository) CreateMany(ctx context.Context, recipes []*recipeModel.Recipe) error {
+	return nil
+}
+
+func (r *stubRecipeRepository) SaveMany(ctx context.Context, created []*recipeModel.Recipe, replaced []*recipeModel.Recipe) error {
+	return nil
+}
+
+func (r *stubRecipeRepository) FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*recipeModel.Recipe, error) {
+	recipe, ok := r.recipes[id]
+	if !ok || recipe.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return recipe, nil
+}
+
+func (r *stubRecipeRepository) ListVersions(ctx context.Context, recipeID uuid.UUID) ([]*recipeModel.RecipeVersion, error) {
+	return nil, nil
+}
+
+func (r *stubRecipeRepository) FindVersion(ctx context.Context, recipeID uuid.UUID, version int) (*recipeModel.RecipeVersion, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubRecipeRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*recipeModel.Recipe, error) {
+	return nil, nil
+}
+
+func (r *stubRecipeRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	return nil
+}
+
+func (r *stubRecipeRepository) Update(ctx context.Context, recipe *recipeModel.Recipe) error {
+	return nil
+}
+
+func (r *stubRecipeRepository) Search(ctx context.Context, userID uuid.UUID, filter recipeDTO.RecipeFilterDTO) ([]*recipeModel.Recipe, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *stubRecipeRepository) ListTags(ctx context.Context, userID uuid.UUID) ([]recipeDTO.RecipeTagDTO, error) {
+	return nil, nil
+}
+
+func TestShoppingListService_AddRecipeToShoppingList_MergesIntoList(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	userID := uuid.New()
+	listID, recipeID, riceLineID := uuid.New(), uuid.New(), uuid.New()
+	rice, beans := 2.0, 500.0
+	servingSize := 4
+	recipes := &stubRecipeRepository{recipes: map[uuid.UUID]*recipeModel.Recipe{
+		recipeID: {ID: recipeID, UserID: userID, Title: "Arroz com feijão", ServingSize: &servingSize, Ingredients: recipeModel.RecipeIngredientsJSON{
+			{Name: "Arroz", Amount: &rice, Unit: "xícara"},
+			{Name: "Feijão", Amount: &beans, Unit: "g"},
+		}},
+	}}
+	profileRepo := new(mockProfileRepository)
+	service := service.NewShoppingListService(repo, new(mockPantryRepository), nil, profileRepo, nil, nil, nil, nil, nil, nil, recipes, nil)
+
+	list := &shoppingModel.ShoppingList{ID: listID, UserID: userID, Items: []shoppingModel.ShoppingListItem{
+		{ID: riceLineID, ShoppingListID: listID, Name: "arroz", Quantity: 240, Unit: "ml", Source: "manual"},
+	}}
+	profileRepo.On("GetByUserID", mock.Anything, userID).Return(&profileModel.Profile{UserID: userID, DietaryRestrictions: profileModel.StringArray{"Vegano"}}, nil).Once()
+	repo.On("GetByID", mock.Anything, listID).Return(list, nil).Twice()
+	repo.On("MoveItems", mock.Anything, mock.MatchedBy(func(saved []*shoppingModel.ShoppingListItem) bool {
+		return len(saved) == 2 &&
+			saved[0].ID == riceLineID && saved[0].Quantity == 1200 && *saved[0].RecipeID == recipeID &&
+			saved[1].Name == "Feijão" && saved[1].Quantity == 1 && saved[1].Unit == "kg" && saved[1].Source == "recipe"
+	}), []uuid.UUID(nil), mock.Anything).Return(nil).Once()
+
+	result, err := service.AddRecipeToShoppingList(context.Background(), userID, dto.AddRecipeToShoppingListDTO{
+		RecipeID:       recipeID,
+		Servings:       8,
+		ShoppingListID: &listID,
+	})
+	require.NoError(t, err)
+	require.False(t, result.Created)
+	require.Equal(t, 8, result.Servings)
+	require.Len(t, result.Ingredients, 2)
+	require.True(t, result.Ingredients[0].Merged)
+	require.False(t, result.Ingredients[1].Merged)
+	require.Len(t, result.ShoppingList.Items, 2)
+	require.Empty(t, result.ShoppingList.DietaryWarnings)
+
+	_, err = service.AddRecipeToShoppingList(context.Background(), uuid.New(), dto.AddRecipeToShoppingListDTO{RecipeID: recipeID})
+	require.ErrorIs(t, err, shoppingDomain.ErrRecipeNotFound)
+
+	repo.AssertExpectations(t)
+}
+
+func TestShoppingListService_UpdateShoppingListItem_FlagsDietaryConflictsOnRename(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	profileRepo := new(mockProfileRepository)
+	service := service.NewShoppingListService(repo, new(mockPantryRepository), nil, profileRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID, listID, itemID := uuid.New(), uuid.New(), uuid.New()
+	list := func() *shoppingModel.ShoppingList {
+		return &shoppingModel.ShoppingList{ID: listID, UserID: userID, DietaryRestrictions: shoppingModel.StringArray{"Sem glúten"}, Items: []shoppingModel.ShoppingListItem{
+			{ID: itemID, ShoppingListID: listID, Name: "Arroz", Quantity: 1, Unit: "kg"},
+		}}
+	}
+	repo.On("GetByID", mock.Anything, listID).Return(list(), nil).Once()
+	repo.On("GetByID", mock.Anything, listID).Return(list(), nil).Once()
+	repo.On("UpdateItem", mock.Anything, mock.Anything).Return(nil).Twice()
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+	repo.On("GetItemsByShoppingListID", mock.Anything, listID).Return([]*shoppingModel.ShoppingListItem(nil), nil).Twice()
+	profileRepo.On("GetByUserID", mock.Anything, userID).Return((*profileModel.Profile)(nil), gorm.ErrRecordNotFound).Once()
+
+	name := "Macarrão"
+	result, err := service.UpdateShoppingListItem(context.Background(), userID, listID, itemID, dto.UpdateShoppingListItemDTO{Name: &name})
+	require.NoError(t, err)
+	require.Equal(t, []dto.DietaryWarningDTO{
+		{Item: "Macarrão", Restriction: "gluten_free", Allergens: []string{"gluten"}, Source: "list"},
+	}, result.DietaryWarnings)
+
+	quantity := 2.0
+	result, err = service.UpdateShoppingListItem(context.Background(), userID, listID, itemID, dto.UpdateShoppingListItemDTO{Quantity: &quantity})
+	require.NoError(t, err)
+	require.Empty(t, result.DietaryWarnings)
+
+	repo.AssertExpectations(t)
+	profileRepo.AssertExpectations(t)
+}
+
+func TestShoppingListService_CreateShoppingListItem_FlagsDietaryConflicts(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	pantryRepo := new(mockPantryRepository)
+	profileRepo := new(mockProfileRepository)
+	service := service.NewShoppingListService(repo, pantryRepo, nil, profileRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID, memberID, listID, pantryID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	list := &shoppingModel.ShoppingList{ID: listID, UserID: userID, PantryID: &pantryID, DietaryRestrictions: shoppingModel.StringArray{"Vegano"}}
+	repo.On("GetByID", mock.Anything, listID).Return(list, nil).Twice()
+	repo.On("CreateItem", mock.Anything, mock.Anything).Return(nil).Once()
+	repo.On("Update", mock.Anything, list).Return(nil).Once()
+	pantryRepo.On("GetByID", mock.Anything, pantryID).Return(&pantryModel.Pantry{ID: pantryID, Name: "Casa"}, nil).Maybe()
+	pantryRepo.On("ListUsersInPantry", mock.Anything, pantryID).Return([]*pantryModel.PantryUserInfo{
+		{UserID: userID, Email: "dono@example.com"},
+		{UserID: memberID, Email: "membro@example.com"},
+	}, nil).Once()
+	profileRepo.On("GetByUserID", mock.Anything, userID).Return((*profileModel.Profile)(nil), gorm.ErrRecordNotFound).Once()
+	profileRepo.On("GetByUserID", mock.Anything, memberID).Return(&profileModel.Profile{UserID: memberID, DietaryRestrictions: profileModel.StringArray{"Celíaca"}}, nil).Once()
+
+	result, err := service.CreateShoppingListItem(context.Background(), userID, listID, dto.CreateShoppingListItemDTO{Name: "Bolo de leite", Quantity: 1, Unit: "un"})
+	require.NoError(t, err)
+	require.Equal(t, []dto.DietaryWarningDTO{
+		{Item: "Bolo de leite", Restriction: "gluten_free", Allergens: []string{"gluten"}, Source: "member", UserID: memberID.String(), Email: "membro@example.com"},
+		{Item: "Bolo de leite", Restriction: "vegan", Allergens: []string{"milk", "lactose"}, Source: "list"},
+	}, result.DietaryWarnings)
+
+	repo.AssertExpectations(t)
+	pantryRepo.AssertExpectations(t)
+	profileRepo.AssertExpectations(t)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/token"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultShareLifetime = 7 * 24 * time.Hour
+	shareTokenBytes      = 24
+	sharePathPrefix      = "/api/v1/public/shopping-lists/"
+)
+
+type shoppingListShareService struct {
+	shoppingListRepo domain.ShoppingListRepository
+	shareRepo        domain.ShoppingListShareRepository
+	now              func() time.Time
+}
+
+func NewShoppingListShareService(shoppingListRepo domain.ShoppingListRepository, shareRepo domain.ShoppingListShareRepository) domain.ShoppingListShareService {
+	return &shoppingListShareService{
+		shoppingListRepo: shoppingListRepo,
+		shareRepo:        shareRepo,
+		now:              time.Now,
+	}
+}
+
+func (s *shoppingListShareService) ExportShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, format string) (*dto.ShoppingListExportDTO, error) {
+	shoppingList, err := loadOwnedList(ctx, s.shoppingListRepo, userID, id, "ExportShoppingList")
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := renderShoppingListExport(ctx, shoppingList, format)
+	if err != nil {
+		return nil, err
+	}
+
+	appLogger.FromContext(ctx).Info("Shopping list exported",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "ExportShoppingList"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", id.String()),
+		zap.String("format", format),
+	)
+
+	return export, nil
+}
+
+func (s *shoppingListShareService) CreateShare(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, input dto.CreateShoppingListShareDTO) (*dto.ShoppingListShareDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := loadOwnedList(ctx, s.shoppingListRepo, userID, shoppingListID, "CreateShare"); err != nil {
+		return nil, err
+	}
+
+	shareToken, err := token.New(shareTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generate share token: %w", err)
+	}
+
+	lifetime := defaultShareLifetime
+	if input.ExpiresInHours > 0 {
+		lifetime = time.Duration(input.ExpiresInHours) * time.Hour
+	}
+
+	share := &shoppingModel.ShoppingListShare{
+		ShoppingListID: shoppingListID,
+		UserID:         userID,
+		Token:          shareToken,
+		ExpiresAt:      s.now().UTC().Add(lifetime),
+	}
+	if err := s.shareRepo.Create(ctx, share); err != nil {
+		logger.Error("Failed to create shopping list share",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "CreateShare"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("create share: %w", err)
+	}
+
+	logger.Info("Shopping list share created",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "CreateShare"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", shoppingListID.String()),
+		zap.String("share_id", share.ID.String()),
+	)
+
+	result := s.convertShareToDTO(share)
+	return &result, nil
+}
+
+func (s *shoppingListShareService) ListShares(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID) ([]dto.ShoppingListShareDTO, error) {
+	if _, err := loadOwnedList(ctx, s.shoppingListRepo, userID, shoppingListID, "ListShares"); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.ListByShoppingListID(ctx, shoppingListID)
+	if err != nil {
+		return nil, fmt.Errorf("list shares: %w", err)
+	}
+
+	result := make([]dto.ShoppingListShareDTO, 0, len(shares))
+	for _, share := range shares {
+		result = append(result, s.convertShareToDTO(share))
+	}
+	return result, nil
+}
+
+func (s *shoppingListShareService) RevokeShare(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, shareID uuid.UUID) error {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := loadOwnedList(ctx, s.shoppingListRepo, userID, shoppingListID, "RevokeShare"); err != nil {
+		return err
+	}
+
+	share, err := s.shareRepo.GetByID(ctx, shareID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrShareNotFound
+		}
+		return fmt.Errorf("get share: %w", err)
+	}
+	if share.ShoppingListID != shoppingListID {
+		return domain.ErrShareNotFound
+	}
+	if share.RevokedAt != nil {
+		return nil
+	}
+
+	revokedAt := s.now().UTC()
+	share.RevokedAt = &revokedAt
+	if err := s.shareRepo.Update(ctx, share); err != nil {
+		logger.Error("Failed to revoke shopping list share",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "RevokeShare"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("share_id", shareID.String()),
+			zap.Error(err),
+		)
+		return fmt.Errorf("revoke share: %w", err)
+	}
+
+	logger.Info("Shopping list share revoked",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "RevokeShare"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("share_id", shareID.String()),
+	)
+	return nil
+}
+
+func (s *shoppingListShareService) GetSharedShoppingList(ctx context.Context, token string) (*dto.SharedShoppingListDTO, error) {
+	share, shoppingList, err := s.resolveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	estimated, _ := calculateListTotals(shoppingList.Items)
+	result := &dto.SharedShoppingListDTO{
+		Name:          shoppingList.Name,
+		Status:        shoppingList.Status,
+		TotalBudget:   shoppingList.TotalBudget,
+		EstimatedCost: roundCurrency(estimated),
+		Categories:    []dto.SharedShoppingListCategoryDTO{},
+		ExpiresAt:     share.ExpiresAt.Format(time.RFC3339),
+		UpdatedAt:     shoppingList.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, group := range groupItemsByCategory(shoppingList.Items) {
+		category := dto.SharedShoppingListCategoryDTO{
+			Name:           group.Name,
+			EstimatedTotal: group.EstimatedTotal,
+			Items:          make([]dto.SharedShoppingListItemDTO, 0, len(group.Items)),
+		}
+		for _, item := range group.Items {
+			category.Items = append(category.Items, dto.SharedShoppingListItemDTO{
+				Name:           item.Name,
+				Quantity:       item.Quantity,
+				Unit:           item.Unit,
+				EstimatedPrice: item.EstimatedPrice,
+				Purchased:      item.Purchased,
+			})
+		}
+		result.Categories = append(result.Categories, category)
+	}
+	return result, nil
+}
+
+func (s *shoppingListShareService) ExportSharedShoppingList(ctx context.Context, token string, format string) (*dto.ShoppingListExportDTO, error) {
+	_, shoppingList, err := s.resolveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return renderShoppingListExport(ctx, shoppingList, format)
+}
+
+// resolveShare loads the list behind a public token. Unknown and revoked
+// tokens are reported the same way so links cannot be probed.
+func (s *shoppingListShareService) resolveShare(ctx context.Context, token string) (*shoppingModel.ShoppingListShare, *shoppingModel.ShoppingList, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, nil, domain.ErrShareNotFound
+	}
+
+	share, err := s.shareRepo.GetByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, domain.ErrShareNotFound
+		}
+		return nil, nil, fmt.Errorf("get share: %w", err)
+	}
+	if share.RevokedAt != nil {
+		return nil, nil, domain.ErrShareNotFound
+	}
+	if !share.Active(s.now()) {
+		return nil, nil, domain.ErrShareExpired
+	}
+
+	shoppingList, err := s.shoppingListRepo.GetByID(ctx, share.ShoppingListID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, domain.ErrShareNotFound
+		}
+		return nil, nil, fmt.Errorf("get shopping list: %w", err)
+	}
+	return share, shoppingList, nil
+}
+
+func (s *shoppingListShareService) convertShareToDTO(share *shoppingModel.ShoppingListShare) dto.ShoppingListShareDTO {
+	result := dto.ShoppingListShareDTO{
+		ID:             share.ID.String(),
+		ShoppingListID: share.ShoppingListID.String(),
+		Token:          share.Token,
+		Path:           sharePathPrefix + share.Token,
+		ExpiresAt:      share.ExpiresAt.Format(time.RFC3339),
+		Active:         share.Active(s.now()),
+		CreatedAt:      share.CreatedAt.Format(time.RFC3339),
+	}
+	if share.RevokedAt != nil {
+		revokedAt := share.RevokedAt.Format(time.RFC3339)
+		result.RevokedAt = &revokedAt
+	}
+	return result
+}
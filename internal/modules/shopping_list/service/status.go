@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// statusTransitions lists the statuses each status can move to. Archived
+// lists are final.
+var statusTransitions = map[string][]string{
+	shoppingModel.StatusDraft:      {shoppingModel.StatusPending, shoppingModel.StatusCancelled},
+	shoppingModel.StatusPending:    {shoppingModel.StatusDraft, shoppingModel.StatusInProgress, shoppingModel.StatusCompleted, shoppingModel.StatusCancelled},
+	shoppingModel.StatusInProgress: {shoppingModel.StatusPending, shoppingModel.StatusCompleted, shoppingModel.StatusCancelled},
+	shoppingModel.StatusCompleted:  {shoppingModel.StatusPending, shoppingModel.StatusArchived},
+	shoppingModel.StatusCancelled:  {shoppingModel.StatusPending, shoppingModel.StatusArchived},
+	shoppingModel.StatusArchived:   {},
+}
+
+// listStatus returns the status of a list, treating lists saved without one
+// as pending.
+func listStatus(sl *shoppingModel.ShoppingList) string {
+	if sl.Status == "" {
+		return shoppingModel.StatusPending
+	}
+	return sl.Status
+}
+
+// isCheckedOut tells whether the purchases of a list may already be in the
+// pantry: completed lists and archived ones, which may have been completed.
+func isCheckedOut(sl *shoppingModel.ShoppingList) bool {
+	status := listStatus(sl)
+	return status == shoppingModel.StatusCompleted || status == shoppingModel.StatusArchived
+}
+
+func allowedTransitions(status string) []string {
+	if status == "" {
+		status = shoppingModel.StatusPending
+	}
+	allowed := statusTransitions[status]
+	result := make([]string, len(allowed))
+	copy(result, allowed)
+	return result
+}
+
+func canTransition(from, to string) bool {
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shoppingListService) TransitionShoppingList(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.TransitionShoppingListDTO) (*dto.ShoppingListResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	shoppingList, err := s.loadOwnedList(ctx, userID, id, "TransitionShoppingList")
+	if err != nil {
+		return nil, err
+	}
+
+	from := listStatus(shoppingList)
+	to := input.Status
+	if !canTransition(from, to) {
+		logger.Warn("Invalid shopping list status transition",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "TransitionShoppingList"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", id.String()),
+			zap.String("from_status", from),
+			zap.String("to_status", to),
+		)
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrInvalidTransition, from, to)
+	}
+
+	// Completing a list checks it out into the pantry and saves it in the same
+	// transaction; reopening it undoes that checkout. Archiving keeps the
+	// checkout.
+	if to == shoppingModel.StatusCompleted {
+		if err := s.performCheckout(ctx, userID, shoppingList, time.Now().UTC(), input.ActualCost); err != nil {
+			logger.Error("Failed to perform checkout",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "TransitionShoppingList"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("shopping_list_id", id.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+	} else {
+		if from == shoppingModel.StatusCompleted && to == shoppingModel.StatusPending {
+			if err := s.revertActiveCheckout(ctx, userID, shoppingList); err != nil {
+				logger.Error("Failed to revert checkout",
+					zap.String(appLogger.FieldModule, "shopping_list"),
+					zap.String(appLogger.FieldFunction, "TransitionShoppingList"),
+					zap.String(appLogger.FieldUserID, userID.String()),
+					zap.String("shopping_list_id", id.String()),
+					zap.Error(err),
+				)
+				return nil, err
+			}
+			shoppingList.CompletedAt = nil
+		}
+		if input.ActualCost != nil {
+			shoppingList.ActualCost = *input.ActualCost
+		}
+		shoppingList.Status = to
+
+		if err := s.shoppingListRepo.Update(ctx, shoppingList); err != nil {
+			logger.Error("Failed to update shopping list status",
+				zap.String(appLogger.FieldModule, "shopping_list"),
+				zap.String(appLogger.FieldFunction, "TransitionShoppingList"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("shopping_list_id", id.String()),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("update shopping list: %w", err)
+		}
+	}
+	s.recordStatusChange(ctx, userID, id, from, to, input.Note)
+
+	logger.Info("Shopping list status changed",
+		zap.String(appLogger.FieldModule, "shopping_list"),
+		zap.String(appLogger.FieldFunction, "TransitionShoppingList"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", id.String()),
+		zap.String("from_status", from),
+		zap.String("to_status", to),
+	)
+
+	return s.reloadShoppingList(ctx, id)
+}
+
+func (s *shoppingListService) GetStatusHistory(ctx context.Context, userID uuid.UUID, id uuid.UUID) ([]dto.StatusChangeDTO, error) {
+	if _, err := s.loadOwnedList(ctx, userID, id, "GetStatusHistory"); err != nil {
+		return nil, err
+	}
+	if s.statusRepo == nil {
+		return []dto.StatusChangeDTO{}, nil
+	}
+
+	changes, err := s.statusRepo.ListByShoppingListID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("list status history: %w", err)
+	}
+
+	result := make([]dto.StatusChangeDTO, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, dto.StatusChangeDTO{
+			ID:         change.ID.String(),
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			ChangedBy:  change.ChangedBy.String(),
+			Note:       change.Note,
+			CreatedAt:  change.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return result, nil
+}
+
+// recordStatusChange appends a status change to the history of a list. The
+// status itself is already saved, so failures are logged and ignored.
+func (s *shoppingListService) recordStatusChange(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, from, to, note string) {
+	if s.statusRepo == nil {
+		return
+	}
+
+	change := &shoppingModel.ShoppingListStatusChange{
+		ShoppingListID: shoppingListID,
+		FromStatus:     from,
+		ToStatus:       to,
+		ChangedBy:      userID,
+		Note:           note,
+	}
+	if err := s.statusRepo.Record(ctx, change); err != nil {
+		appLogger.FromContext(ctx).Error("Failed to record shopping list status change",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "recordStatusChange"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.String("to_status", to),
+			zap.Error(err),
+		)
+	}
+}
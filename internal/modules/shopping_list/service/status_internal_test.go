@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionFollowsStateMachine(t *testing.T) {
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{"draft", "pending", true},
+		{"draft", "completed", false},
+		{"pending", "in_progress", true},
+		{"pending", "archived", false},
+		{"in_progress", "completed", true},
+		{"completed", "pending", true},
+		{"completed", "cancelled", false},
+		{"cancelled", "archived", true},
+		{"archived", "pending", false},
+		{"pending", "pending", false},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.allowed, canTransition(tc.from, tc.to), "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func TestAllowedTransitionsTreatsMissingStatusAsPending(t *testing.T) {
+	require.Equal(t, allowedTransitions("pending"), allowedTransitions(""))
+	require.Empty(t, allowedTransitions("archived"))
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// sectionOrderPrior is how many purchase observations the user-defined aisle
+// order is worth when blended with the learned order.
+const sectionOrderPrior = 3.0
+
+type sectionGroup struct {
+	Section *shoppingModel.StoreSection
+	Items   []shoppingModel.ShoppingListItem
+}
+
+func normalizeCategory(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// findSectionForCategory returns the store section mapped to an item category.
+func findSectionForCategory(store *shoppingModel.Store, category string) *shoppingModel.StoreSection {
+	key := normalizeCategory(category)
+	if store == nil || key == "" {
+		return nil
+	}
+	for idx := range store.Sections {
+		section := &store.Sections[idx]
+		if normalizeCategory(section.Name) == key {
+			return section
+		}
+		for _, mapped := range section.Categories {
+			if normalizeCategory(mapped) == key {
+				return section
+			}
+		}
+	}
+	return nil
+}
+
+// sectionWalkingKey blends the user-defined position with the learned one.
+// Both are expressed as a fraction of the walk so they can be combined.
+func sectionWalkingKey(section shoppingModel.StoreSection, total int) float64 {
+	userFraction := 0.0
+	if total > 1 {
+		userFraction = float64(section.Position) / float64(total-1)
+	}
+	if section.LearnedSamples <= 0 {
+		return userFraction
+	}
+	samples := float64(section.LearnedSamples)
+	return (userFraction*sectionOrderPrior + section.LearnedPosition*samples) / (sectionOrderPrior + samples)
+}
+
+// arrangeItemsByStore groups items by store section in walking order. Items
+// whose category is not mapped to any section are returned in a trailing
+// group with a nil Section.
+func arrangeItemsByStore(store *shoppingModel.Store, items []shoppingModel.ShoppingListItem) []sectionGroup {
+	sections := make([]shoppingModel.StoreSection, len(store.Sections))
+	copy(sections, store.Sections)
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sectionWalkingKey(sections[i], len(sections)) < sectionWalkingKey(sections[j], len(sections))
+	})
+
+	ordered := &shoppingModel.Store{Sections: sections}
+	groups := make([]sectionGroup, len(sections))
+	indexByID := make(map[string]int, len(sections))
+	for idx := range sections {
+		groups[idx].Section = &sections[idx]
+		indexByID[sections[idx].ID.String()] = idx
+	}
+
+	var unsectioned []shoppingModel.ShoppingListItem
+	for _, item := range items {
+		section := findSectionForCategory(ordered, item.Category)
+		if section == nil {
+			unsectioned = append(unsectioned, item)
+			continue
+		}
+		idx := indexByID[section.ID.String()]
+		groups[idx].Items = append(groups[idx].Items, item)
+	}
+
+	result := make([]sectionGroup, 0, len(groups)+1)
+	for _, group := range groups {
+		if len(group.Items) == 0 {
+			continue
+		}
+		sortItemsByName(group.Items)
+		result = append(result, group)
+	}
+	if len(unsectioned) > 0 {
+		sortItemsByName(unsectioned)
+		result = append(result, sectionGroup{Items: unsectioned})
+	}
+	return result
+}
+
+func sortItemsByName(items []shoppingModel.ShoppingListItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+}
+
+// learnedPurchaseFraction is where in the walk an item was picked, computed
+// from how many other items of the list had already been purchased.
+func learnedPurchaseFraction(items []shoppingModel.ShoppingListItem, purchased shoppingModel.ShoppingListItem) float64 {
+	if len(items) <= 1 {
+		return 0
+	}
+	before := 0
+	for _, item := range items {
+		if item.ID == purchased.ID || !item.Purchased {
+			continue
+		}
+		if item.PurchasedAt != nil && purchased.PurchasedAt != nil && item.PurchasedAt.After(*purchased.PurchasedAt) {
+			continue
+		}
+		before++
+	}
+	return float64(before) / float64(len(items)-1)
+}
+
+// recordSectionObservation updates the running average of a section's
+// position with a new purchase observation.
+func recordSectionObservation(section *shoppingModel.StoreSection, fraction float64) {
+	samples := float64(section.LearnedSamples)
+	section.LearnedPosition = (section.LearnedPosition*samples + fraction) / (samples + 1)
+	section.LearnedSamples++
+}
+
+func (s *shoppingListService) ensureStoreOwnership(ctx context.Context, userID uuid.UUID, storeID uuid.UUID) error {
+	if s.storeRepo == nil {
+		return domain.ErrStoreNotFound
+	}
+	store, err := s.storeRepo.GetByID(ctx, storeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrStoreNotFound
+		}
+		return fmt.Errorf("get store: %w", err)
+	}
+	if store.UserID != userID {
+		return domain.ErrStoreNotFound
+	}
+	return nil
+}
+
+func (s *shoppingListService) lookupStore(ctx context.Context, storeID uuid.UUID) *shoppingModel.Store {
+	if s.storeRepo == nil {
+		return nil
+	}
+	store, err := s.storeRepo.GetByID(ctx, storeID)
+	if err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to load store for shopping list",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "lookupStore"),
+			zap.String("store_id", storeID.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return store
+}
+
+// groupItemsByStore returns the items in walking order together with their
+// section grouping.
+func (s *shoppingListService) groupItemsByStore(store *shoppingModel.Store, items []shoppingModel.ShoppingListItem) ([]dto.ShoppingListItemResponseDTO, []dto.ShoppingListSectionDTO) {
+	groups := arrangeItemsByStore(store, items)
+
+	ordered := make([]dto.ShoppingListItemResponseDTO, 0, len(items))
+	sections := make([]dto.ShoppingListSectionDTO, 0, len(groups))
+	for _, group := range groups {
+		section := dto.ShoppingListSectionDTO{
+			Items: make([]dto.ShoppingListItemResponseDTO, 0, len(group.Items)),
+		}
+		if group.Section != nil {
+			section.SectionID = group.Section.ID.String()
+			section.Name = group.Section.Name
+		}
+		for idx := range group.Items {
+			itemDTO := *s.convertItemToResponseDTO(&group.Items[idx])
+			section.Items = append(section.Items, itemDTO)
+			ordered = append(ordered, itemDTO)
+		}
+		sections = append(sections, section)
+	}
+	return ordered, sections
+}
+
+// learnStoreOrder records where in the walk the section of a newly purchased
+// item was visited. Failures are logged and do not affect the item update.
+func (s *shoppingListService) learnStoreOrder(ctx context.Context, sl *shoppingModel.ShoppingList, purchased shoppingModel.ShoppingListItem) {
+	if sl.StoreID == nil || s.storeRepo == nil {
+		return
+	}
+	store := s.lookupStore(ctx, *sl.StoreID)
+	if store == nil {
+		return
+	}
+	section := findSectionForCategory(store, purchased.Category)
+	if section == nil {
+		return
+	}
+
+	recordSectionObservation(section, learnedPurchaseFraction(sl.Items, purchased))
+	if err := s.storeRepo.UpdateSection(ctx, section); err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to record store walking order",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "learnStoreOrder"),
+			zap.String("store_id", store.ID.String()),
+			zap.String("section_id", section.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+)
+
+func newTestStore() *shoppingModel.Store {
+	return &shoppingModel.Store{
+		ID: uuid.New(),
+		Sections: []shoppingModel.StoreSection{
+			{ID: uuid.New(), Name: "Hortifruti", Position: 0, Categories: shoppingModel.StringArray{"Frutas", "Verduras"}},
+			{ID: uuid.New(), Name: "Mercearia", Position: 1, Categories: shoppingModel.StringArray{"Grãos"}},
+			{ID: uuid.New(), Name: "Laticínios", Position: 2},
+		},
+	}
+}
+
+func TestArrangeItemsByStoreGroupsInWalkingOrder(t *testing.T) {
+	store := newTestStore()
+	items := []shoppingModel.ShoppingListItem{
+		{Name: "Queijo", Category: "laticínios"},
+		{Name: "Sabão", Category: "Limpeza"},
+		{Name: "Feijão", Category: "grãos"},
+		{Name: "Banana", Category: "Frutas"},
+		{Name: "Arroz", Category: "Grãos"},
+	}
+
+	groups := arrangeItemsByStore(store, items)
+	require.Len(t, groups, 4)
+	require.Equal(t, "Hortifruti", groups[0].Section.Name)
+	require.Equal(t, "Mercearia", groups[1].Section.Name)
+	require.Equal(t, "Arroz", groups[1].Items[0].Name)
+	require.Equal(t, "Feijão", groups[1].Items[1].Name)
+	require.Equal(t, "Laticínios", groups[2].Section.Name)
+	require.Nil(t, groups[3].Section)
+	require.Equal(t, "Sabão", groups[3].Items[0].Name)
+}
+
+func TestArrangeItemsByStoreUsesLearnedOrder(t *testing.T) {
+	store := newTestStore()
+	// Dairy has consistently been picked first and produce last.
+	store.Sections[2].LearnedPosition = 0
+	store.Sections[2].LearnedSamples = 10
+	store.Sections[0].LearnedPosition = 1
+	store.Sections[0].LearnedSamples = 10
+
+	items := []shoppingModel.ShoppingListItem{
+		{Name: "Banana", Category: "Frutas"},
+		{Name: "Leite", Category: "Laticínios"},
+	}
+
+	groups := arrangeItemsByStore(store, items)
+	require.Len(t, groups, 2)
+	require.Equal(t, "Laticínios", groups[0].Section.Name)
+	require.Equal(t, "Hortifruti", groups[1].Section.Name)
+}
+
+func TestLearnedPurchaseFractionAndObservation(t *testing.T) {
+	first := time.Now().Add(-time.Minute)
+	second := time.Now()
+	items := []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), Purchased: true, PurchasedAt: &first},
+		{ID: uuid.New(), Purchased: true, PurchasedAt: &second},
+		{ID: uuid.New()},
+	}
+
+	require.InDelta(t, 0, learnedPurchaseFraction(items, items[0]), 1e-9)
+	require.InDelta(t, 0.5, learnedPurchaseFraction(items, items[1]), 1e-9)
+
+	section := &shoppingModel.StoreSection{}
+	recordSectionObservation(section, 0.5)
+	recordSectionObservation(section, 1)
+	require.Equal(t, 2, section.LearnedSamples)
+	require.InDelta(t, 0.75, section.LearnedPosition, 1e-9)
+}
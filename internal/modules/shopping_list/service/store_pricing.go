@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	priceSourceManual   = "manual"
+	priceSourcePurchase = "purchase"
+
+	missingReasonNoPrice      = "no_price"
+	missingReasonUnitMismatch = "unit_mismatch"
+)
+
+// normalizeItemName is the key used to match the same product across lists,
+// stores and the pantry: lower case, without accents and extra spaces.
+func normalizeItemName(name string) string {
+	value := units.Fold(name)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func roundCurrency(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func (s *storeService) RecordPrices(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, input dto.RecordStorePricesDTO) ([]dto.StorePriceResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := s.loadOwnedStore(ctx, userID, storeID, "RecordPrices"); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	prices := make([]*shoppingModel.StorePrice, 0, len(input.Prices))
+	for _, entry := range input.Prices {
+		name := strings.TrimSpace(entry.Name)
+		prices = append(prices, &shoppingModel.StorePrice{
+			StoreID:        storeID,
+			UserID:         userID,
+			Name:           name,
+			NormalizedName: normalizeItemName(name),
+			Unit:           strings.TrimSpace(entry.Unit),
+			Price:          entry.Price,
+			Source:         priceSourceManual,
+			ObservedAt:     now,
+		})
+	}
+
+	if err := s.storeRepo.RecordPrices(ctx, prices); err != nil {
+		logger.Error("Failed to record store prices",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "RecordPrices"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("store_id", storeID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("record store prices: %w", err)
+	}
+
+	logger.Info("Store prices recorded",
+		zap.String(appLogger.FieldModule, "store"),
+		zap.String(appLogger.FieldFunction, "RecordPrices"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("store_id", storeID.String()),
+		zap.Int(appLogger.FieldCount, len(prices)),
+	)
+
+	result := make([]dto.StorePriceResponseDTO, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, convertStorePriceToResponseDTO(price))
+	}
+	return result, nil
+}
+
+func (s *storeService) ListPrices(ctx context.Context, userID uuid.UUID, storeID uuid.UUID) ([]dto.StorePriceResponseDTO, error) {
+	if _, err := s.loadOwnedStore(ctx, userID, storeID, "ListPrices"); err != nil {
+		return nil, err
+	}
+
+	prices, err := s.storeRepo.ListPrices(ctx, []uuid.UUID{storeID}, 0)
+	if err != nil {
+		appLogger.FromContext(ctx).Error("Failed to list store prices",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "ListPrices"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("store_id", storeID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list store prices: %w", err)
+	}
+
+	latest := latestStorePrices(prices)
+	result := make([]dto.StorePriceResponseDTO, 0, len(latest))
+	for _, price := range prices {
+		if latest[storePriceKey(price.StoreID, price.NormalizedName)] != price {
+			continue
+		}
+		result = append(result, convertStorePriceToResponseDTO(price))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result, nil
+}
+
+func (s *storeService) ComparePrices(ctx context.Context, userID uuid.UUID, shoppingListID uuid.UUID, storeIDs []uuid.UUID) (*dto.PriceComparisonResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	shoppingList, err := s.shoppingListRepo.GetByID(ctx, shoppingListID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShoppingListNotFound
+		}
+		return nil, fmt.Errorf("get shopping list: %w", err)
+	}
+	if shoppingList.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	var stores []*shoppingModel.Store
+	if len(storeIDs) == 0 {
+		stores, err = s.storeRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("list stores: %w", err)
+		}
+	} else {
+		for _, id := range storeIDs {
+			store, err := s.loadOwnedStore(ctx, userID, id, "ComparePrices")
+			if err != nil {
+				return nil, err
+			}
+			stores = append(stores, store)
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(stores))
+	for _, store := range stores {
+		ids = append(ids, store.ID)
+	}
+	observations, err := s.storeRepo.ListPrices(ctx, ids, 0)
+	if err != nil {
+		logger.Error("Failed to load store prices",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "ComparePrices"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("shopping_list_id", shoppingListID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list store prices: %w", err)
+	}
+
+	comparison := compareStorePrices(stores, shoppingList.Items, observations)
+	comparison.ShoppingListID = shoppingList.ID.String()
+
+	logger.Info("Store prices compared",
+		zap.String(appLogger.FieldModule, "store"),
+		zap.String(appLogger.FieldFunction, "ComparePrices"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("shopping_list_id", shoppingListID.String()),
+		zap.Int(appLogger.FieldCount, len(stores)),
+	)
+
+	return comparison, nil
+}
+
+func storePriceKey(storeID uuid.UUID, normalizedName string) string {
+	return storeID.String() + "|" + normalizedName
+}
+
+// latestStorePrices keeps the most recent observation per store and product.
+func latestStorePrices(observations []*shoppingModel.StorePrice) map[string]*shoppingModel.StorePrice {
+	latest := make(map[string]*shoppingModel.StorePrice, len(observations))
+	for _, observation := range observations {
+		key := storePriceKey(observation.StoreID, observation.NormalizedName)
+		if current, ok := latest[key]; ok && !observation.ObservedAt.After(current.ObservedAt) {
+			continue
+		}
+		latest[key] = observation
+	}
+	return latest
+}
+
+// priceItem prices a list item with a store observation, converting the item
+// quantity into the unit the price was recorded in. The returned reason is
+// empty when the item could be priced.
+func priceItem(item shoppingModel.ShoppingListItem, observation *shoppingModel.StorePrice) (dto.PricedItemDTO, string) {
+	if observation == nil {
+		return dto.PricedItemDTO{}, missingReasonNoPrice
+	}
+	quantity, ok := units.Convert(clampNonNegative(item.Quantity), item.Unit, observation.Unit)
+	if !ok {
+		return dto.PricedItemDTO{}, missingReasonUnitMismatch
+	}
+	return dto.PricedItemDTO{
+		ItemID:     item.ID.String(),
+		Name:       item.Name,
+		Quantity:   item.Quantity,
+		Unit:       item.Unit,
+		UnitPrice:  observation.Price,
+		PriceUnit:  observation.Unit,
+		Total:      roundCurrency(observation.Price * quantity),
+		ObservedAt: observation.ObservedAt.Format(time.RFC3339),
+	}, ""
+}
+
+// compareStorePrices computes the cost of the items at every store and the
+// cheapest way of splitting the purchase between them.
+func compareStorePrices(stores []*shoppingModel.Store, items []shoppingModel.ShoppingListItem, observations []*shoppingModel.StorePrice) *dto.PriceComparisonResponseDTO {
+	latest := latestStorePrices(observations)
+
+	result := &dto.PriceComparisonResponseDTO{
+		Stores:        make([]dto.StorePriceTotalDTO, 0, len(stores)),
+		UnpricedItems: []dto.MissingPriceItemDTO{},
+		CheapestSplit: dto.CheapestSplitDTO{Stores: []dto.PriceSplitStoreDTO{}},
+	}
+
+	type bestOffer struct {
+		storeIdx int
+		priced   dto.PricedItemDTO
+	}
+	best := make([]*bestOffer, len(items))
+	missingReasons := make([]string, len(items))
+
+	for storeIdx, store := range stores {
+		total := dto.StorePriceTotalDTO{
+			StoreID:      store.ID.String(),
+			StoreName:    store.Name,
+			Items:        []dto.PricedItemDTO{},
+			MissingItems: []dto.MissingPriceItemDTO{},
+		}
+		for itemIdx, item := range items {
+			observation := latest[storePriceKey(store.ID, normalizeItemName(item.Name))]
+			priced, reason := priceItem(item, observation)
+			if reason != "" {
+				total.MissingItems = append(total.MissingItems, dto.MissingPriceItemDTO{
+					ItemID: item.ID.String(),
+					Name:   item.Name,
+					Reason: reason,
+				})
+				total.EstimatedTotal += item.EstimatedPrice * clampNonNegative(item.Quantity)
+				if missingReasons[itemIdx] != missingReasonUnitMismatch {
+					missingReasons[itemIdx] = reason
+				}
+				continue
+			}
+			total.Items = append(total.Items, priced)
+			total.KnownTotal += priced.Total
+			total.EstimatedTotal += priced.Total
+			if best[itemIdx] == nil || priced.Total < best[itemIdx].priced.Total {
+				best[itemIdx] = &bestOffer{storeIdx: storeIdx, priced: priced}
+			}
+		}
+		total.KnownTotal = roundCurrency(total.KnownTotal)
+		total.EstimatedTotal = roundCurrency(total.EstimatedTotal)
+		total.Complete = len(total.MissingItems) == 0
+		result.Stores = append(result.Stores, total)
+	}
+
+	splitByStore := make(map[int]*dto.PriceSplitStoreDTO)
+	var splitOrder []int
+	for itemIdx, item := range items {
+		offer := best[itemIdx]
+		if offer == nil {
+			reason := missingReasons[itemIdx]
+			if reason == "" {
+				reason = missingReasonNoPrice
+			}
+			result.UnpricedItems = append(result.UnpricedItems, dto.MissingPriceItemDTO{
+				ItemID: item.ID.String(),
+				Name:   item.Name,
+				Reason: reason,
+			})
+			result.CheapestSplit.EstimatedTotal += item.EstimatedPrice * clampNonNegative(item.Quantity)
+			continue
+		}
+		split, ok := splitByStore[offer.storeIdx]
+		if !ok {
+			store := stores[offer.storeIdx]
+			split = &dto.PriceSplitStoreDTO{StoreID: store.ID.String(), StoreName: store.Name}
+			splitByStore[offer.storeIdx] = split
+			splitOrder = append(splitOrder, offer.storeIdx)
+		}
+		split.Items = append(split.Items, offer.priced)
+		split.Subtotal += offer.priced.Total
+		result.CheapestSplit.KnownTotal += offer.priced.Total
+		result.CheapestSplit.EstimatedTotal += offer.priced.Total
+	}
+
+	sort.Ints(splitOrder)
+	for _, storeIdx := range splitOrder {
+		split := splitByStore[storeIdx]
+		split.Subtotal = roundCurrency(split.Subtotal)
+		result.CheapestSplit.Stores = append(result.CheapestSplit.Stores, *split)
+	}
+	result.CheapestSplit.KnownTotal = roundCurrency(result.CheapestSplit.KnownTotal)
+	result.CheapestSplit.EstimatedTotal = roundCurrency(result.CheapestSplit.EstimatedTotal)
+
+	sort.SliceStable(result.Stores, func(i, j int) bool {
+		if result.Stores[i].Complete != result.Stores[j].Complete {
+			return result.Stores[i].Complete
+		}
+		return result.Stores[i].EstimatedTotal < result.Stores[j].EstimatedTotal
+	})
+	if len(result.Stores) > 0 {
+		cheapest := result.Stores[0]
+		result.CheapestStoreID = cheapest.StoreID
+		result.CheapestSplit.Savings = roundCurrency(math.Max(0, cheapest.EstimatedTotal-result.CheapestSplit.EstimatedTotal))
+	}
+
+	return result
+}
+
+// recordPurchasePrice stores the price paid for an item when the list is tied
+// to a store. Failures are logged and do not affect the item update.
+func (s *shoppingListService) recordPurchasePrice(ctx context.Context, sl *shoppingModel.ShoppingList, item shoppingModel.ShoppingListItem) {
+	if sl.StoreID == nil || s.storeRepo == nil || !item.Purchased || item.ActualPrice <= 0 {
+		return
+	}
+
+	observedAt := time.Now().UTC()
+	if item.PurchasedAt != nil {
+		observedAt = *item.PurchasedAt
+	}
+	listID := sl.ID
+	price := &shoppingModel.StorePrice{
+		StoreID:        *sl.StoreID,
+		UserID:         sl.UserID,
+		Name:           item.Name,
+		NormalizedName: normalizeItemName(item.Name),
+		Unit:           item.Unit,
+		Price:          item.ActualPrice,
+		Source:         priceSourcePurchase,
+		ShoppingListID: &listID,
+		ObservedAt:     observedAt,
+	}
+	if err := s.storeRepo.RecordPrices(ctx, []*shoppingModel.StorePrice{price}); err != nil {
+		appLogger.FromContext(ctx).Warn("Failed to record purchase price",
+			zap.String(appLogger.FieldModule, "shopping_list"),
+			zap.String(appLogger.FieldFunction, "recordPurchasePrice"),
+			zap.String("store_id", sl.StoreID.String()),
+			zap.String("item_id", item.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+func convertStorePriceToResponseDTO(price *shoppingModel.StorePrice) dto.StorePriceResponseDTO {
+	result := dto.StorePriceResponseDTO{
+		ID:         price.ID.String(),
+		StoreID:    price.StoreID.String(),
+		Name:       price.Name,
+		Unit:       price.Unit,
+		Price:      price.Price,
+		Source:     price.Source,
+		ObservedAt: price.ObservedAt.Format(time.RFC3339),
+	}
+	if price.ShoppingListID != nil {
+		listID := price.ShoppingListID.String()
+		result.ShoppingListID = &listID
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+)
+
+func TestCompareStorePricesTotalsAndSplit(t *testing.T) {
+	storeA := &shoppingModel.Store{ID: uuid.New(), Name: "Atacadão"}
+	storeB := &shoppingModel.Store{ID: uuid.New(), Name: "Pão de Açúcar"}
+	now := time.Now()
+
+	items := []shoppingModel.ShoppingListItem{
+		{ID: uuid.New(), Name: "Arroz", Quantity: 2, Unit: "kg", EstimatedPrice: 6},
+		{ID: uuid.New(), Name: "Café", Quantity: 500, Unit: "g", EstimatedPrice: 0.04},
+		{ID: uuid.New(), Name: "Detergente", Quantity: 1, Unit: "un", EstimatedPrice: 3},
+	}
+	observations := []*shoppingModel.StorePrice{
+		{StoreID: storeA.ID, NormalizedName: "arroz", Unit: "kg", Price: 5, ObservedAt: now},
+		{StoreID: storeA.ID, NormalizedName: "arroz", Unit: "kg", Price: 9, ObservedAt: now.Add(-48 * time.Hour)},
+		{StoreID: storeA.ID, NormalizedName: "cafe", Unit: "kg", Price: 40, ObservedAt: now},
+		{StoreID: storeB.ID, NormalizedName: "arroz", Unit: "kg", Price: 7, ObservedAt: now},
+		{StoreID: storeB.ID, NormalizedName: "cafe", Unit: "kg", Price: 30, ObservedAt: now},
+		{StoreID: storeB.ID, NormalizedName: "detergente", Unit: "l", Price: 4, ObservedAt: now},
+	}
+
+	result := compareStorePrices([]*shoppingModel.Store{storeA, storeB}, items, observations)
+	require.Len(t, result.Stores, 2)
+
+	byID := map[string]int{}
+	for idx, store := range result.Stores {
+		byID[store.StoreID] = idx
+	}
+	a := result.Stores[byID[storeA.ID.String()]]
+	require.InDelta(t, 30.0, a.KnownTotal, 1e-9)
+	require.InDelta(t, 33.0, a.EstimatedTotal, 1e-9)
+	require.False(t, a.Complete)
+	require.Len(t, a.MissingItems, 1)
+	require.Equal(t, missingReasonNoPrice, a.MissingItems[0].Reason)
+
+	b := result.Stores[byID[storeB.ID.String()]]
+	require.InDelta(t, 29.0, b.KnownTotal, 1e-9)
+	require.Len(t, b.MissingItems, 1)
+	require.Equal(t, missingReasonUnitMismatch, b.MissingItems[0].Reason)
+
+	require.Equal(t, storeB.ID.String(), result.CheapestStoreID)
+	require.InDelta(t, 25.0, result.CheapestSplit.KnownTotal, 1e-9)
+	require.InDelta(t, 28.0, result.CheapestSplit.EstimatedTotal, 1e-9)
+	require.InDelta(t, 4.0, result.CheapestSplit.Savings, 1e-9)
+	require.Len(t, result.CheapestSplit.Stores, 2)
+	require.Len(t, result.UnpricedItems, 1)
+	require.Equal(t, "Detergente", result.UnpricedItems[0].Name)
+	require.Equal(t, missingReasonUnitMismatch, result.UnpricedItems[0].Reason)
+}
+
+func TestNormalizeItemName(t *testing.T) {
+	require.Equal(t, "feijao carioca", normalizeItemName("  Feijão   Carioca "))
+	require.Equal(t, "acucar", normalizeItemName("AÇÚCAR"))
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/domain"
+	"github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/dto"
+	shoppingModel "github.com/nclsgg/despensa-digital/backend/internal/modules/shopping_list/model"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type storeService struct {
+	storeRepo        domain.StoreRepository
+	shoppingListRepo domain.ShoppingListRepository
+}
+
+func NewStoreService(storeRepo domain.StoreRepository, shoppingListRepo domain.ShoppingListRepository) domain.StoreService {
+	return &storeService{storeRepo: storeRepo, shoppingListRepo: shoppingListRepo}
+}
+
+func (s *storeService) CreateStore(ctx context.Context, userID uuid.UUID, input dto.CreateStoreDTO) (*dto.StoreResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	store := &shoppingModel.Store{
+		UserID:   userID,
+		Name:     strings.TrimSpace(input.Name),
+		Address:  strings.TrimSpace(input.Address),
+		Sections: buildStoreSections(input.Sections, nil),
+	}
+
+	if err := s.storeRepo.Create(ctx, store); err != nil {
+		logger.Error("Failed to create store",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "CreateStore"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("create store: %w", err)
+	}
+
+	logger.Info("Store created successfully",
+		zap.String(appLogger.FieldModule, "store"),
+		zap.String(appLogger.FieldFunction, "CreateStore"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("store_id", store.ID.String()),
+		zap.Int(appLogger.FieldCount, len(store.Sections)),
+	)
+
+	return convertStoreToResponseDTO(store), nil
+}
+
+func (s *storeService) GetStore(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*dto.StoreResponseDTO, error) {
+	store, err := s.loadOwnedStore(ctx, userID, id, "GetStore")
+	if err != nil {
+		return nil, err
+	}
+	return convertStoreToResponseDTO(store), nil
+}
+
+func (s *storeService) ListStores(ctx context.Context, userID uuid.UUID) ([]*dto.StoreResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	stores, err := s.storeRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list stores",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "ListStores"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list stores: %w", err)
+	}
+
+	result := make([]*dto.StoreResponseDTO, 0, len(stores))
+	for _, store := range stores {
+		result = append(result, convertStoreToResponseDTO(store))
+	}
+	return result, nil
+}
+
+func (s *storeService) UpdateStore(ctx context.Context, userID uuid.UUID, id uuid.UUID, input dto.UpdateStoreDTO) (*dto.StoreResponseDTO, error) {
+	logger := appLogger.FromContext(ctx)
+
+	store, err := s.loadOwnedStore(ctx, userID, id, "UpdateStore")
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		store.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Address != nil {
+		store.Address = strings.TrimSpace(*input.Address)
+	}
+
+	if err := s.storeRepo.Update(ctx, store); err != nil {
+		logger.Error("Failed to update store",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "UpdateStore"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("store_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("update store: %w", err)
+	}
+
+	if input.Sections != nil {
+		sections := buildStoreSections(*input.Sections, store.Sections)
+		if err := s.storeRepo.ReplaceSections(ctx, store.ID, sections); err != nil {
+			logger.Error("Failed to replace store sections",
+				zap.String(appLogger.FieldModule, "store"),
+				zap.String(appLogger.FieldFunction, "UpdateStore"),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("store_id", id.String()),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("replace store sections: %w", err)
+		}
+	}
+
+	updated, err := s.storeRepo.GetByID(ctx, store.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrStoreNotFound
+		}
+		return nil, fmt.Errorf("reload store: %w", err)
+	}
+
+	logger.Info("Store updated successfully",
+		zap.String(appLogger.FieldModule, "store"),
+		zap.String(appLogger.FieldFunction, "UpdateStore"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("store_id", id.String()),
+	)
+
+	return convertStoreToResponseDTO(updated), nil
+}
+
+func (s *storeService) DeleteStore(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	logger := appLogger.FromContext(ctx)
+
+	if _, err := s.loadOwnedStore(ctx, userID, id, "DeleteStore"); err != nil {
+		return err
+	}
+
+	if err := s.storeRepo.Delete(ctx, id); err != nil {
+		logger.Error("Failed to delete store",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, "DeleteStore"),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("store_id", id.String()),
+			zap.Error(err),
+		)
+		return fmt.Errorf("delete store: %w", err)
+	}
+
+	logger.Info("Store deleted successfully",
+		zap.String(appLogger.FieldModule, "store"),
+		zap.String(appLogger.FieldFunction, "DeleteStore"),
+		zap.String(appLogger.FieldUserID, userID.String()),
+		zap.String("store_id", id.String()),
+	)
+
+	return nil
+}
+
+func (s *storeService) loadOwnedStore(ctx context.Context, userID uuid.UUID, id uuid.UUID, function string) (*shoppingModel.Store, error) {
+	logger := appLogger.FromContext(ctx)
+
+	store, err := s.storeRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Store not found",
+				zap.String(appLogger.FieldModule, "store"),
+				zap.String(appLogger.FieldFunction, function),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.String("store_id", id.String()),
+			)
+			return nil, domain.ErrStoreNotFound
+		}
+		logger.Error("Failed to get store",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("store_id", id.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get store: %w", err)
+	}
+
+	if store.UserID != userID {
+		logger.Warn("Unauthorized access to store",
+			zap.String(appLogger.FieldModule, "store"),
+			zap.String(appLogger.FieldFunction, function),
+			zap.String(appLogger.FieldUserID, userID.String()),
+			zap.String("store_id", id.String()),
+			zap.String("owner_id", store.UserID.String()),
+		)
+		return nil, domain.ErrUnauthorized
+	}
+
+	return store, nil
+}
+
+// buildStoreSections converts the requested layout into sections, carrying
+// over the learned order of existing sections that keep the same name.
+func buildStoreSections(inputs []dto.StoreSectionInputDTO, existing []shoppingModel.StoreSection) []shoppingModel.StoreSection {
+	previous := make(map[string]shoppingModel.StoreSection, len(existing))
+	for _, section := range existing {
+		previous[normalizeCategory(section.Name)] = section
+	}
+
+	sections := make([]shoppingModel.StoreSection, 0, len(inputs))
+	for idx, input := range inputs {
+		section := shoppingModel.StoreSection{
+			Name:       strings.TrimSpace(input.Name),
+			Position:   idx,
+			Categories: shoppingModel.StringArray(normalizeStringSlice(input.Categories)),
+		}
+		if old, ok := previous[normalizeCategory(section.Name)]; ok {
+			section.LearnedPosition = old.LearnedPosition
+			section.LearnedSamples = old.LearnedSamples
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+func convertStoreToResponseDTO(store *shoppingModel.Store) *dto.StoreResponseDTO {
+	sections := make([]dto.StoreSectionResponseDTO, 0, len(store.Sections))
+	for _, section := range store.Sections {
+		sections = append(sections, dto.StoreSectionResponseDTO{
+			ID:              section.ID.String(),
+			Name:            section.Name,
+			Position:        section.Position,
+			Categories:      toShoppingListStringSlice(section.Categories),
+			LearnedPosition: section.LearnedPosition,
+			LearnedSamples:  section.LearnedSamples,
+		})
+	}
+
+	return &dto.StoreResponseDTO{
+		ID:        store.ID.String(),
+		UserID:    store.UserID.String(),
+		Name:      store.Name,
+		Address:   store.Address,
+		Sections:  sections,
+		CreatedAt: store.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: store.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -12,8 +12,11 @@ import (
 	"go.uber.org/zap"
 )
 
-func CreditGuardMiddleware(creditService domain.CreditService) gin.HandlerFunc {
-	__logParams := map[string]any{"creditService": creditService}
+// CreditGuardMiddleware stops requests of users without credits with 402.
+// Requests any of skip accepts, such as the free modes of an endpoint, are not
+// checked.
+func CreditGuardMiddleware(creditService domain.CreditService, skip ...func(c *gin.Context) bool) gin.HandlerFunc {
+	__logParams := map[string]any{"creditService": creditService, "skip": len(skip)}
 	__logStart := time.Now()
 	defer func() {
 		zap.L().Info("function.exit", zap.String("func", "CreditGuardMiddleware"), zap.Any("result", nil), zap.Duration("duration", time.Since(__logStart)))
@@ -21,6 +24,13 @@ func CreditGuardMiddleware(creditService domain.CreditService) gin.HandlerFunc {
 	zap.L().Info("function.entry", zap.String("func", "CreditGuardMiddleware"), zap.Any("params", __logParams))
 
 	return func(c *gin.Context) {
+		for _, free := range skip {
+			if free(c) {
+				c.Next()
+				return
+			}
+		}
+
 		userID, ok := extractUserID(c)
 		if !ok {
 			response.Unauthorized(c, "user not found in context")
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	_ "github.com/nclsgg/despensa-digital/backend/cmd/server/docs"
 	swaggerFiles "github.com/swaggo/files"
@@ -34,6 +36,11 @@ import (
 	recipeRepo "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/repository"
 	recipeService "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/service"
 
+	// Nutrition module imports
+	nutritionHandler "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/handler"
+	nutritionRepo "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/repository"
+	nutritionService "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/service"
+
 	// Profile module imports
 	profileHandler "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/handler"
 	profileRepo "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/repository"
@@ -46,6 +53,7 @@ import (
 
 	middleware "github.com/nclsgg/despensa-digital/backend/internal/router/middlewares"
 	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"github.com/nclsgg/despensa-digital/backend/pkg/recipeschema"
 )
 
 func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, logger *zap.Logger) {
@@ -121,26 +129,67 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, logger *zap.Log
 	profileServiceInstance := profileService.NewProfileService(profileRepoInstance)
 	profileHandlerInstance := profileHandler.NewProfileHandler(profileServiceInstance)
 
+	recipeRepoInstance := recipeRepo.NewRecipeRepository(db)
+	mealPlanRepoInstance := recipeRepo.NewMealPlanRepository(db)
+
 	// Shopping list module setup
 	shoppingListRepoInstance := shoppingListRepo.NewShoppingListRepository(db)
+	storeRepoInstance := shoppingListRepo.NewStoreRepository(db)
+	budgetRepoInstance := shoppingListRepo.NewBudgetRepository(db)
+	budgetServiceInstance := shoppingListService.NewBudgetService(budgetRepoInstance, pantryRepoInstance, profileRepoInstance)
+	budgetHandlerInstance := shoppingListHandler.NewBudgetHandler(budgetServiceInstance)
+	checkoutRepoInstance := shoppingListRepo.NewCheckoutRepository(db)
+	statusHistoryRepoInstance := shoppingListRepo.NewStatusHistoryRepository(db)
+	parLevelRepoInstance := shoppingListRepo.NewParLevelRepository(db)
+	parLevelServiceInstance := shoppingListService.NewParLevelService(parLevelRepoInstance, pantryRepoInstance)
+	parLevelHandlerInstance := shoppingListHandler.NewParLevelHandler(parLevelServiceInstance)
 	shoppingListServiceInstance := shoppingListService.NewShoppingListService(
 		shoppingListRepoInstance,
 		pantryRepoInstance,
 		itemRepoInstance,
 		profileRepoInstance,
 		llmServiceInstance,
+		storeRepoInstance,
+		budgetServiceInstance,
+		checkoutRepoInstance,
+		statusHistoryRepoInstance,
+		parLevelRepoInstance,
+		recipeRepoInstance,
+		mealPlanRepoInstance,
 	)
 	shoppingListHandlerInstance := shoppingListHandler.NewShoppingListHandler(shoppingListServiceInstance, creditServiceInstance)
+	storeServiceInstance := shoppingListService.NewStoreService(storeRepoInstance, shoppingListRepoInstance)
+	storeHandlerInstance := shoppingListHandler.NewStoreHandler(storeServiceInstance)
+	shoppingListShareRepoInstance := shoppingListRepo.NewShoppingListShareRepository(db)
+	shoppingListShareServiceInstance := shoppingListService.NewShoppingListShareService(shoppingListRepoInstance, shoppingListShareRepoInstance)
+	shoppingListShareHandlerInstance := shoppingListHandler.NewShoppingListShareHandler(shoppingListShareServiceInstance)
+
+	// Nutrition module setup
+	nutritionServiceInstance := nutritionService.NewNutritionService(nutritionRepo.NewFoodRepository(db))
+	nutritionHandlerInstance := nutritionHandler.NewNutritionHandler(nutritionServiceInstance)
+	if _, err := nutritionServiceInstance.SeedFoods(appLogger.WithLogger(context.Background(), logger)); err != nil {
+		logger.Error("Failed to seed the food table", zap.Error(err))
+	}
 
 	// Recipe module setup
-	recipeRepoInstance := recipeRepo.NewRecipeRepository(db)
+	recipeCookingRepoInstance := recipeRepo.NewRecipeCookingRepository(db)
 	recipeServiceInstance := recipeService.NewRecipeService(
 		llmServiceInstance,
 		itemRepoInstance,
 		pantryServiceInstance,
 		recipeRepoInstance,
+		recipeCookingRepoInstance,
+		recipeschema.NewHTTPFetcher(),
+		nutritionServiceInstance,
+		profileRepoInstance,
+		cfg.RecipeDuplicatePolicy,
 	)
 	recipeHandlerInstance := recipeHandler.NewRecipeHandler(recipeServiceInstance, llmServiceInstance, creditServiceInstance)
+	mealPlanServiceInstance := recipeService.NewMealPlanService(mealPlanRepoInstance, recipeRepoInstance, itemRepoInstance, pantryServiceInstance)
+	mealPlanHandlerInstance := recipeHandler.NewMealPlanHandler(mealPlanServiceInstance)
+	recipeShareRepoInstance := recipeRepo.NewRecipeShareRepository(db)
+	recipeShareServiceInstance := recipeService.NewRecipeShareService(recipeRepoInstance, recipeShareRepoInstance, userRepoInstance, pantryServiceInstance, profileRepoInstance)
+	recipeShareHandlerInstance := recipeHandler.NewRecipeShareHandler(recipeShareServiceInstance)
 
 	// Pantry routes
 	pantryHandlerInstance := pantryHandler.NewPantryHandler(pantryServiceInstance, itemServiceInstance)
@@ -234,7 +283,62 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, logger *zap.Log
 		shoppingListGroup.POST("/:id/items", shoppingListHandlerInstance.CreateShoppingListItem)
 		shoppingListGroup.PUT("/:id/items/:itemId", shoppingListHandlerInstance.UpdateShoppingListItem)
 		shoppingListGroup.DELETE("/:id/items/:itemId", shoppingListHandlerInstance.DeleteShoppingListItem)
-		shoppingListGroup.POST("/generate", middleware.CreditGuardMiddleware(creditServiceInstance), shoppingListHandlerInstance.GenerateAIShoppingList)
+		shoppingListGroup.GET("/:id/price-comparison", storeHandlerInstance.ComparePrices)
+		shoppingListGroup.POST("/:id/receipt", shoppingListHandlerInstance.ImportReceipt)
+		shoppingListGroup.GET("/:id/checkouts", shoppingListHandlerInstance.ListCheckouts)
+		shoppingListGroup.POST("/:id/checkout/revert", shoppingListHandlerInstance.RevertCheckout)
+		shoppingListGroup.POST("/:id/transitions", shoppingListHandlerInstance.TransitionShoppingList)
+		shoppingListGroup.GET("/:id/transitions", shoppingListHandlerInstance.GetStatusHistory)
+		shoppingListGroup.POST("/:id/optimize", shoppingListHandlerInstance.OptimizeShoppingList)
+		shoppingListGroup.POST("/:id/duplicate", shoppingListHandlerInstance.DuplicateShoppingList)
+		shoppingListGroup.POST("/:id/items/move", shoppingListHandlerInstance.MoveShoppingListItems)
+		shoppingListGroup.GET("/:id/export", shoppingListShareHandlerInstance.ExportShoppingList)
+		shoppingListGroup.POST("/:id/shares", shoppingListShareHandlerInstance.CreateShare)
+		shoppingListGroup.GET("/:id/shares", shoppingListShareHandlerInstance.ListShares)
+		shoppingListGroup.DELETE("/:id/shares/:shareId", shoppingListShareHandlerInstance.RevokeShare)
+		shoppingListGroup.POST("/generate", middleware.CreditGuardMiddleware(creditServiceInstance, shoppingListHandler.IsRuleBasedGeneration), shoppingListHandlerInstance.GenerateAIShoppingList)
+		shoppingListGroup.POST("/merge", shoppingListHandlerInstance.MergeShoppingLists)
+		shoppingListGroup.POST("/from-recipe", shoppingListHandlerInstance.AddRecipeToShoppingList)
+		shoppingListGroup.POST("/from-meal-plan", shoppingListHandlerInstance.GenerateFromMealPlan)
+	}
+
+	// Public shopping list links, no authentication
+	publicShoppingListGroup := r.Group("/api/v1/public/shopping-lists")
+	{
+		publicShoppingListGroup.GET("/:token", shoppingListShareHandlerInstance.GetSharedShoppingList)
+		publicShoppingListGroup.GET("/:token/export", shoppingListShareHandlerInstance.ExportSharedShoppingList)
+	}
+
+	// Store routes
+	storeGroup := r.Group("/api/v1/stores")
+	storeGroup.Use(middleware.AuthMiddleware(cfg, userRepoInstance))
+	storeGroup.Use(middleware.ProfileCompleteMiddleware())
+	{
+		storeGroup.POST("", storeHandlerInstance.CreateStore)
+		storeGroup.GET("", storeHandlerInstance.ListStores)
+		storeGroup.GET("/:id", storeHandlerInstance.GetStore)
+		storeGroup.PUT("/:id", storeHandlerInstance.UpdateStore)
+		storeGroup.DELETE("/:id", storeHandlerInstance.DeleteStore)
+		storeGroup.POST("/:id/prices", storeHandlerInstance.RecordPrices)
+		storeGroup.GET("/:id/prices", storeHandlerInstance.ListPrices)
+	}
+
+	// Budget routes
+	budgetGroup := r.Group("/api/v1/budgets")
+	budgetGroup.Use(middleware.AuthMiddleware(cfg, userRepoInstance))
+	budgetGroup.Use(middleware.ProfileCompleteMiddleware())
+	{
+		budgetGroup.GET("/ledger", budgetHandlerInstance.GetLedger)
+		budgetGroup.PUT("/pantries/:pantryId", budgetHandlerInstance.SetPantryBudget)
+	}
+
+	// Par level routes
+	parLevelGroup := r.Group("/api/v1/par-levels")
+	parLevelGroup.Use(middleware.AuthMiddleware(cfg, userRepoInstance))
+	parLevelGroup.Use(middleware.ProfileCompleteMiddleware())
+	{
+		parLevelGroup.GET("/pantries/:pantryId", parLevelHandlerInstance.ListParLevels)
+		parLevelGroup.PUT("/pantries/:pantryId", parLevelHandlerInstance.SetParLevels)
 	}
 
 	recipeGroup := r.Group("/api/v1/recipes")
@@ -243,8 +347,35 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, logger *zap.Log
 	{
 		recipeGroup.POST("/generate", middleware.CreditGuardMiddleware(creditServiceInstance), recipeHandlerInstance.GenerateRecipe)
 		recipeGroup.POST("/save", recipeHandlerInstance.SaveRecipe)
+		recipeGroup.POST("/duplicates", recipeHandlerInstance.FindRecipeDuplicates)
+		recipeGroup.POST("/import", recipeHandlerInstance.ImportRecipe)
+		recipeGroup.POST("/search/ingredients", recipeHandlerInstance.SearchRecipesByIngredients)
 		recipeGroup.GET("", recipeHandlerInstance.GetRecipes)
+		recipeGroup.GET("/tags", recipeHandlerInstance.ListRecipeTags)
+		recipeGroup.GET("/export", recipeHandlerInstance.ExportRecipes)
+		recipeGroup.POST("/shared/copy", recipeShareHandlerInstance.CopySharedRecipe)
 		recipeGroup.GET("/:id", recipeHandlerInstance.GetRecipeByID)
+		recipeGroup.PATCH("/:id", recipeHandlerInstance.UpdateRecipe)
+		recipeGroup.DELETE("/:id", recipeHandlerInstance.DeleteRecipe)
+		recipeGroup.PUT("/:id/tags", recipeHandlerInstance.SetRecipeTags)
+		recipeGroup.PUT("/:id/favorite", recipeHandlerInstance.SetRecipeFavorite)
+		recipeGroup.PUT("/:id/rating", recipeHandlerInstance.SetRecipeRating)
+		recipeGroup.PUT("/:id/notes", recipeHandlerInstance.SetRecipeNotes)
+		recipeGroup.POST("/:id/cook/preview", recipeHandlerInstance.PreviewCooking)
+		recipeGroup.POST("/:id/cook", recipeHandlerInstance.CookRecipe)
+		recipeGroup.POST("/:id/cooked", recipeHandlerInstance.LogRecipeCooking)
+		recipeGroup.GET("/:id/cookings", recipeHandlerInstance.ListRecipeCookings)
+		recipeGroup.GET("/:id/versions", recipeHandlerInstance.ListRecipeVersions)
+		recipeGroup.GET("/:id/versions/diff", recipeHandlerInstance.DiffRecipeVersions)
+		recipeGroup.GET("/:id/versions/:version", recipeHandlerInstance.GetRecipeVersion)
+		recipeGroup.POST("/:id/versions/:version/restore", recipeHandlerInstance.RestoreRecipeVersion)
+		recipeGroup.GET("/:id/scale", recipeHandlerInstance.ScaleRecipe)
+		recipeGroup.GET("/:id/substitutions", recipeHandlerInstance.SuggestSubstitutions)
+		recipeGroup.GET("/:id/export", recipeHandlerInstance.ExportRecipe)
+		recipeGroup.POST("/:id/nutrition", recipeHandlerInstance.CalculateRecipeNutrition)
+		recipeGroup.POST("/:id/shares", recipeShareHandlerInstance.CreateShare)
+		recipeGroup.GET("/:id/shares", recipeShareHandlerInstance.ListShares)
+		recipeGroup.DELETE("/:id/shares/:shareId", recipeShareHandlerInstance.RevokeShare)
 		recipeGroup.GET("/ingredients", recipeHandlerInstance.GetAvailableIngredients)
 		recipeGroup.GET("/pantries/:pantry_id/ingredients", recipeHandlerInstance.GetAvailableIngredients)
 		recipeGroup.POST("/chat", middleware.CreditGuardMiddleware(creditServiceInstance), recipeHandlerInstance.ChatWithLLM)
@@ -253,6 +384,31 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, logger *zap.Log
 		recipeGroup.POST("/tokens/estimate", recipeHandlerInstance.EstimateTokens)
 	}
 
+	// Public recipe links, no authentication
+	publicRecipeGroup := r.Group("/api/v1/public/recipes")
+	{
+		publicRecipeGroup.GET("/:token", recipeShareHandlerInstance.GetSharedRecipe)
+	}
+
+	mealPlanGroup := r.Group("/api/v1/meal-plans")
+	mealPlanGroup.Use(middleware.AuthMiddleware(cfg, userRepoInstance))
+	mealPlanGroup.Use(middleware.ProfileCompleteMiddleware())
+	{
+		mealPlanGroup.GET("", mealPlanHandlerInstance.ListMealPlan)
+		mealPlanGroup.POST("", mealPlanHandlerInstance.PlanMeal)
+		mealPlanGroup.POST("/generate", mealPlanHandlerInstance.GenerateMealPlan)
+		mealPlanGroup.PATCH("/:id", mealPlanHandlerInstance.UpdateMealPlanEntry)
+		mealPlanGroup.DELETE("/:id", mealPlanHandlerInstance.DeleteMealPlanEntry)
+	}
+
+	nutritionGroup := r.Group("/api/v1/nutrition")
+	nutritionGroup.Use(middleware.AuthMiddleware(cfg, userRepoInstance))
+	nutritionGroup.Use(middleware.ProfileCompleteMiddleware())
+	{
+		nutritionGroup.GET("/foods", nutritionHandlerInstance.SearchFoods)
+		nutritionGroup.POST("/estimate", nutritionHandlerInstance.EstimateNutrition)
+	}
+
 	// Swagger routes
 	r.GET(
 		"/swagger/*any",
@@ -8,6 +8,7 @@ import (
 	authModel "github.com/nclsgg/despensa-digital/backend/internal/modules/auth/model"
 	creditsModel "github.com/nclsgg/despensa-digital/backend/internal/modules/credits/model"
 	itemModel "github.com/nclsgg/despensa-digital/backend/internal/modules/item/model"
+	nutritionModel "github.com/nclsgg/despensa-digital/backend/internal/modules/nutrition/model"
 	pantryModel "github.com/nclsgg/despensa-digital/backend/internal/modules/pantry/model"
 	profileModel "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/model"
 	recipeModel "github.com/nclsgg/despensa-digital/backend/internal/modules/recipe/model"
@@ -71,9 +72,24 @@ func MigrateItems(db *gorm.DB) {
 		&profileModel.Profile{},
 		&shoppingListModel.ShoppingList{},
 		&shoppingListModel.ShoppingListItem{},
+		&shoppingListModel.Store{},
+		&shoppingListModel.StoreSection{},
+		&shoppingListModel.StorePrice{},
+		&shoppingListModel.ShoppingListShare{},
+		&shoppingListModel.PantryBudget{},
+		&shoppingListModel.ShoppingListCheckout{},
+		&shoppingListModel.CheckoutChange{},
+		&shoppingListModel.ShoppingListStatusChange{},
+		&shoppingListModel.ParLevel{},
 		&creditsModel.CreditWallet{},
 		&creditsModel.CreditTransaction{},
 		&recipeModel.Recipe{},
+		&recipeModel.RecipeVersion{},
+		&recipeModel.RecipeCooking{},
+		&recipeModel.RecipeCookingDeduction{},
+		&recipeModel.RecipeShare{},
+		&recipeModel.MealPlanEntry{},
+		&nutritionModel.Food{},
 	)
 	if err != nil {
 		zap.L().Error("function.error", zap.String("func", "MigrateItems"), zap.Error(err), zap.Any("params", __logParams))
@@ -0,0 +1,152 @@
+package dietary
+
+import (
+	"sort"
+	"strings"
+)
+
+// allergenTable maps common ingredients of Brazilian cooking to their
+// allergens. Longer names take precedence over the names inside them, so
+// entries with no allergens, such as "leite de coco", keep "leite" from
+// matching.
+var allergenTable = map[string][]string{
+	// Meat and products of slaughtered animals
+	"carne": {Meat}, "carne moida": {Meat}, "carne seca": {Meat}, "carne de sol": {Meat}, "charque": {Meat},
+	"bife": {Meat}, "picanha": {Meat}, "alcatra": {Meat}, "patinho": {Meat}, "acem": {Meat}, "musculo": {Meat},
+	"costela": {Meat}, "cupim": {Meat}, "fraldinha": {Meat}, "maminha": {Meat}, "contrafile": {Meat}, "file mignon": {Meat},
+	"frango": {Meat}, "galinha": {Meat}, "sobrecoxa": {Meat}, "peru": {Meat}, "chester": {Meat}, "pato": {Meat},
+	"porco": {Meat}, "lombo": {Meat}, "pernil": {Meat}, "bisteca": {Meat}, "bacon": {Meat}, "toucinho": {Meat},
+	"torresmo": {Meat}, "banha": {Meat}, "linguica": {Meat}, "calabresa": {Meat}, "salsicha": {Meat}, "paio": {Meat},
+	"presunto": {Meat}, "mortadela": {Meat}, "salame": {Meat}, "copa": {Meat}, "pancetta": {Meat},
+	"hamburguer": {Meat}, "almondega": {Meat}, "kafta": {Meat}, "figado": {Meat}, "moela": {Meat}, "mocoto": {Meat},
+	"rabada": {Meat}, "cordeiro": {Meat}, "carneiro": {Meat}, "cabrito": {Meat}, "vitela": {Meat}, "gelatina": {Meat},
+	"caldo de carne": {Meat}, "caldo de galinha": {Meat}, "caldo de frango": {Meat},
+
+	// Fish and shellfish
+	"peixe": {Fish}, "salmao": {Fish}, "atum": {Fish}, "sardinha": {Fish}, "bacalhau": {Fish}, "tilapia": {Fish},
+	"merluza": {Fish}, "pescada": {Fish}, "anchova": {Fish}, "truta": {Fish}, "robalo": {Fish}, "linguado": {Fish},
+	"tambaqui": {Fish}, "pintado": {Fish}, "caldo de peixe": {Fish}, "molho de peixe": {Fish}, "molho ingles": {Fish, Gluten},
+	"camarao": {Shellfish}, "camaroes": {Shellfish}, "lagosta": {Shellfish}, "caranguejo": {Shellfish}, "siri": {Shellfish},
+	"lula": {Shellfish}, "polvo": {Shellfish}, "mexilhao": {Shellfish}, "mexilhoes": {Shellfish}, "marisco": {Shellfish},
+	"ostra": {Shellfish}, "vieira": {Shellfish}, "sururu": {Shellfish}, "frutos do mar": {Shellfish}, "caldo de camarao": {Shellfish},
+
+	// Milk and dairy
+	"leite": {Milk, Lactose}, "leite em po": {Milk, Lactose}, "leite condensado": {Milk, Lactose}, "leite sem lactose": {Milk},
+	"creme de leite": {Milk, Lactose}, "nata": {Milk, Lactose}, "manteiga": {Milk, Lactose}, "ghee": {Milk},
+	"queijo": {Milk, Lactose}, "requeijao": {Milk, Lactose}, "cream cheese": {Milk, Lactose}, "catupiry": {Milk, Lactose},
+	"mussarela": {Milk, Lactose}, "mucarela": {Milk, Lactose}, "parmesao": {Milk, Lactose}, "ricota": {Milk, Lactose},
+	"iogurte": {Milk, Lactose}, "coalhada": {Milk, Lactose}, "kefir": {Milk, Lactose}, "doce de leite": {Milk, Lactose},
+	"chantilly": {Milk, Lactose}, "soro de leite": {Milk, Lactose}, "whey": {Milk, Lactose}, "caseina": {Milk},
+	"chocolate": {Milk, Lactose}, "chocolate em po": nil, "chocolate amargo": nil,
+
+	// Eggs
+	"ovo": {Egg}, "gema": {Egg}, "maionese": {Egg}, "merengue": {Egg}, "suspiro": {Egg}, "albumina": {Egg},
+
+	// Gluten
+	"trigo": {Gluten}, "farinha de trigo": {Gluten}, "farinha de rosca": {Gluten}, "panko": {Gluten}, "semolina": {Gluten},
+	"pao": {Gluten}, "paes": {Gluten}, "torrada": {Gluten}, "biscoito": {Gluten}, "bolacha": {Gluten}, "bolo": {Gluten},
+	"macarrao": {Gluten}, "espaguete": {Gluten}, "talharim": {Gluten}, "penne": {Gluten}, "lasanha": {Gluten},
+	"nhoque": {Gluten}, "lamen": {Gluten}, "miojo": {Gluten}, "cuscuz marroquino": {Gluten}, "massa folhada": {Gluten, Milk, Lactose},
+	"cevada": {Gluten}, "centeio": {Gluten}, "malte": {Gluten}, "cerveja": {Gluten}, "aveia": {Gluten}, "granola": {Gluten},
+	"seitan": {Gluten}, "macarrao de arroz": nil, "pao de queijo": {Milk, Lactose, Egg},
+
+	// Peanuts and tree nuts
+	"amendoim": {Peanut}, "pasta de amendoim": {Peanut}, "manteiga de amendoim": {Peanut}, "pacoca": {Peanut}, "pe de moleque": {Peanut},
+	"castanha": {TreeNut}, "castanha de caju": {TreeNut}, "castanha do para": {TreeNut}, "noz": {TreeNut}, "nozes": {TreeNut},
+	"amendoa": {TreeNut}, "avela": {TreeNut}, "pistache": {TreeNut}, "macadamia": {TreeNut},
+	"creme de avela": {TreeNut, Milk, Lactose}, "nutella": {TreeNut, Milk, Lactose},
+	"leite de amendoa": {TreeNut}, "leite de castanha": {TreeNut}, "farinha de amendoa": {TreeNut},
+
+	// Soy and sesame
+	"soja": {Soy}, "tofu": {Soy}, "shoyu": {Soy, Gluten}, "molho de soja": {Soy, Gluten}, "misso": {Soy}, "edamame": {Soy},
+	"leite de soja": {Soy}, "carne de soja": {Soy}, "proteina de soja": {Soy}, "proteina texturizada": {Soy},
+	"gergelim": {Sesame}, "tahine": {Sesame}, "tahini": {Sesame}, "homus": {Sesame}, "hummus": {Sesame},
+
+	// Honey
+	"mel": {Honey}, "mel de engenho": nil,
+
+	// Plant-based versions with none of the allergens of the names inside them
+	"leite de coco": nil, "creme de coco": nil, "creme de leite de coco": nil, "leite de arroz": nil,
+	"leite de aveia": {Gluten}, "bebida de aveia": {Gluten}, "manteiga de cacau": nil,
+}
+
+// freeMarkers are words telling an ingredient is free of allergens, as in
+// "pão sem glúten" or "queijo vegano".
+var freeMarkers = map[string][]string{
+	"sem lactose":  {Lactose},
+	"zero lactose": {Lactose},
+	"sem gluten":   {Gluten},
+	"sem ovo":      {Egg},
+	"vegano":       {Meat, Fish, Shellfish, Milk, Lactose, Egg, Honey},
+	"vegana":       {Meat, Fish, Shellfish, Milk, Lactose, Egg, Honey},
+	"vegetal":      {Meat, Fish, Shellfish, Milk, Lactose, Egg, Honey},
+	"vegetariano":  {Meat, Fish, Shellfish},
+	"vegetariana":  {Meat, Fish, Shellfish},
+}
+
+// allergenOrder is the order allergens are listed in.
+var allergenOrder = []string{Meat, Fish, Shellfish, Milk, Lactose, Egg, Gluten, Peanut, TreeNut, Soy, Sesame, Honey}
+
+type allergenEntry struct {
+	words     []string
+	allergens []string
+}
+
+// allergenIndex is the table with its names normalized, the names with more
+// words first.
+var allergenIndex = buildAllergenIndex()
+
+func buildAllergenIndex() []allergenEntry {
+	index := make([]allergenEntry, 0, len(allergenTable))
+	for name, allergens := range allergenTable {
+		index = append(index, allergenEntry{words: strings.Fields(normalize(name)), allergens: allergens})
+	}
+	sort.Slice(index, func(i, j int) bool {
+		if len(index[i].words) != len(index[j].words) {
+			return len(index[i].words) > len(index[j].words)
+		}
+		return strings.Join(index[i].words, " ") < strings.Join(index[j].words, " ")
+	})
+	return index
+}
+
+// Allergens lists the allergens of an ingredient found in the table. Each
+// word of the ingredient counts for the longest name it is part of, so
+// "farofa de bacon com ovos" has meat and egg, and "leite de coco" nothing.
+func Allergens(ingredient string) []string {
+	name := normalize(ingredient)
+	words := strings.Fields(name)
+	used := make([]bool, len(words))
+	found := make(map[string]bool)
+	for _, entry := range allergenIndex {
+	positions:
+		for i := 0; i+len(entry.words) <= len(words); i++ {
+			for j, word := range entry.words {
+				if used[i+j] || words[i+j] != word {
+					continue positions
+				}
+			}
+			for j := range entry.words {
+				used[i+j] = true
+			}
+			for _, allergen := range entry.allergens {
+				found[allergen] = true
+			}
+		}
+	}
+	for marker, allergens := range freeMarkers {
+		if containsPhrase(name, normalize(marker)) {
+			for _, allergen := range allergens {
+				delete(found, allergen)
+			}
+		}
+	}
+
+	var allergens []string
+	for _, allergen := range allergenOrder {
+		if found[allergen] {
+			allergens = append(allergens, allergen)
+		}
+	}
+	return allergens
+}
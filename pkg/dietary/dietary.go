@@ -0,0 +1,178 @@
+// Package dietary normalizes the free-text dietary restrictions of profiles,
+// shopping lists and recipes into a shared taxonomy, and checks ingredients
+// against it through the allergens of common ingredients.
+package dietary
+
+import (
+	"strings"
+
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+)
+
+// Restrictions of the taxonomy.
+const (
+	Vegan         = "vegan"
+	Vegetarian    = "vegetarian"
+	LactoseFree   = "lactose_free"
+	DairyFree     = "dairy_free"
+	GlutenFree    = "gluten_free"
+	EggFree       = "egg_free"
+	NutFree       = "nut_free"
+	SoyFree       = "soy_free"
+	FishFree      = "fish_free"
+	ShellfishFree = "shellfish_free"
+	SesameFree    = "sesame_free"
+)
+
+// Allergens, and the animal products diets exclude, an ingredient can have.
+const (
+	Meat      = "meat"
+	Fish      = "fish"
+	Shellfish = "shellfish"
+	Milk      = "milk"
+	Lactose   = "lactose"
+	Egg       = "egg"
+	Gluten    = "gluten"
+	Peanut    = "peanut"
+	TreeNut   = "tree_nut"
+	Soy       = "soy"
+	Sesame    = "sesame"
+	Honey     = "honey"
+)
+
+// restriction is an entry of the taxonomy: the allergens it forbids and the
+// ways people write it. The tag itself is always recognized.
+type restriction struct {
+	tag      string
+	forbids  []string
+	synonyms []string
+}
+
+var taxonomy = []restriction{
+	{Vegan, []string{Meat, Fish, Shellfish, Milk, Lactose, Egg, Honey}, []string{"vegano", "vegana", "veganismo", "plant based"}},
+	{Vegetarian, []string{Meat, Fish, Shellfish}, []string{"vegetariano", "vegetariana", "ovolactovegetariano", "sem carne"}},
+	{LactoseFree, []string{Lactose}, []string{"sem lactose", "zero lactose", "intolerancia a lactose", "intolerante a lactose"}},
+	{DairyFree, []string{Milk, Lactose}, []string{"sem leite", "sem laticinio", "alergia a leite", "alergia ao leite", "alergia a proteina do leite", "aplv"}},
+	{GlutenFree, []string{Gluten}, []string{"sem gluten", "celiaco", "celiaca", "doenca celiaca", "intolerancia ao gluten"}},
+	{EggFree, []string{Egg}, []string{"sem ovo", "alergia a ovo", "alergia ao ovo"}},
+	{NutFree, []string{Peanut, TreeNut}, []string{"sem amendoim", "sem castanha", "sem nozes", "sem oleaginosa", "alergia a amendoim", "alergia a castanha", "alergia a nozes", "alergia a oleaginosa"}},
+	{SoyFree, []string{Soy}, []string{"sem soja", "alergia a soja"}},
+	{FishFree, []string{Fish}, []string{"sem peixe", "alergia a peixe"}},
+	{ShellfishFree, []string{Shellfish}, []string{"sem frutos do mar", "sem crustaceo", "alergia a frutos do mar", "alergia a crustaceo", "alergia a camarao"}},
+	{SesameFree, []string{Sesame}, []string{"sem gergelim", "alergia a gergelim"}},
+}
+
+var punctuationReplacer = strings.NewReplacer(
+	"-", " ", "_", " ", ",", " ", ".", " ", "/", " ", "(", " ", ")", " ",
+)
+
+// normalize lowercases a name, drops its accents and punctuation and makes
+// its words singular, so "Intolerância à lactose" and "Ovos" are
+// "intolerancia a lactose" and "ovo".
+func normalize(name string) string {
+	words := strings.Fields(punctuationReplacer.Replace(units.Fold(name)))
+	for i, word := range words {
+		if len(word) > 3 && strings.HasSuffix(word, "s") {
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// containsPhrase tells whether the words of phrase appear in a row in a
+// normalized name.
+func containsPhrase(name, phrase string) bool {
+	return phrase != "" && strings.Contains(" "+name+" ", " "+phrase+" ")
+}
+
+// Tags lists the restrictions of the taxonomy, in its order.
+func Tags() []string {
+	tags := make([]string, 0, len(taxonomy))
+	for _, entry := range taxonomy {
+		tags = append(tags, entry.tag)
+	}
+	return tags
+}
+
+// Forbids lists the allergens a restriction of the taxonomy forbids.
+func Forbids(tag string) []string {
+	for _, entry := range taxonomy {
+		if entry.tag == tag {
+			return entry.forbids
+		}
+	}
+	return nil
+}
+
+// Normalize maps free-text restrictions, in Portuguese or English, to the
+// restrictions of the taxonomy, in its order. Restrictions it does not know
+// (such as "low carb") are left out.
+func Normalize(restrictions []string) []string {
+	found := make(map[string]bool)
+	for _, text := range restrictions {
+		name := normalize(text)
+		for _, entry := range taxonomy {
+			if found[entry.tag] {
+				continue
+			}
+			if containsPhrase(name, normalize(entry.tag)) {
+				found[entry.tag] = true
+				continue
+			}
+			for _, synonym := range entry.synonyms {
+				if containsPhrase(name, normalize(synonym)) {
+					found[entry.tag] = true
+					break
+				}
+			}
+		}
+	}
+
+	tags := []string{}
+	for _, entry := range taxonomy {
+		if found[entry.tag] {
+			tags = append(tags, entry.tag)
+		}
+	}
+	return tags
+}
+
+// Conflict is an ingredient that breaks a restriction.
+type Conflict struct {
+	Ingredient  string
+	Restriction string
+	// Allergens are the allergens of the ingredient the restriction forbids.
+	Allergens []string
+}
+
+// Check finds the ingredients that break any of the restrictions, which are
+// normalized first. An ingredient breaking several restrictions gives a
+// conflict for each one.
+func Check(ingredients []string, restrictions []string) []Conflict {
+	tags := Normalize(restrictions)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	var conflicts []Conflict
+	for _, ingredient := range ingredients {
+		allergens := Allergens(ingredient)
+		if len(allergens) == 0 {
+			continue
+		}
+		for _, tag := range tags {
+			var forbidden []string
+			for _, allergen := range allergens {
+				for _, forbid := range Forbids(tag) {
+					if allergen == forbid {
+						forbidden = append(forbidden, allergen)
+					}
+				}
+			}
+			if len(forbidden) > 0 {
+				conflicts = append(conflicts, Conflict{Ingredient: ingredient, Restriction: tag, Allergens: forbidden})
+			}
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,140 @@
+package dietary
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	profileModel "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, []string{Vegetarian, LactoseFree, GlutenFree, EggFree}, Normalize([]string{"Intolerância à lactose", "celíaco", "sem ovos", "low carb", "vegetariano"}))
+	assert.Equal(t, []string{Vegan, DairyFree}, Normalize([]string{"APLV", "Vegana", "vegan"}))
+	assert.Equal(t, []string{GlutenFree, NutFree, ShellfishFree}, Normalize([]string{"gluten-free", "nut_free", "Alergia a frutos do mar"}))
+	assert.Empty(t, Normalize([]string{"low carb", ""}))
+}
+
+func TestAllergens(t *testing.T) {
+	assert.Equal(t, []string{Milk, Lactose}, Allergens("Queijo parmesão ralado"))
+	assert.Equal(t, []string{Meat, Egg}, Allergens("Farofa de bacon com ovos"))
+	assert.Equal(t, []string{Peanut}, Allergens("Manteiga de amendoim"))
+	assert.Equal(t, []string{Gluten, Soy}, Allergens("Molho shoyu"))
+	assert.Equal(t, []string{Milk}, Allergens("Creme de leite sem lactose"))
+	assert.Empty(t, Allergens("Leite de coco"))
+	assert.Empty(t, Allergens("Queijo vegano"))
+	assert.Empty(t, Allergens("Macarrão sem glúten"))
+	assert.Empty(t, Allergens("Cenouras"))
+}
+
+func TestCheck(t *testing.T) {
+	conflicts := Check([]string{"Leite sem lactose", "Ovos", "Arroz", "Pão de queijo"}, []string{"vegano", "sem lactose"})
+	assert.Equal(t, []Conflict{
+		{Ingredient: "Leite sem lactose", Restriction: Vegan, Allergens: []string{Milk}},
+		{Ingredient: "Ovos", Restriction: Vegan, Allergens: []string{Egg}},
+		{Ingredient: "Pão de queijo", Restriction: Vegan, Allergens: []string{Milk, Lactose, Egg}},
+		{Ingredient: "Pão de queijo", Restriction: LactoseFree, Allergens: []string{Lactose}},
+	}, conflicts)
+
+	assert.Empty(t, Check([]string{"Bacon"}, []string{"low carb"}))
+	assert.Empty(t, Check([]string{"Tofu"}, []string{"vegetariano"}))
+}
+
+func TestTaxonomyForbidsKnownAllergens(t *testing.T) {
+	known := make(map[string]bool)
+	for _, allergen := range allergenOrder {
+		known[allergen] = true
+	}
+	for _, tag := range Tags() {
+		assert.NotEmpty(t, Forbids(tag), tag)
+		for _, allergen := range Forbids(tag) {
+			assert.True(t, known[allergen], "%s forbids unknown allergen %s", tag, allergen)
+		}
+	}
+	for name, allergens := range allergenTable {
+		for _, allergen := range allergens {
+			assert.True(t, known[allergen], "%s has unknown allergen %s", name, allergen)
+		}
+	}
+}
+
+func TestHousehold(t *testing.T) {
+	owner, member := uuid.New(), uuid.New()
+	restrictions := map[uuid.UUID][]string{member: {"sem ovo"}}
+	members, err := Household(owner, []Member{{UserID: owner, Email: "dono@example.com"}, {UserID: member, Email: "membro@example.com"}, {UserID: member}},
+		func(userID uuid.UUID) ([]string, error) { return restrictions[userID], nil })
+	require.NoError(t, err)
+	assert.Equal(t, []Member{
+		{UserID: owner, Email: "dono@example.com"},
+		{UserID: member, Email: "membro@example.com", Restrictions: []string{"sem ovo"}},
+	}, members)
+
+	_, err = Household(owner, nil, func(uuid.UUID) ([]string, error) { return nil, errors.New("boom") })
+	assert.Error(t, err)
+}
+
+func TestCheckHousehold(t *testing.T) {
+	members := []Member{{UserID: uuid.New(), Email: "membro@example.com", Restrictions: []string{"vegano"}}}
+	warnings := CheckHousehold([]string{"Ovos", "Queijo"}, []string{"sem ovo", "vegano"}, "list", members)
+	assert.Equal(t, []Warning{
+		{Conflict: Conflict{Ingredient: "Ovos", Restriction: Vegan, Allergens: []string{Egg}}, Source: SourceMember, Member: &members[0]},
+		{Conflict: Conflict{Ingredient: "Queijo", Restriction: Vegan, Allergens: []string{Milk, Lactose}}, Source: SourceMember, Member: &members[0]},
+		{Conflict: Conflict{Ingredient: "Ovos", Restriction: EggFree, Allergens: []string{Egg}}, Source: "list"},
+	}, warnings)
+}
+
+type stubProfileRepository struct {
+	profiles map[uuid.UUID]*profileModel.Profile
+	err      error
+}
+
+func (r *stubProfileRepository) Create(ctx context.Context, profile *profileModel.Profile) error {
+	return nil
+}
+
+func (r *stubProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*profileModel.Profile, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return profile, nil
+}
+
+func (r *stubProfileRepository) Update(ctx context.Context, profile *profileModel.Profile) error {
+	return nil
+}
+
+func (r *stubProfileRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func TestProfileRestrictions(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := &stubProfileRepository{profiles: map[uuid.UUID]*profileModel.Profile{
+		userID: {DietaryRestrictions: profileModel.StringArray{"vegano"}},
+	}}
+
+	restrictions, err := ProfileRestrictions(ctx, repo, "test", "TestProfileRestrictions")(userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vegano"}, restrictions)
+
+	restrictions, err = ProfileRestrictions(ctx, repo, "test", "TestProfileRestrictions")(uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, restrictions)
+
+	restrictions, err = ProfileRestrictions(ctx, nil, "test", "TestProfileRestrictions")(userID)
+	require.NoError(t, err)
+	assert.Empty(t, restrictions)
+
+	repo.err = errors.New("boom")
+	_, err = ProfileRestrictions(ctx, repo, "test", "TestProfileRestrictions")(userID)
+	assert.Error(t, err)
+}
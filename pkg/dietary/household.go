@@ -0,0 +1,70 @@
+package dietary
+
+import "github.com/google/uuid"
+
+// SourceMember is the source of the warnings about the restrictions of a
+// member of the household.
+const SourceMember = "member"
+
+// Member is someone of a household with the dietary restrictions of their
+// profile.
+type Member struct {
+	UserID       uuid.UUID
+	Email        string
+	Restrictions []string
+}
+
+// Household lists the owner and then the users, each one once, with the
+// restrictions restrictionsOf returns for them. The email of the owner is
+// taken from the users when they are among them.
+func Household(owner uuid.UUID, users []Member, restrictionsOf RestrictionsFunc) ([]Member, error) {
+	members := []Member{{UserID: owner}}
+	positions := map[uuid.UUID]int{owner: 0}
+	for _, user := range users {
+		if position, ok := positions[user.UserID]; ok {
+			if members[position].Email == "" {
+				members[position].Email = user.Email
+			}
+			continue
+		}
+		positions[user.UserID] = len(members)
+		members = append(members, Member{UserID: user.UserID, Email: user.Email})
+	}
+
+	for i := range members {
+		restrictions, err := restrictionsOf(members[i].UserID)
+		if err != nil {
+			return nil, err
+		}
+		members[i].Restrictions = restrictions
+	}
+	return members, nil
+}
+
+// Warning is a conflict with the restrictions of a member, or with the
+// restrictions of a recipe or shopping list when Member is nil.
+type Warning struct {
+	Conflict
+	Source string
+	Member *Member
+}
+
+// CheckHousehold checks the ingredients against the restrictions of each
+// member and then against the restrictions of the recipe or list itself,
+// reported with source. A conflict a member already has is not repeated.
+func CheckHousehold(ingredients []string, restrictions []string, source string, members []Member) []Warning {
+	var warnings []Warning
+	reported := make(map[string]bool)
+	for i := range members {
+		for _, conflict := range Check(ingredients, members[i].Restrictions) {
+			reported[conflict.Ingredient+"|"+conflict.Restriction] = true
+			warnings = append(warnings, Warning{Conflict: conflict, Source: SourceMember, Member: &members[i]})
+		}
+	}
+	for _, conflict := range Check(ingredients, restrictions) {
+		if !reported[conflict.Ingredient+"|"+conflict.Restriction] {
+			warnings = append(warnings, Warning{Conflict: conflict, Source: source})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,43 @@
+package dietary
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	profileDomain "github.com/nclsgg/despensa-digital/backend/internal/modules/profile/domain"
+	appLogger "github.com/nclsgg/despensa-digital/backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RestrictionsFunc returns the dietary restrictions of a user.
+type RestrictionsFunc func(userID uuid.UUID) ([]string, error)
+
+// ProfileRestrictions reads the dietary restrictions from the profiles of the
+// users. A user without a profile, or a nil repository, has none. Failures are
+// logged with the module and function that asked for the restrictions.
+func ProfileRestrictions(ctx context.Context, profiles profileDomain.ProfileRepository, module, function string) RestrictionsFunc {
+	return func(userID uuid.UUID) ([]string, error) {
+		if profiles == nil {
+			return nil, nil
+		}
+		profile, err := profiles.GetByUserID(ctx, userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			appLogger.FromContext(ctx).Error("Failed to get user profile",
+				zap.String(appLogger.FieldModule, module),
+				zap.String(appLogger.FieldFunction, function),
+				zap.String(appLogger.FieldUserID, userID.String()),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		if profile == nil {
+			return nil, nil
+		}
+		return profile.DietaryRestrictions, nil
+	}
+}
@@ -0,0 +1,137 @@
+// Package nfce parses Brazilian consumer electronic invoices (NFC-e) from the
+// authorized XML, the text of the SEFAZ consultation page or the content of
+// the receipt QR code. Parsing is done offline; nothing is fetched.
+package nfce
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatXML    Format = "xml"
+	FormatText   Format = "text"
+	FormatQRCode Format = "qrcode"
+)
+
+var (
+	ErrEmptyReceipt   = errors.New("nfce: empty receipt")
+	ErrInvalidReceipt = errors.New("nfce: invalid receipt")
+)
+
+// Receipt is the normalized content of an NFC-e. QR codes only carry the
+// access key and, for offline emissions, the issue date and total, so a
+// receipt parsed from a QR code has no lines.
+type Receipt struct {
+	AccessKey  string
+	IssuerName string
+	IssuerCNPJ string
+	IssuedAt   *time.Time
+	Total      float64
+	Lines      []Line
+}
+
+// Line is a product line. UnitPrice and Total already account for the line
+// discount, if any.
+type Line struct {
+	Number      int
+	Code        string
+	EAN         string
+	Description string
+	Unit        string
+	Quantity    float64
+	UnitPrice   float64
+	Total       float64
+}
+
+var packageSizePattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(KG|G|GR|MG|ML|L|LT)\b`)
+
+// PackageSize extracts the size printed in the description, such as the 5KG
+// in "ARROZ TIPO 1 5KG".
+func (l Line) PackageSize() (float64, string, bool) {
+	matches := packageSizePattern.FindAllStringSubmatch(l.Description, -1)
+	if len(matches) == 0 {
+		return 0, "", false
+	}
+	last := matches[len(matches)-1]
+	amount, err := parseNumber(last[1])
+	if err != nil || amount <= 0 {
+		return 0, "", false
+	}
+	return amount, strings.ToLower(last[2]), true
+}
+
+// DetectFormat guesses the format of a receipt payload.
+func DetectFormat(content string) Format {
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "<"):
+		return FormatXML
+	case strings.HasPrefix(strings.ToLower(trimmed), "http"), qrPayloadPattern.MatchString(trimmed):
+		return FormatQRCode
+	default:
+		return FormatText
+	}
+}
+
+// Parse parses a receipt in the given format. An empty format is detected
+// from the content.
+func Parse(content string, format Format) (*Receipt, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyReceipt
+	}
+	if format == "" {
+		format = DetectFormat(content)
+	}
+	switch format {
+	case FormatXML:
+		return ParseXML([]byte(content))
+	case FormatQRCode:
+		return ParseQRCode(content)
+	case FormatText:
+		return ParseText(content)
+	default:
+		return nil, ErrInvalidReceipt
+	}
+}
+
+// parseNumber accepts both the XML notation (1234.56) and the Brazilian one
+// used on printed receipts (1.234,56).
+func parseNumber(value string) (float64, error) {
+	cleaned := strings.TrimSpace(value)
+	cleaned = strings.TrimPrefix(cleaned, "R$")
+	cleaned = strings.TrimSpace(cleaned)
+	if strings.Contains(cleaned, ",") {
+		cleaned = strings.ReplaceAll(cleaned, ".", "")
+		cleaned = strings.ReplaceAll(cleaned, ",", ".")
+	}
+	return strconv.ParseFloat(cleaned, 64)
+}
+
+func parseIssuedAt(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "02/01/2006 15:04:05", "02/01/2006"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package nfce
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestParseXML(t *testing.T) {
+	receipt, err := Parse(readFixture(t, "nfce_proc.xml"), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "35240912345678000190650010000123451000123456", receipt.AccessKey)
+	assert.Equal(t, "MERCADO EXEMPLO", receipt.IssuerName)
+	assert.Equal(t, "12345678000190", receipt.IssuerCNPJ)
+	require.NotNil(t, receipt.IssuedAt)
+	assert.Equal(t, 2024, receipt.IssuedAt.Year())
+	assert.InDelta(t, 88.98, receipt.Total, 1e-9)
+	require.Len(t, receipt.Lines, 5)
+
+	banana := receipt.Lines[2]
+	assert.Equal(t, "BANANA PRATA KG", banana.Description)
+	assert.Equal(t, "KG", banana.Unit)
+	assert.Empty(t, banana.EAN)
+	assert.InDelta(t, 1.235, banana.Quantity, 1e-9)
+
+	milk := receipt.Lines[3]
+	assert.InDelta(t, 30.0, milk.Total, 1e-9)
+	assert.InDelta(t, 5.0, milk.UnitPrice, 1e-9)
+	amount, unit, ok := milk.PackageSize()
+	require.True(t, ok)
+	assert.Equal(t, 1.0, amount)
+	assert.Equal(t, "l", unit)
+}
+
+func TestParseText(t *testing.T) {
+	receipt, err := Parse(readFixture(t, "nfce_consulta.txt"), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "SUPERMERCADO EXEMPLO LTDA", receipt.IssuerName)
+	assert.Equal(t, "12345678000190", receipt.IssuerCNPJ)
+	assert.Equal(t, "35240912345678000190650010000123451000123456", receipt.AccessKey)
+	assert.InDelta(t, 51.51, receipt.Total, 1e-9)
+	require.NotNil(t, receipt.IssuedAt)
+	require.Len(t, receipt.Lines, 3)
+
+	assert.Equal(t, "ARROZ T.JOAO T1 5KG", receipt.Lines[0].Description)
+	assert.Equal(t, "7893500018469", receipt.Lines[0].EAN)
+	assert.InDelta(t, 25.9, receipt.Lines[0].UnitPrice, 1e-9)
+	assert.Equal(t, "FEIJAO CARIOCA KICALDO 1KG", receipt.Lines[1].Description)
+	assert.InDelta(t, 2, receipt.Lines[1].Quantity, 1e-9)
+	assert.InDelta(t, 1.235, receipt.Lines[2].Quantity, 1e-9)
+	assert.Empty(t, receipt.Lines[2].EAN)
+}
+
+func TestParseQRCode(t *testing.T) {
+	online := "https://www.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx?p=35240912345678000190650010000123451000123456|2|1|1|ABCDEF0123"
+	assert.Equal(t, FormatQRCode, DetectFormat(online))
+
+	receipt, err := Parse(online, "")
+	require.NoError(t, err)
+	assert.Equal(t, "35240912345678000190650010000123451000123456", receipt.AccessKey)
+	assert.Empty(t, receipt.Lines)
+
+	offline := "35240912345678000190650010000123451000123456|2|1|15|88.98|6a6f|1|ABCDEF0123"
+	receipt, err = Parse(offline, "")
+	require.NoError(t, err)
+	assert.InDelta(t, 88.98, receipt.Total, 1e-9)
+
+	_, err = Parse("https://example.com/?p=123|2|1", "")
+	assert.ErrorIs(t, err, ErrInvalidReceipt)
+}
+
+func TestParseRejectsInvalidContent(t *testing.T) {
+	_, err := Parse("   ", "")
+	assert.ErrorIs(t, err, ErrEmptyReceipt)
+
+	_, err = Parse("<nfeProc><NFe><infNFe></infNFe></NFe></nfeProc>", FormatXML)
+	assert.ErrorIs(t, err, ErrInvalidReceipt)
+
+	_, err = Parse("lista de compras sem formato", FormatText)
+	assert.ErrorIs(t, err, ErrInvalidReceipt)
+}
@@ -0,0 +1,56 @@
+package nfce
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var qrPayloadPattern = regexp.MustCompile(`^\d{44}\|`)
+
+// ParseQRCode reads the access key from the QR code content, either the full
+// consultation URL or just its "p" parameter. Version 2 offline (contingency)
+// codes also carry the total. QR codes never include the product lines.
+func ParseQRCode(content string) (*Receipt, error) {
+	trimmed := strings.TrimSpace(content)
+
+	payload := trimmed
+	var query url.Values
+	if strings.HasPrefix(strings.ToLower(trimmed), "http") {
+		parsed, err := url.Parse(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReceipt, err)
+		}
+		query = parsed.Query()
+		payload = query.Get("p")
+	}
+
+	receipt := &Receipt{}
+	switch {
+	case payload != "":
+		fields := strings.Split(payload, "|")
+		receipt.AccessKey = onlyDigits(fields[0])
+		// chave|versao|tpAmb|dia|vNF|digVal|cIdToken|hash
+		if len(fields) >= 8 {
+			if total, err := parseNumber(fields[4]); err == nil {
+				receipt.Total = total
+			}
+		}
+	case query != nil && query.Get("chNFe") != "":
+		// Version 1 codes list the fields as query parameters.
+		receipt.AccessKey = onlyDigits(query.Get("chNFe"))
+		if total, err := parseNumber(query.Get("vNF")); err == nil {
+			receipt.Total = total
+		}
+		if decoded, err := hex.DecodeString(query.Get("dhEmi")); err == nil {
+			receipt.IssuedAt = parseIssuedAt(string(decoded))
+		}
+	}
+
+	if len(receipt.AccessKey) != 44 {
+		return nil, fmt.Errorf("%w: access key not found in QR code", ErrInvalidReceipt)
+	}
+	return receipt, nil
+}
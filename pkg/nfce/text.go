@@ -0,0 +1,90 @@
+package nfce
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	textLinePattern  = regexp.MustCompile(`(?i)([^\n]*?)\s*\(\s*C[óo]digo:\s*([^)]*?)\s*\)\s*Qtde\.?:\s*([\d.,]+)\s*UN:\s*(\S+)\s*Vl\.?\s*Unit\.?:\s*([\d.,]+)\s*Vl\.?\s*Total\s*([\d.,]+)`)
+	textPayPattern   = regexp.MustCompile(`(?i)Valor\s+a\s+pagar\s*R\$:?\s*([\d.,]+)`)
+	textTotalPattern = regexp.MustCompile(`(?i)Valor\s+total\s*R\$:?\s*([\d.,]+)`)
+	textKeyPattern   = regexp.MustCompile(`(?i)Chave\s+de\s+acesso:?\s*([\d\s]{44,70})`)
+	textCNPJPattern  = regexp.MustCompile(`(?i)CNPJ:?\s*([\d./-]{14,18})`)
+	textIssuePattern = regexp.MustCompile(`(?i)Emiss[ãa]o:?\s*(\d{2}/\d{2}/\d{4}\s+\d{2}:\d{2}:\d{2})`)
+)
+
+// ParseText parses the receipt as shown on the SEFAZ consultation page
+// reached through the QR code, copied as plain text.
+func ParseText(content string) (*Receipt, error) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	matches := textLinePattern.FindAllStringSubmatch(normalized, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: no product lines", ErrInvalidReceipt)
+	}
+
+	receipt := &Receipt{}
+	for _, line := range strings.Split(normalized, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			if !strings.Contains(strings.ToUpper(trimmed), "CNPJ") && !textLinePattern.MatchString(trimmed) {
+				receipt.IssuerName = trimmed
+			}
+			break
+		}
+	}
+	if match := textCNPJPattern.FindStringSubmatch(normalized); match != nil {
+		receipt.IssuerCNPJ = onlyDigits(match[1])
+	}
+	if match := textKeyPattern.FindStringSubmatch(normalized); match != nil {
+		if digits := onlyDigits(match[1]); len(digits) >= 44 {
+			receipt.AccessKey = digits[:44]
+		}
+	}
+	if match := textIssuePattern.FindStringSubmatch(normalized); match != nil {
+		if issuedAt, err := time.Parse("02/01/2006 15:04:05", strings.Join(strings.Fields(match[1]), " ")); err == nil {
+			receipt.IssuedAt = &issuedAt
+		}
+	}
+	totalMatch := textPayPattern.FindStringSubmatch(normalized)
+	if totalMatch == nil {
+		totalMatch = textTotalPattern.FindStringSubmatch(normalized)
+	}
+	if totalMatch != nil {
+		if total, err := parseNumber(totalMatch[1]); err == nil {
+			receipt.Total = total
+		}
+	}
+
+	for idx, match := range matches {
+		quantity, err := parseNumber(match[3])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d quantity: %v", ErrInvalidReceipt, idx+1, err)
+		}
+		unitPrice, err := parseNumber(match[5])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d unit price: %v", ErrInvalidReceipt, idx+1, err)
+		}
+		total, err := parseNumber(match[6])
+		if err != nil {
+			total = unitPrice * quantity
+		}
+		code := strings.TrimSpace(match[2])
+		line := Line{
+			Number:      idx + 1,
+			Code:        code,
+			Description: strings.TrimSpace(match[1]),
+			Unit:        strings.TrimSpace(match[4]),
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			Total:       total,
+		}
+		if digits := onlyDigits(code); len(digits) == 8 || len(digits) == 13 || len(digits) == 14 {
+			line.EAN = digits
+		}
+		receipt.Lines = append(receipt.Lines, line)
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,130 @@
+package nfce
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type xmlInfNFe struct {
+	ID  string `xml:"Id,attr"`
+	Ide struct {
+		DhEmi string `xml:"dhEmi"`
+	} `xml:"ide"`
+	Emit struct {
+		CNPJ  string `xml:"CNPJ"`
+		XNome string `xml:"xNome"`
+		XFant string `xml:"xFant"`
+	} `xml:"emit"`
+	Det []struct {
+		NItem string `xml:"nItem,attr"`
+		Prod  struct {
+			CProd  string `xml:"cProd"`
+			CEAN   string `xml:"cEAN"`
+			XProd  string `xml:"xProd"`
+			UCom   string `xml:"uCom"`
+			QCom   string `xml:"qCom"`
+			VUnCom string `xml:"vUnCom"`
+			VProd  string `xml:"vProd"`
+			VDesc  string `xml:"vDesc"`
+		} `xml:"prod"`
+	} `xml:"det"`
+	Total struct {
+		ICMSTot struct {
+			VNF string `xml:"vNF"`
+		} `xml:"ICMSTot"`
+	} `xml:"total"`
+}
+
+type xmlNFe struct {
+	InfNFe xmlInfNFe `xml:"infNFe"`
+}
+
+// xmlDocument accepts both the authorized envelope (nfeProc) and a bare NFe.
+type xmlDocument struct {
+	XMLName xml.Name
+	NFe     *xmlNFe   `xml:"NFe"`
+	InfNFe  xmlInfNFe `xml:"infNFe"`
+	ProtNFe struct {
+		InfProt struct {
+			ChNFe string `xml:"chNFe"`
+		} `xml:"infProt"`
+	} `xml:"protNFe"`
+}
+
+// ParseXML parses an NFC-e XML, either the nfeProc envelope or the NFe alone.
+func ParseXML(data []byte) (*Receipt, error) {
+	var doc xmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceipt, err)
+	}
+
+	inf := doc.InfNFe
+	if doc.NFe != nil {
+		inf = doc.NFe.InfNFe
+	}
+	if len(inf.Det) == 0 {
+		return nil, fmt.Errorf("%w: no product lines", ErrInvalidReceipt)
+	}
+
+	receipt := &Receipt{
+		AccessKey:  onlyDigits(doc.ProtNFe.InfProt.ChNFe),
+		IssuerName: strings.TrimSpace(inf.Emit.XFant),
+		IssuerCNPJ: onlyDigits(inf.Emit.CNPJ),
+		IssuedAt:   parseIssuedAt(inf.Ide.DhEmi),
+	}
+	if receipt.AccessKey == "" {
+		receipt.AccessKey = onlyDigits(inf.ID)
+	}
+	if receipt.IssuerName == "" {
+		receipt.IssuerName = strings.TrimSpace(inf.Emit.XNome)
+	}
+	if total, err := parseNumber(inf.Total.ICMSTot.VNF); err == nil {
+		receipt.Total = total
+	}
+
+	for idx, det := range inf.Det {
+		prod := det.Prod
+		quantity, err := parseNumber(prod.QCom)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d quantity: %v", ErrInvalidReceipt, idx+1, err)
+		}
+		unitPrice, err := parseNumber(prod.VUnCom)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d unit price: %v", ErrInvalidReceipt, idx+1, err)
+		}
+		total, err := parseNumber(prod.VProd)
+		if err != nil {
+			total = unitPrice * quantity
+		}
+		if discount, err := parseNumber(prod.VDesc); err == nil && discount > 0 {
+			total -= discount
+			if quantity > 0 {
+				unitPrice = total / quantity
+			}
+		}
+
+		number, err := strconv.Atoi(det.NItem)
+		if err != nil {
+			number = idx + 1
+		}
+		ean := strings.TrimSpace(prod.CEAN)
+		if strings.EqualFold(ean, "SEM GTIN") {
+			ean = ""
+		}
+
+		receipt.Lines = append(receipt.Lines, Line{
+			Number:      number,
+			Code:        strings.TrimSpace(prod.CProd),
+			EAN:         ean,
+			Description: strings.TrimSpace(prod.XProd),
+			Unit:        strings.TrimSpace(prod.UCom),
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			Total:       total,
+		})
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,182 @@
+// Package pdf writes simple printable documents made of lines of text using
+// the standard Helvetica fonts, so no font files have to be embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+)
+
+// Style controls how a line is rendered.
+type Style struct {
+	Size   float64
+	Bold   bool
+	Indent float64
+}
+
+var (
+	Title   = Style{Size: 18, Bold: true}
+	Heading = Style{Size: 13, Bold: true}
+	Body    = Style{Size: 10}
+	Small   = Style{Size: 8}
+)
+
+type textLine struct {
+	text  string
+	style Style
+	y     float64
+}
+
+// Document accumulates lines and breaks pages automatically.
+type Document struct {
+	pages [][]textLine
+	y     float64
+}
+
+func New() *Document {
+	d := &Document{}
+	d.newPage()
+	return d
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pageHeight - margin
+}
+
+// Text adds a line of text, wrapping it to the page width.
+func (d *Document) Text(text string, style Style) {
+	if style.Size <= 0 {
+		style.Size = Body.Size
+	}
+	for _, line := range wrap(text, style) {
+		lineHeight := style.Size * 1.4
+		if d.y-lineHeight < margin {
+			d.newPage()
+		}
+		d.y -= lineHeight
+		current := len(d.pages) - 1
+		d.pages[current] = append(d.pages[current], textLine{text: line, style: style, y: d.y})
+	}
+}
+
+// Space adds vertical space.
+func (d *Document) Space(points float64) {
+	d.y -= points
+	if d.y < margin {
+		d.newPage()
+	}
+}
+
+// wrap splits text into lines that fit the page, estimating the width of
+// Helvetica glyphs as roughly half of the font size.
+func wrap(text string, style Style) []string {
+	available := pageWidth - 2*margin - style.Indent
+	maxChars := int(available / (style.Size * 0.5))
+	if maxChars < 10 {
+		maxChars = 10
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		current := words[0]
+		for _, word := range words[1:] {
+			if utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > maxChars {
+				lines = append(lines, current)
+				current = word
+				continue
+			}
+			current += " " + word
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// encodeText converts UTF-8 into the WinAnsi encoding used by the standard
+// fonts and escapes the PDF string delimiters.
+func encodeText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r == '•':
+			b.WriteString(`\225`)
+		case r == '–':
+			b.WriteString(`\226`)
+		case r == '—':
+			b.WriteString(`\227`)
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are fixed; pages and their contents follow in pairs.
+	pageCount := len(d.pages)
+	kids := make([]string, pageCount)
+	for idx := range d.pages {
+		kids[idx] = fmt.Sprintf("%d 0 R", 5+idx*2)
+	}
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for idx, lines := range d.pages {
+		var content bytes.Buffer
+		for _, line := range lines {
+			font := "F1"
+			if line.style.Bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+				font, line.style.Size, margin+line.style.Indent, line.y, encodeText(line.text))
+		}
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+idx*2))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
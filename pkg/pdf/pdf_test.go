@@ -0,0 +1,35 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentBytes(t *testing.T) {
+	doc := New()
+	doc.Text("Lista de Compras", Title)
+	doc.Text("Feijão (carioca)", Body)
+
+	out := doc.Bytes()
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), `(Feij\343o \(carioca\)) Tj`)
+	assert.Contains(t, string(out), "/Count 1")
+}
+
+func TestDocumentBreaksPages(t *testing.T) {
+	doc := New()
+	for i := 0; i < 120; i++ {
+		doc.Text("Arroz 5 kg", Body)
+	}
+	assert.Contains(t, string(doc.Bytes()), "/Count 3")
+}
+
+func TestWrapLongLines(t *testing.T) {
+	lines := wrap(strings.Repeat("palavra ", 40), Body)
+	assert.Greater(t, len(lines), 1)
+}
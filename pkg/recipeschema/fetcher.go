@@ -0,0 +1,128 @@
+package recipeschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL      = errors.New("recipeschema: invalid url")
+	ErrBlockedAddress  = errors.New("recipeschema: address not allowed")
+	ErrPageTooLarge    = errors.New("recipeschema: page too large")
+	ErrUnexpectedReply = errors.New("recipeschema: unexpected response")
+)
+
+const (
+	defaultFetchTimeout = 10 * time.Second
+	defaultMaxPageSize  = 2 << 20
+)
+
+// Fetcher downloads the HTML of a recipe page.
+type Fetcher interface {
+	Fetch(ctx context.Context, pageURL string) (string, error)
+}
+
+// HTTPFetcher fetches pages over HTTP. It only reaches public addresses, so
+// a user cannot make the server read its own network.
+type HTTPFetcher struct {
+	client      *http.Client
+	maxPageSize int64
+}
+
+func NewHTTPFetcher() *HTTPFetcher {
+	dialer := &net.Dialer{
+		Timeout: defaultFetchTimeout,
+		// The address is checked after the name is resolved, which also
+		// covers the redirects.
+		Control: func(_ string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: defaultFetchTimeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+	}
+	return &HTTPFetcher{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   defaultFetchTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return fmt.Errorf("%w: too many redirects", ErrUnexpectedReply)
+				}
+				return checkURL(req.URL)
+			},
+		},
+		maxPageSize: defaultMaxPageSize,
+	}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, pageURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(pageURL))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if err := checkURL(parsed); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "DespensaDigital/1.0 (+recipe import)")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("recipeschema: fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: status %d", ErrUnexpectedReply, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxPageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("recipeschema: read page: %w", err)
+	}
+	if int64(len(body)) > f.maxPageSize {
+		return "", ErrPageTooLarge
+	}
+	return string(body), nil
+}
+
+func checkURL(pageURL *url.URL) error {
+	if pageURL.Scheme != "http" && pageURL.Scheme != "https" {
+		return fmt.Errorf("%w: only http and https pages can be imported", ErrInvalidURL)
+	}
+	if pageURL.Hostname() == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsMulticast() &&
+		!ip.IsInterfaceLocalMulticast()
+}
@@ -0,0 +1,151 @@
+package recipeschema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+)
+
+// Ingredient is an ingredient line split into its parts. Amount is nil when
+// the line has no quantity, as in "sal a gosto", and Unit keeps the text of
+// the page, such as "xícaras".
+type Ingredient struct {
+	Amount *float64
+	Unit   string
+	Name   string
+}
+
+var fractionReplacer = strings.NewReplacer(
+	"½", " 1/2", "⅓", " 1/3", "⅔", " 2/3", "¼", " 1/4", "¾", " 3/4", "⅛", " 1/8",
+	"⁄", "/",
+)
+
+var (
+	// "xícara (chá)" and "colher (sopa)" are how Brazilian recipes name the
+	// kitchen measures.
+	spoonQualifierPattern = regexp.MustCompile(`(?i)\b(colher(?:es)?)\s*\(\s*(?:de\s+)?(sopa|ch[aá]|caf[eé])\s*\)`)
+	cupQualifierPattern   = regexp.MustCompile(`(?i)\b(x[ií]caras?|copos?)\s*\(\s*(?:de\s+)?(?:ch[aá]|americano|requeij[aã]o)\s*\)`)
+	toTastePattern        = regexp.MustCompile(`(?i)[,\s]*\b(?:a gosto|q\.?\s?b\.?|quanto baste)\b\.?`)
+	gluedUnitPattern      = regexp.MustCompile(`(?i)\b(\d+(?:[.,]\d+)?)(mg|g|kg|ml|l)\b`)
+	numberPattern         = regexp.MustCompile(`^\d+(?:[.,]\d+)?$`)
+	fractionPattern       = regexp.MustCompile(`^(\d+)/(\d+)$`)
+)
+
+var wordAmounts = map[string]float64{
+	"meia": 0.5, "meio": 0.5, "um": 1, "uma": 1, "dois": 2, "duas": 2, "tres": 3, "três": 3,
+}
+
+// containerUnits are the measures that only count packages or pieces, which
+// the units package does not know.
+var containerUnits = map[string]bool{
+	"lata": true, "latas": true, "dente": true, "dentes": true, "pitada": true, "pitadas": true,
+	"maço": true, "maços": true, "maco": true, "macos": true, "pacote": true, "pacotes": true,
+	"caixa": true, "caixas": true, "caixinha": true, "caixinhas": true, "fatia": true, "fatias": true,
+	"vidro": true, "vidros": true, "envelope": true, "envelopes": true, "ramo": true, "ramos": true,
+	"folha": true, "folhas": true, "punhado": true, "punhados": true, "pedaço": true, "pedaços": true,
+	"colher de cafe": true, "colheres de cafe": true, "colher de café": true, "colheres de café": true,
+}
+
+// ParseIngredient splits an ingredient line such as "2 xícaras (chá) de
+// farinha de trigo" into amount, unit and name.
+func ParseIngredient(line string) Ingredient {
+	text := cleanText(fractionReplacer.Replace(line))
+	text = spoonQualifierPattern.ReplaceAllString(text, "$1 de $2")
+	text = cupQualifierPattern.ReplaceAllString(text, "$1")
+	text = gluedUnitPattern.ReplaceAllString(text, "$1 $2")
+	text = strings.TrimSpace(toTastePattern.ReplaceAllString(text, ""))
+
+	tokens := strings.Fields(text)
+	amount, used := parseAmount(tokens)
+	tokens = tokens[used:]
+
+	ingredient := Ingredient{Amount: amount}
+	if amount != nil {
+		if unit, size := matchUnit(tokens); size > 0 {
+			ingredient.Unit = unit
+			tokens = tokens[size:]
+		}
+	}
+	if ingredient.Unit != "" && len(tokens) > 1 {
+		switch strings.ToLower(tokens[0]) {
+		case "de", "do", "da", "dos", "das":
+			tokens = tokens[1:]
+		}
+	}
+	ingredient.Name = strings.Trim(strings.Join(tokens, " "), " ,;-")
+	if ingredient.Name == "" {
+		ingredient.Name = cleanText(line)
+	}
+	return ingredient
+}
+
+// parseAmount reads the quantity at the start of the tokens: a number, a
+// fraction, a mixed number such as "1 1/2" or a range such as "2 a 3", of
+// which the first value is kept. It returns how many tokens were used.
+func parseAmount(tokens []string) (*float64, int) {
+	if len(tokens) == 0 {
+		return nil, 0
+	}
+	value, ok := parseNumber(tokens[0])
+	if !ok {
+		// "2-3 ovos" is a range written as a single token.
+		if parts := strings.SplitN(tokens[0], "-", 2); len(parts) == 2 {
+			if low, lowOK := parseNumber(parts[0]); lowOK {
+				if _, highOK := parseNumber(parts[1]); highOK {
+					return &low, 1
+				}
+			}
+		}
+		if word, found := wordAmounts[strings.ToLower(tokens[0])]; found && len(tokens) > 1 {
+			return &word, 1
+		}
+		return nil, 0
+	}
+
+	used := 1
+	if used < len(tokens) && !strings.Contains(tokens[0], "/") {
+		if fraction, fractionOK := parseNumber(tokens[used]); fractionOK && strings.Contains(tokens[used], "/") {
+			value += fraction
+			used++
+		}
+	}
+	if used+1 < len(tokens) {
+		switch strings.ToLower(tokens[used]) {
+		case "a", "ou", "-", "–":
+			if _, highOK := parseNumber(tokens[used+1]); highOK {
+				used += 2
+			}
+		}
+	}
+	return &value, used
+}
+
+func parseNumber(token string) (float64, bool) {
+	if match := fractionPattern.FindStringSubmatch(token); match != nil {
+		numerator, _ := strconv.ParseFloat(match[1], 64)
+		denominator, _ := strconv.ParseFloat(match[2], 64)
+		if denominator == 0 {
+			return 0, false
+		}
+		return numerator / denominator, true
+	}
+	if numberPattern.MatchString(token) {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(token, ",", "."), 64)
+		return value, err == nil
+	}
+	return 0, false
+}
+
+// matchUnit finds the longest unit, up to three words, at the start of the
+// tokens.
+func matchUnit(tokens []string) (string, int) {
+	for size := min(3, len(tokens)); size > 0; size-- {
+		phrase := strings.Join(tokens[:size], " ")
+		if _, known := units.Lookup(phrase); known || containerUnits[strings.ToLower(phrase)] {
+			return phrase, size
+		}
+	}
+	return "", 0
+}
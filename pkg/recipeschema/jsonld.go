@@ -0,0 +1,188 @@
+package recipeschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// parseJSONLD looks for a Recipe in the JSON-LD scripts of the page, including
+// the ones nested in @graph or in arrays.
+func parseJSONLD(root *xhtml.Node) *Recipe {
+	var scripts []string
+	walk(root, func(node *xhtml.Node) bool {
+		if node.Type == xhtml.ElementNode && node.Data == "script" && strings.EqualFold(strings.TrimSpace(attr(node, "type")), "application/ld+json") {
+			scripts = append(scripts, textContent(node))
+			return false
+		}
+		return true
+	})
+
+	for _, script := range scripts {
+		var data any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(script)), &data); err != nil {
+			continue
+		}
+		if node := findRecipeNode(data); node != nil {
+			return recipeFromJSONLD(node)
+		}
+	}
+	return nil
+}
+
+func findRecipeNode(data any) map[string]any {
+	switch value := data.(type) {
+	case []any:
+		for _, item := range value {
+			if node := findRecipeNode(item); node != nil {
+				return node
+			}
+		}
+	case map[string]any:
+		if hasType(value["@type"], "Recipe") {
+			return value
+		}
+		if graph, ok := value["@graph"]; ok {
+			return findRecipeNode(graph)
+		}
+		// Some pages wrap the recipe in the WebPage that shows it.
+		if entity, ok := value["mainEntity"]; ok {
+			return findRecipeNode(entity)
+		}
+	}
+	return nil
+}
+
+func hasType(value any, name string) bool {
+	switch typed := value.(type) {
+	case string:
+		return strings.EqualFold(strings.TrimPrefix(strings.TrimPrefix(typed, "http://schema.org/"), "https://schema.org/"), name)
+	case []any:
+		for _, item := range typed {
+			if hasType(item, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func recipeFromJSONLD(node map[string]any) *Recipe {
+	recipe := &Recipe{
+		Format:      FormatJSONLD,
+		Name:        cleanText(firstString(node["name"])),
+		Description: cleanText(firstString(node["description"])),
+		Yield:       cleanText(firstString(node["recipeYield"])),
+		Category:    cleanText(firstString(node["recipeCategory"])),
+		Cuisine:     cleanText(firstString(node["recipeCuisine"])),
+		Keywords:    splitKeywords(node["keywords"]),
+	}
+	recipe.PrepTime, _ = ParseDuration(firstString(node["prepTime"]))
+	recipe.CookTime, _ = ParseDuration(firstString(node["cookTime"]))
+	recipe.TotalTime, _ = ParseDuration(firstString(node["totalTime"]))
+
+	ingredients := node["recipeIngredient"]
+	if ingredients == nil {
+		ingredients = node["ingredients"]
+	}
+	for _, line := range stringList(ingredients) {
+		if text := cleanText(line); text != "" {
+			recipe.Ingredients = append(recipe.Ingredients, text)
+		}
+	}
+	recipe.Instructions = jsonLDInstructions(node["recipeInstructions"])
+
+	if nutrition, ok := node["nutrition"].(map[string]any); ok {
+		recipe.Calories = cleanText(firstString(nutrition["calories"]))
+		recipe.Protein = cleanText(firstString(nutrition["proteinContent"]))
+		recipe.Carbs = cleanText(firstString(nutrition["carbohydrateContent"]))
+		recipe.Fat = cleanText(firstString(nutrition["fatContent"]))
+	}
+	return recipe
+}
+
+// jsonLDInstructions flattens the instructions, which can be a text, a list of
+// texts, a list of HowToStep or HowToSection grouping steps.
+func jsonLDInstructions(value any) []string {
+	var steps []string
+	switch typed := value.(type) {
+	case string:
+		for _, line := range strings.Split(html.UnescapeString(typed), "\n") {
+			if text := cleanText(line); text != "" {
+				steps = append(steps, text)
+			}
+		}
+	case []any:
+		for _, item := range typed {
+			steps = append(steps, jsonLDInstructions(item)...)
+		}
+	case map[string]any:
+		if elements, ok := typed["itemListElement"]; ok {
+			return jsonLDInstructions(elements)
+		}
+		text := firstString(typed["text"])
+		if text == "" {
+			text = firstString(typed["name"])
+		}
+		if text = cleanText(text); text != "" {
+			steps = append(steps, text)
+		}
+	}
+	return steps
+}
+
+func splitKeywords(value any) []string {
+	var keywords []string
+	for _, item := range stringList(value) {
+		for _, keyword := range strings.Split(item, ",") {
+			if text := cleanText(keyword); text != "" {
+				keywords = append(keywords, text)
+			}
+		}
+	}
+	return keywords
+}
+
+// stringList reads a value that can be a text or a list of texts.
+func stringList(value any) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []any:
+		values := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if text := firstString(item); text != "" {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// firstString reads a text from a value that can also be a number, a list or
+// an object with a name or value.
+func firstString(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case float64:
+		return fmt.Sprint(typed)
+	case []any:
+		for _, item := range typed {
+			if text := firstString(item); text != "" {
+				return text
+			}
+		}
+	case map[string]any:
+		for _, key := range []string{"name", "value", "@value", "text"} {
+			if text := firstString(typed[key]); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
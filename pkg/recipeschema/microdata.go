@@ -0,0 +1,216 @@
+package recipeschema
+
+import (
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// parseMicrodata reads the first element with itemtype schema.org/Recipe and
+// its itemprop attributes.
+func parseMicrodata(root *xhtml.Node) *Recipe {
+	var scope *xhtml.Node
+	walk(root, func(node *xhtml.Node) bool {
+		if scope != nil {
+			return false
+		}
+		if node.Type == xhtml.ElementNode && hasAttr(node, "itemscope") && isRecipeType(attr(node, "itemtype")) {
+			scope = node
+			return false
+		}
+		return true
+	})
+	if scope == nil {
+		return nil
+	}
+
+	props := make(map[string][]string)
+	var instructionNodes []*xhtml.Node
+	var nutrition *xhtml.Node
+	collectProps(scope, func(name string, node *xhtml.Node) {
+		switch name {
+		case "recipeInstructions":
+			instructionNodes = append(instructionNodes, node)
+		case "nutrition":
+			nutrition = node
+		default:
+			props[name] = append(props[name], itemValue(node))
+		}
+	})
+
+	recipe := &Recipe{
+		Format:      FormatMicrodata,
+		Name:        first(props["name"]),
+		Description: first(props["description"]),
+		Yield:       first(props["recipeYield"]),
+		Category:    first(props["recipeCategory"]),
+		Cuisine:     first(props["recipeCuisine"]),
+	}
+	recipe.PrepTime, _ = ParseDuration(first(props["prepTime"]))
+	recipe.CookTime, _ = ParseDuration(first(props["cookTime"]))
+	recipe.TotalTime, _ = ParseDuration(first(props["totalTime"]))
+
+	ingredients := props["recipeIngredient"]
+	if len(ingredients) == 0 {
+		ingredients = props["ingredients"]
+	}
+	for _, line := range ingredients {
+		if line != "" {
+			recipe.Ingredients = append(recipe.Ingredients, line)
+		}
+	}
+	for _, keywords := range props["keywords"] {
+		for _, keyword := range strings.Split(keywords, ",") {
+			if text := cleanText(keyword); text != "" {
+				recipe.Keywords = append(recipe.Keywords, text)
+			}
+		}
+	}
+	for _, node := range instructionNodes {
+		recipe.Instructions = append(recipe.Instructions, microdataInstructions(node)...)
+	}
+
+	if nutrition != nil {
+		nutritionProps := make(map[string]string)
+		collectProps(nutrition, func(name string, node *xhtml.Node) {
+			if _, ok := nutritionProps[name]; !ok {
+				nutritionProps[name] = itemValue(node)
+			}
+		})
+		recipe.Calories = nutritionProps["calories"]
+		recipe.Protein = nutritionProps["proteinContent"]
+		recipe.Carbs = nutritionProps["carbohydrateContent"]
+		recipe.Fat = nutritionProps["fatContent"]
+	}
+	return recipe
+}
+
+// microdataInstructions reads the steps of an instructions element, which can
+// be a HowToStep, a list whose items are the steps or a block of text.
+func microdataInstructions(node *xhtml.Node) []string {
+	var steps []string
+	collectProps(node, func(name string, child *xhtml.Node) {
+		if name == "text" || name == "itemListElement" {
+			if text := itemValue(child); text != "" {
+				steps = append(steps, text)
+			}
+		}
+	})
+	if len(steps) > 0 {
+		return steps
+	}
+
+	walk(node, func(child *xhtml.Node) bool {
+		if child.Type == xhtml.ElementNode && (child.Data == "li" || child.Data == "p") {
+			if text := cleanText(textContent(child)); text != "" {
+				steps = append(steps, text)
+			}
+			return false
+		}
+		return true
+	})
+	if len(steps) > 0 {
+		return steps
+	}
+	if text := cleanText(textContent(node)); text != "" {
+		steps = append(steps, text)
+	}
+	return steps
+}
+
+// collectProps calls visit for each itemprop of the scope, without entering
+// the nested scopes.
+func collectProps(scope *xhtml.Node, visit func(name string, node *xhtml.Node)) {
+	for child := scope.FirstChild; child != nil; child = child.NextSibling {
+		walk(child, func(node *xhtml.Node) bool {
+			if node.Type != xhtml.ElementNode {
+				return true
+			}
+			for _, name := range strings.Fields(attr(node, "itemprop")) {
+				visit(name, node)
+			}
+			return !hasAttr(node, "itemscope")
+		})
+	}
+}
+
+// itemValue follows the microdata rules for the value of a property.
+func itemValue(node *xhtml.Node) string {
+	if value, ok := lookupAttr(node, "content"); ok {
+		return cleanText(value)
+	}
+	switch node.Data {
+	case "meta":
+		return cleanText(attr(node, "content"))
+	case "time":
+		if value, ok := lookupAttr(node, "datetime"); ok {
+			return cleanText(value)
+		}
+	case "img", "audio", "video", "source":
+		return strings.TrimSpace(attr(node, "src"))
+	case "a", "link":
+		return strings.TrimSpace(attr(node, "href"))
+	case "data", "meter":
+		return cleanText(attr(node, "value"))
+	}
+	return cleanText(textContent(node))
+}
+
+func isRecipeType(itemType string) bool {
+	for _, value := range strings.Fields(itemType) {
+		value = strings.TrimSuffix(value, "/")
+		if strings.HasSuffix(strings.ToLower(value), "schema.org/recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// walk visits the node and its descendants in document order. Returning false
+// from visit skips the children of the node.
+func walk(node *xhtml.Node, visit func(*xhtml.Node) bool) {
+	if !visit(node) {
+		return
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		walk(child, visit)
+	}
+}
+
+func textContent(node *xhtml.Node) string {
+	var builder strings.Builder
+	walk(node, func(child *xhtml.Node) bool {
+		if child.Type == xhtml.TextNode {
+			builder.WriteString(child.Data)
+			builder.WriteString(" ")
+		}
+		return true
+	})
+	return builder.String()
+}
+
+func lookupAttr(node *xhtml.Node, name string) (string, bool) {
+	for _, attribute := range node.Attr {
+		if strings.EqualFold(attribute.Key, name) {
+			return attribute.Val, true
+		}
+	}
+	return "", false
+}
+
+func attr(node *xhtml.Node, name string) string {
+	value, _ := lookupAttr(node, name)
+	return value
+}
+
+func hasAttr(node *xhtml.Node, name string) bool {
+	_, ok := lookupAttr(node, name)
+	return ok
+}
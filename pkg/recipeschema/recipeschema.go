@@ -0,0 +1,122 @@
+// Package recipeschema extracts schema.org Recipe data from HTML pages, from
+// JSON-LD scripts or from microdata attributes. Parsing is done offline; pages
+// are fetched through a Fetcher.
+package recipeschema
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+type Format string
+
+const (
+	FormatJSONLD    Format = "json-ld"
+	FormatMicrodata Format = "microdata"
+)
+
+var (
+	ErrEmptyPage = errors.New("recipeschema: empty page")
+	ErrNoRecipe  = errors.New("recipeschema: no schema.org recipe found")
+)
+
+// Recipe is the content of a schema.org Recipe. Times are in minutes and are
+// zero when the page does not inform them.
+type Recipe struct {
+	Format       Format
+	Name         string
+	Description  string
+	Yield        string
+	PrepTime     int
+	CookTime     int
+	TotalTime    int
+	Category     string
+	Cuisine      string
+	Keywords     []string
+	Ingredients  []string
+	Instructions []string
+	Calories     string
+	Protein      string
+	Carbs        string
+	Fat          string
+}
+
+var yieldPattern = regexp.MustCompile(`\d+`)
+
+// Servings reads the number of servings from the yield, such as the 8 in
+// "8 porções". It is zero when the yield has no number.
+func (r Recipe) Servings() int {
+	match := yieldPattern.FindString(r.Yield)
+	if match == "" {
+		return 0
+	}
+	value, err := strconv.Atoi(match)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// Parse extracts the first recipe of a page, preferring JSON-LD to
+// microdata.
+func Parse(page string) (*Recipe, error) {
+	if strings.TrimSpace(page) == "" {
+		return nil, ErrEmptyPage
+	}
+	root, err := xhtml.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("recipeschema: parse html: %w", err)
+	}
+	if recipe := parseJSONLD(root); recipe != nil {
+		return recipe, nil
+	}
+	if recipe := parseMicrodata(root); recipe != nil {
+		return recipe, nil
+	}
+	return nil, ErrNoRecipe
+}
+
+var isoDurationPattern = regexp.MustCompile(`(?i)^P(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// ParseDuration converts an ISO 8601 duration such as PT1H30M into minutes.
+// It reports false when the value is not a duration.
+func ParseDuration(value string) (int, bool) {
+	match := isoDurationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || strings.EqualFold(strings.TrimSpace(value), "P") || strings.EqualFold(strings.TrimSpace(value), "PT") {
+		return 0, false
+	}
+	weights := []float64{24 * 60, 60, 1, 1.0 / 60}
+	total := 0.0
+	for idx, weight := range weights {
+		if match[idx+1] == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(match[idx+1], ",", "."), 64)
+		if err != nil {
+			return 0, false
+		}
+		total += amount * weight
+	}
+	return int(total + 0.5), true
+}
+
+var (
+	tagPattern   = regexp.MustCompile(`<[^>]*>`)
+	spacePattern = regexp.MustCompile(`\s+`)
+	punctPattern = regexp.MustCompile(`\s+([.,;:!?])`)
+)
+
+// cleanText drops the markup some sites leave in the values and collapses the
+// whitespace.
+func cleanText(value string) string {
+	value = tagPattern.ReplaceAllString(value, " ")
+	value = html.UnescapeString(value)
+	value = spacePattern.ReplaceAllString(value, " ")
+	return strings.TrimSpace(punctPattern.ReplaceAllString(value, "$1"))
+}
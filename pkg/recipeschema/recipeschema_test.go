@@ -0,0 +1,154 @@
+package recipeschema
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestParseJSONLDGraph(t *testing.T) {
+	recipe, err := Parse(readFixture(t, "bolo_cenoura_jsonld.html"))
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatJSONLD, recipe.Format)
+	assert.Equal(t, "Bolo de cenoura com cobertura de chocolate", recipe.Name)
+	assert.Equal(t, "O clássico bolo de cenoura fofinho.", recipe.Description)
+	assert.Equal(t, 12, recipe.Servings())
+	assert.Equal(t, 20, recipe.PrepTime)
+	assert.Equal(t, 40, recipe.CookTime)
+	assert.Equal(t, 60, recipe.TotalTime)
+	assert.Equal(t, "Sobremesa", recipe.Category)
+	assert.Equal(t, []string{"bolo", "cenoura", "chocolate", "café da tarde"}, recipe.Keywords)
+	require.Len(t, recipe.Ingredients, 9)
+	assert.Equal(t, "1/2 xícara (chá) de óleo", recipe.Ingredients[2])
+	assert.Equal(t, []string{
+		"Bata no liquidificador a cenoura, os ovos e o óleo.",
+		"Misture o açúcar, a farinha e o fermento.",
+		"Asse em forno preaquecido a 180 °C por 40 minutos.",
+		"Derreta o chocolate com o creme de leite e cubra o bolo.",
+	}, recipe.Instructions)
+	assert.Equal(t, "320 kcal", recipe.Calories)
+	assert.Equal(t, "48 g", recipe.Carbs)
+}
+
+func TestParseMicrodata(t *testing.T) {
+	recipe, err := Parse(readFixture(t, "feijoada_microdata.html"))
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatMicrodata, recipe.Format)
+	assert.Equal(t, "Feijoada simples", recipe.Name)
+	assert.Equal(t, "Almoço", recipe.Category)
+	assert.Equal(t, 8, recipe.Servings())
+	assert.Equal(t, 30, recipe.PrepTime)
+	assert.Equal(t, 150, recipe.CookTime)
+	assert.Zero(t, recipe.TotalTime)
+	assert.Equal(t, []string{"feijão", "carne de porco"}, recipe.Keywords)
+	assert.Equal(t, []string{
+		"1 kg de feijão preto",
+		"500 g de costelinha de porco",
+		"2 colheres de sopa de azeite",
+		"4 dentes de alho",
+		"Folhas de louro",
+	}, recipe.Ingredients)
+	assert.Equal(t, []string{
+		"Deixe o feijão de molho na véspera.",
+		"Cozinhe o feijão com as carnes na panela de pressão.",
+		"Refogue o alho no azeite e junte ao feijão.",
+	}, recipe.Instructions)
+	assert.Equal(t, "540 calorias", recipe.Calories)
+	assert.Equal(t, "32 g", recipe.Protein)
+	assert.Empty(t, recipe.Fat)
+}
+
+func TestParseWithoutRecipe(t *testing.T) {
+	_, err := Parse(readFixture(t, "sem_receita.html"))
+	assert.ErrorIs(t, err, ErrNoRecipe)
+
+	_, err = Parse("   ")
+	assert.ErrorIs(t, err, ErrEmptyPage)
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]int{
+		"PT20M":   20,
+		"PT1H30M": 90,
+		"P1DT2H":  1560,
+		"PT90S":   2,
+		"pt45m":   45,
+	}
+	for value, expected := range cases {
+		minutes, ok := ParseDuration(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, minutes, value)
+	}
+
+	for _, value := range []string{"", "P", "PT", "20 minutos"} {
+		_, ok := ParseDuration(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestParseIngredient(t *testing.T) {
+	cases := []struct {
+		line   string
+		amount float64
+		unit   string
+		name   string
+	}{
+		{"2 xícaras (chá) de farinha de trigo", 2, "xícaras", "farinha de trigo"},
+		{"1/2 xícara (chá) de óleo", 0.5, "xícara", "óleo"},
+		{"2 1/2 xícaras de leite", 2.5, "xícaras", "leite"},
+		{"1½ colher (sopa) de manteiga", 1.5, "colher de sopa", "manteiga"},
+		{"3 colheres (chá) de sal", 3, "colheres de chá", "sal"},
+		{"200g de chocolate meio amargo", 200, "g", "chocolate meio amargo"},
+		{"1,5 kg de carne moída", 1.5, "kg", "carne moída"},
+		{"1 lata de leite condensado", 1, "lata", "leite condensado"},
+		{"4 dentes de alho", 4, "dentes", "alho"},
+		{"2 a 3 tomates", 2, "", "tomates"},
+		{"meia xícara de açúcar", 0.5, "xícara", "açúcar"},
+		{"3 ovos", 3, "", "ovos"},
+	}
+	for _, tc := range cases {
+		ingredient := ParseIngredient(tc.line)
+		require.NotNil(t, ingredient.Amount, tc.line)
+		assert.InDelta(t, tc.amount, *ingredient.Amount, 1e-9, tc.line)
+		assert.Equal(t, tc.unit, ingredient.Unit, tc.line)
+		assert.Equal(t, tc.name, ingredient.Name, tc.line)
+	}
+
+	salt := ParseIngredient("sal a gosto")
+	assert.Nil(t, salt.Amount)
+	assert.Empty(t, salt.Unit)
+	assert.Equal(t, "sal", salt.Name)
+
+	leaves := ParseIngredient("Folhas de louro")
+	assert.Nil(t, leaves.Amount)
+	assert.Equal(t, "Folhas de louro", leaves.Name)
+}
+
+func TestHTTPFetcherRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher()
+	_, err := fetcher.Fetch(context.Background(), server.URL)
+	assert.ErrorIs(t, err, ErrBlockedAddress)
+
+	_, err = fetcher.Fetch(context.Background(), "file:///etc/passwd")
+	assert.ErrorIs(t, err, ErrInvalidURL)
+}
@@ -0,0 +1,36 @@
+// Package slug turns names typed by users into names safe for files and
+// URLs.
+package slug
+
+import (
+	"strings"
+
+	"github.com/nclsgg/despensa-digital/backend/pkg/units"
+)
+
+// Make lowercases a name, drops its accents and joins its letters and digits
+// with dashes, so "Pão de Queijo!" is "pao-de-queijo". A name without any of
+// them gives fallback.
+func Make(name, fallback string) string {
+	value := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '-'
+		}
+	}, units.Fold(name))
+	value = strings.Trim(value, "-")
+	for strings.Contains(value, "--") {
+		value = strings.ReplaceAll(value, "--", "-")
+	}
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// FileName is the slug of a name with an extension.
+func FileName(name, fallback, extension string) string {
+	return Make(name, fallback) + "." + extension
+}
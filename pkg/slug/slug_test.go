@@ -0,0 +1,18 @@
+package slug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	assert.Equal(t, "pao-de-queijo", Make("Pão de Queijo!", "receita"))
+	assert.Equal(t, "compras-da-semana-2", Make("  Compras -- da Semana #2 ", "lista"))
+	assert.Equal(t, "receita", Make("🍰", "receita"))
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "feira.csv", FileName("Feira", "lista-de-compras", "csv"))
+	assert.Equal(t, "lista-de-compras.pdf", FileName("", "lista-de-compras", "pdf"))
+}
@@ -0,0 +1,17 @@
+// Package token generates the random tokens of links that must not be
+// guessed.
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New returns size random bytes from crypto/rand as 2*size hex characters.
+func New(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
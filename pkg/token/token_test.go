@@ -0,0 +1,21 @@
+package token
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	first, err := New(16)
+	require.NoError(t, err)
+	assert.Len(t, first, 32)
+	_, err = hex.DecodeString(first)
+	assert.NoError(t, err)
+
+	second, err := New(16)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
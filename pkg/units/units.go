@@ -0,0 +1,175 @@
+// Package units normalizes the measurement units used across pantry items,
+// shopping lists and recipes, and converts amounts between compatible units.
+package units
+
+import (
+	"math"
+	"strings"
+)
+
+type Dimension string
+
+const (
+	DimensionMass    Dimension = "mass"
+	DimensionVolume  Dimension = "volume"
+	DimensionCount   Dimension = "count"
+	DimensionUnknown Dimension = "unknown"
+)
+
+// Unit describes a canonical unit. Factor converts one of this unit into the
+// base unit of its dimension (g, ml or un).
+type Unit struct {
+	Symbol    string
+	Dimension Dimension
+	Factor    float64
+}
+
+var canonical = map[string]Unit{
+	"mg":          {Symbol: "mg", Dimension: DimensionMass, Factor: 0.001},
+	"g":           {Symbol: "g", Dimension: DimensionMass, Factor: 1},
+	"kg":          {Symbol: "kg", Dimension: DimensionMass, Factor: 1000},
+	"ml":          {Symbol: "ml", Dimension: DimensionVolume, Factor: 1},
+	"l":           {Symbol: "l", Dimension: DimensionVolume, Factor: 1000},
+	"colher_cha":  {Symbol: "colher_cha", Dimension: DimensionVolume, Factor: 5},
+	"colher_sopa": {Symbol: "colher_sopa", Dimension: DimensionVolume, Factor: 15},
+	"xicara":      {Symbol: "xicara", Dimension: DimensionVolume, Factor: 240},
+	"copo":        {Symbol: "copo", Dimension: DimensionVolume, Factor: 200},
+	"un":          {Symbol: "un", Dimension: DimensionCount, Factor: 1},
+	"dz":          {Symbol: "dz", Dimension: DimensionCount, Factor: 12},
+}
+
+var aliases = map[string]string{
+	"miligrama": "mg", "miligramas": "mg",
+	"grama": "g", "gramas": "g", "gr": "g", "grs": "g",
+	"quilo": "kg", "quilos": "kg", "kilo": "kg", "kilos": "kg", "kgs": "kg", "quilograma": "kg", "quilogramas": "kg",
+	"mililitro": "ml", "mililitros": "ml",
+	"litro": "l", "litros": "l", "lt": "l", "lts": "l",
+	"colher de cha": "colher_cha", "colheres de cha": "colher_cha", "colher (cha)": "colher_cha", "colher cha": "colher_cha", "csc": "colher_cha", "tsp": "colher_cha",
+	"colher de sopa": "colher_sopa", "colheres de sopa": "colher_sopa", "colher (sopa)": "colher_sopa", "colher sopa": "colher_sopa", "colher": "colher_sopa", "colheres": "colher_sopa", "cs": "colher_sopa", "tbsp": "colher_sopa",
+	"xicaras": "xicara", "xic": "xicara", "cup": "xicara", "cups": "xicara",
+	"copos":   "copo",
+	"unidade": "un", "unidades": "un", "und": "un", "unid": "un", "u": "un", "unit": "un", "units": "un", "pc": "un", "pcs": "un", "peca": "un", "pecas": "un",
+	"duzia": "dz", "duzias": "dz",
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// Fold lowercases a text and drops the accents of Portuguese, so "Açúcar"
+// and "acucar" compare equal. Names of items, ingredients and units are
+// matched on their folded form.
+func Fold(text string) string {
+	return accentReplacer.Replace(strings.ToLower(text))
+}
+
+func clean(unit string) string {
+	value := Fold(strings.TrimSpace(unit))
+	value = strings.TrimSuffix(value, ".")
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// Lookup resolves a free-text unit into its canonical form.
+func Lookup(unit string) (Unit, bool) {
+	key := clean(unit)
+	if u, ok := canonical[key]; ok {
+		return u, true
+	}
+	if alias, ok := aliases[key]; ok {
+		return canonical[alias], true
+	}
+	return Unit{Symbol: key, Dimension: DimensionUnknown, Factor: 1}, false
+}
+
+// Normalize returns the canonical symbol of a unit, or the cleaned input when
+// the unit is not known.
+func Normalize(unit string) string {
+	u, _ := Lookup(unit)
+	return u.Symbol
+}
+
+// Compatible reports whether amounts in the two units can be converted.
+func Compatible(from, to string) bool {
+	a, okA := Lookup(from)
+	b, okB := Lookup(to)
+	if !okA || !okB {
+		return a.Symbol == b.Symbol
+	}
+	return a.Dimension == b.Dimension
+}
+
+// Convert converts an amount between two units of the same dimension.
+// Unknown units are only convertible to themselves.
+func Convert(amount float64, from, to string) (float64, bool) {
+	a, okA := Lookup(from)
+	b, okB := Lookup(to)
+	if !okA || !okB {
+		if a.Symbol == b.Symbol {
+			return amount, true
+		}
+		return 0, false
+	}
+	if a.Dimension != b.Dimension {
+		return 0, false
+	}
+	return amount * a.Factor / b.Factor, true
+}
+
+// ToBase converts an amount into the base unit of its dimension.
+func ToBase(amount float64, unit string) (float64, Unit) {
+	u, ok := Lookup(unit)
+	if !ok {
+		return amount, u
+	}
+	base := canonical[baseSymbol(u.Dimension)]
+	return amount * u.Factor, base
+}
+
+// Humanize expresses an amount in the most readable unit of its dimension,
+// e.g. 1500 g becomes 1.5 kg and 0.25 l becomes 250 ml. Kitchen measures and
+// unknown units are kept as they are.
+func Humanize(amount float64, unit string) (float64, string) {
+	u, ok := Lookup(unit)
+	if !ok {
+		return amount, unit
+	}
+	switch u.Dimension {
+	case DimensionMass:
+		grams := amount * u.Factor
+		if math.Abs(grams) >= 1000 {
+			return grams / 1000, "kg"
+		}
+		if math.Abs(grams) < 1 && grams != 0 {
+			return grams * 1000, "mg"
+		}
+		return grams, "g"
+	case DimensionVolume:
+		if u.Symbol != "ml" && u.Symbol != "l" {
+			return amount, u.Symbol
+		}
+		ml := amount * u.Factor
+		if math.Abs(ml) >= 1000 {
+			return ml / 1000, "l"
+		}
+		return ml, "ml"
+	case DimensionCount:
+		return amount * u.Factor, "un"
+	}
+	return amount, u.Symbol
+}
+
+func baseSymbol(dimension Dimension) string {
+	switch dimension {
+	case DimensionMass:
+		return "g"
+	case DimensionVolume:
+		return "ml"
+	default:
+		return "un"
+	}
+}
@@ -0,0 +1,51 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "kg", Normalize(" Quilos "))
+	assert.Equal(t, "colher_sopa", Normalize("colheres de sopa"))
+	assert.Equal(t, "un", Normalize("Unidades"))
+	assert.Equal(t, "pitada", Normalize("Pitada"))
+}
+
+func TestFold(t *testing.T) {
+	assert.Equal(t, "acucar mascavo", Fold("Açúcar Mascavo"))
+	assert.Equal(t, "pao frances", Fold("PÃO FRANCÊS"))
+	assert.Equal(t, "linguica", Fold("lingüiça"))
+}
+
+func TestConvert(t *testing.T) {
+	value, ok := Convert(500, "g", "kg")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, value, 1e-9)
+
+	value, ok = Convert(2, "xícaras", "ml")
+	assert.True(t, ok)
+	assert.InDelta(t, 480, value, 1e-9)
+
+	_, ok = Convert(1, "kg", "l")
+	assert.False(t, ok)
+
+	value, ok = Convert(3, "pitada", "pitada")
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, value)
+}
+
+func TestHumanize(t *testing.T) {
+	amount, unit := Humanize(1000, "g")
+	assert.Equal(t, 1.0, amount)
+	assert.Equal(t, "kg", unit)
+
+	amount, unit = Humanize(0.25, "l")
+	assert.Equal(t, 250.0, amount)
+	assert.Equal(t, "ml", unit)
+
+	amount, unit = Humanize(2, "xicara")
+	assert.Equal(t, 2.0, amount)
+	assert.Equal(t, "xicara", unit)
+}